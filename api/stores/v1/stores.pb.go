@@ -9,6 +9,7 @@ package stores_v1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -27,6 +28,15 @@ type AddStoreRequest struct {
 	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	AddressId     string                 `protobuf:"bytes,3,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
 	RequestedBy   string                 `protobuf:"bytes,4,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"`
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	Tags          []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	ServiceArea   string                 `protobuf:"bytes,8,opt,name=service_area,json=serviceArea,proto3" json:"service_area,omitempty"`
+	ParentId      string                 `protobuf:"bytes,9,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Region        string                 `protobuf:"bytes,10,opt,name=region,proto3" json:"region,omitempty"`
+	Capabilities  []string               `protobuf:"bytes,11,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	Locale        string                 `protobuf:"bytes,12,opt,name=locale,proto3" json:"locale,omitempty"`
+	Translations  []*StoreTranslation    `protobuf:"bytes,13,rep,name=translations,proto3" json:"translations,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -89,6 +99,69 @@ func (x *AddStoreRequest) GetRequestedBy() string {
 	return ""
 }
 
+func (x *AddStoreRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *AddStoreRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *AddStoreRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AddStoreRequest) GetServiceArea() string {
+	if x != nil {
+		return x.ServiceArea
+	}
+	return ""
+}
+
+func (x *AddStoreRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *AddStoreRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *AddStoreRequest) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *AddStoreRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *AddStoreRequest) GetTranslations() []*StoreTranslation {
+	if x != nil {
+		return x.Translations
+	}
+	return nil
+}
+
 type AddStoreResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
@@ -142,10 +215,12 @@ func (x *AddStoreResponse) GetId() string {
 }
 
 type GetStoreRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	IncludeAddress bool                   `protobuf:"varint,2,opt,name=include_address,json=includeAddress,proto3" json:"include_address,omitempty"`
+	Locale         string                 `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *GetStoreRequest) Reset() {
@@ -185,6 +260,20 @@ func (x *GetStoreRequest) GetId() string {
 	return ""
 }
 
+func (x *GetStoreRequest) GetIncludeAddress() bool {
+	if x != nil {
+		return x.IncludeAddress
+	}
+	return false
+}
+
+func (x *GetStoreRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
 type GetStoreResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Store         *Store                 `protobuf:"bytes,1,opt,name=store,proto3,oneof" json:"store,omitempty"`
@@ -235,6 +324,19 @@ type Store struct {
 	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	Org           string                 `protobuf:"bytes,3,opt,name=org,proto3" json:"org,omitempty"`
 	AddressId     string                 `protobuf:"bytes,4,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	Address       *Address               `protobuf:"bytes,5,opt,name=address,proto3,oneof" json:"address,omitempty"`
+	Description   string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	Tags          []string               `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	Status        string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ServiceArea   string                 `protobuf:"bytes,10,opt,name=service_area,json=serviceArea,proto3" json:"service_area,omitempty"`
+	ParentId      string                 `protobuf:"bytes,11,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Region        string                 `protobuf:"bytes,12,opt,name=region,proto3" json:"region,omitempty"`
+	Capabilities  []string               `protobuf:"bytes,13,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	Closures      []*StoreClosure        `protobuf:"bytes,14,rep,name=closures,proto3" json:"closures,omitempty"`
+	Locale        string                 `protobuf:"bytes,15,opt,name=locale,proto3" json:"locale,omitempty"`
+	Translations  []*StoreTranslation    `protobuf:"bytes,16,rep,name=translations,proto3" json:"translations,omitempty"`
+	Attachments   []*StoreAttachment     `protobuf:"bytes,17,rep,name=attachments,proto3" json:"attachments,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -297,20 +399,242 @@ func (x *Store) GetAddressId() string {
 	return ""
 }
 
-type UpdateStoreRequest struct {
+func (x *Store) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *Store) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Store) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Store) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Store) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Store) GetServiceArea() string {
+	if x != nil {
+		return x.ServiceArea
+	}
+	return ""
+}
+
+func (x *Store) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Store) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Store) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *Store) GetClosures() []*StoreClosure {
+	if x != nil {
+		return x.Closures
+	}
+	return nil
+}
+
+func (x *Store) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *Store) GetTranslations() []*StoreTranslation {
+	if x != nil {
+		return x.Translations
+	}
+	return nil
+}
+
+func (x *Store) GetAttachments() []*StoreAttachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+type StoreTranslation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Locale        string                 `protobuf:"bytes,1,opt,name=locale,proto3" json:"locale,omitempty"`
 	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Org           string                 `protobuf:"bytes,3,opt,name=org,proto3" json:"org,omitempty"`
-	AddressId     string                 `protobuf:"bytes,4,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
-	RequestedBy   string                 `protobuf:"bytes,5,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *StoreTranslation) Reset() {
+	*x = StoreTranslation{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreTranslation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreTranslation) ProtoMessage() {}
+
+func (x *StoreTranslation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreTranslation.ProtoReflect.Descriptor instead.
+func (*StoreTranslation) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StoreTranslation) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *StoreTranslation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StoreTranslation) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type Address struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	FormattedAddress string                 `protobuf:"bytes,1,opt,name=formatted_address,json=formattedAddress,proto3" json:"formatted_address,omitempty"`
+	Latitude         float64                `protobuf:"fixed64,2,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude        float64                `protobuf:"fixed64,3,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Address) GetFormattedAddress() string {
+	if x != nil {
+		return x.FormattedAddress
+	}
+	return ""
+}
+
+func (x *Address) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Address) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+type UpdateStoreRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Org               string                 `protobuf:"bytes,3,opt,name=org,proto3" json:"org,omitempty"`
+	AddressId         string                 `protobuf:"bytes,4,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	RequestedBy       string                 `protobuf:"bytes,5,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"`
+	Description       string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	Tags              []string               `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	Status            string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	ServiceArea       string                 `protobuf:"bytes,9,opt,name=service_area,json=serviceArea,proto3" json:"service_area,omitempty"`
+	ParentId          string                 `protobuf:"bytes,10,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Region            string                 `protobuf:"bytes,11,opt,name=region,proto3" json:"region,omitempty"`
+	DetachParent      bool                   `protobuf:"varint,12,opt,name=detach_parent,json=detachParent,proto3" json:"detach_parent,omitempty"`
+	Capabilities      []string               `protobuf:"bytes,13,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	Locale            string                 `protobuf:"bytes,14,opt,name=locale,proto3" json:"locale,omitempty"`
+	Translations      []*StoreTranslation    `protobuf:"bytes,15,rep,name=translations,proto3" json:"translations,omitempty"`
+	ClearTranslations bool                   `protobuf:"varint,16,opt,name=clear_translations,json=clearTranslations,proto3" json:"clear_translations,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
 func (x *UpdateStoreRequest) Reset() {
 	*x = UpdateStoreRequest{}
-	mi := &file_api_stores_v1_stores_proto_msgTypes[5]
+	mi := &file_api_stores_v1_stores_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -322,7 +646,3138 @@ func (x *UpdateStoreRequest) String() string {
 func (*UpdateStoreRequest) ProtoMessage() {}
 
 func (x *UpdateStoreRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_stores_v1_stores_proto_msgTypes[5]
+	mi := &file_api_stores_v1_stores_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStoreRequest.ProtoReflect.Descriptor instead.
+func (*UpdateStoreRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateStoreRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateStoreRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateStoreRequest) GetOrg() string {
+	if x != nil {
+		return x.Org
+	}
+	return ""
+}
+
+func (x *UpdateStoreRequest) GetAddressId() string {
+	if x != nil {
+		return x.AddressId
+	}
+	return ""
+}
+
+func (x *UpdateStoreRequest) GetRequestedBy() string {
+	if x != nil {
+		return x.RequestedBy
+	}
+	return ""
+}
+
+func (x *UpdateStoreRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateStoreRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *UpdateStoreRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateStoreRequest) GetServiceArea() string {
+	if x != nil {
+		return x.ServiceArea
+	}
+	return ""
+}
+
+func (x *UpdateStoreRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *UpdateStoreRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *UpdateStoreRequest) GetDetachParent() bool {
+	if x != nil {
+		return x.DetachParent
+	}
+	return false
+}
+
+func (x *UpdateStoreRequest) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *UpdateStoreRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *UpdateStoreRequest) GetTranslations() []*StoreTranslation {
+	if x != nil {
+		return x.Translations
+	}
+	return nil
+}
+
+func (x *UpdateStoreRequest) GetClearTranslations() bool {
+	if x != nil {
+		return x.ClearTranslations
+	}
+	return false
+}
+
+type UpdateStoreResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Store         *Store                 `protobuf:"bytes,2,opt,name=store,proto3,oneof" json:"store,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateStoreResponse) Reset() {
+	*x = UpdateStoreResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateStoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateStoreResponse) ProtoMessage() {}
+
+func (x *UpdateStoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStoreResponse.ProtoReflect.Descriptor instead.
+func (*UpdateStoreResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateStoreResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *UpdateStoreResponse) GetStore() *Store {
+	if x != nil {
+		return x.Store
+	}
+	return nil
+}
+
+type DeleteStoreRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RequestedBy   string                 `protobuf:"bytes,2,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteStoreRequest) Reset() {
+	*x = DeleteStoreRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteStoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteStoreRequest) ProtoMessage() {}
+
+func (x *DeleteStoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteStoreRequest.ProtoReflect.Descriptor instead.
+func (*DeleteStoreRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteStoreRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteStoreRequest) GetRequestedBy() string {
+	if x != nil {
+		return x.RequestedBy
+	}
+	return ""
+}
+
+type DeleteStoreResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteStoreResponse) Reset() {
+	*x = DeleteStoreResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteStoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteStoreResponse) ProtoMessage() {}
+
+func (x *DeleteStoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteStoreResponse.ProtoReflect.Descriptor instead.
+func (*DeleteStoreResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteStoreResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+type SearchStoreRequest struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Org                      string                 `protobuf:"bytes,1,opt,name=org,proto3" json:"org,omitempty"`
+	Name                     string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	AddressId                string                 `protobuf:"bytes,3,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	AddressStr               string                 `protobuf:"bytes,4,opt,name=address_str,json=addressStr,proto3" json:"address_str,omitempty"`
+	Latitude                 float64                `protobuf:"fixed64,5,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude                float64                `protobuf:"fixed64,6,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Distance                 uint32                 `protobuf:"varint,7,opt,name=distance,proto3" json:"distance,omitempty"`
+	IncludeAddress           bool                   `protobuf:"varint,8,opt,name=include_address,json=includeAddress,proto3" json:"include_address,omitempty"`
+	Query                    string                 `protobuf:"bytes,9,opt,name=query,proto3" json:"query,omitempty"`
+	Fuzzy                    bool                   `protobuf:"varint,10,opt,name=fuzzy,proto3" json:"fuzzy,omitempty"`
+	MinSimilarity            float64                `protobuf:"fixed64,11,opt,name=min_similarity,json=minSimilarity,proto3" json:"min_similarity,omitempty"`
+	Facets                   []string               `protobuf:"bytes,12,rep,name=facets,proto3" json:"facets,omitempty"`
+	Limit                    uint32                 `protobuf:"varint,13,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset                   uint32                 `protobuf:"varint,14,opt,name=offset,proto3" json:"offset,omitempty"`
+	Within                   *WithinFilter          `protobuf:"bytes,15,opt,name=within,proto3,oneof" json:"within,omitempty"`
+	KNearest                 uint32                 `protobuf:"varint,16,opt,name=k_nearest,json=kNearest,proto3" json:"k_nearest,omitempty"`
+	RankBy                   string                 `protobuf:"bytes,17,opt,name=rank_by,json=rankBy,proto3" json:"rank_by,omitempty"`
+	Region                   string                 `protobuf:"bytes,18,opt,name=region,proto3" json:"region,omitempty"`
+	Capabilities             []string               `protobuf:"bytes,19,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	IncludeTemporarilyClosed bool                   `protobuf:"varint,20,opt,name=include_temporarily_closed,json=includeTemporarilyClosed,proto3" json:"include_temporarily_closed,omitempty"`
+	Locale                   string                 `protobuf:"bytes,21,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *SearchStoreRequest) Reset() {
+	*x = SearchStoreRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchStoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchStoreRequest) ProtoMessage() {}
+
+func (x *SearchStoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchStoreRequest.ProtoReflect.Descriptor instead.
+func (*SearchStoreRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SearchStoreRequest) GetOrg() string {
+	if x != nil {
+		return x.Org
+	}
+	return ""
+}
+
+func (x *SearchStoreRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SearchStoreRequest) GetAddressId() string {
+	if x != nil {
+		return x.AddressId
+	}
+	return ""
+}
+
+func (x *SearchStoreRequest) GetAddressStr() string {
+	if x != nil {
+		return x.AddressStr
+	}
+	return ""
+}
+
+func (x *SearchStoreRequest) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *SearchStoreRequest) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *SearchStoreRequest) GetDistance() uint32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *SearchStoreRequest) GetIncludeAddress() bool {
+	if x != nil {
+		return x.IncludeAddress
+	}
+	return false
+}
+
+func (x *SearchStoreRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchStoreRequest) GetFuzzy() bool {
+	if x != nil {
+		return x.Fuzzy
+	}
+	return false
+}
+
+func (x *SearchStoreRequest) GetMinSimilarity() float64 {
+	if x != nil {
+		return x.MinSimilarity
+	}
+	return 0
+}
+
+func (x *SearchStoreRequest) GetFacets() []string {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+func (x *SearchStoreRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchStoreRequest) GetOffset() uint32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *SearchStoreRequest) GetWithin() *WithinFilter {
+	if x != nil {
+		return x.Within
+	}
+	return nil
+}
+
+func (x *SearchStoreRequest) GetKNearest() uint32 {
+	if x != nil {
+		return x.KNearest
+	}
+	return 0
+}
+
+func (x *SearchStoreRequest) GetRankBy() string {
+	if x != nil {
+		return x.RankBy
+	}
+	return ""
+}
+
+func (x *SearchStoreRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *SearchStoreRequest) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *SearchStoreRequest) GetIncludeTemporarilyClosed() bool {
+	if x != nil {
+		return x.IncludeTemporarilyClosed
+	}
+	return false
+}
+
+func (x *SearchStoreRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type WithinFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bbox          *BoundingBox           `protobuf:"bytes,1,opt,name=bbox,proto3" json:"bbox,omitempty"`
+	Geojson       string                 `protobuf:"bytes,2,opt,name=geojson,proto3" json:"geojson,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WithinFilter) Reset() {
+	*x = WithinFilter{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WithinFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WithinFilter) ProtoMessage() {}
+
+func (x *WithinFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WithinFilter.ProtoReflect.Descriptor instead.
+func (*WithinFilter) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WithinFilter) GetBbox() *BoundingBox {
+	if x != nil {
+		return x.Bbox
+	}
+	return nil
+}
+
+func (x *WithinFilter) GetGeojson() string {
+	if x != nil {
+		return x.Geojson
+	}
+	return ""
+}
+
+type SearchStoreResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stores        []*StoreGeo            `protobuf:"bytes,1,rep,name=stores,proto3" json:"stores,omitempty"`
+	Geo           *Point                 `protobuf:"bytes,2,opt,name=geo,proto3,oneof" json:"geo,omitempty"`
+	Total         uint32                 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Facets        []*Facet               `protobuf:"bytes,4,rep,name=facets,proto3" json:"facets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchStoreResponse) Reset() {
+	*x = SearchStoreResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchStoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchStoreResponse) ProtoMessage() {}
+
+func (x *SearchStoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchStoreResponse.ProtoReflect.Descriptor instead.
+func (*SearchStoreResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SearchStoreResponse) GetStores() []*StoreGeo {
+	if x != nil {
+		return x.Stores
+	}
+	return nil
+}
+
+func (x *SearchStoreResponse) GetGeo() *Point {
+	if x != nil {
+		return x.Geo
+	}
+	return nil
+}
+
+func (x *SearchStoreResponse) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SearchStoreResponse) GetFacets() []*Facet {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+type Facet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Buckets       []*FacetBucket         `protobuf:"bytes,2,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Facet) Reset() {
+	*x = Facet{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Facet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Facet) ProtoMessage() {}
+
+func (x *Facet) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Facet.ProtoReflect.Descriptor instead.
+func (*Facet) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Facet) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *Facet) GetBuckets() []*FacetBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+type FacetBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Count         uint32                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FacetBucket) Reset() {
+	*x = FacetBucket{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FacetBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FacetBucket) ProtoMessage() {}
+
+func (x *FacetBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FacetBucket.ProtoReflect.Descriptor instead.
+func (*FacetBucket) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *FacetBucket) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *FacetBucket) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type StoreGeo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Store         *Store                 `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	Distance      *float32               `protobuf:"fixed32,2,opt,name=distance,proto3,oneof" json:"distance,omitempty"`
+	Score         *float64               `protobuf:"fixed64,3,opt,name=score,proto3,oneof" json:"score,omitempty"`
+	EtaSeconds    *uint32                `protobuf:"varint,4,opt,name=eta_seconds,json=etaSeconds,proto3,oneof" json:"eta_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreGeo) Reset() {
+	*x = StoreGeo{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreGeo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreGeo) ProtoMessage() {}
+
+func (x *StoreGeo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreGeo.ProtoReflect.Descriptor instead.
+func (*StoreGeo) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StoreGeo) GetStore() *Store {
+	if x != nil {
+		return x.Store
+	}
+	return nil
+}
+
+func (x *StoreGeo) GetDistance() float32 {
+	if x != nil && x.Distance != nil {
+		return *x.Distance
+	}
+	return 0
+}
+
+func (x *StoreGeo) GetScore() float64 {
+	if x != nil && x.Score != nil {
+		return *x.Score
+	}
+	return 0
+}
+
+func (x *StoreGeo) GetEtaSeconds() uint32 {
+	if x != nil && x.EtaSeconds != nil {
+		return *x.EtaSeconds
+	}
+	return 0
+}
+
+type Point struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Point) Reset() {
+	*x = Point{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Point) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Point) ProtoMessage() {}
+
+func (x *Point) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Point.ProtoReflect.Descriptor instead.
+func (*Point) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Point) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Point) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+type AddressChange struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	AddressId         string                 `protobuf:"bytes,1,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	PreviousAddressId string                 `protobuf:"bytes,2,opt,name=previous_address_id,json=previousAddressId,proto3" json:"previous_address_id,omitempty"`
+	ChangedAt         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AddressChange) Reset() {
+	*x = AddressChange{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddressChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddressChange) ProtoMessage() {}
+
+func (x *AddressChange) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddressChange.ProtoReflect.Descriptor instead.
+func (*AddressChange) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AddressChange) GetAddressId() string {
+	if x != nil {
+		return x.AddressId
+	}
+	return ""
+}
+
+func (x *AddressChange) GetPreviousAddressId() string {
+	if x != nil {
+		return x.PreviousAddressId
+	}
+	return ""
+}
+
+func (x *AddressChange) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+type GetStoreAddressHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStoreAddressHistoryRequest) Reset() {
+	*x = GetStoreAddressHistoryRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStoreAddressHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStoreAddressHistoryRequest) ProtoMessage() {}
+
+func (x *GetStoreAddressHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStoreAddressHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetStoreAddressHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetStoreAddressHistoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetStoreAddressHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*AddressChange       `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStoreAddressHistoryResponse) Reset() {
+	*x = GetStoreAddressHistoryResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStoreAddressHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStoreAddressHistoryResponse) ProtoMessage() {}
+
+func (x *GetStoreAddressHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStoreAddressHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetStoreAddressHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetStoreAddressHistoryResponse) GetChanges() []*AddressChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+type GetStoreStatsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Org             string                 `protobuf:"bytes,1,opt,name=org,proto3" json:"org,omitempty"`
+	From            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To              *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Interval        string                 `protobuf:"bytes,4,opt,name=interval,proto3" json:"interval,omitempty"`
+	RegionPrecision uint32                 `protobuf:"varint,5,opt,name=region_precision,json=regionPrecision,proto3" json:"region_precision,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetStoreStatsRequest) Reset() {
+	*x = GetStoreStatsRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStoreStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStoreStatsRequest) ProtoMessage() {}
+
+func (x *GetStoreStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStoreStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStoreStatsRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetStoreStatsRequest) GetOrg() string {
+	if x != nil {
+		return x.Org
+	}
+	return ""
+}
+
+func (x *GetStoreStatsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetStoreStatsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *GetStoreStatsRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetStoreStatsRequest) GetRegionPrecision() uint32 {
+	if x != nil {
+		return x.RegionPrecision
+	}
+	return 0
+}
+
+type GetStoreStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         uint32                 `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	Orgs          []*StatsCount          `protobuf:"bytes,2,rep,name=orgs,proto3" json:"orgs,omitempty"`
+	Series        []*StatsBucket         `protobuf:"bytes,3,rep,name=series,proto3" json:"series,omitempty"`
+	Regions       []*RegionCount         `protobuf:"bytes,4,rep,name=regions,proto3" json:"regions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStoreStatsResponse) Reset() {
+	*x = GetStoreStatsResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStoreStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStoreStatsResponse) ProtoMessage() {}
+
+func (x *GetStoreStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStoreStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStoreStatsResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetStoreStatsResponse) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetStoreStatsResponse) GetOrgs() []*StatsCount {
+	if x != nil {
+		return x.Orgs
+	}
+	return nil
+}
+
+func (x *GetStoreStatsResponse) GetSeries() []*StatsBucket {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+func (x *GetStoreStatsResponse) GetRegions() []*RegionCount {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+type StatsCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Count         uint32                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsCount) Reset() {
+	*x = StatsCount{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsCount) ProtoMessage() {}
+
+func (x *StatsCount) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsCount.ProtoReflect.Descriptor instead.
+func (*StatsCount) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *StatsCount) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *StatsCount) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type StatsBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Added         uint32                 `protobuf:"varint,2,opt,name=added,proto3" json:"added,omitempty"`
+	Deleted       uint32                 `protobuf:"varint,3,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsBucket) Reset() {
+	*x = StatsBucket{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsBucket) ProtoMessage() {}
+
+func (x *StatsBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsBucket.ProtoReflect.Descriptor instead.
+func (*StatsBucket) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *StatsBucket) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *StatsBucket) GetAdded() uint32 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+func (x *StatsBucket) GetDeleted() uint32 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+type BoundingBox struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MinLatitude   float64                `protobuf:"fixed64,1,opt,name=min_latitude,json=minLatitude,proto3" json:"min_latitude,omitempty"`
+	MinLongitude  float64                `protobuf:"fixed64,2,opt,name=min_longitude,json=minLongitude,proto3" json:"min_longitude,omitempty"`
+	MaxLatitude   float64                `protobuf:"fixed64,3,opt,name=max_latitude,json=maxLatitude,proto3" json:"max_latitude,omitempty"`
+	MaxLongitude  float64                `protobuf:"fixed64,4,opt,name=max_longitude,json=maxLongitude,proto3" json:"max_longitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BoundingBox) Reset() {
+	*x = BoundingBox{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BoundingBox) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoundingBox) ProtoMessage() {}
+
+func (x *BoundingBox) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoundingBox.ProtoReflect.Descriptor instead.
+func (*BoundingBox) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *BoundingBox) GetMinLatitude() float64 {
+	if x != nil {
+		return x.MinLatitude
+	}
+	return 0
+}
+
+func (x *BoundingBox) GetMinLongitude() float64 {
+	if x != nil {
+		return x.MinLongitude
+	}
+	return 0
+}
+
+func (x *BoundingBox) GetMaxLatitude() float64 {
+	if x != nil {
+		return x.MaxLatitude
+	}
+	return 0
+}
+
+func (x *BoundingBox) GetMaxLongitude() float64 {
+	if x != nil {
+		return x.MaxLongitude
+	}
+	return 0
+}
+
+type ClusterStoresRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Org           string                 `protobuf:"bytes,1,opt,name=org,proto3" json:"org,omitempty"`
+	Bbox          *BoundingBox           `protobuf:"bytes,2,opt,name=bbox,proto3" json:"bbox,omitempty"`
+	Zoom          uint32                 `protobuf:"varint,3,opt,name=zoom,proto3" json:"zoom,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClusterStoresRequest) Reset() {
+	*x = ClusterStoresRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterStoresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterStoresRequest) ProtoMessage() {}
+
+func (x *ClusterStoresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterStoresRequest.ProtoReflect.Descriptor instead.
+func (*ClusterStoresRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ClusterStoresRequest) GetOrg() string {
+	if x != nil {
+		return x.Org
+	}
+	return ""
+}
+
+func (x *ClusterStoresRequest) GetBbox() *BoundingBox {
+	if x != nil {
+		return x.Bbox
+	}
+	return nil
+}
+
+func (x *ClusterStoresRequest) GetZoom() uint32 {
+	if x != nil {
+		return x.Zoom
+	}
+	return 0
+}
+
+type ClusterStoresResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Clusters      []*StoreCluster        `protobuf:"bytes,1,rep,name=clusters,proto3" json:"clusters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClusterStoresResponse) Reset() {
+	*x = ClusterStoresResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterStoresResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterStoresResponse) ProtoMessage() {}
+
+func (x *ClusterStoresResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterStoresResponse.ProtoReflect.Descriptor instead.
+func (*ClusterStoresResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ClusterStoresResponse) GetClusters() []*StoreCluster {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
+
+type FindServingStoresRequest struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Org                      string                 `protobuf:"bytes,1,opt,name=org,proto3" json:"org,omitempty"`
+	AddressId                string                 `protobuf:"bytes,2,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	AddressStr               string                 `protobuf:"bytes,3,opt,name=address_str,json=addressStr,proto3" json:"address_str,omitempty"`
+	Latitude                 float64                `protobuf:"fixed64,4,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude                float64                `protobuf:"fixed64,5,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	IncludeAddress           bool                   `protobuf:"varint,6,opt,name=include_address,json=includeAddress,proto3" json:"include_address,omitempty"`
+	Limit                    uint32                 `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	RankBy                   string                 `protobuf:"bytes,8,opt,name=rank_by,json=rankBy,proto3" json:"rank_by,omitempty"`
+	Capabilities             []string               `protobuf:"bytes,9,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	IncludeTemporarilyClosed bool                   `protobuf:"varint,10,opt,name=include_temporarily_closed,json=includeTemporarilyClosed,proto3" json:"include_temporarily_closed,omitempty"`
+	Locale                   string                 `protobuf:"bytes,11,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *FindServingStoresRequest) Reset() {
+	*x = FindServingStoresRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindServingStoresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindServingStoresRequest) ProtoMessage() {}
+
+func (x *FindServingStoresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindServingStoresRequest.ProtoReflect.Descriptor instead.
+func (*FindServingStoresRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *FindServingStoresRequest) GetOrg() string {
+	if x != nil {
+		return x.Org
+	}
+	return ""
+}
+
+func (x *FindServingStoresRequest) GetAddressId() string {
+	if x != nil {
+		return x.AddressId
+	}
+	return ""
+}
+
+func (x *FindServingStoresRequest) GetAddressStr() string {
+	if x != nil {
+		return x.AddressStr
+	}
+	return ""
+}
+
+func (x *FindServingStoresRequest) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *FindServingStoresRequest) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *FindServingStoresRequest) GetIncludeAddress() bool {
+	if x != nil {
+		return x.IncludeAddress
+	}
+	return false
+}
+
+func (x *FindServingStoresRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *FindServingStoresRequest) GetRankBy() string {
+	if x != nil {
+		return x.RankBy
+	}
+	return ""
+}
+
+func (x *FindServingStoresRequest) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *FindServingStoresRequest) GetIncludeTemporarilyClosed() bool {
+	if x != nil {
+		return x.IncludeTemporarilyClosed
+	}
+	return false
+}
+
+func (x *FindServingStoresRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type FindServingStoresResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stores        []*StoreGeo            `protobuf:"bytes,1,rep,name=stores,proto3" json:"stores,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindServingStoresResponse) Reset() {
+	*x = FindServingStoresResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindServingStoresResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindServingStoresResponse) ProtoMessage() {}
+
+func (x *FindServingStoresResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindServingStoresResponse.ProtoReflect.Descriptor instead.
+func (*FindServingStoresResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *FindServingStoresResponse) GetStores() []*StoreGeo {
+	if x != nil {
+		return x.Stores
+	}
+	return nil
+}
+
+type ListChildStoresRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	IncludeAddress bool                   `protobuf:"varint,2,opt,name=include_address,json=includeAddress,proto3" json:"include_address,omitempty"`
+	Locale         string                 `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListChildStoresRequest) Reset() {
+	*x = ListChildStoresRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChildStoresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChildStoresRequest) ProtoMessage() {}
+
+func (x *ListChildStoresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChildStoresRequest.ProtoReflect.Descriptor instead.
+func (*ListChildStoresRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListChildStoresRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ListChildStoresRequest) GetIncludeAddress() bool {
+	if x != nil {
+		return x.IncludeAddress
+	}
+	return false
+}
+
+func (x *ListChildStoresRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type ListChildStoresResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stores        []*Store               `protobuf:"bytes,1,rep,name=stores,proto3" json:"stores,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChildStoresResponse) Reset() {
+	*x = ListChildStoresResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChildStoresResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChildStoresResponse) ProtoMessage() {}
+
+func (x *ListChildStoresResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChildStoresResponse.ProtoReflect.Descriptor instead.
+func (*ListChildStoresResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListChildStoresResponse) GetStores() []*Store {
+	if x != nil {
+		return x.Stores
+	}
+	return nil
+}
+
+type GetStoreAncestorsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStoreAncestorsRequest) Reset() {
+	*x = GetStoreAncestorsRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStoreAncestorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStoreAncestorsRequest) ProtoMessage() {}
+
+func (x *GetStoreAncestorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStoreAncestorsRequest.ProtoReflect.Descriptor instead.
+func (*GetStoreAncestorsRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *GetStoreAncestorsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetStoreAncestorsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stores        []*Store               `protobuf:"bytes,1,rep,name=stores,proto3" json:"stores,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStoreAncestorsResponse) Reset() {
+	*x = GetStoreAncestorsResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStoreAncestorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStoreAncestorsResponse) ProtoMessage() {}
+
+func (x *GetStoreAncestorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStoreAncestorsResponse.ProtoReflect.Descriptor instead.
+func (*GetStoreAncestorsResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetStoreAncestorsResponse) GetStores() []*Store {
+	if x != nil {
+		return x.Stores
+	}
+	return nil
+}
+
+type GetCapabilityCatalogRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCapabilityCatalogRequest) Reset() {
+	*x = GetCapabilityCatalogRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilityCatalogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilityCatalogRequest) ProtoMessage() {}
+
+func (x *GetCapabilityCatalogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilityCatalogRequest.ProtoReflect.Descriptor instead.
+func (*GetCapabilityCatalogRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{34}
+}
+
+type GetCapabilityCatalogResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       uint32                 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Capabilities  []*Capability          `protobuf:"bytes,2,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCapabilityCatalogResponse) Reset() {
+	*x = GetCapabilityCatalogResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilityCatalogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilityCatalogResponse) ProtoMessage() {}
+
+func (x *GetCapabilityCatalogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilityCatalogResponse.ProtoReflect.Descriptor instead.
+func (*GetCapabilityCatalogResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetCapabilityCatalogResponse) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *GetCapabilityCatalogResponse) GetCapabilities() []*Capability {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+type Capability struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Deprecated    bool                   `protobuf:"varint,4,opt,name=deprecated,proto3" json:"deprecated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Capability) Reset() {
+	*x = Capability{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Capability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Capability) ProtoMessage() {}
+
+func (x *Capability) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Capability.ProtoReflect.Descriptor instead.
+func (*Capability) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *Capability) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Capability) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Capability) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Capability) GetDeprecated() bool {
+	if x != nil {
+		return x.Deprecated
+	}
+	return false
+}
+
+type StoreClosure struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=until,proto3,oneof" json:"until,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreClosure) Reset() {
+	*x = StoreClosure{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreClosure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreClosure) ProtoMessage() {}
+
+func (x *StoreClosure) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreClosure.ProtoReflect.Descriptor instead.
+func (*StoreClosure) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *StoreClosure) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StoreClosure) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *StoreClosure) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *StoreClosure) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ScheduleClosureRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StoreId       string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=until,proto3,oneof" json:"until,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleClosureRequest) Reset() {
+	*x = ScheduleClosureRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleClosureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleClosureRequest) ProtoMessage() {}
+
+func (x *ScheduleClosureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleClosureRequest.ProtoReflect.Descriptor instead.
+func (*ScheduleClosureRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ScheduleClosureRequest) GetStoreId() string {
+	if x != nil {
+		return x.StoreId
+	}
+	return ""
+}
+
+func (x *ScheduleClosureRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ScheduleClosureRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *ScheduleClosureRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ScheduleClosureResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Closure       *StoreClosure          `protobuf:"bytes,1,opt,name=closure,proto3" json:"closure,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleClosureResponse) Reset() {
+	*x = ScheduleClosureResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleClosureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleClosureResponse) ProtoMessage() {}
+
+func (x *ScheduleClosureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleClosureResponse.ProtoReflect.Descriptor instead.
+func (*ScheduleClosureResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ScheduleClosureResponse) GetClosure() *StoreClosure {
+	if x != nil {
+		return x.Closure
+	}
+	return nil
+}
+
+type CancelClosureRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StoreId       string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	ClosureId     string                 `protobuf:"bytes,2,opt,name=closure_id,json=closureId,proto3" json:"closure_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelClosureRequest) Reset() {
+	*x = CancelClosureRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelClosureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelClosureRequest) ProtoMessage() {}
+
+func (x *CancelClosureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelClosureRequest.ProtoReflect.Descriptor instead.
+func (*CancelClosureRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *CancelClosureRequest) GetStoreId() string {
+	if x != nil {
+		return x.StoreId
+	}
+	return ""
+}
+
+func (x *CancelClosureRequest) GetClosureId() string {
+	if x != nil {
+		return x.ClosureId
+	}
+	return ""
+}
+
+type CancelClosureResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelClosureResponse) Reset() {
+	*x = CancelClosureResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelClosureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelClosureResponse) ProtoMessage() {}
+
+func (x *CancelClosureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelClosureResponse.ProtoReflect.Descriptor instead.
+func (*CancelClosureResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *CancelClosureResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+type StoreAttachment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Uri           string                 `protobuf:"bytes,3,opt,name=uri,proto3" json:"uri,omitempty"`
+	Checksum      string                 `protobuf:"bytes,4,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	Width         uint32                 `protobuf:"varint,5,opt,name=width,proto3" json:"width,omitempty"`
+	Height        uint32                 `protobuf:"varint,6,opt,name=height,proto3" json:"height,omitempty"`
+	ContentType   string                 `protobuf:"bytes,7,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreAttachment) Reset() {
+	*x = StoreAttachment{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreAttachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreAttachment) ProtoMessage() {}
+
+func (x *StoreAttachment) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreAttachment.ProtoReflect.Descriptor instead.
+func (*StoreAttachment) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *StoreAttachment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StoreAttachment) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *StoreAttachment) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+func (x *StoreAttachment) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *StoreAttachment) GetWidth() uint32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *StoreAttachment) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *StoreAttachment) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *StoreAttachment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type AddAttachmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StoreId       string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Uri           string                 `protobuf:"bytes,3,opt,name=uri,proto3" json:"uri,omitempty"`
+	Checksum      string                 `protobuf:"bytes,4,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	Width         uint32                 `protobuf:"varint,5,opt,name=width,proto3" json:"width,omitempty"`
+	Height        uint32                 `protobuf:"varint,6,opt,name=height,proto3" json:"height,omitempty"`
+	ContentType   string                 `protobuf:"bytes,7,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddAttachmentRequest) Reset() {
+	*x = AddAttachmentRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddAttachmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddAttachmentRequest) ProtoMessage() {}
+
+func (x *AddAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*AddAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *AddAttachmentRequest) GetStoreId() string {
+	if x != nil {
+		return x.StoreId
+	}
+	return ""
+}
+
+func (x *AddAttachmentRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *AddAttachmentRequest) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+func (x *AddAttachmentRequest) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *AddAttachmentRequest) GetWidth() uint32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *AddAttachmentRequest) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *AddAttachmentRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type AddAttachmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attachment    *StoreAttachment       `protobuf:"bytes,1,opt,name=attachment,proto3" json:"attachment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddAttachmentResponse) Reset() {
+	*x = AddAttachmentResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddAttachmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddAttachmentResponse) ProtoMessage() {}
+
+func (x *AddAttachmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddAttachmentResponse.ProtoReflect.Descriptor instead.
+func (*AddAttachmentResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *AddAttachmentResponse) GetAttachment() *StoreAttachment {
+	if x != nil {
+		return x.Attachment
+	}
+	return nil
+}
+
+type RemoveAttachmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StoreId       string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	AttachmentId  string                 `protobuf:"bytes,2,opt,name=attachment_id,json=attachmentId,proto3" json:"attachment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveAttachmentRequest) Reset() {
+	*x = RemoveAttachmentRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveAttachmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveAttachmentRequest) ProtoMessage() {}
+
+func (x *RemoveAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*RemoveAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *RemoveAttachmentRequest) GetStoreId() string {
+	if x != nil {
+		return x.StoreId
+	}
+	return ""
+}
+
+func (x *RemoveAttachmentRequest) GetAttachmentId() string {
+	if x != nil {
+		return x.AttachmentId
+	}
+	return ""
+}
+
+type RemoveAttachmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveAttachmentResponse) Reset() {
+	*x = RemoveAttachmentResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveAttachmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveAttachmentResponse) ProtoMessage() {}
+
+func (x *RemoveAttachmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveAttachmentResponse.ProtoReflect.Descriptor instead.
+func (*RemoveAttachmentResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RemoveAttachmentResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+type ReorderAttachmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StoreId       string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	AttachmentIds []string               `protobuf:"bytes,2,rep,name=attachment_ids,json=attachmentIds,proto3" json:"attachment_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReorderAttachmentsRequest) Reset() {
+	*x = ReorderAttachmentsRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReorderAttachmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReorderAttachmentsRequest) ProtoMessage() {}
+
+func (x *ReorderAttachmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReorderAttachmentsRequest.ProtoReflect.Descriptor instead.
+func (*ReorderAttachmentsRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ReorderAttachmentsRequest) GetStoreId() string {
+	if x != nil {
+		return x.StoreId
+	}
+	return ""
+}
+
+func (x *ReorderAttachmentsRequest) GetAttachmentIds() []string {
+	if x != nil {
+		return x.AttachmentIds
+	}
+	return nil
+}
+
+type ReorderAttachmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attachments   []*StoreAttachment     `protobuf:"bytes,1,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReorderAttachmentsResponse) Reset() {
+	*x = ReorderAttachmentsResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReorderAttachmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReorderAttachmentsResponse) ProtoMessage() {}
+
+func (x *ReorderAttachmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReorderAttachmentsResponse.ProtoReflect.Descriptor instead.
+func (*ReorderAttachmentsResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ReorderAttachmentsResponse) GetAttachments() []*StoreAttachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+type UploadAttachmentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*UploadAttachmentRequest_Info
+	//	*UploadAttachmentRequest_Chunk
+	Data          isUploadAttachmentRequest_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadAttachmentRequest) Reset() {
+	*x = UploadAttachmentRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadAttachmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadAttachmentRequest) ProtoMessage() {}
+
+func (x *UploadAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*UploadAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *UploadAttachmentRequest) GetData() isUploadAttachmentRequest_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *UploadAttachmentRequest) GetInfo() *UploadAttachmentInfo {
+	if x != nil {
+		if x, ok := x.Data.(*UploadAttachmentRequest_Info); ok {
+			return x.Info
+		}
+	}
+	return nil
+}
+
+func (x *UploadAttachmentRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Data.(*UploadAttachmentRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isUploadAttachmentRequest_Data interface {
+	isUploadAttachmentRequest_Data()
+}
+
+type UploadAttachmentRequest_Info struct {
+	Info *UploadAttachmentInfo `protobuf:"bytes,1,opt,name=info,proto3,oneof"`
+}
+
+type UploadAttachmentRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*UploadAttachmentRequest_Info) isUploadAttachmentRequest_Data() {}
+
+func (*UploadAttachmentRequest_Chunk) isUploadAttachmentRequest_Data() {}
+
+type UploadAttachmentInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StoreId       string                 `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadAttachmentInfo) Reset() {
+	*x = UploadAttachmentInfo{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadAttachmentInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadAttachmentInfo) ProtoMessage() {}
+
+func (x *UploadAttachmentInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadAttachmentInfo.ProtoReflect.Descriptor instead.
+func (*UploadAttachmentInfo) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *UploadAttachmentInfo) GetStoreId() string {
+	if x != nil {
+		return x.StoreId
+	}
+	return ""
+}
+
+func (x *UploadAttachmentInfo) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+type UploadAttachmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attachment    *StoreAttachment       `protobuf:"bytes,1,opt,name=attachment,proto3" json:"attachment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadAttachmentResponse) Reset() {
+	*x = UploadAttachmentResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadAttachmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadAttachmentResponse) ProtoMessage() {}
+
+func (x *UploadAttachmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadAttachmentResponse.ProtoReflect.Descriptor instead.
+func (*UploadAttachmentResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *UploadAttachmentResponse) GetAttachment() *StoreAttachment {
+	if x != nil {
+		return x.Attachment
+	}
+	return nil
+}
+
+type StoreFieldViolation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreFieldViolation) Reset() {
+	*x = StoreFieldViolation{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreFieldViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreFieldViolation) ProtoMessage() {}
+
+func (x *StoreFieldViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreFieldViolation.ProtoReflect.Descriptor instead.
+func (*StoreFieldViolation) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *StoreFieldViolation) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *StoreFieldViolation) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type StoreValidationError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Violations    []*StoreFieldViolation `protobuf:"bytes,1,rep,name=violations,proto3" json:"violations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreValidationError) Reset() {
+	*x = StoreValidationError{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreValidationError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreValidationError) ProtoMessage() {}
+
+func (x *StoreValidationError) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreValidationError.ProtoReflect.Descriptor instead.
+func (*StoreValidationError) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *StoreValidationError) GetViolations() []*StoreFieldViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+type StoreCluster struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Region        string                 `protobuf:"bytes,1,opt,name=region,proto3" json:"region,omitempty"`
+	Centroid      *Point                 `protobuf:"bytes,2,opt,name=centroid,proto3" json:"centroid,omitempty"`
+	Count         uint32                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	SampleIds     []string               `protobuf:"bytes,4,rep,name=sample_ids,json=sampleIds,proto3" json:"sample_ids,omitempty"`
+	Store         *Store                 `protobuf:"bytes,5,opt,name=store,proto3,oneof" json:"store,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreCluster) Reset() {
+	*x = StoreCluster{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreCluster) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreCluster) ProtoMessage() {}
+
+func (x *StoreCluster) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreCluster.ProtoReflect.Descriptor instead.
+func (*StoreCluster) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *StoreCluster) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *StoreCluster) GetCentroid() *Point {
+	if x != nil {
+		return x.Centroid
+	}
+	return nil
+}
+
+func (x *StoreCluster) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *StoreCluster) GetSampleIds() []string {
+	if x != nil {
+		return x.SampleIds
+	}
+	return nil
+}
+
+func (x *StoreCluster) GetStore() *Store {
+	if x != nil {
+		return x.Store
+	}
+	return nil
+}
+
+type RegionCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Region        string                 `protobuf:"bytes,1,opt,name=region,proto3" json:"region,omitempty"`
+	Count         uint32                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Center        *Point                 `protobuf:"bytes,3,opt,name=center,proto3" json:"center,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegionCount) Reset() {
+	*x = RegionCount{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegionCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegionCount) ProtoMessage() {}
+
+func (x *RegionCount) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegionCount.ProtoReflect.Descriptor instead.
+func (*RegionCount) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *RegionCount) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *RegionCount) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *RegionCount) GetCenter() *Point {
+	if x != nil {
+		return x.Center
+	}
+	return nil
+}
+
+type Webhook struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	EventTypes    []string               `protobuf:"bytes,3,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	Org           string                 `protobuf:"bytes,4,opt,name=org,proto3" json:"org,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *Webhook) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *Webhook) GetOrg() string {
+	if x != nil {
+		return x.Org
+	}
+	return ""
+}
+
+func (x *Webhook) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type RegisterWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	EventTypes    []string               `protobuf:"bytes,2,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	Org           string                 `protobuf:"bytes,3,opt,name=org,proto3" json:"org,omitempty"`
+	Secret        string                 `protobuf:"bytes,4,opt,name=secret,proto3" json:"secret,omitempty"`
+	RequestedBy   string                 `protobuf:"bytes,5,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterWebhookRequest) Reset() {
+	*x = RegisterWebhookRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterWebhookRequest) ProtoMessage() {}
+
+func (x *RegisterWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -333,69 +3788,69 @@ func (x *UpdateStoreRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateStoreRequest.ProtoReflect.Descriptor instead.
-func (*UpdateStoreRequest) Descriptor() ([]byte, []int) {
-	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use RegisterWebhookRequest.ProtoReflect.Descriptor instead.
+func (*RegisterWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{57}
 }
 
-func (x *UpdateStoreRequest) GetId() string {
+func (x *RegisterWebhookRequest) GetUrl() string {
 	if x != nil {
-		return x.Id
+		return x.Url
 	}
 	return ""
 }
 
-func (x *UpdateStoreRequest) GetName() string {
+func (x *RegisterWebhookRequest) GetEventTypes() []string {
 	if x != nil {
-		return x.Name
+		return x.EventTypes
 	}
-	return ""
+	return nil
 }
 
-func (x *UpdateStoreRequest) GetOrg() string {
+func (x *RegisterWebhookRequest) GetOrg() string {
 	if x != nil {
 		return x.Org
 	}
 	return ""
 }
 
-func (x *UpdateStoreRequest) GetAddressId() string {
+func (x *RegisterWebhookRequest) GetSecret() string {
 	if x != nil {
-		return x.AddressId
+		return x.Secret
 	}
 	return ""
 }
 
-func (x *UpdateStoreRequest) GetRequestedBy() string {
+func (x *RegisterWebhookRequest) GetRequestedBy() string {
 	if x != nil {
 		return x.RequestedBy
 	}
 	return ""
 }
 
-type UpdateStoreResponse struct {
+type RegisterWebhookResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
-	Store         *Store                 `protobuf:"bytes,2,opt,name=store,proto3,oneof" json:"store,omitempty"`
+	Id            *string                `protobuf:"bytes,2,opt,name=id,proto3,oneof" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateStoreResponse) Reset() {
-	*x = UpdateStoreResponse{}
-	mi := &file_api_stores_v1_stores_proto_msgTypes[6]
+func (x *RegisterWebhookResponse) Reset() {
+	*x = RegisterWebhookResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateStoreResponse) String() string {
+func (x *RegisterWebhookResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateStoreResponse) ProtoMessage() {}
+func (*RegisterWebhookResponse) ProtoMessage() {}
 
-func (x *UpdateStoreResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_stores_v1_stores_proto_msgTypes[6]
+func (x *RegisterWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -406,26 +3861,26 @@ func (x *UpdateStoreResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateStoreResponse.ProtoReflect.Descriptor instead.
-func (*UpdateStoreResponse) Descriptor() ([]byte, []int) {
-	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use RegisterWebhookResponse.ProtoReflect.Descriptor instead.
+func (*RegisterWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{58}
 }
 
-func (x *UpdateStoreResponse) GetOk() bool {
+func (x *RegisterWebhookResponse) GetOk() bool {
 	if x != nil {
 		return x.Ok
 	}
 	return false
 }
 
-func (x *UpdateStoreResponse) GetStore() *Store {
-	if x != nil {
-		return x.Store
+func (x *RegisterWebhookResponse) GetId() string {
+	if x != nil && x.Id != nil {
+		return *x.Id
 	}
-	return nil
+	return ""
 }
 
-type DeleteStoreRequest struct {
+type DeleteWebhookRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	RequestedBy   string                 `protobuf:"bytes,2,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"`
@@ -433,21 +3888,21 @@ type DeleteStoreRequest struct {
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteStoreRequest) Reset() {
-	*x = DeleteStoreRequest{}
-	mi := &file_api_stores_v1_stores_proto_msgTypes[7]
+func (x *DeleteWebhookRequest) Reset() {
+	*x = DeleteWebhookRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteStoreRequest) String() string {
+func (x *DeleteWebhookRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteStoreRequest) ProtoMessage() {}
+func (*DeleteWebhookRequest) ProtoMessage() {}
 
-func (x *DeleteStoreRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_stores_v1_stores_proto_msgTypes[7]
+func (x *DeleteWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -458,47 +3913,47 @@ func (x *DeleteStoreRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteStoreRequest.ProtoReflect.Descriptor instead.
-func (*DeleteStoreRequest) Descriptor() ([]byte, []int) {
-	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use DeleteWebhookRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{59}
 }
 
-func (x *DeleteStoreRequest) GetId() string {
+func (x *DeleteWebhookRequest) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *DeleteStoreRequest) GetRequestedBy() string {
+func (x *DeleteWebhookRequest) GetRequestedBy() string {
 	if x != nil {
 		return x.RequestedBy
 	}
 	return ""
 }
 
-type DeleteStoreResponse struct {
+type DeleteWebhookResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteStoreResponse) Reset() {
-	*x = DeleteStoreResponse{}
-	mi := &file_api_stores_v1_stores_proto_msgTypes[8]
+func (x *DeleteWebhookResponse) Reset() {
+	*x = DeleteWebhookResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteStoreResponse) String() string {
+func (x *DeleteWebhookResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteStoreResponse) ProtoMessage() {}
+func (*DeleteWebhookResponse) ProtoMessage() {}
 
-func (x *DeleteStoreResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_stores_v1_stores_proto_msgTypes[8]
+func (x *DeleteWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -509,46 +3964,40 @@ func (x *DeleteStoreResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteStoreResponse.ProtoReflect.Descriptor instead.
-func (*DeleteStoreResponse) Descriptor() ([]byte, []int) {
-	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use DeleteWebhookResponse.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *DeleteStoreResponse) GetOk() bool {
+func (x *DeleteWebhookResponse) GetOk() bool {
 	if x != nil {
 		return x.Ok
 	}
 	return false
 }
 
-type SearchStoreRequest struct {
+type ListWebhooksRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Org           string                 `protobuf:"bytes,1,opt,name=org,proto3" json:"org,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	AddressId     string                 `protobuf:"bytes,3,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
-	AddressStr    string                 `protobuf:"bytes,4,opt,name=address_str,json=addressStr,proto3" json:"address_str,omitempty"`
-	Latitude      float64                `protobuf:"fixed64,5,opt,name=latitude,proto3" json:"latitude,omitempty"`
-	Longitude     float64                `protobuf:"fixed64,6,opt,name=longitude,proto3" json:"longitude,omitempty"`
-	Distance      uint32                 `protobuf:"varint,7,opt,name=distance,proto3" json:"distance,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SearchStoreRequest) Reset() {
-	*x = SearchStoreRequest{}
-	mi := &file_api_stores_v1_stores_proto_msgTypes[9]
+func (x *ListWebhooksRequest) Reset() {
+	*x = ListWebhooksRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SearchStoreRequest) String() string {
+func (x *ListWebhooksRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SearchStoreRequest) ProtoMessage() {}
+func (*ListWebhooksRequest) ProtoMessage() {}
 
-func (x *SearchStoreRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_stores_v1_stores_proto_msgTypes[9]
+func (x *ListWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -559,83 +4008,94 @@ func (x *SearchStoreRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SearchStoreRequest.ProtoReflect.Descriptor instead.
-func (*SearchStoreRequest) Descriptor() ([]byte, []int) {
-	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use ListWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{61}
 }
 
-func (x *SearchStoreRequest) GetOrg() string {
+func (x *ListWebhooksRequest) GetOrg() string {
 	if x != nil {
 		return x.Org
 	}
 	return ""
 }
 
-func (x *SearchStoreRequest) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
+type ListWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SearchStoreRequest) GetAddressId() string {
-	if x != nil {
-		return x.AddressId
-	}
-	return ""
+func (x *ListWebhooksResponse) Reset() {
+	*x = ListWebhooksResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *SearchStoreRequest) GetAddressStr() string {
-	if x != nil {
-		return x.AddressStr
-	}
-	return ""
+func (x *ListWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *SearchStoreRequest) GetLatitude() float64 {
+func (*ListWebhooksResponse) ProtoMessage() {}
+
+func (x *ListWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[62]
 	if x != nil {
-		return x.Latitude
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *SearchStoreRequest) GetLongitude() float64 {
-	if x != nil {
-		return x.Longitude
-	}
-	return 0
+// Deprecated: Use ListWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *SearchStoreRequest) GetDistance() uint32 {
+func (x *ListWebhooksResponse) GetWebhooks() []*Webhook {
 	if x != nil {
-		return x.Distance
+		return x.Webhooks
 	}
-	return 0
+	return nil
 }
 
-type SearchStoreResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Stores        []*StoreGeo            `protobuf:"bytes,1,rep,name=stores,proto3" json:"stores,omitempty"`
-	Geo           *Point                 `protobuf:"bytes,2,opt,name=geo,proto3,oneof" json:"geo,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type WebhookDelivery struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	WebhookId      string                 `protobuf:"bytes,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	EventId        string                 `protobuf:"bytes,3,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	EventType      string                 `protobuf:"bytes,4,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Status         string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Attempts       uint32                 `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	LastStatusCode *int32                 `protobuf:"varint,7,opt,name=last_status_code,json=lastStatusCode,proto3,oneof" json:"last_status_code,omitempty"`
+	LastError      string                 `protobuf:"bytes,8,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	NextAttemptAt  *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	DeliveredAt    *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=delivered_at,json=deliveredAt,proto3,oneof" json:"delivered_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *SearchStoreResponse) Reset() {
-	*x = SearchStoreResponse{}
-	mi := &file_api_stores_v1_stores_proto_msgTypes[10]
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SearchStoreResponse) String() string {
+func (x *WebhookDelivery) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SearchStoreResponse) ProtoMessage() {}
+func (*WebhookDelivery) ProtoMessage() {}
 
-func (x *SearchStoreResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_stores_v1_stores_proto_msgTypes[10]
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -646,48 +4106,112 @@ func (x *SearchStoreResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SearchStoreResponse.ProtoReflect.Descriptor instead.
-func (*SearchStoreResponse) Descriptor() ([]byte, []int) {
-	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{63}
 }
 
-func (x *SearchStoreResponse) GetStores() []*StoreGeo {
+func (x *WebhookDelivery) GetId() string {
 	if x != nil {
-		return x.Stores
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetAttempts() uint32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetLastStatusCode() int32 {
+	if x != nil && x.LastStatusCode != nil {
+		return *x.LastStatusCode
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
 	}
 	return nil
 }
 
-func (x *SearchStoreResponse) GetGeo() *Point {
+func (x *WebhookDelivery) GetNextAttemptAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Geo
+		return x.NextAttemptAt
 	}
 	return nil
 }
 
-type StoreGeo struct {
+func (x *WebhookDelivery) GetDeliveredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliveredAt
+	}
+	return nil
+}
+
+type GetWebhookDeliveriesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Store         *Store                 `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
-	Distance      *float32               `protobuf:"fixed32,2,opt,name=distance,proto3,oneof" json:"distance,omitempty"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Limit         uint32                 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StoreGeo) Reset() {
-	*x = StoreGeo{}
-	mi := &file_api_stores_v1_stores_proto_msgTypes[11]
+func (x *GetWebhookDeliveriesRequest) Reset() {
+	*x = GetWebhookDeliveriesRequest{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StoreGeo) String() string {
+func (x *GetWebhookDeliveriesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StoreGeo) ProtoMessage() {}
+func (*GetWebhookDeliveriesRequest) ProtoMessage() {}
 
-func (x *StoreGeo) ProtoReflect() protoreflect.Message {
-	mi := &file_api_stores_v1_stores_proto_msgTypes[11]
+func (x *GetWebhookDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -698,48 +4222,54 @@ func (x *StoreGeo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StoreGeo.ProtoReflect.Descriptor instead.
-func (*StoreGeo) Descriptor() ([]byte, []int) {
-	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use GetWebhookDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*GetWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{64}
 }
 
-func (x *StoreGeo) GetStore() *Store {
+func (x *GetWebhookDeliveriesRequest) GetWebhookId() string {
 	if x != nil {
-		return x.Store
+		return x.WebhookId
 	}
-	return nil
+	return ""
 }
 
-func (x *StoreGeo) GetDistance() float32 {
-	if x != nil && x.Distance != nil {
-		return *x.Distance
+func (x *GetWebhookDeliveriesRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetWebhookDeliveriesRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
 	}
 	return 0
 }
 
-type Point struct {
+type GetWebhookDeliveriesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
-	Longitude     float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Point) Reset() {
-	*x = Point{}
-	mi := &file_api_stores_v1_stores_proto_msgTypes[12]
+func (x *GetWebhookDeliveriesResponse) Reset() {
+	*x = GetWebhookDeliveriesResponse{}
+	mi := &file_api_stores_v1_stores_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Point) String() string {
+func (x *GetWebhookDeliveriesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Point) ProtoMessage() {}
+func (*GetWebhookDeliveriesResponse) ProtoMessage() {}
 
-func (x *Point) ProtoReflect() protoreflect.Message {
-	mi := &file_api_stores_v1_stores_proto_msgTypes[12]
+func (x *GetWebhookDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_stores_v1_stores_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -750,58 +4280,100 @@ func (x *Point) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Point.ProtoReflect.Descriptor instead.
-func (*Point) Descriptor() ([]byte, []int) {
-	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{12}
-}
-
-func (x *Point) GetLatitude() float64 {
-	if x != nil {
-		return x.Latitude
-	}
-	return 0
+// Deprecated: Use GetWebhookDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*GetWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_api_stores_v1_stores_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *Point) GetLongitude() float64 {
+func (x *GetWebhookDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
 	if x != nil {
-		return x.Longitude
+		return x.Deliveries
 	}
-	return 0
+	return nil
 }
 
 var File_api_stores_v1_stores_proto protoreflect.FileDescriptor
 
 const file_api_stores_v1_stores_proto_rawDesc = "" +
 	"\n" +
-	"\x1aapi/stores/v1/stores.proto\x12\tstores.v1\"y\n" +
+	"\x1aapi/stores/v1/stores.proto\x12\tstores.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9c\x03\n" +
 	"\x0fAddStoreRequest\x12\x10\n" +
 	"\x03org\x18\x01 \x01(\tR\x03org\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
 	"\n" +
 	"address_id\x18\x03 \x01(\tR\taddressId\x12!\n" +
-	"\frequested_by\x18\x04 \x01(\tR\vrequestedBy\">\n" +
+	"\frequested_by\x18\x04 \x01(\tR\vrequestedBy\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12!\n" +
+	"\fservice_area\x18\b \x01(\tR\vserviceArea\x12\x1b\n" +
+	"\tparent_id\x18\t \x01(\tR\bparentId\x12\x16\n" +
+	"\x06region\x18\n" +
+	" \x01(\tR\x06region\x12\"\n" +
+	"\fcapabilities\x18\v \x03(\tR\fcapabilities\x12\x16\n" +
+	"\x06locale\x18\f \x01(\tR\x06locale\x12?\n" +
+	"\ftranslations\x18\r \x03(\v2\x1b.stores.v1.StoreTranslationR\ftranslations\">\n" +
 	"\x10AddStoreResponse\x12\x0e\n" +
 	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x13\n" +
 	"\x02id\x18\x02 \x01(\tH\x00R\x02id\x88\x01\x01B\x05\n" +
-	"\x03_id\"!\n" +
+	"\x03_id\"b\n" +
 	"\x0fGetStoreRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"I\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0finclude_address\x18\x02 \x01(\bR\x0eincludeAddress\x12\x16\n" +
+	"\x06locale\x18\x03 \x01(\tR\x06locale\"I\n" +
 	"\x10GetStoreResponse\x12+\n" +
 	"\x05store\x18\x01 \x01(\v2\x10.stores.v1.StoreH\x00R\x05store\x88\x01\x01B\b\n" +
-	"\x06_store\"\\\n" +
+	"\x06_store\"\xec\x04\n" +
 	"\x05Store\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x10\n" +
 	"\x03org\x18\x03 \x01(\tR\x03org\x12\x1d\n" +
 	"\n" +
-	"address_id\x18\x04 \x01(\tR\taddressId\"\x8c\x01\n" +
+	"address_id\x18\x04 \x01(\tR\taddressId\x121\n" +
+	"\aaddress\x18\x05 \x01(\v2\x12.stores.v1.AddressH\x00R\aaddress\x88\x01\x01\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12!\n" +
+	"\fservice_area\x18\n" +
+	" \x01(\tR\vserviceArea\x12\x1b\n" +
+	"\tparent_id\x18\v \x01(\tR\bparentId\x12\x16\n" +
+	"\x06region\x18\f \x01(\tR\x06region\x12\"\n" +
+	"\fcapabilities\x18\r \x03(\tR\fcapabilities\x123\n" +
+	"\bclosures\x18\x0e \x03(\v2\x17.stores.v1.StoreClosureR\bclosures\x12\x16\n" +
+	"\x06locale\x18\x0f \x01(\tR\x06locale\x12?\n" +
+	"\ftranslations\x18\x10 \x03(\v2\x1b.stores.v1.StoreTranslationR\ftranslations\x12<\n" +
+	"\vattachments\x18\x11 \x03(\v2\x1a.stores.v1.StoreAttachmentR\vattachmentsB\n" +
+	"\n" +
+	"\b_address\"`\n" +
+	"\x10StoreTranslation\x12\x16\n" +
+	"\x06locale\x18\x01 \x01(\tR\x06locale\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"p\n" +
+	"\aAddress\x12+\n" +
+	"\x11formatted_address\x18\x01 \x01(\tR\x10formattedAddress\x12\x1a\n" +
+	"\blatitude\x18\x02 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x03 \x01(\x01R\tlongitude\"\x83\x04\n" +
 	"\x12UpdateStoreRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x10\n" +
 	"\x03org\x18\x03 \x01(\tR\x03org\x12\x1d\n" +
 	"\n" +
 	"address_id\x18\x04 \x01(\tR\taddressId\x12!\n" +
-	"\frequested_by\x18\x05 \x01(\tR\vrequestedBy\"\\\n" +
+	"\frequested_by\x18\x05 \x01(\tR\vrequestedBy\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12!\n" +
+	"\fservice_area\x18\t \x01(\tR\vserviceArea\x12\x1b\n" +
+	"\tparent_id\x18\n" +
+	" \x01(\tR\bparentId\x12\x16\n" +
+	"\x06region\x18\v \x01(\tR\x06region\x12#\n" +
+	"\rdetach_parent\x18\f \x01(\bR\fdetachParent\x12\"\n" +
+	"\fcapabilities\x18\r \x03(\tR\fcapabilities\x12\x16\n" +
+	"\x06locale\x18\x0e \x01(\tR\x06locale\x12?\n" +
+	"\ftranslations\x18\x0f \x03(\v2\x1b.stores.v1.StoreTranslationR\ftranslations\x12-\n" +
+	"\x12clear_translations\x18\x10 \x01(\bR\x11clearTranslations\"\\\n" +
 	"\x13UpdateStoreResponse\x12\x0e\n" +
 	"\x02ok\x18\x01 \x01(\bR\x02ok\x12+\n" +
 	"\x05store\x18\x02 \x01(\v2\x10.stores.v1.StoreH\x00R\x05store\x88\x01\x01B\b\n" +
@@ -810,7 +4382,7 @@ const file_api_stores_v1_stores_proto_rawDesc = "" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
 	"\frequested_by\x18\x02 \x01(\tR\vrequestedBy\"%\n" +
 	"\x13DeleteStoreResponse\x12\x0e\n" +
-	"\x02ok\x18\x01 \x01(\bR\x02ok\"\xd0\x01\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\"\x9b\x05\n" +
 	"\x12SearchStoreRequest\x12\x10\n" +
 	"\x03org\x18\x01 \x01(\tR\x03org\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
@@ -820,24 +4392,290 @@ const file_api_stores_v1_stores_proto_rawDesc = "" +
 	"addressStr\x12\x1a\n" +
 	"\blatitude\x18\x05 \x01(\x01R\blatitude\x12\x1c\n" +
 	"\tlongitude\x18\x06 \x01(\x01R\tlongitude\x12\x1a\n" +
-	"\bdistance\x18\a \x01(\rR\bdistance\"s\n" +
+	"\bdistance\x18\a \x01(\rR\bdistance\x12'\n" +
+	"\x0finclude_address\x18\b \x01(\bR\x0eincludeAddress\x12\x14\n" +
+	"\x05query\x18\t \x01(\tR\x05query\x12\x14\n" +
+	"\x05fuzzy\x18\n" +
+	" \x01(\bR\x05fuzzy\x12%\n" +
+	"\x0emin_similarity\x18\v \x01(\x01R\rminSimilarity\x12\x16\n" +
+	"\x06facets\x18\f \x03(\tR\x06facets\x12\x14\n" +
+	"\x05limit\x18\r \x01(\rR\x05limit\x12\x16\n" +
+	"\x06offset\x18\x0e \x01(\rR\x06offset\x124\n" +
+	"\x06within\x18\x0f \x01(\v2\x17.stores.v1.WithinFilterH\x00R\x06within\x88\x01\x01\x12\x1b\n" +
+	"\tk_nearest\x18\x10 \x01(\rR\bkNearest\x12\x17\n" +
+	"\arank_by\x18\x11 \x01(\tR\x06rankBy\x12\x16\n" +
+	"\x06region\x18\x12 \x01(\tR\x06region\x12\"\n" +
+	"\fcapabilities\x18\x13 \x03(\tR\fcapabilities\x12<\n" +
+	"\x1ainclude_temporarily_closed\x18\x14 \x01(\bR\x18includeTemporarilyClosed\x12\x16\n" +
+	"\x06locale\x18\x15 \x01(\tR\x06localeB\t\n" +
+	"\a_within\"T\n" +
+	"\fWithinFilter\x12*\n" +
+	"\x04bbox\x18\x01 \x01(\v2\x16.stores.v1.BoundingBoxR\x04bbox\x12\x18\n" +
+	"\ageojson\x18\x02 \x01(\tR\ageojson\"\xb3\x01\n" +
 	"\x13SearchStoreResponse\x12+\n" +
 	"\x06stores\x18\x01 \x03(\v2\x13.stores.v1.StoreGeoR\x06stores\x12'\n" +
-	"\x03geo\x18\x02 \x01(\v2\x10.stores.v1.PointH\x00R\x03geo\x88\x01\x01B\x06\n" +
-	"\x04_geo\"`\n" +
+	"\x03geo\x18\x02 \x01(\v2\x10.stores.v1.PointH\x00R\x03geo\x88\x01\x01\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\rR\x05total\x12(\n" +
+	"\x06facets\x18\x04 \x03(\v2\x10.stores.v1.FacetR\x06facetsB\x06\n" +
+	"\x04_geo\"O\n" +
+	"\x05Facet\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x120\n" +
+	"\abuckets\x18\x02 \x03(\v2\x16.stores.v1.FacetBucketR\abuckets\"9\n" +
+	"\vFacetBucket\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\"\xbb\x01\n" +
 	"\bStoreGeo\x12&\n" +
 	"\x05store\x18\x01 \x01(\v2\x10.stores.v1.StoreR\x05store\x12\x1f\n" +
-	"\bdistance\x18\x02 \x01(\x02H\x00R\bdistance\x88\x01\x01B\v\n" +
-	"\t_distance\"A\n" +
+	"\bdistance\x18\x02 \x01(\x02H\x00R\bdistance\x88\x01\x01\x12\x19\n" +
+	"\x05score\x18\x03 \x01(\x01H\x01R\x05score\x88\x01\x01\x12$\n" +
+	"\veta_seconds\x18\x04 \x01(\rH\x02R\n" +
+	"etaSeconds\x88\x01\x01B\v\n" +
+	"\t_distanceB\b\n" +
+	"\x06_scoreB\x0e\n" +
+	"\f_eta_seconds\"A\n" +
 	"\x05Point\x12\x1a\n" +
 	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
-	"\tlongitude\x18\x02 \x01(\x01R\tlongitude2\x86\x03\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\"\x99\x01\n" +
+	"\rAddressChange\x12\x1d\n" +
+	"\n" +
+	"address_id\x18\x01 \x01(\tR\taddressId\x12.\n" +
+	"\x13previous_address_id\x18\x02 \x01(\tR\x11previousAddressId\x129\n" +
+	"\n" +
+	"changed_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\"/\n" +
+	"\x1dGetStoreAddressHistoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"T\n" +
+	"\x1eGetStoreAddressHistoryResponse\x122\n" +
+	"\achanges\x18\x01 \x03(\v2\x18.stores.v1.AddressChangeR\achanges\"\xcb\x01\n" +
+	"\x14GetStoreStatsRequest\x12\x10\n" +
+	"\x03org\x18\x01 \x01(\tR\x03org\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x1a\n" +
+	"\binterval\x18\x04 \x01(\tR\binterval\x12)\n" +
+	"\x10region_precision\x18\x05 \x01(\rR\x0fregionPrecision\"\xba\x01\n" +
+	"\x15GetStoreStatsResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\rR\x05total\x12)\n" +
+	"\x04orgs\x18\x02 \x03(\v2\x15.stores.v1.StatsCountR\x04orgs\x12.\n" +
+	"\x06series\x18\x03 \x03(\v2\x16.stores.v1.StatsBucketR\x06series\x120\n" +
+	"\aregions\x18\x04 \x03(\v2\x16.stores.v1.RegionCountR\aregions\"4\n" +
+	"\n" +
+	"StatsCount\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\"o\n" +
+	"\vStatsBucket\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12\x14\n" +
+	"\x05added\x18\x02 \x01(\rR\x05added\x12\x18\n" +
+	"\adeleted\x18\x03 \x01(\rR\adeleted\"\x9d\x01\n" +
+	"\vBoundingBox\x12!\n" +
+	"\fmin_latitude\x18\x01 \x01(\x01R\vminLatitude\x12#\n" +
+	"\rmin_longitude\x18\x02 \x01(\x01R\fminLongitude\x12!\n" +
+	"\fmax_latitude\x18\x03 \x01(\x01R\vmaxLatitude\x12#\n" +
+	"\rmax_longitude\x18\x04 \x01(\x01R\fmaxLongitude\"h\n" +
+	"\x14ClusterStoresRequest\x12\x10\n" +
+	"\x03org\x18\x01 \x01(\tR\x03org\x12*\n" +
+	"\x04bbox\x18\x02 \x01(\v2\x16.stores.v1.BoundingBoxR\x04bbox\x12\x12\n" +
+	"\x04zoom\x18\x03 \x01(\rR\x04zoom\"L\n" +
+	"\x15ClusterStoresResponse\x123\n" +
+	"\bclusters\x18\x01 \x03(\v2\x17.stores.v1.StoreClusterR\bclusters\"\xf8\x02\n" +
+	"\x18FindServingStoresRequest\x12\x10\n" +
+	"\x03org\x18\x01 \x01(\tR\x03org\x12\x1d\n" +
+	"\n" +
+	"address_id\x18\x02 \x01(\tR\taddressId\x12\x1f\n" +
+	"\vaddress_str\x18\x03 \x01(\tR\n" +
+	"addressStr\x12\x1a\n" +
+	"\blatitude\x18\x04 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x05 \x01(\x01R\tlongitude\x12'\n" +
+	"\x0finclude_address\x18\x06 \x01(\bR\x0eincludeAddress\x12\x14\n" +
+	"\x05limit\x18\a \x01(\rR\x05limit\x12\x17\n" +
+	"\arank_by\x18\b \x01(\tR\x06rankBy\x12\"\n" +
+	"\fcapabilities\x18\t \x03(\tR\fcapabilities\x12<\n" +
+	"\x1ainclude_temporarily_closed\x18\n" +
+	" \x01(\bR\x18includeTemporarilyClosed\x12\x16\n" +
+	"\x06locale\x18\v \x01(\tR\x06locale\"H\n" +
+	"\x19FindServingStoresResponse\x12+\n" +
+	"\x06stores\x18\x01 \x03(\v2\x13.stores.v1.StoreGeoR\x06stores\"i\n" +
+	"\x16ListChildStoresRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0finclude_address\x18\x02 \x01(\bR\x0eincludeAddress\x12\x16\n" +
+	"\x06locale\x18\x03 \x01(\tR\x06locale\"C\n" +
+	"\x17ListChildStoresResponse\x12(\n" +
+	"\x06stores\x18\x01 \x03(\v2\x10.stores.v1.StoreR\x06stores\"*\n" +
+	"\x18GetStoreAncestorsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"E\n" +
+	"\x19GetStoreAncestorsResponse\x12(\n" +
+	"\x06stores\x18\x01 \x03(\v2\x10.stores.v1.StoreR\x06stores\"\x1d\n" +
+	"\x1bGetCapabilityCatalogRequest\"s\n" +
+	"\x1cGetCapabilityCatalogResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\rR\aversion\x129\n" +
+	"\fcapabilities\x18\x02 \x03(\v2\x15.stores.v1.CapabilityR\fcapabilities\"v\n" +
+	"\n" +
+	"Capability\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1e\n" +
+	"\n" +
+	"deprecated\x18\x04 \x01(\bR\n" +
+	"deprecated\"\xa7\x01\n" +
+	"\fStoreClosure\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x125\n" +
+	"\x05until\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampH\x00R\x05until\x88\x01\x01\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reasonB\b\n" +
+	"\x06_until\"\xbc\x01\n" +
+	"\x16ScheduleClosureRequest\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x125\n" +
+	"\x05until\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampH\x00R\x05until\x88\x01\x01\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reasonB\b\n" +
+	"\x06_until\"L\n" +
+	"\x17ScheduleClosureResponse\x121\n" +
+	"\aclosure\x18\x01 \x01(\v2\x17.stores.v1.StoreClosureR\aclosure\"P\n" +
+	"\x14CancelClosureRequest\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12\x1d\n" +
+	"\n" +
+	"closure_id\x18\x02 \x01(\tR\tclosureId\"'\n" +
+	"\x15CancelClosureResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\"\xef\x01\n" +
+	"\x0fStoreAttachment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x10\n" +
+	"\x03uri\x18\x03 \x01(\tR\x03uri\x12\x1a\n" +
+	"\bchecksum\x18\x04 \x01(\tR\bchecksum\x12\x14\n" +
+	"\x05width\x18\x05 \x01(\rR\x05width\x12\x16\n" +
+	"\x06height\x18\x06 \x01(\rR\x06height\x12!\n" +
+	"\fcontent_type\x18\a \x01(\tR\vcontentType\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xc4\x01\n" +
+	"\x14AddAttachmentRequest\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x10\n" +
+	"\x03uri\x18\x03 \x01(\tR\x03uri\x12\x1a\n" +
+	"\bchecksum\x18\x04 \x01(\tR\bchecksum\x12\x14\n" +
+	"\x05width\x18\x05 \x01(\rR\x05width\x12\x16\n" +
+	"\x06height\x18\x06 \x01(\rR\x06height\x12!\n" +
+	"\fcontent_type\x18\a \x01(\tR\vcontentType\"S\n" +
+	"\x15AddAttachmentResponse\x12:\n" +
+	"\n" +
+	"attachment\x18\x01 \x01(\v2\x1a.stores.v1.StoreAttachmentR\n" +
+	"attachment\"Y\n" +
+	"\x17RemoveAttachmentRequest\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12#\n" +
+	"\rattachment_id\x18\x02 \x01(\tR\fattachmentId\"*\n" +
+	"\x18RemoveAttachmentResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\"]\n" +
+	"\x19ReorderAttachmentsRequest\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12%\n" +
+	"\x0eattachment_ids\x18\x02 \x03(\tR\rattachmentIds\"Z\n" +
+	"\x1aReorderAttachmentsResponse\x12<\n" +
+	"\vattachments\x18\x01 \x03(\v2\x1a.stores.v1.StoreAttachmentR\vattachments\"p\n" +
+	"\x17UploadAttachmentRequest\x125\n" +
+	"\x04info\x18\x01 \x01(\v2\x1f.stores.v1.UploadAttachmentInfoH\x00R\x04info\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\x06\n" +
+	"\x04data\"E\n" +
+	"\x14UploadAttachmentInfo\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\tR\astoreId\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\"V\n" +
+	"\x18UploadAttachmentResponse\x12:\n" +
+	"\n" +
+	"attachment\x18\x01 \x01(\v2\x1a.stores.v1.StoreAttachmentR\n" +
+	"attachment\"M\n" +
+	"\x13StoreFieldViolation\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\"V\n" +
+	"\x14StoreValidationError\x12>\n" +
+	"\n" +
+	"violations\x18\x01 \x03(\v2\x1e.stores.v1.StoreFieldViolationR\n" +
+	"violations\"\xc0\x01\n" +
+	"\fStoreCluster\x12\x16\n" +
+	"\x06region\x18\x01 \x01(\tR\x06region\x12,\n" +
+	"\bcentroid\x18\x02 \x01(\v2\x10.stores.v1.PointR\bcentroid\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\rR\x05count\x12\x1d\n" +
+	"\n" +
+	"sample_ids\x18\x04 \x03(\tR\tsampleIds\x12+\n" +
+	"\x05store\x18\x05 \x01(\v2\x10.stores.v1.StoreH\x00R\x05store\x88\x01\x01B\b\n" +
+	"\x06_store\"e\n" +
+	"\vRegionCount\x12\x16\n" +
+	"\x06region\x18\x01 \x01(\tR\x06region\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\x12(\n" +
+	"\x06center\x18\x03 \x01(\v2\x10.stores.v1.PointR\x06center\"\x99\x01\n" +
+	"\aWebhook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1f\n" +
+	"\vevent_types\x18\x03 \x03(\tR\n" +
+	"eventTypes\x12\x10\n" +
+	"\x03org\x18\x04 \x01(\tR\x03org\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x98\x01\n" +
+	"\x16RegisterWebhookRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1f\n" +
+	"\vevent_types\x18\x02 \x03(\tR\n" +
+	"eventTypes\x12\x10\n" +
+	"\x03org\x18\x03 \x01(\tR\x03org\x12\x16\n" +
+	"\x06secret\x18\x04 \x01(\tR\x06secret\x12!\n" +
+	"\frequested_by\x18\x05 \x01(\tR\vrequestedBy\"E\n" +
+	"\x17RegisterWebhookResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x13\n" +
+	"\x02id\x18\x02 \x01(\tH\x00R\x02id\x88\x01\x01B\x05\n" +
+	"\x03_id\"I\n" +
+	"\x14DeleteWebhookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\frequested_by\x18\x02 \x01(\tR\vrequestedBy\"'\n" +
+	"\x15DeleteWebhookResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\"'\n" +
+	"\x13ListWebhooksRequest\x12\x10\n" +
+	"\x03org\x18\x01 \x01(\tR\x03org\"F\n" +
+	"\x14ListWebhooksResponse\x12.\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\x12.stores.v1.WebhookR\bwebhooks\"\xe5\x03\n" +
+	"\x0fWebhookDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\tR\twebhookId\x12\x19\n" +
+	"\bevent_id\x18\x03 \x01(\tR\aeventId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x04 \x01(\tR\teventType\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\rR\battempts\x12-\n" +
+	"\x10last_status_code\x18\a \x01(\x05H\x00R\x0elastStatusCode\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\b \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12B\n" +
+	"\x0fnext_attempt_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\rnextAttemptAt\x12B\n" +
+	"\fdelivered_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampH\x01R\vdeliveredAt\x88\x01\x01B\x13\n" +
+	"\x11_last_status_codeB\x0f\n" +
+	"\r_delivered_at\"j\n" +
+	"\x1bGetWebhookDeliveriesRequest\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tR\twebhookId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\rR\x05limit\"Z\n" +
+	"\x1cGetWebhookDeliveriesResponse\x12:\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x1a.stores.v1.WebhookDeliveryR\n" +
+	"deliveries2\xcb\x0f\n" +
 	"\x06Stores\x12E\n" +
 	"\bAddStore\x12\x1a.stores.v1.AddStoreRequest\x1a\x1b.stores.v1.AddStoreResponse\"\x00\x12E\n" +
 	"\bGetStore\x12\x1a.stores.v1.GetStoreRequest\x1a\x1b.stores.v1.GetStoreResponse\"\x00\x12N\n" +
 	"\vUpdateStore\x12\x1d.stores.v1.UpdateStoreRequest\x1a\x1e.stores.v1.UpdateStoreResponse\"\x00\x12N\n" +
 	"\vDeleteStore\x12\x1d.stores.v1.DeleteStoreRequest\x1a\x1e.stores.v1.DeleteStoreResponse\"\x00\x12N\n" +
-	"\vSearchStore\x12\x1d.stores.v1.SearchStoreRequest\x1a\x1e.stores.v1.SearchStoreResponse\"\x00B1Z/github.com/comfforts/comff-stores/api/stores_v1b\x06proto3"
+	"\vSearchStore\x12\x1d.stores.v1.SearchStoreRequest\x1a\x1e.stores.v1.SearchStoreResponse\"\x00\x12o\n" +
+	"\x16GetStoreAddressHistory\x12(.stores.v1.GetStoreAddressHistoryRequest\x1a).stores.v1.GetStoreAddressHistoryResponse\"\x00\x12T\n" +
+	"\rGetStoreStats\x12\x1f.stores.v1.GetStoreStatsRequest\x1a .stores.v1.GetStoreStatsResponse\"\x00\x12T\n" +
+	"\rClusterStores\x12\x1f.stores.v1.ClusterStoresRequest\x1a .stores.v1.ClusterStoresResponse\"\x00\x12`\n" +
+	"\x11FindServingStores\x12#.stores.v1.FindServingStoresRequest\x1a$.stores.v1.FindServingStoresResponse\"\x00\x12Z\n" +
+	"\x0fListChildStores\x12!.stores.v1.ListChildStoresRequest\x1a\".stores.v1.ListChildStoresResponse\"\x00\x12`\n" +
+	"\x11GetStoreAncestors\x12#.stores.v1.GetStoreAncestorsRequest\x1a$.stores.v1.GetStoreAncestorsResponse\"\x00\x12i\n" +
+	"\x14GetCapabilityCatalog\x12&.stores.v1.GetCapabilityCatalogRequest\x1a'.stores.v1.GetCapabilityCatalogResponse\"\x00\x12Z\n" +
+	"\x0fScheduleClosure\x12!.stores.v1.ScheduleClosureRequest\x1a\".stores.v1.ScheduleClosureResponse\"\x00\x12T\n" +
+	"\rCancelClosure\x12\x1f.stores.v1.CancelClosureRequest\x1a .stores.v1.CancelClosureResponse\"\x00\x12T\n" +
+	"\rAddAttachment\x12\x1f.stores.v1.AddAttachmentRequest\x1a .stores.v1.AddAttachmentResponse\"\x00\x12]\n" +
+	"\x10RemoveAttachment\x12\".stores.v1.RemoveAttachmentRequest\x1a#.stores.v1.RemoveAttachmentResponse\"\x00\x12c\n" +
+	"\x12ReorderAttachments\x12$.stores.v1.ReorderAttachmentsRequest\x1a%.stores.v1.ReorderAttachmentsResponse\"\x00\x12_\n" +
+	"\x10UploadAttachment\x12\".stores.v1.UploadAttachmentRequest\x1a#.stores.v1.UploadAttachmentResponse\"\x00(\x01\x12Z\n" +
+	"\x0fRegisterWebhook\x12!.stores.v1.RegisterWebhookRequest\x1a\".stores.v1.RegisterWebhookResponse\"\x00\x12T\n" +
+	"\rDeleteWebhook\x12\x1f.stores.v1.DeleteWebhookRequest\x1a .stores.v1.DeleteWebhookResponse\"\x00\x12Q\n" +
+	"\fListWebhooks\x12\x1e.stores.v1.ListWebhooksRequest\x1a\x1f.stores.v1.ListWebhooksResponse\"\x00\x12i\n" +
+	"\x14GetWebhookDeliveries\x12&.stores.v1.GetWebhookDeliveriesRequest\x1a'.stores.v1.GetWebhookDeliveriesResponse\"\x00B1Z/github.com/comfforts/comff-stores/api/stores_v1b\x06proto3"
 
 var (
 	file_api_stores_v1_stores_proto_rawDescOnce sync.Once
@@ -851,43 +4689,176 @@ func file_api_stores_v1_stores_proto_rawDescGZIP() []byte {
 	return file_api_stores_v1_stores_proto_rawDescData
 }
 
-var file_api_stores_v1_stores_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_api_stores_v1_stores_proto_msgTypes = make([]protoimpl.MessageInfo, 66)
 var file_api_stores_v1_stores_proto_goTypes = []any{
-	(*AddStoreRequest)(nil),     // 0: stores.v1.AddStoreRequest
-	(*AddStoreResponse)(nil),    // 1: stores.v1.AddStoreResponse
-	(*GetStoreRequest)(nil),     // 2: stores.v1.GetStoreRequest
-	(*GetStoreResponse)(nil),    // 3: stores.v1.GetStoreResponse
-	(*Store)(nil),               // 4: stores.v1.Store
-	(*UpdateStoreRequest)(nil),  // 5: stores.v1.UpdateStoreRequest
-	(*UpdateStoreResponse)(nil), // 6: stores.v1.UpdateStoreResponse
-	(*DeleteStoreRequest)(nil),  // 7: stores.v1.DeleteStoreRequest
-	(*DeleteStoreResponse)(nil), // 8: stores.v1.DeleteStoreResponse
-	(*SearchStoreRequest)(nil),  // 9: stores.v1.SearchStoreRequest
-	(*SearchStoreResponse)(nil), // 10: stores.v1.SearchStoreResponse
-	(*StoreGeo)(nil),            // 11: stores.v1.StoreGeo
-	(*Point)(nil),               // 12: stores.v1.Point
+	(*AddStoreRequest)(nil),                // 0: stores.v1.AddStoreRequest
+	(*AddStoreResponse)(nil),               // 1: stores.v1.AddStoreResponse
+	(*GetStoreRequest)(nil),                // 2: stores.v1.GetStoreRequest
+	(*GetStoreResponse)(nil),               // 3: stores.v1.GetStoreResponse
+	(*Store)(nil),                          // 4: stores.v1.Store
+	(*StoreTranslation)(nil),               // 5: stores.v1.StoreTranslation
+	(*Address)(nil),                        // 6: stores.v1.Address
+	(*UpdateStoreRequest)(nil),             // 7: stores.v1.UpdateStoreRequest
+	(*UpdateStoreResponse)(nil),            // 8: stores.v1.UpdateStoreResponse
+	(*DeleteStoreRequest)(nil),             // 9: stores.v1.DeleteStoreRequest
+	(*DeleteStoreResponse)(nil),            // 10: stores.v1.DeleteStoreResponse
+	(*SearchStoreRequest)(nil),             // 11: stores.v1.SearchStoreRequest
+	(*WithinFilter)(nil),                   // 12: stores.v1.WithinFilter
+	(*SearchStoreResponse)(nil),            // 13: stores.v1.SearchStoreResponse
+	(*Facet)(nil),                          // 14: stores.v1.Facet
+	(*FacetBucket)(nil),                    // 15: stores.v1.FacetBucket
+	(*StoreGeo)(nil),                       // 16: stores.v1.StoreGeo
+	(*Point)(nil),                          // 17: stores.v1.Point
+	(*AddressChange)(nil),                  // 18: stores.v1.AddressChange
+	(*GetStoreAddressHistoryRequest)(nil),  // 19: stores.v1.GetStoreAddressHistoryRequest
+	(*GetStoreAddressHistoryResponse)(nil), // 20: stores.v1.GetStoreAddressHistoryResponse
+	(*GetStoreStatsRequest)(nil),           // 21: stores.v1.GetStoreStatsRequest
+	(*GetStoreStatsResponse)(nil),          // 22: stores.v1.GetStoreStatsResponse
+	(*StatsCount)(nil),                     // 23: stores.v1.StatsCount
+	(*StatsBucket)(nil),                    // 24: stores.v1.StatsBucket
+	(*BoundingBox)(nil),                    // 25: stores.v1.BoundingBox
+	(*ClusterStoresRequest)(nil),           // 26: stores.v1.ClusterStoresRequest
+	(*ClusterStoresResponse)(nil),          // 27: stores.v1.ClusterStoresResponse
+	(*FindServingStoresRequest)(nil),       // 28: stores.v1.FindServingStoresRequest
+	(*FindServingStoresResponse)(nil),      // 29: stores.v1.FindServingStoresResponse
+	(*ListChildStoresRequest)(nil),         // 30: stores.v1.ListChildStoresRequest
+	(*ListChildStoresResponse)(nil),        // 31: stores.v1.ListChildStoresResponse
+	(*GetStoreAncestorsRequest)(nil),       // 32: stores.v1.GetStoreAncestorsRequest
+	(*GetStoreAncestorsResponse)(nil),      // 33: stores.v1.GetStoreAncestorsResponse
+	(*GetCapabilityCatalogRequest)(nil),    // 34: stores.v1.GetCapabilityCatalogRequest
+	(*GetCapabilityCatalogResponse)(nil),   // 35: stores.v1.GetCapabilityCatalogResponse
+	(*Capability)(nil),                     // 36: stores.v1.Capability
+	(*StoreClosure)(nil),                   // 37: stores.v1.StoreClosure
+	(*ScheduleClosureRequest)(nil),         // 38: stores.v1.ScheduleClosureRequest
+	(*ScheduleClosureResponse)(nil),        // 39: stores.v1.ScheduleClosureResponse
+	(*CancelClosureRequest)(nil),           // 40: stores.v1.CancelClosureRequest
+	(*CancelClosureResponse)(nil),          // 41: stores.v1.CancelClosureResponse
+	(*StoreAttachment)(nil),                // 42: stores.v1.StoreAttachment
+	(*AddAttachmentRequest)(nil),           // 43: stores.v1.AddAttachmentRequest
+	(*AddAttachmentResponse)(nil),          // 44: stores.v1.AddAttachmentResponse
+	(*RemoveAttachmentRequest)(nil),        // 45: stores.v1.RemoveAttachmentRequest
+	(*RemoveAttachmentResponse)(nil),       // 46: stores.v1.RemoveAttachmentResponse
+	(*ReorderAttachmentsRequest)(nil),      // 47: stores.v1.ReorderAttachmentsRequest
+	(*ReorderAttachmentsResponse)(nil),     // 48: stores.v1.ReorderAttachmentsResponse
+	(*UploadAttachmentRequest)(nil),        // 49: stores.v1.UploadAttachmentRequest
+	(*UploadAttachmentInfo)(nil),           // 50: stores.v1.UploadAttachmentInfo
+	(*UploadAttachmentResponse)(nil),       // 51: stores.v1.UploadAttachmentResponse
+	(*StoreFieldViolation)(nil),            // 52: stores.v1.StoreFieldViolation
+	(*StoreValidationError)(nil),           // 53: stores.v1.StoreValidationError
+	(*StoreCluster)(nil),                   // 54: stores.v1.StoreCluster
+	(*RegionCount)(nil),                    // 55: stores.v1.RegionCount
+	(*Webhook)(nil),                        // 56: stores.v1.Webhook
+	(*RegisterWebhookRequest)(nil),         // 57: stores.v1.RegisterWebhookRequest
+	(*RegisterWebhookResponse)(nil),        // 58: stores.v1.RegisterWebhookResponse
+	(*DeleteWebhookRequest)(nil),           // 59: stores.v1.DeleteWebhookRequest
+	(*DeleteWebhookResponse)(nil),          // 60: stores.v1.DeleteWebhookResponse
+	(*ListWebhooksRequest)(nil),            // 61: stores.v1.ListWebhooksRequest
+	(*ListWebhooksResponse)(nil),           // 62: stores.v1.ListWebhooksResponse
+	(*WebhookDelivery)(nil),                // 63: stores.v1.WebhookDelivery
+	(*GetWebhookDeliveriesRequest)(nil),    // 64: stores.v1.GetWebhookDeliveriesRequest
+	(*GetWebhookDeliveriesResponse)(nil),   // 65: stores.v1.GetWebhookDeliveriesResponse
+	(*timestamppb.Timestamp)(nil),          // 66: google.protobuf.Timestamp
 }
 var file_api_stores_v1_stores_proto_depIdxs = []int32{
-	4,  // 0: stores.v1.GetStoreResponse.store:type_name -> stores.v1.Store
-	4,  // 1: stores.v1.UpdateStoreResponse.store:type_name -> stores.v1.Store
-	11, // 2: stores.v1.SearchStoreResponse.stores:type_name -> stores.v1.StoreGeo
-	12, // 3: stores.v1.SearchStoreResponse.geo:type_name -> stores.v1.Point
-	4,  // 4: stores.v1.StoreGeo.store:type_name -> stores.v1.Store
-	0,  // 5: stores.v1.Stores.AddStore:input_type -> stores.v1.AddStoreRequest
-	2,  // 6: stores.v1.Stores.GetStore:input_type -> stores.v1.GetStoreRequest
-	5,  // 7: stores.v1.Stores.UpdateStore:input_type -> stores.v1.UpdateStoreRequest
-	7,  // 8: stores.v1.Stores.DeleteStore:input_type -> stores.v1.DeleteStoreRequest
-	9,  // 9: stores.v1.Stores.SearchStore:input_type -> stores.v1.SearchStoreRequest
-	1,  // 10: stores.v1.Stores.AddStore:output_type -> stores.v1.AddStoreResponse
-	3,  // 11: stores.v1.Stores.GetStore:output_type -> stores.v1.GetStoreResponse
-	6,  // 12: stores.v1.Stores.UpdateStore:output_type -> stores.v1.UpdateStoreResponse
-	8,  // 13: stores.v1.Stores.DeleteStore:output_type -> stores.v1.DeleteStoreResponse
-	10, // 14: stores.v1.Stores.SearchStore:output_type -> stores.v1.SearchStoreResponse
-	10, // [10:15] is the sub-list for method output_type
-	5,  // [5:10] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	5,  // 0: stores.v1.AddStoreRequest.translations:type_name -> stores.v1.StoreTranslation
+	4,  // 1: stores.v1.GetStoreResponse.store:type_name -> stores.v1.Store
+	6,  // 2: stores.v1.Store.address:type_name -> stores.v1.Address
+	66, // 3: stores.v1.Store.created_at:type_name -> google.protobuf.Timestamp
+	37, // 4: stores.v1.Store.closures:type_name -> stores.v1.StoreClosure
+	5,  // 5: stores.v1.Store.translations:type_name -> stores.v1.StoreTranslation
+	42, // 6: stores.v1.Store.attachments:type_name -> stores.v1.StoreAttachment
+	5,  // 7: stores.v1.UpdateStoreRequest.translations:type_name -> stores.v1.StoreTranslation
+	4,  // 8: stores.v1.UpdateStoreResponse.store:type_name -> stores.v1.Store
+	12, // 9: stores.v1.SearchStoreRequest.within:type_name -> stores.v1.WithinFilter
+	25, // 10: stores.v1.WithinFilter.bbox:type_name -> stores.v1.BoundingBox
+	16, // 11: stores.v1.SearchStoreResponse.stores:type_name -> stores.v1.StoreGeo
+	17, // 12: stores.v1.SearchStoreResponse.geo:type_name -> stores.v1.Point
+	14, // 13: stores.v1.SearchStoreResponse.facets:type_name -> stores.v1.Facet
+	15, // 14: stores.v1.Facet.buckets:type_name -> stores.v1.FacetBucket
+	4,  // 15: stores.v1.StoreGeo.store:type_name -> stores.v1.Store
+	66, // 16: stores.v1.AddressChange.changed_at:type_name -> google.protobuf.Timestamp
+	18, // 17: stores.v1.GetStoreAddressHistoryResponse.changes:type_name -> stores.v1.AddressChange
+	66, // 18: stores.v1.GetStoreStatsRequest.from:type_name -> google.protobuf.Timestamp
+	66, // 19: stores.v1.GetStoreStatsRequest.to:type_name -> google.protobuf.Timestamp
+	23, // 20: stores.v1.GetStoreStatsResponse.orgs:type_name -> stores.v1.StatsCount
+	24, // 21: stores.v1.GetStoreStatsResponse.series:type_name -> stores.v1.StatsBucket
+	55, // 22: stores.v1.GetStoreStatsResponse.regions:type_name -> stores.v1.RegionCount
+	66, // 23: stores.v1.StatsBucket.start:type_name -> google.protobuf.Timestamp
+	25, // 24: stores.v1.ClusterStoresRequest.bbox:type_name -> stores.v1.BoundingBox
+	54, // 25: stores.v1.ClusterStoresResponse.clusters:type_name -> stores.v1.StoreCluster
+	16, // 26: stores.v1.FindServingStoresResponse.stores:type_name -> stores.v1.StoreGeo
+	4,  // 27: stores.v1.ListChildStoresResponse.stores:type_name -> stores.v1.Store
+	4,  // 28: stores.v1.GetStoreAncestorsResponse.stores:type_name -> stores.v1.Store
+	36, // 29: stores.v1.GetCapabilityCatalogResponse.capabilities:type_name -> stores.v1.Capability
+	66, // 30: stores.v1.StoreClosure.from:type_name -> google.protobuf.Timestamp
+	66, // 31: stores.v1.StoreClosure.until:type_name -> google.protobuf.Timestamp
+	66, // 32: stores.v1.ScheduleClosureRequest.from:type_name -> google.protobuf.Timestamp
+	66, // 33: stores.v1.ScheduleClosureRequest.until:type_name -> google.protobuf.Timestamp
+	37, // 34: stores.v1.ScheduleClosureResponse.closure:type_name -> stores.v1.StoreClosure
+	66, // 35: stores.v1.StoreAttachment.created_at:type_name -> google.protobuf.Timestamp
+	42, // 36: stores.v1.AddAttachmentResponse.attachment:type_name -> stores.v1.StoreAttachment
+	42, // 37: stores.v1.ReorderAttachmentsResponse.attachments:type_name -> stores.v1.StoreAttachment
+	50, // 38: stores.v1.UploadAttachmentRequest.info:type_name -> stores.v1.UploadAttachmentInfo
+	42, // 39: stores.v1.UploadAttachmentResponse.attachment:type_name -> stores.v1.StoreAttachment
+	52, // 40: stores.v1.StoreValidationError.violations:type_name -> stores.v1.StoreFieldViolation
+	17, // 41: stores.v1.StoreCluster.centroid:type_name -> stores.v1.Point
+	4,  // 42: stores.v1.StoreCluster.store:type_name -> stores.v1.Store
+	17, // 43: stores.v1.RegionCount.center:type_name -> stores.v1.Point
+	66, // 44: stores.v1.Webhook.created_at:type_name -> google.protobuf.Timestamp
+	56, // 45: stores.v1.ListWebhooksResponse.webhooks:type_name -> stores.v1.Webhook
+	66, // 46: stores.v1.WebhookDelivery.created_at:type_name -> google.protobuf.Timestamp
+	66, // 47: stores.v1.WebhookDelivery.next_attempt_at:type_name -> google.protobuf.Timestamp
+	66, // 48: stores.v1.WebhookDelivery.delivered_at:type_name -> google.protobuf.Timestamp
+	63, // 49: stores.v1.GetWebhookDeliveriesResponse.deliveries:type_name -> stores.v1.WebhookDelivery
+	0,  // 50: stores.v1.Stores.AddStore:input_type -> stores.v1.AddStoreRequest
+	2,  // 51: stores.v1.Stores.GetStore:input_type -> stores.v1.GetStoreRequest
+	7,  // 52: stores.v1.Stores.UpdateStore:input_type -> stores.v1.UpdateStoreRequest
+	9,  // 53: stores.v1.Stores.DeleteStore:input_type -> stores.v1.DeleteStoreRequest
+	11, // 54: stores.v1.Stores.SearchStore:input_type -> stores.v1.SearchStoreRequest
+	19, // 55: stores.v1.Stores.GetStoreAddressHistory:input_type -> stores.v1.GetStoreAddressHistoryRequest
+	21, // 56: stores.v1.Stores.GetStoreStats:input_type -> stores.v1.GetStoreStatsRequest
+	26, // 57: stores.v1.Stores.ClusterStores:input_type -> stores.v1.ClusterStoresRequest
+	28, // 58: stores.v1.Stores.FindServingStores:input_type -> stores.v1.FindServingStoresRequest
+	30, // 59: stores.v1.Stores.ListChildStores:input_type -> stores.v1.ListChildStoresRequest
+	32, // 60: stores.v1.Stores.GetStoreAncestors:input_type -> stores.v1.GetStoreAncestorsRequest
+	34, // 61: stores.v1.Stores.GetCapabilityCatalog:input_type -> stores.v1.GetCapabilityCatalogRequest
+	38, // 62: stores.v1.Stores.ScheduleClosure:input_type -> stores.v1.ScheduleClosureRequest
+	40, // 63: stores.v1.Stores.CancelClosure:input_type -> stores.v1.CancelClosureRequest
+	43, // 64: stores.v1.Stores.AddAttachment:input_type -> stores.v1.AddAttachmentRequest
+	45, // 65: stores.v1.Stores.RemoveAttachment:input_type -> stores.v1.RemoveAttachmentRequest
+	47, // 66: stores.v1.Stores.ReorderAttachments:input_type -> stores.v1.ReorderAttachmentsRequest
+	49, // 67: stores.v1.Stores.UploadAttachment:input_type -> stores.v1.UploadAttachmentRequest
+	57, // 68: stores.v1.Stores.RegisterWebhook:input_type -> stores.v1.RegisterWebhookRequest
+	59, // 69: stores.v1.Stores.DeleteWebhook:input_type -> stores.v1.DeleteWebhookRequest
+	61, // 70: stores.v1.Stores.ListWebhooks:input_type -> stores.v1.ListWebhooksRequest
+	64, // 71: stores.v1.Stores.GetWebhookDeliveries:input_type -> stores.v1.GetWebhookDeliveriesRequest
+	1,  // 72: stores.v1.Stores.AddStore:output_type -> stores.v1.AddStoreResponse
+	3,  // 73: stores.v1.Stores.GetStore:output_type -> stores.v1.GetStoreResponse
+	8,  // 74: stores.v1.Stores.UpdateStore:output_type -> stores.v1.UpdateStoreResponse
+	10, // 75: stores.v1.Stores.DeleteStore:output_type -> stores.v1.DeleteStoreResponse
+	13, // 76: stores.v1.Stores.SearchStore:output_type -> stores.v1.SearchStoreResponse
+	20, // 77: stores.v1.Stores.GetStoreAddressHistory:output_type -> stores.v1.GetStoreAddressHistoryResponse
+	22, // 78: stores.v1.Stores.GetStoreStats:output_type -> stores.v1.GetStoreStatsResponse
+	27, // 79: stores.v1.Stores.ClusterStores:output_type -> stores.v1.ClusterStoresResponse
+	29, // 80: stores.v1.Stores.FindServingStores:output_type -> stores.v1.FindServingStoresResponse
+	31, // 81: stores.v1.Stores.ListChildStores:output_type -> stores.v1.ListChildStoresResponse
+	33, // 82: stores.v1.Stores.GetStoreAncestors:output_type -> stores.v1.GetStoreAncestorsResponse
+	35, // 83: stores.v1.Stores.GetCapabilityCatalog:output_type -> stores.v1.GetCapabilityCatalogResponse
+	39, // 84: stores.v1.Stores.ScheduleClosure:output_type -> stores.v1.ScheduleClosureResponse
+	41, // 85: stores.v1.Stores.CancelClosure:output_type -> stores.v1.CancelClosureResponse
+	44, // 86: stores.v1.Stores.AddAttachment:output_type -> stores.v1.AddAttachmentResponse
+	46, // 87: stores.v1.Stores.RemoveAttachment:output_type -> stores.v1.RemoveAttachmentResponse
+	48, // 88: stores.v1.Stores.ReorderAttachments:output_type -> stores.v1.ReorderAttachmentsResponse
+	51, // 89: stores.v1.Stores.UploadAttachment:output_type -> stores.v1.UploadAttachmentResponse
+	58, // 90: stores.v1.Stores.RegisterWebhook:output_type -> stores.v1.RegisterWebhookResponse
+	60, // 91: stores.v1.Stores.DeleteWebhook:output_type -> stores.v1.DeleteWebhookResponse
+	62, // 92: stores.v1.Stores.ListWebhooks:output_type -> stores.v1.ListWebhooksResponse
+	65, // 93: stores.v1.Stores.GetWebhookDeliveries:output_type -> stores.v1.GetWebhookDeliveriesResponse
+	72, // [72:94] is the sub-list for method output_type
+	50, // [50:72] is the sub-list for method input_type
+	50, // [50:50] is the sub-list for extension type_name
+	50, // [50:50] is the sub-list for extension extendee
+	0,  // [0:50] is the sub-list for field type_name
 }
 
 func init() { file_api_stores_v1_stores_proto_init() }
@@ -897,16 +4868,27 @@ func file_api_stores_v1_stores_proto_init() {
 	}
 	file_api_stores_v1_stores_proto_msgTypes[1].OneofWrappers = []any{}
 	file_api_stores_v1_stores_proto_msgTypes[3].OneofWrappers = []any{}
-	file_api_stores_v1_stores_proto_msgTypes[6].OneofWrappers = []any{}
-	file_api_stores_v1_stores_proto_msgTypes[10].OneofWrappers = []any{}
+	file_api_stores_v1_stores_proto_msgTypes[4].OneofWrappers = []any{}
+	file_api_stores_v1_stores_proto_msgTypes[8].OneofWrappers = []any{}
 	file_api_stores_v1_stores_proto_msgTypes[11].OneofWrappers = []any{}
+	file_api_stores_v1_stores_proto_msgTypes[13].OneofWrappers = []any{}
+	file_api_stores_v1_stores_proto_msgTypes[16].OneofWrappers = []any{}
+	file_api_stores_v1_stores_proto_msgTypes[37].OneofWrappers = []any{}
+	file_api_stores_v1_stores_proto_msgTypes[38].OneofWrappers = []any{}
+	file_api_stores_v1_stores_proto_msgTypes[49].OneofWrappers = []any{
+		(*UploadAttachmentRequest_Info)(nil),
+		(*UploadAttachmentRequest_Chunk)(nil),
+	}
+	file_api_stores_v1_stores_proto_msgTypes[54].OneofWrappers = []any{}
+	file_api_stores_v1_stores_proto_msgTypes[58].OneofWrappers = []any{}
+	file_api_stores_v1_stores_proto_msgTypes[63].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_stores_v1_stores_proto_rawDesc), len(file_api_stores_v1_stores_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   66,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,11 +19,28 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Stores_AddStore_FullMethodName    = "/stores.v1.Stores/AddStore"
-	Stores_GetStore_FullMethodName    = "/stores.v1.Stores/GetStore"
-	Stores_UpdateStore_FullMethodName = "/stores.v1.Stores/UpdateStore"
-	Stores_DeleteStore_FullMethodName = "/stores.v1.Stores/DeleteStore"
-	Stores_SearchStore_FullMethodName = "/stores.v1.Stores/SearchStore"
+	Stores_AddStore_FullMethodName               = "/stores.v1.Stores/AddStore"
+	Stores_GetStore_FullMethodName               = "/stores.v1.Stores/GetStore"
+	Stores_UpdateStore_FullMethodName            = "/stores.v1.Stores/UpdateStore"
+	Stores_DeleteStore_FullMethodName            = "/stores.v1.Stores/DeleteStore"
+	Stores_SearchStore_FullMethodName            = "/stores.v1.Stores/SearchStore"
+	Stores_GetStoreAddressHistory_FullMethodName = "/stores.v1.Stores/GetStoreAddressHistory"
+	Stores_GetStoreStats_FullMethodName          = "/stores.v1.Stores/GetStoreStats"
+	Stores_ClusterStores_FullMethodName          = "/stores.v1.Stores/ClusterStores"
+	Stores_FindServingStores_FullMethodName      = "/stores.v1.Stores/FindServingStores"
+	Stores_ListChildStores_FullMethodName        = "/stores.v1.Stores/ListChildStores"
+	Stores_GetStoreAncestors_FullMethodName      = "/stores.v1.Stores/GetStoreAncestors"
+	Stores_GetCapabilityCatalog_FullMethodName   = "/stores.v1.Stores/GetCapabilityCatalog"
+	Stores_ScheduleClosure_FullMethodName        = "/stores.v1.Stores/ScheduleClosure"
+	Stores_CancelClosure_FullMethodName          = "/stores.v1.Stores/CancelClosure"
+	Stores_AddAttachment_FullMethodName          = "/stores.v1.Stores/AddAttachment"
+	Stores_RemoveAttachment_FullMethodName       = "/stores.v1.Stores/RemoveAttachment"
+	Stores_ReorderAttachments_FullMethodName     = "/stores.v1.Stores/ReorderAttachments"
+	Stores_UploadAttachment_FullMethodName       = "/stores.v1.Stores/UploadAttachment"
+	Stores_RegisterWebhook_FullMethodName        = "/stores.v1.Stores/RegisterWebhook"
+	Stores_DeleteWebhook_FullMethodName          = "/stores.v1.Stores/DeleteWebhook"
+	Stores_ListWebhooks_FullMethodName           = "/stores.v1.Stores/ListWebhooks"
+	Stores_GetWebhookDeliveries_FullMethodName   = "/stores.v1.Stores/GetWebhookDeliveries"
 )
 
 // StoresClient is the client API for Stores service.
@@ -35,6 +52,23 @@ type StoresClient interface {
 	UpdateStore(ctx context.Context, in *UpdateStoreRequest, opts ...grpc.CallOption) (*UpdateStoreResponse, error)
 	DeleteStore(ctx context.Context, in *DeleteStoreRequest, opts ...grpc.CallOption) (*DeleteStoreResponse, error)
 	SearchStore(ctx context.Context, in *SearchStoreRequest, opts ...grpc.CallOption) (*SearchStoreResponse, error)
+	GetStoreAddressHistory(ctx context.Context, in *GetStoreAddressHistoryRequest, opts ...grpc.CallOption) (*GetStoreAddressHistoryResponse, error)
+	GetStoreStats(ctx context.Context, in *GetStoreStatsRequest, opts ...grpc.CallOption) (*GetStoreStatsResponse, error)
+	ClusterStores(ctx context.Context, in *ClusterStoresRequest, opts ...grpc.CallOption) (*ClusterStoresResponse, error)
+	FindServingStores(ctx context.Context, in *FindServingStoresRequest, opts ...grpc.CallOption) (*FindServingStoresResponse, error)
+	ListChildStores(ctx context.Context, in *ListChildStoresRequest, opts ...grpc.CallOption) (*ListChildStoresResponse, error)
+	GetStoreAncestors(ctx context.Context, in *GetStoreAncestorsRequest, opts ...grpc.CallOption) (*GetStoreAncestorsResponse, error)
+	GetCapabilityCatalog(ctx context.Context, in *GetCapabilityCatalogRequest, opts ...grpc.CallOption) (*GetCapabilityCatalogResponse, error)
+	ScheduleClosure(ctx context.Context, in *ScheduleClosureRequest, opts ...grpc.CallOption) (*ScheduleClosureResponse, error)
+	CancelClosure(ctx context.Context, in *CancelClosureRequest, opts ...grpc.CallOption) (*CancelClosureResponse, error)
+	AddAttachment(ctx context.Context, in *AddAttachmentRequest, opts ...grpc.CallOption) (*AddAttachmentResponse, error)
+	RemoveAttachment(ctx context.Context, in *RemoveAttachmentRequest, opts ...grpc.CallOption) (*RemoveAttachmentResponse, error)
+	ReorderAttachments(ctx context.Context, in *ReorderAttachmentsRequest, opts ...grpc.CallOption) (*ReorderAttachmentsResponse, error)
+	UploadAttachment(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadAttachmentRequest, UploadAttachmentResponse], error)
+	RegisterWebhook(ctx context.Context, in *RegisterWebhookRequest, opts ...grpc.CallOption) (*RegisterWebhookResponse, error)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error)
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	GetWebhookDeliveries(ctx context.Context, in *GetWebhookDeliveriesRequest, opts ...grpc.CallOption) (*GetWebhookDeliveriesResponse, error)
 }
 
 type storesClient struct {
@@ -95,6 +129,179 @@ func (c *storesClient) SearchStore(ctx context.Context, in *SearchStoreRequest,
 	return out, nil
 }
 
+func (c *storesClient) GetStoreAddressHistory(ctx context.Context, in *GetStoreAddressHistoryRequest, opts ...grpc.CallOption) (*GetStoreAddressHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStoreAddressHistoryResponse)
+	err := c.cc.Invoke(ctx, Stores_GetStoreAddressHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storesClient) GetStoreStats(ctx context.Context, in *GetStoreStatsRequest, opts ...grpc.CallOption) (*GetStoreStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStoreStatsResponse)
+	err := c.cc.Invoke(ctx, Stores_GetStoreStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storesClient) ClusterStores(ctx context.Context, in *ClusterStoresRequest, opts ...grpc.CallOption) (*ClusterStoresResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClusterStoresResponse)
+	err := c.cc.Invoke(ctx, Stores_ClusterStores_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storesClient) FindServingStores(ctx context.Context, in *FindServingStoresRequest, opts ...grpc.CallOption) (*FindServingStoresResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindServingStoresResponse)
+	err := c.cc.Invoke(ctx, Stores_FindServingStores_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storesClient) ListChildStores(ctx context.Context, in *ListChildStoresRequest, opts ...grpc.CallOption) (*ListChildStoresResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListChildStoresResponse)
+	err := c.cc.Invoke(ctx, Stores_ListChildStores_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storesClient) GetStoreAncestors(ctx context.Context, in *GetStoreAncestorsRequest, opts ...grpc.CallOption) (*GetStoreAncestorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStoreAncestorsResponse)
+	err := c.cc.Invoke(ctx, Stores_GetStoreAncestors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storesClient) GetCapabilityCatalog(ctx context.Context, in *GetCapabilityCatalogRequest, opts ...grpc.CallOption) (*GetCapabilityCatalogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCapabilityCatalogResponse)
+	err := c.cc.Invoke(ctx, Stores_GetCapabilityCatalog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storesClient) ScheduleClosure(ctx context.Context, in *ScheduleClosureRequest, opts ...grpc.CallOption) (*ScheduleClosureResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScheduleClosureResponse)
+	err := c.cc.Invoke(ctx, Stores_ScheduleClosure_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storesClient) CancelClosure(ctx context.Context, in *CancelClosureRequest, opts ...grpc.CallOption) (*CancelClosureResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelClosureResponse)
+	err := c.cc.Invoke(ctx, Stores_CancelClosure_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storesClient) AddAttachment(ctx context.Context, in *AddAttachmentRequest, opts ...grpc.CallOption) (*AddAttachmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddAttachmentResponse)
+	err := c.cc.Invoke(ctx, Stores_AddAttachment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storesClient) RemoveAttachment(ctx context.Context, in *RemoveAttachmentRequest, opts ...grpc.CallOption) (*RemoveAttachmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveAttachmentResponse)
+	err := c.cc.Invoke(ctx, Stores_RemoveAttachment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storesClient) ReorderAttachments(ctx context.Context, in *ReorderAttachmentsRequest, opts ...grpc.CallOption) (*ReorderAttachmentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReorderAttachmentsResponse)
+	err := c.cc.Invoke(ctx, Stores_ReorderAttachments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storesClient) UploadAttachment(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadAttachmentRequest, UploadAttachmentResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Stores_ServiceDesc.Streams[0], Stores_UploadAttachment_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadAttachmentRequest, UploadAttachmentResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Stores_UploadAttachmentClient = grpc.ClientStreamingClient[UploadAttachmentRequest, UploadAttachmentResponse]
+
+func (c *storesClient) RegisterWebhook(ctx context.Context, in *RegisterWebhookRequest, opts ...grpc.CallOption) (*RegisterWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterWebhookResponse)
+	err := c.cc.Invoke(ctx, Stores_RegisterWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storesClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteWebhookResponse)
+	err := c.cc.Invoke(ctx, Stores_DeleteWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storesClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, Stores_ListWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storesClient) GetWebhookDeliveries(ctx context.Context, in *GetWebhookDeliveriesRequest, opts ...grpc.CallOption) (*GetWebhookDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, Stores_GetWebhookDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StoresServer is the server API for Stores service.
 // All implementations must embed UnimplementedStoresServer
 // for forward compatibility.
@@ -104,6 +311,23 @@ type StoresServer interface {
 	UpdateStore(context.Context, *UpdateStoreRequest) (*UpdateStoreResponse, error)
 	DeleteStore(context.Context, *DeleteStoreRequest) (*DeleteStoreResponse, error)
 	SearchStore(context.Context, *SearchStoreRequest) (*SearchStoreResponse, error)
+	GetStoreAddressHistory(context.Context, *GetStoreAddressHistoryRequest) (*GetStoreAddressHistoryResponse, error)
+	GetStoreStats(context.Context, *GetStoreStatsRequest) (*GetStoreStatsResponse, error)
+	ClusterStores(context.Context, *ClusterStoresRequest) (*ClusterStoresResponse, error)
+	FindServingStores(context.Context, *FindServingStoresRequest) (*FindServingStoresResponse, error)
+	ListChildStores(context.Context, *ListChildStoresRequest) (*ListChildStoresResponse, error)
+	GetStoreAncestors(context.Context, *GetStoreAncestorsRequest) (*GetStoreAncestorsResponse, error)
+	GetCapabilityCatalog(context.Context, *GetCapabilityCatalogRequest) (*GetCapabilityCatalogResponse, error)
+	ScheduleClosure(context.Context, *ScheduleClosureRequest) (*ScheduleClosureResponse, error)
+	CancelClosure(context.Context, *CancelClosureRequest) (*CancelClosureResponse, error)
+	AddAttachment(context.Context, *AddAttachmentRequest) (*AddAttachmentResponse, error)
+	RemoveAttachment(context.Context, *RemoveAttachmentRequest) (*RemoveAttachmentResponse, error)
+	ReorderAttachments(context.Context, *ReorderAttachmentsRequest) (*ReorderAttachmentsResponse, error)
+	UploadAttachment(grpc.ClientStreamingServer[UploadAttachmentRequest, UploadAttachmentResponse]) error
+	RegisterWebhook(context.Context, *RegisterWebhookRequest) (*RegisterWebhookResponse, error)
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error)
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	GetWebhookDeliveries(context.Context, *GetWebhookDeliveriesRequest) (*GetWebhookDeliveriesResponse, error)
 	mustEmbedUnimplementedStoresServer()
 }
 
@@ -129,6 +353,57 @@ func (UnimplementedStoresServer) DeleteStore(context.Context, *DeleteStoreReques
 func (UnimplementedStoresServer) SearchStore(context.Context, *SearchStoreRequest) (*SearchStoreResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SearchStore not implemented")
 }
+func (UnimplementedStoresServer) GetStoreAddressHistory(context.Context, *GetStoreAddressHistoryRequest) (*GetStoreAddressHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStoreAddressHistory not implemented")
+}
+func (UnimplementedStoresServer) GetStoreStats(context.Context, *GetStoreStatsRequest) (*GetStoreStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStoreStats not implemented")
+}
+func (UnimplementedStoresServer) ClusterStores(context.Context, *ClusterStoresRequest) (*ClusterStoresResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClusterStores not implemented")
+}
+func (UnimplementedStoresServer) FindServingStores(context.Context, *FindServingStoresRequest) (*FindServingStoresResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindServingStores not implemented")
+}
+func (UnimplementedStoresServer) ListChildStores(context.Context, *ListChildStoresRequest) (*ListChildStoresResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChildStores not implemented")
+}
+func (UnimplementedStoresServer) GetStoreAncestors(context.Context, *GetStoreAncestorsRequest) (*GetStoreAncestorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStoreAncestors not implemented")
+}
+func (UnimplementedStoresServer) GetCapabilityCatalog(context.Context, *GetCapabilityCatalogRequest) (*GetCapabilityCatalogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapabilityCatalog not implemented")
+}
+func (UnimplementedStoresServer) ScheduleClosure(context.Context, *ScheduleClosureRequest) (*ScheduleClosureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleClosure not implemented")
+}
+func (UnimplementedStoresServer) CancelClosure(context.Context, *CancelClosureRequest) (*CancelClosureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelClosure not implemented")
+}
+func (UnimplementedStoresServer) AddAttachment(context.Context, *AddAttachmentRequest) (*AddAttachmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddAttachment not implemented")
+}
+func (UnimplementedStoresServer) RemoveAttachment(context.Context, *RemoveAttachmentRequest) (*RemoveAttachmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveAttachment not implemented")
+}
+func (UnimplementedStoresServer) ReorderAttachments(context.Context, *ReorderAttachmentsRequest) (*ReorderAttachmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReorderAttachments not implemented")
+}
+func (UnimplementedStoresServer) UploadAttachment(grpc.ClientStreamingServer[UploadAttachmentRequest, UploadAttachmentResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadAttachment not implemented")
+}
+func (UnimplementedStoresServer) RegisterWebhook(context.Context, *RegisterWebhookRequest) (*RegisterWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterWebhook not implemented")
+}
+func (UnimplementedStoresServer) DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedStoresServer) ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedStoresServer) GetWebhookDeliveries(context.Context, *GetWebhookDeliveriesRequest) (*GetWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhookDeliveries not implemented")
+}
 func (UnimplementedStoresServer) mustEmbedUnimplementedStoresServer() {}
 func (UnimplementedStoresServer) testEmbeddedByValue()                {}
 
@@ -240,6 +515,301 @@ func _Stores_SearchStore_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Stores_GetStoreAddressHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStoreAddressHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).GetStoreAddressHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_GetStoreAddressHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).GetStoreAddressHistory(ctx, req.(*GetStoreAddressHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stores_GetStoreStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStoreStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).GetStoreStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_GetStoreStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).GetStoreStats(ctx, req.(*GetStoreStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stores_ClusterStores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClusterStoresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).ClusterStores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_ClusterStores_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).ClusterStores(ctx, req.(*ClusterStoresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stores_FindServingStores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindServingStoresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).FindServingStores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_FindServingStores_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).FindServingStores(ctx, req.(*FindServingStoresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stores_ListChildStores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChildStoresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).ListChildStores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_ListChildStores_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).ListChildStores(ctx, req.(*ListChildStoresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stores_GetStoreAncestors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStoreAncestorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).GetStoreAncestors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_GetStoreAncestors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).GetStoreAncestors(ctx, req.(*GetStoreAncestorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stores_GetCapabilityCatalog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapabilityCatalogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).GetCapabilityCatalog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_GetCapabilityCatalog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).GetCapabilityCatalog(ctx, req.(*GetCapabilityCatalogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stores_ScheduleClosure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleClosureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).ScheduleClosure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_ScheduleClosure_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).ScheduleClosure(ctx, req.(*ScheduleClosureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stores_CancelClosure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelClosureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).CancelClosure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_CancelClosure_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).CancelClosure(ctx, req.(*CancelClosureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stores_AddAttachment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddAttachmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).AddAttachment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_AddAttachment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).AddAttachment(ctx, req.(*AddAttachmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stores_RemoveAttachment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveAttachmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).RemoveAttachment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_RemoveAttachment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).RemoveAttachment(ctx, req.(*RemoveAttachmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stores_ReorderAttachments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReorderAttachmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).ReorderAttachments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_ReorderAttachments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).ReorderAttachments(ctx, req.(*ReorderAttachmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stores_UploadAttachment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StoresServer).UploadAttachment(&grpc.GenericServerStream[UploadAttachmentRequest, UploadAttachmentResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Stores_UploadAttachmentServer = grpc.ClientStreamingServer[UploadAttachmentRequest, UploadAttachmentResponse]
+
+func _Stores_RegisterWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).RegisterWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_RegisterWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).RegisterWebhook(ctx, req.(*RegisterWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stores_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_DeleteWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stores_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_ListWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stores_GetWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoresServer).GetWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stores_GetWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoresServer).GetWebhookDeliveries(ctx, req.(*GetWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Stores_ServiceDesc is the grpc.ServiceDesc for Stores service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -267,7 +837,77 @@ var Stores_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SearchStore",
 			Handler:    _Stores_SearchStore_Handler,
 		},
+		{
+			MethodName: "GetStoreAddressHistory",
+			Handler:    _Stores_GetStoreAddressHistory_Handler,
+		},
+		{
+			MethodName: "GetStoreStats",
+			Handler:    _Stores_GetStoreStats_Handler,
+		},
+		{
+			MethodName: "ClusterStores",
+			Handler:    _Stores_ClusterStores_Handler,
+		},
+		{
+			MethodName: "FindServingStores",
+			Handler:    _Stores_FindServingStores_Handler,
+		},
+		{
+			MethodName: "ListChildStores",
+			Handler:    _Stores_ListChildStores_Handler,
+		},
+		{
+			MethodName: "GetStoreAncestors",
+			Handler:    _Stores_GetStoreAncestors_Handler,
+		},
+		{
+			MethodName: "GetCapabilityCatalog",
+			Handler:    _Stores_GetCapabilityCatalog_Handler,
+		},
+		{
+			MethodName: "ScheduleClosure",
+			Handler:    _Stores_ScheduleClosure_Handler,
+		},
+		{
+			MethodName: "CancelClosure",
+			Handler:    _Stores_CancelClosure_Handler,
+		},
+		{
+			MethodName: "AddAttachment",
+			Handler:    _Stores_AddAttachment_Handler,
+		},
+		{
+			MethodName: "RemoveAttachment",
+			Handler:    _Stores_RemoveAttachment_Handler,
+		},
+		{
+			MethodName: "ReorderAttachments",
+			Handler:    _Stores_ReorderAttachments_Handler,
+		},
+		{
+			MethodName: "RegisterWebhook",
+			Handler:    _Stores_RegisterWebhook_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _Stores_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _Stores_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "GetWebhookDeliveries",
+			Handler:    _Stores_GetWebhookDeliveries_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadAttachment",
+			Handler:       _Stores_UploadAttachment_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/stores/v1/stores.proto",
 }
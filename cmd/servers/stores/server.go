@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"net"
 	"os"
@@ -18,10 +19,29 @@ import (
 	"google.golang.org/grpc/credentials"
 
 	grpchandler "github.com/comfforts/comff-stores/internal/delivery/stores/grpc_handler"
+	blobdom "github.com/comfforts/comff-stores/internal/domain/blobs"
+	evdom "github.com/comfforts/comff-stores/internal/domain/events"
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	iddom "github.com/comfforts/comff-stores/internal/domain/idempotency"
+	indom "github.com/comfforts/comff-stores/internal/domain/infra"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+	whdom "github.com/comfforts/comff-stores/internal/domain/webhooks"
+	"github.com/comfforts/comff-stores/internal/infra/blobs"
+	"github.com/comfforts/comff-stores/internal/infra/capabilities"
+	geoinfra "github.com/comfforts/comff-stores/internal/infra/geo"
 	"github.com/comfforts/comff-stores/internal/infra/mongostore"
 	"github.com/comfforts/comff-stores/internal/infra/observability"
+	"github.com/comfforts/comff-stores/internal/infra/publisher"
+	"github.com/comfforts/comff-stores/internal/infra/routing"
+	"github.com/comfforts/comff-stores/internal/infra/validation"
+	idrepo "github.com/comfforts/comff-stores/internal/repo/idempotency"
+	mgrepo "github.com/comfforts/comff-stores/internal/repo/migrations"
+	obrepo "github.com/comfforts/comff-stores/internal/repo/outbox"
 	strepo "github.com/comfforts/comff-stores/internal/repo/stores"
+	whrepo "github.com/comfforts/comff-stores/internal/repo/webhooks"
+	"github.com/comfforts/comff-stores/internal/usecase/relay"
 	"github.com/comfforts/comff-stores/internal/usecase/services/stores"
+	whsvc "github.com/comfforts/comff-stores/internal/usecase/services/webhooks"
 	envutils "github.com/comfforts/comff-stores/pkg/utils/environ"
 )
 
@@ -75,45 +95,276 @@ func main() {
 	defer cancel()
 	startCtx = logger.WithLogger(startCtx, l)
 
-	// Initialize MongoDB store
-	nmCfg := envutils.BuildMongoStoreConfig(true)
-	ms, err := mongostore.NewMongoStore(startCtx, nmCfg)
-	if err != nil {
-		l.Error("failed to initialize mongo store", "error", err.Error())
+	// Initialize repositories, the memory backend runs without outside services
+	// but doesn't persist stores & has no webhooks or idempotency keys
+	repoType := flag.String("stores-repo", envutils.BuildStoresRepoConfig(), "stores repository backend, mongo or memory")
+	migrate := flag.Bool("migrate", envutils.BuildMigrateConfig(), "apply pending mongo migrations at startup")
+	uniqueness := flag.String("address-uniqueness", envutils.BuildAddressUniquenessConfig(), "store address uniqueness rule, global, org or none")
+	flag.Parse()
+
+	var (
+		sr    stdom.StoresRepo
+		or    evdom.OutboxRepo
+		ir    iddom.IdempotencyRepo
+		wr    whdom.WebhooksRepo
+		whPub evdom.EventPublisher
+	)
+	switch *repoType {
+	case "memory":
+		msr, err := strepo.NewMemoryStoresRepo(startCtx, metrics, stdom.AddressUniqueness(*uniqueness))
+		if err != nil {
+			l.Error("failed to initialize in-memory stores repository", "error", err.Error())
+			panic(err)
+		}
+		sr, or = msr, msr
+		l.Info("using in-memory stores repository, webhooks & idempotency keys disabled")
+	case "mongo":
+		// Initialize MongoDB store
+		nmCfg := envutils.BuildMongoStoreConfig(true)
+		ms, err := mongostore.NewMongoStore(startCtx, nmCfg)
+		if err != nil {
+			l.Error("failed to initialize mongo store", "error", err.Error())
+			panic(err)
+		}
+
+		// Apply pending migrations, replicas wait on the migrations lock
+		if *migrate {
+			mg, err := mgrepo.NewMigrator(startCtx, ms, strepo.Migrations(stdom.AddressUniqueness(*uniqueness)), mgrepo.MigratorOptions{})
+			if err != nil {
+				l.Error("failed to initialize migrator", "error", err.Error())
+				panic(err)
+			}
+			applied, err := mg.Up(startCtx, 0)
+			if err != nil {
+				l.Error("failed to apply migrations", "error", err.Error())
+				panic(err)
+			}
+			l.Info("migrations up to date", "applied", applied)
+		}
+
+		// Initialize stores repository
+		sr, err = strepo.NewStoresRepo(startCtx, ms, metrics, stdom.AddressUniqueness(*uniqueness))
+		if err != nil {
+			l.Error("failed to initialize stores repository", "error", err.Error())
+			panic(err)
+		}
+
+		// Initialize outbox repository
+		or, err = obrepo.NewOutboxRepo(startCtx, ms)
+		if err != nil {
+			l.Error("failed to initialize outbox repository", "error", err.Error())
+			panic(err)
+		}
+
+		// Initialize idempotency keys repository
+		ir, err = idrepo.NewIdempotencyRepo(startCtx, ms)
+		if err != nil {
+			l.Error("failed to initialize idempotency repository", "error", err.Error())
+			panic(err)
+		}
+
+		// Initialize webhooks repository
+		wr, err = whrepo.NewWebhooksRepo(startCtx, ms)
+		if err != nil {
+			l.Error("failed to initialize webhooks repository", "error", err.Error())
+			panic(err)
+		}
+
+		// Initialize webhook fan-out publisher, enqueues deliveries for matching webhooks
+		whPub, err = whsvc.NewWebhookPublisher(startCtx, wr)
+		if err != nil {
+			l.Error("failed to initialize webhook publisher", "error", err.Error())
+			panic(err)
+		}
+	default:
+		err := fmt.Errorf("unknown stores repository backend: %s", *repoType)
+		l.Error("failed to initialize stores repository", "error", err.Error())
 		panic(err)
 	}
 
-	// Initialize stores repository
-	sr, err := strepo.NewStoresRepo(startCtx, ms, metrics)
+	// Initialize outbox event publisher & relay
+	pubType, pubFilePath := envutils.BuildOutboxConfig()
+	var pub evdom.EventPublisher
+	switch pubType {
+	case "none":
+		l.Info("outbox event stream publishing disabled, events delivered to webhooks only")
+	case "file":
+		pub, err = publisher.NewFilePublisher(pubFilePath)
+		if err != nil {
+			l.Error("failed to initialize file event publisher", "error", err.Error(), "path", pubFilePath)
+			panic(err)
+		}
+	default:
+		pub = publisher.NewStdoutPublisher()
+	}
+	switch {
+	case pub != nil && whPub != nil:
+		pub = publisher.NewMultiPublisher(pub, whPub)
+	case whPub != nil:
+		pub = whPub
+	case pub == nil:
+		// nothing to deliver to, events are marked delivered & expire from the outbox
+		pub = publisher.NewMultiPublisher()
+	}
+
+	// background workers, stopped on shutdown
+	workers := []indom.Worker{}
+	workerCtx := logger.WithLogger(context.Background(), l)
+
+	outboxRelay, err := relay.NewOutboxRelay(startCtx, or, pub, relay.DefaultRelayOptions())
 	if err != nil {
-		l.Error("failed to initialize stores repository", "error", err.Error())
+		l.Error("failed to initialize outbox relay", "error", err.Error())
 		panic(err)
 	}
+	if err := outboxRelay.Start(workerCtx); err != nil {
+		l.Error("failed to start outbox relay", "error", err.Error())
+		panic(err)
+	}
+	workers = append(workers, outboxRelay)
+	l.Info("outbox relay started", "publisher", pubType)
+
+	// Initialize webhook delivery dispatcher & webhooks service
+	var ws whdom.WebhooksService
+	if wr != nil {
+		dispatcherOpts := relay.DefaultDispatcherOptions()
+		dispatcherOpts.MaxAttempts = envutils.BuildWebhookConfig()
+		dispatcher, err := relay.NewWebhookDispatcher(startCtx, wr, dispatcherOpts)
+		if err != nil {
+			l.Error("failed to initialize webhook dispatcher", "error", err.Error())
+			panic(err)
+		}
+		if err := dispatcher.Start(workerCtx); err != nil {
+			l.Error("failed to start webhook dispatcher", "error", err.Error())
+			panic(err)
+		}
+		workers = append(workers, dispatcher)
+
+		ws, err = whsvc.NewWebhooksService(startCtx, wr)
+		if err != nil {
+			l.Error("failed to initialize webhooks service", "error", err.Error())
+			panic(err)
+		}
+	}
+
+	// Initialize geocoder
+	var geocoder geodom.Geocoder
+	geoProvider, geoFixturesPath := envutils.BuildGeoProviderConfig()
+	switch geoProvider {
+	case "offline":
+		geocoder, err = geoinfra.NewOfflineGeocoder(startCtx, geoFixturesPath)
+		if err != nil {
+			l.Error("failed to initialize offline geocoder", "error", err.Error())
+			panic(err)
+		}
+	default:
+		// Initialize geo client options
+		clientOpts := geocl.NewDefaultClientOption()
+		clientOpts.Caller = "stores-service-geo-client"
+
+		// Initialize geo client
+		gc, err := geocl.NewClient(startCtx, clientOpts)
+		if err != nil {
+			l.Error("failed to initialize geo client", "error", err.Error())
+			panic(err)
+		}
+
+		// Wrap geo client with caching, retries & circuit breaker
+		rgc, err := geoinfra.NewResilientClient(startCtx, gc, envutils.BuildGeoResilienceConfig())
+		if err != nil {
+			l.Error("failed to initialize resilient geo client", "error", err.Error())
+			panic(err)
+		}
+
+		geocoder, err = geoinfra.NewComffGeocoder(rgc)
+		if err != nil {
+			l.Error("failed to initialize geocoder", "error", err.Error())
+			panic(err)
+		}
+	}
+	l.Info("geocoder initialized", "provider", geoProvider)
 
-	// Initialize geo client options
-	clientOpts := geocl.NewDefaultClientOption()
-	clientOpts.Caller = "stores-service-geo-client"
+	// Initialize routing provider
+	var router geodom.RoutingProvider
+	routingProvider, routingMatrixPath := envutils.BuildRoutingConfig()
+	switch routingProvider {
+	case "matrix":
+		router, err = routing.NewMatrixRouter(startCtx, routingMatrixPath)
+		if err != nil {
+			l.Error("failed to initialize matrix router", "error", err.Error())
+			panic(err)
+		}
+	default:
+		router = routing.NewHaversineRouter(routing.HaversineOptions{})
+	}
+	l.Info("routing provider initialized", "provider", routingProvider)
+
+	// Load capability catalog
+	catalog, err := capabilities.LoadCatalog(startCtx, envutils.BuildCapabilityCatalogConfig())
+	if err != nil {
+		l.Error("failed to load capability catalog", "error", err.Error())
+		panic(err)
+	}
 
-	// Initialize geo client
-	gc, err := geocl.NewClient(startCtx, clientOpts)
+	// Load store validation rules
+	rules, err := validation.LoadRules(startCtx, envutils.BuildValidationRulesConfig())
 	if err != nil {
-		l.Error("failed to initialize geo client", "error", err.Error())
+		l.Error("failed to load store validation rules", "error", err.Error())
+		panic(err)
+	}
+
+	// Initialize blob storage for attachment uploads, disabled with none
+	var blobStore blobdom.BlobStore
+	blobProvider, blobDir, blobBaseURL := envutils.BuildBlobStoreConfig()
+	switch blobProvider {
+	case "none":
+		l.Info("blob storage disabled, attachment uploads unavailable")
+	case "local":
+		blobStore, err = blobs.NewLocalBlobStore(startCtx, blobs.LocalOptions{Dir: blobDir, BaseURL: blobBaseURL})
+		if err != nil {
+			l.Error("failed to initialize local blob store", "error", err.Error())
+			panic(err)
+		}
+	default:
+		err := fmt.Errorf("unknown blob store: %s", blobProvider)
+		l.Error("failed to initialize blob store", "error", err.Error())
 		panic(err)
 	}
+	l.Info("blob store initialized", "provider", blobProvider)
 
 	// Initialize stores service
-	ss, err := stores.NewStoresService(startCtx, sr, gc, metrics)
+	ss, err := stores.NewStoresService(startCtx, sr, geocoder, metrics, stores.StoresServiceOptions{
+		Routing:         router,
+		Capabilities:    catalog,
+		Blobs:           blobStore,
+		ValidationRules: rules,
+	})
 	if err != nil {
 		l.Error("failed to initialize stores service", "error", err.Error())
 		panic(err)
 	}
 
+	// Start status scheduler, closing & reopening stores on their closures' schedule
+	scheduler, err := relay.NewStatusScheduler(startCtx, ss, relay.DefaultSchedulerOptions())
+	if err != nil {
+		l.Error("failed to initialize status scheduler", "error", err.Error())
+		panic(err)
+	}
+	if err := scheduler.Start(workerCtx); err != nil {
+		l.Error("failed to start status scheduler", "error", err.Error())
+		panic(err)
+	}
+	workers = append(workers, scheduler)
+
 	// Build gRPC server config
-	cfg, err := grpchandler.BuildServerConfig(startCtx, ss)
+	cfg, err := grpchandler.BuildServerConfig(startCtx, ss, ws)
 	if err != nil {
 		l.Error("failed to build gRPC server config", "error", err.Error())
 		panic(err)
 	}
+	if ir != nil {
+		cfg.IdempotencyRepo = ir
+		cfg.IdempotencyTTL = envutils.BuildIdempotencyConfig()
+	}
 
 	srvTLSCfg := envutils.BuildServerTLSConfig()
 
@@ -196,12 +447,22 @@ func main() {
 		l.Error("failed to shut down stores metrics server", "error", err.Error())
 	}
 
+	for _, w := range workers {
+		if err := w.Stop(shutdownCtx); err != nil {
+			l.Error("error stopping background worker", "error", err.Error())
+		}
+	}
+
+	if err := pub.Close(shutdownCtx); err != nil {
+		l.Error("error closing outbox event publisher", "error", err.Error())
+	}
+
 	if err = sr.Close(shutdownCtx); err != nil {
 		l.Error("error closing stores repository", "error", err.Error())
 	}
 
-	if err := gc.Close(shutdownCtx); err != nil {
-		l.Error("error closing geo client", "error", err.Error())
+	if err := geocoder.Close(shutdownCtx); err != nil {
+		l.Error("error closing geocoder", "error", err.Error())
 	}
 
 	<-shutdownCtx.Done()
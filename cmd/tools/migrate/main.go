@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/comfforts/logger"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+	"github.com/comfforts/comff-stores/internal/infra/mongostore"
+	mgrepo "github.com/comfforts/comff-stores/internal/repo/migrations"
+	strepo "github.com/comfforts/comff-stores/internal/repo/stores"
+	envutils "github.com/comfforts/comff-stores/pkg/utils/environ"
+)
+
+const usage = `usage: migrate <command> [flags]
+
+commands:
+  status          list migrations & whether they're applied
+  up [-to N]      apply pending migrations, up to version N when set
+  down [-steps N] revert the latest N applied migrations (default 1)
+
+flags:
+  -address-uniqueness  store address uniqueness rule the address indexes enforce,
+                       global, org or none, defaults to STORES_ADDRESS_UNIQUENESS
+
+MongoDB is configured with the server's MONGO_* environment variables.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	uniqueness := envutils.BuildAddressUniquenessConfig()
+	statusFlags := flag.NewFlagSet("status", flag.ExitOnError)
+	statusFlags.StringVar(&uniqueness, "address-uniqueness", uniqueness, "store address uniqueness rule, global, org or none")
+	upFlags := flag.NewFlagSet("up", flag.ExitOnError)
+	to := upFlags.Int("to", 0, "apply migrations up to this version, all when 0")
+	upFlags.StringVar(&uniqueness, "address-uniqueness", uniqueness, "store address uniqueness rule, global, org or none")
+	downFlags := flag.NewFlagSet("down", flag.ExitOnError)
+	steps := downFlags.Int("steps", 1, "number of applied migrations to revert")
+	downFlags.StringVar(&uniqueness, "address-uniqueness", uniqueness, "store address uniqueness rule, global, org or none")
+
+	cmd := os.Args[1]
+	switch cmd {
+	case "status":
+		_ = statusFlags.Parse(os.Args[2:])
+	case "up":
+		_ = upFlags.Parse(os.Args[2:])
+	case "down":
+		_ = downFlags.Parse(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if cmd == "down" && *steps < 1 {
+		fmt.Fprintf(os.Stderr, "invalid steps %d, must be at least 1\n", *steps)
+		os.Exit(2)
+	}
+
+	uniq := stdom.AddressUniqueness(uniqueness)
+	if !uniq.Valid() {
+		fmt.Fprintf(os.Stderr, "invalid address uniqueness rule %q\n", uniqueness)
+		os.Exit(2)
+	}
+
+	l := logger.GetSlogLogger()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	ms, err := mongostore.NewMongoStore(ctx, envutils.BuildMongoStoreConfig(true))
+	if err != nil {
+		l.Error("error connecting to mongo", "error", err.Error())
+		os.Exit(1)
+	}
+	defer func() {
+		if err := ms.Close(ctx); err != nil {
+			l.Error("error closing mongo store", "error", err.Error())
+		}
+	}()
+
+	mg, err := mgrepo.NewMigrator(ctx, ms, strepo.Migrations(uniq), mgrepo.MigratorOptions{})
+	if err != nil {
+		l.Error("error initializing migrator", "error", err.Error())
+		os.Exit(1)
+	}
+
+	switch cmd {
+	case "status":
+		statuses, err := mg.Status(ctx)
+		if err != nil {
+			l.Error("error getting migrations status", "error", err.Error())
+			os.Exit(1)
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, st := range statuses {
+			state, appliedAt := "pending", ""
+			if st.Applied {
+				state, appliedAt = "applied", st.AppliedAt.Format(time.RFC3339)
+			}
+			if st.Unknown {
+				state = "applied (unknown)"
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", st.Version, st.Name, state, appliedAt)
+		}
+		_ = tw.Flush()
+	case "up":
+		applied, err := mg.Up(ctx, *to)
+		if err != nil {
+			l.Error("error applying migrations", "error", err.Error(), "applied", applied)
+			os.Exit(1)
+		}
+		fmt.Printf("applied %d migrations %v\n", len(applied), applied)
+	case "down":
+		reverted, err := mg.Down(ctx, *steps)
+		if err != nil {
+			l.Error("error reverting migrations", "error", err.Error(), "reverted", reverted)
+			os.Exit(1)
+		}
+		fmt.Printf("reverted %d migrations %v\n", len(reverted), reverted)
+	}
+}
@@ -2,6 +2,7 @@ package grpchandler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -26,8 +27,11 @@ import (
 	"github.com/comfforts/logger"
 
 	api "github.com/comfforts/comff-stores/api/stores/v1"
+	iddom "github.com/comfforts/comff-stores/internal/domain/idempotency"
 	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+	whdom "github.com/comfforts/comff-stores/internal/domain/webhooks"
 	"github.com/comfforts/comff-stores/internal/infra/observability"
+	"github.com/comfforts/comff-stores/internal/usecase/services/stores"
 )
 
 var _ api.StoresServer = (*grpcServer)(nil)
@@ -39,6 +43,20 @@ const (
 	updateStoreAction  = "update-store"
 	deleteStoreAction  = "delete-store"
 	searchStoresAction = "search-stores"
+
+	getStoreAddressHistoryAction = "get-store-address-history"
+	getStoreStatsAction          = "get-store-stats"
+	clusterStoresAction          = "cluster-stores"
+	findServingStoresAction      = "find-serving-stores"
+	listChildStoresAction        = "list-child-stores"
+	getStoreAncestorsAction      = "get-store-ancestors"
+	getCapabilityCatalogAction   = "get-capability-catalog"
+	scheduleClosureAction        = "schedule-closure"
+	cancelClosureAction          = "cancel-closure"
+	addAttachmentAction          = "add-attachment"
+	removeAttachmentAction       = "remove-attachment"
+	reorderAttachmentsAction     = "reorder-attachments"
+	uploadAttachmentAction       = "upload-attachment"
 )
 
 const (
@@ -47,6 +65,20 @@ const (
 	ERR_UNAUTHORIZED_UPDATE_STORE  = "unauthorized to update store"
 	ERR_UNAUTHORIZED_DELETE_STORE  = "unauthorized to delete store"
 	ERR_UNAUTHORIZED_SEARCH_STORES = "unauthorized to search stores"
+
+	ERR_UNAUTHORIZED_GET_STORE_ADDRESS_HISTORY = "unauthorized to get store address history"
+	ERR_UNAUTHORIZED_GET_STORE_STATS           = "unauthorized to get store stats"
+	ERR_UNAUTHORIZED_CLUSTER_STORES            = "unauthorized to cluster stores"
+	ERR_UNAUTHORIZED_FIND_SERVING_STORES       = "unauthorized to find serving stores"
+	ERR_UNAUTHORIZED_LIST_CHILD_STORES         = "unauthorized to list child stores"
+	ERR_UNAUTHORIZED_GET_STORE_ANCESTORS       = "unauthorized to get store ancestors"
+	ERR_UNAUTHORIZED_GET_CAPABILITY_CATALOG    = "unauthorized to get capability catalog"
+	ERR_UNAUTHORIZED_SCHEDULE_CLOSURE          = "unauthorized to schedule store closure"
+	ERR_UNAUTHORIZED_CANCEL_CLOSURE            = "unauthorized to cancel store closure"
+	ERR_UNAUTHORIZED_ADD_ATTACHMENT            = "unauthorized to add store attachment"
+	ERR_UNAUTHORIZED_REMOVE_ATTACHMENT         = "unauthorized to remove store attachment"
+	ERR_UNAUTHORIZED_REORDER_ATTACHMENTS       = "unauthorized to reorder store attachments"
+	ERR_UNAUTHORIZED_UPLOAD_ATTACHMENT         = "unauthorized to upload store attachment"
 )
 
 type subjectContextKey struct{}
@@ -63,9 +95,13 @@ type Authorizer interface {
 type Config struct {
 	Authorizer Authorizer
 	stdom.StoresService
+	WebhooksService whdom.WebhooksService
+	// IdempotencyRepo, when set, enables idempotency keys on mutating RPCs.
+	IdempotencyRepo iddom.IdempotencyRepo
+	IdempotencyTTL  time.Duration
 }
 
-func BuildServerConfig(ctx context.Context, ss stdom.StoresService) (*Config, error) {
+func BuildServerConfig(ctx context.Context, ss stdom.StoresService, ws whdom.WebhooksService) (*Config, error) {
 	// Initialize the authorizer for the geo service
 	authorizer, err := config.SetupAuthorizer()
 	if err != nil {
@@ -73,8 +109,9 @@ func BuildServerConfig(ctx context.Context, ss stdom.StoresService) (*Config, er
 	}
 
 	servCfg := &Config{
-		StoresService: ss,
-		Authorizer:    authorizer,
+		StoresService:   ss,
+		WebhooksService: ws,
+		Authorizer:      authorizer,
 	}
 	return servCfg, nil
 }
@@ -111,22 +148,28 @@ func NewGRPCServer(config *Config, opts ...grpc.ServerOption) (*grpc.Server, err
 		return nil, err
 	}
 
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		grpc_ctxtags.UnaryServerInterceptor(),
+		grpc_auth.UnaryServerInterceptor(authenticate),
+		grpc_auth.UnaryServerInterceptor(decorateContext),
+		grpc_auth.UnaryServerInterceptor(metadataLogger),
+		UnaryLoggingInterceptor(),
+		UnaryMetricsInterceptor(srv.metrics),
+	}
+	if config.IdempotencyRepo != nil {
+		unaryInterceptors = append(unaryInterceptors, UnaryIdempotencyInterceptor(config.IdempotencyRepo, config.IdempotencyTTL))
+	}
+
 	opts = append(opts,
 		grpc.StreamInterceptor(
 			grpc_middleware.ChainStreamServer(
 				grpc_auth.StreamServerInterceptor(authenticate),
 				grpc_auth.StreamServerInterceptor(decorateContext),
+				StreamIdempotencyInterceptor(),
 			),
 		),
 		grpc.UnaryInterceptor(
-			grpc_middleware.ChainUnaryServer(
-				grpc_ctxtags.UnaryServerInterceptor(),
-				grpc_auth.UnaryServerInterceptor(authenticate),
-				grpc_auth.UnaryServerInterceptor(decorateContext),
-				grpc_auth.UnaryServerInterceptor(metadataLogger),
-				UnaryLoggingInterceptor(),
-				UnaryMetricsInterceptor(srv.metrics),
-			),
+			grpc_middleware.ChainUnaryServer(unaryInterceptors...),
 		),
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 	)
@@ -171,6 +214,33 @@ func (s *grpcServer) AddStore(ctx context.Context, req *api.AddStoreRequest) (*a
 	storeID, err := s.StoresService.AddStore(ctx, params)
 	if err != nil {
 		l.Error("error adding store", "error", err.Error())
+		if st, ok := validationErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := geoErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := duplicateErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := storeStatusErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := serviceAreaErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := hierarchyErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := capabilityErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := localeErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := conflictErrorStatus(err); ok {
+			return nil, st.Err()
+		}
 		st := status.New(codes.Internal, "error adding store")
 		return nil, st.Err()
 	}
@@ -203,9 +273,18 @@ func (s *grpcServer) GetStore(ctx context.Context, req *api.GetStoreRequest) (*a
 		return nil, st.Err()
 	}
 
-	store, err := s.StoresService.GetStore(ctx, req.GetId())
+	opts := stdom.MapToGetStoreOptions(req)
+	if opts.Locales, err = preferredLocales(ctx, req.GetLocale()); err != nil {
+		st, _ := localeErrorStatus(err)
+		return nil, st.Err()
+	}
+
+	store, err := s.StoresService.GetStore(ctx, req.GetId(), opts)
 	if err != nil {
 		l.Error("error getting store", "error", err.Error(), "store_id", req.GetId())
+		if st, ok := geoErrorStatus(err); ok {
+			return nil, st.Err()
+		}
 		st := status.New(codes.Internal, "error getting store")
 		return nil, st.Err()
 	}
@@ -242,6 +321,30 @@ func (s *grpcServer) UpdateStore(ctx context.Context, req *api.UpdateStoreReques
 	err = s.StoresService.UpdateStore(ctx, req.GetId(), params)
 	if err != nil {
 		l.Error("error updating store", "error", err.Error(), "store_id", req.GetId())
+		if st, ok := validationErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := duplicateErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := storeStatusErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := serviceAreaErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := hierarchyErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := capabilityErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := localeErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := conflictErrorStatus(err); ok {
+			return nil, st.Err()
+		}
 		st := status.New(codes.Internal, "error updating store")
 		return nil, st.Err()
 	}
@@ -276,6 +379,12 @@ func (s *grpcServer) DeleteStore(ctx context.Context, req *api.DeleteStoreReques
 	err = s.StoresService.DeleteStore(ctx, req.GetId())
 	if err != nil {
 		l.Error("error deleting store", "error", err.Error(), "store_id", req.GetId())
+		if st, ok := hierarchyErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := conflictErrorStatus(err); ok {
+			return nil, st.Err()
+		}
 		st := status.New(codes.Internal, "error deleting store")
 		return nil, st.Err()
 	}
@@ -307,26 +416,758 @@ func (s *grpcServer) SearchStore(ctx context.Context, req *api.SearchStoreReques
 		return nil, st.Err()
 	}
 	params := stdom.MapToSearchStoreParams(req)
+	if params.Locales, err = preferredLocales(ctx, req.GetLocale()); err != nil {
+		st, _ := localeErrorStatus(err)
+		return nil, st.Err()
+	}
 
-	stores, err := s.StoresService.SearchStores(ctx, params)
+	result, err := s.StoresService.SearchStores(ctx, params)
 	if err != nil {
 		l.Error("error searching stores", "error", err.Error())
+		if st, ok := geoErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := searchErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := hierarchyErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := capabilityErrorStatus(err); ok {
+			return nil, st.Err()
+		}
 		st := status.New(codes.Internal, "error searching stores")
 		return nil, st.Err()
 	}
 
 	var storeGeoProtos []*api.StoreGeo
-	for _, st := range stores {
-		storeGeoProtos = append(storeGeoProtos, &api.StoreGeo{
+	for _, st := range result.Stores {
+		stGeo := &api.StoreGeo{
 			Store: stdom.MapToStoreProto(st),
-		})
+		}
+		if req.GetQuery() != "" || req.GetFuzzy() {
+			stGeo.Score = &st.Score
+		}
+		if req.GetKNearest() > 0 {
+			setStoreDistance(stGeo, st)
+		}
+		storeGeoProtos = append(storeGeoProtos, stGeo)
 	}
 
 	return &api.SearchStoreResponse{
 		Stores: storeGeoProtos,
+		Total:  uint32(result.Total),
+		Facets: stdom.MapToFacetProtos(result.Facets),
 	}, nil
 }
 
+func (s *grpcServer) GetStoreAddressHistory(ctx context.Context, req *api.GetStoreAddressHistoryRequest) (*api.GetStoreAddressHistoryResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		getStoreAddressHistoryAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_GET_STORE_ADDRESS_HISTORY)
+		return nil, st.Err()
+	}
+
+	if req == nil || req.GetId() == "" {
+		l.Error("GetStoreAddressHistory called with invalid request: missing store ID")
+		st := status.New(codes.InvalidArgument, "store ID is required")
+		return nil, st.Err()
+	}
+
+	changes, err := s.StoresService.GetAddressHistory(ctx, req.GetId())
+	if err != nil {
+		l.Error("error getting store address history", "error", err.Error(), "store_id", req.GetId())
+		st := status.New(codes.Internal, "error getting store address history")
+		return nil, st.Err()
+	}
+
+	changeProtos := []*api.AddressChange{}
+	for _, ch := range changes {
+		changeProtos = append(changeProtos, stdom.MapToAddressChangeProto(ch))
+	}
+
+	return &api.GetStoreAddressHistoryResponse{
+		Changes: changeProtos,
+	}, nil
+}
+
+func (s *grpcServer) GetStoreStats(ctx context.Context, req *api.GetStoreStatsRequest) (*api.GetStoreStatsResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		getStoreStatsAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_GET_STORE_STATS)
+		return nil, st.Err()
+	}
+
+	if req == nil {
+		l.Error("GetStoreStats called with nil request")
+		st := status.New(codes.InvalidArgument, "request cannot be nil")
+		return nil, st.Err()
+	}
+
+	stats, err := s.StoresService.GetStoreStats(ctx, stdom.MapToStoreStatsParams(req))
+	if err != nil {
+		l.Error("error getting store stats", "error", err.Error())
+		if errors.Is(err, stores.ErrInvalidStats) {
+			st := status.New(codes.InvalidArgument, err.Error())
+			return nil, st.Err()
+		}
+		st := status.New(codes.Internal, "error getting store stats")
+		return nil, st.Err()
+	}
+
+	return stdom.MapToStoreStatsResponse(stats), nil
+}
+
+func (s *grpcServer) ClusterStores(ctx context.Context, req *api.ClusterStoresRequest) (*api.ClusterStoresResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		clusterStoresAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_CLUSTER_STORES)
+		return nil, st.Err()
+	}
+
+	if req == nil || req.GetBbox() == nil {
+		l.Error("ClusterStores called with invalid request: missing bounding box")
+		st := status.New(codes.InvalidArgument, "bounding box is required")
+		return nil, st.Err()
+	}
+
+	clusters, err := s.StoresService.ClusterStores(ctx, stdom.MapToClusterStoresParams(req))
+	if err != nil {
+		l.Error("error clustering stores", "error", err.Error())
+		if errors.Is(err, stores.ErrInvalidBounds) || errors.Is(err, stores.ErrInvalidZoom) {
+			st := status.New(codes.InvalidArgument, err.Error())
+			return nil, st.Err()
+		}
+		st := status.New(codes.Internal, "error clustering stores")
+		return nil, st.Err()
+	}
+
+	clusterProtos := []*api.StoreCluster{}
+	for _, c := range clusters {
+		clusterProtos = append(clusterProtos, stdom.MapToStoreClusterProto(c))
+	}
+
+	return &api.ClusterStoresResponse{
+		Clusters: clusterProtos,
+	}, nil
+}
+
+func (s *grpcServer) FindServingStores(ctx context.Context, req *api.FindServingStoresRequest) (*api.FindServingStoresResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		findServingStoresAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_FIND_SERVING_STORES)
+		return nil, st.Err()
+	}
+
+	if req == nil || (req.GetAddressId() == "" && req.GetAddressStr() == "" && (req.GetLatitude() == 0 || req.GetLongitude() == 0)) {
+		l.Error("FindServingStores called with invalid request: missing address or point")
+		st := status.New(codes.InvalidArgument, "address or point is required")
+		return nil, st.Err()
+	}
+
+	params := stdom.MapToFindServingStoresParams(req)
+	if params.Locales, err = preferredLocales(ctx, req.GetLocale()); err != nil {
+		st, _ := localeErrorStatus(err)
+		return nil, st.Err()
+	}
+
+	storesList, err := s.StoresService.FindServingStores(ctx, params)
+	if err != nil {
+		l.Error("error finding serving stores", "error", err.Error())
+		if st, ok := geoErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := searchErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := capabilityErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		st := status.New(codes.Internal, "error finding serving stores")
+		return nil, st.Err()
+	}
+
+	storeGeoProtos := []*api.StoreGeo{}
+	for _, st := range storesList {
+		stGeo := &api.StoreGeo{
+			Store: stdom.MapToStoreProto(st),
+		}
+		setStoreDistance(stGeo, st)
+		storeGeoProtos = append(storeGeoProtos, stGeo)
+	}
+
+	return &api.FindServingStoresResponse{
+		Stores: storeGeoProtos,
+	}, nil
+}
+
+func (s *grpcServer) ListChildStores(ctx context.Context, req *api.ListChildStoresRequest) (*api.ListChildStoresResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		listChildStoresAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_LIST_CHILD_STORES)
+		return nil, st.Err()
+	}
+
+	if req == nil || req.GetId() == "" {
+		l.Error("ListChildStores called with invalid request: missing store ID")
+		st := status.New(codes.InvalidArgument, "store ID is required")
+		return nil, st.Err()
+	}
+
+	locales, err := preferredLocales(ctx, req.GetLocale())
+	if err != nil {
+		st, _ := localeErrorStatus(err)
+		return nil, st.Err()
+	}
+
+	children, err := s.StoresService.ListChildStores(ctx, req.GetId(), &stdom.GetStoreOptions{
+		IncludeAddress: req.GetIncludeAddress(),
+		Locales:        locales,
+	})
+	if err != nil {
+		l.Error("error listing child stores", "error", err.Error(), "store_id", req.GetId())
+		if st, ok := geoErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		st := status.New(codes.Internal, "error listing child stores")
+		return nil, st.Err()
+	}
+
+	storeProtos := []*api.Store{}
+	for _, st := range children {
+		storeProtos = append(storeProtos, stdom.MapToStoreProto(st))
+	}
+
+	return &api.ListChildStoresResponse{
+		Stores: storeProtos,
+	}, nil
+}
+
+func (s *grpcServer) GetStoreAncestors(ctx context.Context, req *api.GetStoreAncestorsRequest) (*api.GetStoreAncestorsResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		getStoreAncestorsAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_GET_STORE_ANCESTORS)
+		return nil, st.Err()
+	}
+
+	if req == nil || req.GetId() == "" {
+		l.Error("GetStoreAncestors called with invalid request: missing store ID")
+		st := status.New(codes.InvalidArgument, "store ID is required")
+		return nil, st.Err()
+	}
+
+	ancestors, err := s.StoresService.GetStoreAncestors(ctx, req.GetId())
+	if err != nil {
+		l.Error("error getting store ancestors", "error", err.Error(), "store_id", req.GetId())
+		st := status.New(codes.Internal, "error getting store ancestors")
+		return nil, st.Err()
+	}
+
+	storeProtos := []*api.Store{}
+	for _, st := range ancestors {
+		storeProtos = append(storeProtos, stdom.MapToStoreProto(st))
+	}
+
+	return &api.GetStoreAncestorsResponse{
+		Stores: storeProtos,
+	}, nil
+}
+
+func (s *grpcServer) GetCapabilityCatalog(ctx context.Context, req *api.GetCapabilityCatalogRequest) (*api.GetCapabilityCatalogResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		getCapabilityCatalogAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_GET_CAPABILITY_CATALOG)
+		return nil, st.Err()
+	}
+
+	catalog, err := s.StoresService.GetCapabilityCatalog(ctx)
+	if err != nil {
+		l.Error("error getting capability catalog", "error", err.Error())
+		st := status.New(codes.Internal, "error getting capability catalog")
+		return nil, st.Err()
+	}
+
+	return stdom.MapToCapabilityCatalogProto(catalog), nil
+}
+
+func (s *grpcServer) ScheduleClosure(ctx context.Context, req *api.ScheduleClosureRequest) (*api.ScheduleClosureResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		scheduleClosureAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_SCHEDULE_CLOSURE)
+		return nil, st.Err()
+	}
+
+	if req == nil || req.GetStoreId() == "" {
+		l.Error("ScheduleClosure called with invalid request: missing store ID")
+		st := status.New(codes.InvalidArgument, "store ID is required")
+		return nil, st.Err()
+	}
+
+	closure, err := s.StoresService.ScheduleClosure(ctx, req.GetStoreId(), stdom.MapToScheduleClosureParams(req))
+	if err != nil {
+		l.Error("error scheduling store closure", "error", err.Error(), "store_id", req.GetStoreId())
+		if st, ok := closureErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := conflictErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		st := status.New(codes.Internal, "error scheduling store closure")
+		return nil, st.Err()
+	}
+
+	return &api.ScheduleClosureResponse{
+		Closure: stdom.MapToStoreClosureProto(closure),
+	}, nil
+}
+
+func (s *grpcServer) CancelClosure(ctx context.Context, req *api.CancelClosureRequest) (*api.CancelClosureResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		cancelClosureAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_CANCEL_CLOSURE)
+		return nil, st.Err()
+	}
+
+	if req == nil || req.GetStoreId() == "" || req.GetClosureId() == "" {
+		l.Error("CancelClosure called with invalid request: missing store or closure ID")
+		st := status.New(codes.InvalidArgument, "store ID and closure ID are required")
+		return nil, st.Err()
+	}
+
+	if err := s.StoresService.CancelClosure(ctx, req.GetStoreId(), req.GetClosureId()); err != nil {
+		l.Error("error canceling store closure", "error", err.Error(), "store_id", req.GetStoreId(), "closure_id", req.GetClosureId())
+		if st, ok := closureErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := conflictErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		st := status.New(codes.Internal, "error canceling store closure")
+		return nil, st.Err()
+	}
+
+	return &api.CancelClosureResponse{
+		Ok: true,
+	}, nil
+}
+
+func (s *grpcServer) AddAttachment(ctx context.Context, req *api.AddAttachmentRequest) (*api.AddAttachmentResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		addAttachmentAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_ADD_ATTACHMENT)
+		return nil, st.Err()
+	}
+
+	if req == nil || req.GetStoreId() == "" || req.GetUri() == "" {
+		l.Error("AddAttachment called with invalid request: missing store ID or URI")
+		st := status.New(codes.InvalidArgument, "store ID and URI are required")
+		return nil, st.Err()
+	}
+
+	attachment, err := s.StoresService.AddAttachment(ctx, req.GetStoreId(), stdom.MapToAddAttachmentParams(req))
+	if err != nil {
+		l.Error("error adding store attachment", "error", err.Error(), "store_id", req.GetStoreId())
+		if st, ok := attachmentErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := conflictErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		st := status.New(codes.Internal, "error adding store attachment")
+		return nil, st.Err()
+	}
+
+	return &api.AddAttachmentResponse{
+		Attachment: stdom.MapToStoreAttachmentProto(attachment),
+	}, nil
+}
+
+func (s *grpcServer) RemoveAttachment(ctx context.Context, req *api.RemoveAttachmentRequest) (*api.RemoveAttachmentResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		removeAttachmentAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_REMOVE_ATTACHMENT)
+		return nil, st.Err()
+	}
+
+	if req == nil || req.GetStoreId() == "" || req.GetAttachmentId() == "" {
+		l.Error("RemoveAttachment called with invalid request: missing store or attachment ID")
+		st := status.New(codes.InvalidArgument, "store ID and attachment ID are required")
+		return nil, st.Err()
+	}
+
+	if err := s.StoresService.RemoveAttachment(ctx, req.GetStoreId(), req.GetAttachmentId()); err != nil {
+		l.Error("error removing store attachment", "error", err.Error(), "store_id", req.GetStoreId(), "attachment_id", req.GetAttachmentId())
+		if st, ok := attachmentErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := conflictErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		st := status.New(codes.Internal, "error removing store attachment")
+		return nil, st.Err()
+	}
+
+	return &api.RemoveAttachmentResponse{
+		Ok: true,
+	}, nil
+}
+
+func (s *grpcServer) ReorderAttachments(ctx context.Context, req *api.ReorderAttachmentsRequest) (*api.ReorderAttachmentsResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		reorderAttachmentsAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_REORDER_ATTACHMENTS)
+		return nil, st.Err()
+	}
+
+	if req == nil || req.GetStoreId() == "" || len(req.GetAttachmentIds()) == 0 {
+		l.Error("ReorderAttachments called with invalid request: missing store or attachment IDs")
+		st := status.New(codes.InvalidArgument, "store ID and attachment IDs are required")
+		return nil, st.Err()
+	}
+
+	attachments, err := s.StoresService.ReorderAttachments(ctx, req.GetStoreId(), req.GetAttachmentIds())
+	if err != nil {
+		l.Error("error reordering store attachments", "error", err.Error(), "store_id", req.GetStoreId())
+		if st, ok := attachmentErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		if st, ok := conflictErrorStatus(err); ok {
+			return nil, st.Err()
+		}
+		st := status.New(codes.Internal, "error reordering store attachments")
+		return nil, st.Err()
+	}
+
+	return &api.ReorderAttachmentsResponse{
+		Attachments: stdom.MapToStoreAttachmentProtos(attachments),
+	}, nil
+}
+
+// UploadAttachment reads the upload's info from the first message, then streams the
+// chunks after it to the service as the attachment's content.
+func (s *grpcServer) UploadAttachment(stream grpc.ClientStreamingServer[api.UploadAttachmentRequest, api.UploadAttachmentResponse]) error {
+	ctx := stream.Context()
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		uploadAttachmentAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_UPLOAD_ATTACHMENT)
+		return st.Err()
+	}
+
+	req, err := stream.Recv()
+	if err != nil {
+		l.Error("error receiving attachment upload info", "error", err.Error())
+		return err
+	}
+	info := req.GetInfo()
+	if info == nil || info.GetStoreId() == "" {
+		l.Error("UploadAttachment called with invalid request: missing upload info")
+		st := status.New(codes.InvalidArgument, "upload info with store ID is required first")
+		return st.Err()
+	}
+
+	attachment, err := s.StoresService.UploadAttachment(
+		ctx,
+		info.GetStoreId(),
+		&stdom.UploadAttachmentParams{Kind: stdom.AttachmentKind(info.GetKind())},
+		&uploadReader{stream: stream},
+	)
+	if err != nil {
+		l.Error("error uploading store attachment", "error", err.Error(), "store_id", info.GetStoreId())
+		if st, ok := attachmentErrorStatus(err); ok {
+			return st.Err()
+		}
+		if st, ok := conflictErrorStatus(err); ok {
+			return st.Err()
+		}
+		if _, ok := status.FromError(err); ok {
+			// the stream's own errors, e.g. a canceled upload
+			return err
+		}
+		st := status.New(codes.Internal, "error uploading store attachment")
+		return st.Err()
+	}
+
+	return stream.SendAndClose(&api.UploadAttachmentResponse{
+		Attachment: stdom.MapToStoreAttachmentProto(attachment),
+	})
+}
+
+// uploadReader reads an attachment upload's content from its stream's chunks.
+type uploadReader struct {
+	stream grpc.ClientStreamingServer[api.UploadAttachmentRequest, api.UploadAttachmentResponse]
+	chunk  []byte
+}
+
+func (ur *uploadReader) Read(p []byte) (int, error) {
+	for len(ur.chunk) == 0 {
+		req, err := ur.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if req.GetInfo() != nil {
+			return 0, status.New(codes.InvalidArgument, "upload info is only sent first").Err()
+		}
+		ur.chunk = req.GetChunk()
+	}
+	n := copy(p, ur.chunk)
+	ur.chunk = ur.chunk[n:]
+	return n, nil
+}
+
+// setStoreDistance sets the store's distance, the road distance & eta when it was routed.
+func setStoreDistance(stGeo *api.StoreGeo, st *stdom.Store) {
+	distance := float32(st.Distance)
+	if st.Route != nil {
+		distance = float32(st.Route.DistanceMeters)
+		eta := uint32(st.Route.Duration.Seconds())
+		stGeo.EtaSeconds = &eta
+	}
+	stGeo.Distance = &distance
+}
+
+// geoErrorStatus maps geo lookup errors, telling an unavailable geo service,
+// worth retrying, apart from an address geo rejected.
+func geoErrorStatus(err error) (*status.Status, bool) {
+	switch {
+	case errors.Is(err, stores.ErrGeoUnavailable):
+		return status.New(codes.Unavailable, err.Error()), true
+	case errors.Is(err, stores.ErrInvalidAddressId),
+		errors.Is(err, stores.ErrInvalidAddressStr),
+		errors.Is(err, stores.ErrInvalidLatLon):
+		return status.New(codes.InvalidArgument, err.Error()), true
+	}
+	return nil, false
+}
+
+// validationErrorStatus maps stores breaking the validation rules to InvalidArgument,
+// detailed with every field violation.
+func validationErrorStatus(err error) (*status.Status, bool) {
+	var ve *stores.ValidationError
+	if !errors.As(err, &ve) {
+		return nil, false
+	}
+	st := status.New(codes.InvalidArgument, err.Error())
+	if detailed, err := st.WithDetails(stdom.MapToStoreValidationErrorProto(ve.Violations)); err == nil {
+		return detailed, true
+	}
+	return st, true
+}
+
+// searchErrorStatus maps invalid search parameters to InvalidArgument.
+func searchErrorStatus(err error) (*status.Status, bool) {
+	if errors.Is(err, stores.ErrInvalidSearch) || errors.Is(err, stores.ErrInvalidWithin) {
+		return status.New(codes.InvalidArgument, err.Error()), true
+	}
+	return nil, false
+}
+
+// serviceAreaErrorStatus maps an invalid service area to InvalidArgument.
+func serviceAreaErrorStatus(err error) (*status.Status, bool) {
+	if errors.Is(err, stores.ErrInvalidServiceArea) {
+		return status.New(codes.InvalidArgument, err.Error()), true
+	}
+	return nil, false
+}
+
+// hierarchyErrorStatus maps invalid parents & regions to InvalidArgument, and changes
+// to stores with satellites to FailedPrecondition.
+func hierarchyErrorStatus(err error) (*status.Status, bool) {
+	switch {
+	case errors.Is(err, stores.ErrInvalidParent), errors.Is(err, stores.ErrInvalidRegion):
+		return status.New(codes.InvalidArgument, err.Error()), true
+	case errors.Is(err, stores.ErrStoreHasChildren):
+		return status.New(codes.FailedPrecondition, err.Error()), true
+	}
+	return nil, false
+}
+
+// capabilityErrorStatus maps capabilities missing from the catalog, or deprecated, to InvalidArgument.
+func capabilityErrorStatus(err error) (*status.Status, bool) {
+	if errors.Is(err, stores.ErrInvalidCapability) {
+		return status.New(codes.InvalidArgument, err.Error()), true
+	}
+	return nil, false
+}
+
+// storeStatusErrorStatus maps an unknown store status to InvalidArgument.
+func storeStatusErrorStatus(err error) (*status.Status, bool) {
+	if errors.Is(err, stores.ErrInvalidStatus) || errors.Is(err, stores.ErrScheduledStatus) {
+		return status.New(codes.InvalidArgument, err.Error()), true
+	}
+	return nil, false
+}
+
+// closureErrorStatus maps invalid closures to InvalidArgument & unknown closures to NotFound.
+func closureErrorStatus(err error) (*status.Status, bool) {
+	switch {
+	case errors.Is(err, stores.ErrInvalidClosure):
+		return status.New(codes.InvalidArgument, err.Error()), true
+	case errors.Is(err, stores.ErrClosureNotFound):
+		return status.New(codes.NotFound, err.Error()), true
+	}
+	return nil, false
+}
+
+// attachmentErrorStatus maps invalid attachments to InvalidArgument, unknown attachments
+// to NotFound & uploads without blob storage to Unimplemented.
+func attachmentErrorStatus(err error) (*status.Status, bool) {
+	switch {
+	case errors.Is(err, stores.ErrInvalidAttachment):
+		return status.New(codes.InvalidArgument, err.Error()), true
+	case errors.Is(err, stores.ErrAttachmentNotFound):
+		return status.New(codes.NotFound, err.Error()), true
+	case errors.Is(err, stores.ErrBlobsUnavailable):
+		return status.New(codes.Unimplemented, err.Error()), true
+	}
+	return nil, false
+}
+
+// localeErrorStatus maps invalid locales & translations to InvalidArgument.
+func localeErrorStatus(err error) (*status.Status, bool) {
+	if errors.Is(err, stores.ErrInvalidLocale) || errors.Is(err, stores.ErrInvalidTranslation) {
+		return status.New(codes.InvalidArgument, err.Error()), true
+	}
+	return nil, false
+}
+
+// duplicateErrorStatus maps address uniqueness conflicts to AlreadyExists, naming the existing store.
+func duplicateErrorStatus(err error) (*status.Status, bool) {
+	var dupErr *stdom.DuplicateStoreError
+	switch {
+	case errors.As(err, &dupErr):
+		return status.New(codes.AlreadyExists, dupErr.Error()), true
+	case errors.Is(err, stdom.ErrDuplicateStore):
+		return status.New(codes.AlreadyExists, err.Error()), true
+	}
+	return nil, false
+}
+
+// conflictErrorStatus maps updates losing repeated races with other updates of the store
+// to Aborted, for the client to retry.
+func conflictErrorStatus(err error) (*status.Status, bool) {
+	if errors.Is(err, stdom.ErrStoreConflict) {
+		return status.New(codes.Aborted, err.Error()), true
+	}
+	return nil, false
+}
+
 func authenticate(ctx context.Context) (context.Context, error) {
 	peer, ok := peer.FromContext(ctx)
 	if !ok {
@@ -456,6 +1297,25 @@ func splitFullMethod(fullMethod string) (string, string) {
 	return strings.TrimPrefix(service, "/"), method
 }
 
+// preferredLocales returns the caller's preferred locales, the request's locale first,
+// then those of the accept-language metadata.
+func preferredLocales(ctx context.Context, locale string) ([]string, error) {
+	var locales []string
+	if locale != "" {
+		normalized, ok := stdom.NormalizeLocale(locale)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", stores.ErrInvalidLocale, locale)
+		}
+		locales = append(locales, normalized)
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, header := range md.Get(stdom.ACCEPT_LANGUAGE_HEADER) {
+			locales = append(locales, stdom.ParseAcceptLanguage(header)...)
+		}
+	}
+	return locales, nil
+}
+
 func firstMetadataValue(md metadata.MD, key string) string {
 	values := md.Get(key)
 	if len(values) == 0 {
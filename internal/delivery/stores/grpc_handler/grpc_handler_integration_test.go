@@ -22,10 +22,14 @@ import (
 
 	api "github.com/comfforts/comff-stores/api/stores/v1"
 	grpchandler "github.com/comfforts/comff-stores/internal/delivery/stores/grpc_handler"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+	geoinfra "github.com/comfforts/comff-stores/internal/infra/geo"
 	"github.com/comfforts/comff-stores/internal/infra/mongostore"
 	"github.com/comfforts/comff-stores/internal/infra/observability"
 	strepo "github.com/comfforts/comff-stores/internal/repo/stores"
+	whrepo "github.com/comfforts/comff-stores/internal/repo/webhooks"
 	"github.com/comfforts/comff-stores/internal/usecase/services/stores"
+	whsvc "github.com/comfforts/comff-stores/internal/usecase/services/webhooks"
 	envutils "github.com/comfforts/comff-stores/pkg/utils/environ"
 	testutils "github.com/comfforts/comff-stores/pkg/utils/test"
 )
@@ -320,7 +324,13 @@ func newServer(ctx context.Context, addr string) (*grpc.Server, func() error, er
 	}
 
 	// Initialize stores repository
-	sr, err := strepo.NewStoresRepo(ctx, ms, metrics)
+	sr, err := strepo.NewStoresRepo(ctx, ms, metrics, stdom.ADDRESS_UNIQUE_GLOBAL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Initialize webhooks repository
+	wr, err := whrepo.NewWebhooksRepo(ctx, ms)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -352,14 +362,26 @@ func newServer(ctx context.Context, addr string) (*grpc.Server, func() error, er
 		return err
 	}
 
+	// Initialize geocoder
+	geocoder, err := geoinfra.NewComffGeocoder(gc)
+	if err != nil {
+		return nil, closeFn, err
+	}
+
 	// Initialize stores service
-	ss, err := stores.NewStoresService(ctx, sr, gc, metrics)
+	ss, err := stores.NewStoresService(ctx, sr, geocoder, metrics, stores.StoresServiceOptions{})
+	if err != nil {
+		return nil, closeFn, err
+	}
+
+	// Initialize webhooks service
+	ws, err := whsvc.NewWebhooksService(ctx, wr)
 	if err != nil {
 		return nil, closeFn, err
 	}
 
 	// Build gRPC server config
-	cfg, err := grpchandler.BuildServerConfig(ctx, ss)
+	cfg, err := grpchandler.BuildServerConfig(ctx, ss, ws)
 	if err != nil {
 		return nil, closeFn, err
 	}
@@ -0,0 +1,1244 @@
+package grpchandler_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/comfforts/logger"
+
+	api "github.com/comfforts/comff-stores/api/stores/v1"
+	grpchandler "github.com/comfforts/comff-stores/internal/delivery/stores/grpc_handler"
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+	"github.com/comfforts/comff-stores/internal/infra/blobs"
+	"github.com/comfforts/comff-stores/internal/infra/routing"
+	"github.com/comfforts/comff-stores/internal/testharness"
+	testutils "github.com/comfforts/comff-stores/pkg/utils/test"
+)
+
+// in-process tests against the harness stores server, in-memory repo & fake geo server
+
+func TestGRPCHandler_InProcess_Authz(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{
+		Policy: testharness.Policy{
+			testharness.ROOT_SUBJECT:   {testharness.ALL_ACTIONS},
+			testharness.NOBODY_SUBJECT: {"get-store"},
+		},
+	})
+
+	_, err := srv.NobodyClient.AddStore(ctx, &api.AddStoreRequest{
+		Org:       "Test Org",
+		Name:      "Test Store",
+		AddressId: "dacdbddabcadccbdacac",
+	})
+	requireStatus(t, err, codes.Unauthenticated, grpchandler.ERR_UNAUTHORIZED_ADD_STORE)
+
+	asResp, err := srv.Client.AddStore(ctx, &api.AddStoreRequest{
+		Org:       "Test Org",
+		Name:      "Test Store",
+		AddressId: "dacdbddabcadccbdacac",
+	})
+	require.NoError(t, err)
+
+	// nobody is allowed reads by this policy
+	gsResp, err := srv.NobodyClient.GetStore(ctx, &api.GetStoreRequest{Id: asResp.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, "Test Store", gsResp.GetStore().GetName())
+
+	_, err = srv.NobodyClient.DeleteStore(ctx, &api.DeleteStoreRequest{Id: asResp.GetId()})
+	requireStatus(t, err, codes.Unauthenticated, grpchandler.ERR_UNAUTHORIZED_DELETE_STORE)
+
+	// webhooks aren't configured
+	_, err = srv.Client.ListWebhooks(ctx, &api.ListWebhooksRequest{})
+	requireStatus(t, err, codes.Unimplemented, grpchandler.ERR_WEBHOOKS_DISABLED)
+}
+
+func TestGRPCHandler_InProcess_Stores(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{})
+
+	gc, err := srv.Geo.Client(ctx)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, gc.Close(ctx))
+	}()
+
+	dests := append(testutils.BuildPetalumaSet1(), testutils.BuildPetalumaSet2()...)
+	stIds := []string{}
+	for i, dest := range dests {
+		resp, err := gc.GeoLocate(ctx, dest)
+		require.NoError(t, err)
+
+		asResp, err := srv.Client.AddStore(ctx, &api.AddStoreRequest{
+			Org:       fmt.Sprintf("Test Org %d", i%2),
+			Name:      fmt.Sprintf("Test Store %d", i),
+			AddressId: resp.GetPoint().GetHash(),
+		})
+		require.NoError(t, err, dest.GetStreet())
+		stIds = append(stIds, asResp.GetId())
+	}
+
+	gsResp, err := srv.Client.GetStore(ctx, &api.GetStoreRequest{Id: stIds[0]})
+	require.NoError(t, err)
+	require.Equal(t, "Test Store 0", gsResp.GetStore().GetName())
+	require.Equal(t, "dacdbddabcadccbdacac", gsResp.GetStore().GetAddressId())
+
+	// same address, the conflict names the existing store
+	_, err = srv.Client.AddStore(ctx, &api.AddStoreRequest{
+		Org:       "Test Org",
+		Name:      "Duplicate Store",
+		AddressId: gsResp.GetStore().GetAddressId(),
+	})
+	requireCode(t, err, codes.AlreadyExists)
+	require.Contains(t, status.Convert(err).Message(), stIds[0])
+
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{
+		Id:        stIds[1],
+		AddressId: gsResp.GetStore().GetAddressId(),
+	})
+	requireCode(t, err, codes.AlreadyExists)
+
+	ssResp, err := srv.Client.SearchStore(ctx, &api.SearchStoreRequest{Org: "test org 0"})
+	require.NoError(t, err)
+	require.Len(t, ssResp.GetStores(), (len(dests)+1)/2)
+
+	ssResp, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{
+		Latitude:  dests[1].GetLatitude(),
+		Longitude: dests[1].GetLongitude(),
+		Distance:  10000,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, ssResp.GetStores())
+
+	ssResp, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{
+		AddressStr: "201 Fair St, Petaluma",
+	})
+	require.NoError(t, err)
+	require.Len(t, ssResp.GetStores(), 1)
+	require.Equal(t, "Test Store 4", ssResp.GetStores()[0].GetStore().GetName())
+
+	_, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{
+		AddressStr: "1 Nowhere Rd, Atlantis",
+	})
+	requireCode(t, err, codes.InvalidArgument)
+
+	_, err = srv.Client.AddStore(ctx, &api.AddStoreRequest{
+		Org:       "Test Org",
+		Name:      "Lost Store",
+		AddressId: "dacd",
+	})
+	requireCode(t, err, codes.InvalidArgument)
+
+	for _, id := range stIds {
+		dResp, err := srv.Client.DeleteStore(ctx, &api.DeleteStoreRequest{Id: id})
+		require.NoError(t, err)
+		require.True(t, dResp.GetOk())
+	}
+	_, err = srv.Client.GetStore(ctx, &api.GetStoreRequest{Id: stIds[0]})
+	require.Error(t, err)
+}
+
+func TestGRPCHandler_InProcess_AddressHydration(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{})
+
+	turquoise := geodom.EncodeAddressId(38.22507858276367, -122.61660766601562, geodom.DEFAULT_ADDRESS_ID_PRECISION)
+	fair := geodom.EncodeAddressId(38.227476, -122.6461669, geodom.DEFAULT_ADDRESS_ID_PRECISION)
+
+	asResp, err := srv.Client.AddStore(ctx, &api.AddStoreRequest{
+		Org:       "Test Org",
+		Name:      "Moving Store",
+		AddressId: turquoise,
+	})
+	require.NoError(t, err)
+
+	gsResp, err := srv.Client.GetStore(ctx, &api.GetStoreRequest{Id: asResp.GetId()})
+	require.NoError(t, err)
+	require.Nil(t, gsResp.GetStore().GetAddress())
+
+	gsResp, err = srv.Client.GetStore(ctx, &api.GetStoreRequest{Id: asResp.GetId(), IncludeAddress: true})
+	require.NoError(t, err)
+	require.Equal(t, "2 Turquoise Ct, Petaluma, CA 94952, USA", gsResp.GetStore().GetAddress().GetFormattedAddress())
+	require.InDelta(t, 38.22507858276367, gsResp.GetStore().GetAddress().GetLatitude(), 1e-9)
+
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: asResp.GetId(), AddressId: fair})
+	require.NoError(t, err)
+
+	ssResp, err := srv.Client.SearchStore(ctx, &api.SearchStoreRequest{Org: "Test Org", IncludeAddress: true})
+	require.NoError(t, err)
+	require.Len(t, ssResp.GetStores(), 1)
+	require.Equal(t, "201 Fair St, Petaluma, CA 94952, USA", ssResp.GetStores()[0].GetStore().GetAddress().GetFormattedAddress())
+
+	hResp, err := srv.Client.GetStoreAddressHistory(ctx, &api.GetStoreAddressHistoryRequest{Id: asResp.GetId()})
+	require.NoError(t, err)
+	require.Len(t, hResp.GetChanges(), 2)
+	require.Equal(t, turquoise, hResp.GetChanges()[0].GetAddressId())
+	require.Empty(t, hResp.GetChanges()[0].GetPreviousAddressId())
+	require.Equal(t, fair, hResp.GetChanges()[1].GetAddressId())
+	require.Equal(t, turquoise, hResp.GetChanges()[1].GetPreviousAddressId())
+
+	// resolved addresses are served from the geo client cache while geo is down
+	srv.Geo.SetFailure(status.Error(codes.Unavailable, "geo is down"))
+	gsResp, err = srv.Client.GetStore(ctx, &api.GetStoreRequest{Id: asResp.GetId(), IncludeAddress: true})
+	require.NoError(t, err)
+	require.Equal(t, "201 Fair St, Petaluma, CA 94952, USA", gsResp.GetStore().GetAddress().GetFormattedAddress())
+
+	_, err = srv.NobodyClient.GetStoreAddressHistory(ctx, &api.GetStoreAddressHistoryRequest{Id: asResp.GetId()})
+	requireStatus(t, err, codes.Unauthenticated, grpchandler.ERR_UNAUTHORIZED_GET_STORE_ADDRESS_HISTORY)
+}
+
+func TestGRPCHandler_InProcess_TextSearch(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{})
+
+	for _, req := range []*api.AddStoreRequest{
+		{Org: "Test Org", Name: "Blue Bottle Coffee", AddressId: "dacdbddabcadccbdacac", Tags: []string{"espresso"}},
+		{Org: "Test Org", Name: "Corner Bakery", AddressId: geodom.EncodeAddressId(38.227476, -122.6461669, geodom.DEFAULT_ADDRESS_ID_PRECISION), Description: "Pastries & coffee"},
+	} {
+		_, err := srv.Client.AddStore(ctx, req)
+		require.NoError(t, err, req.GetName())
+	}
+
+	ssResp, err := srv.Client.SearchStore(ctx, &api.SearchStoreRequest{Query: "coffee", Org: "test"})
+	require.NoError(t, err)
+	require.Len(t, ssResp.GetStores(), 2)
+	require.Equal(t, "Blue Bottle Coffee", ssResp.GetStores()[0].GetStore().GetName())
+	require.Equal(t, []string{"espresso"}, ssResp.GetStores()[0].GetStore().GetTags())
+	require.Greater(t, ssResp.GetStores()[0].GetScore(), ssResp.GetStores()[1].GetScore())
+	require.Equal(t, "Pastries & coffee", ssResp.GetStores()[1].GetStore().GetDescription())
+
+	// scores are only set on query searches
+	ssResp, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{Org: "test"})
+	require.NoError(t, err)
+	require.Nil(t, ssResp.GetStores()[0].Score)
+}
+
+func TestGRPCHandler_InProcess_FacetsAndPaging(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{})
+
+	for _, req := range []*api.AddStoreRequest{
+		{Org: "Test Org", Name: "Blue Bottle Coffee", AddressId: "dacdbddabcadccbdacac", Tags: []string{"coffee"}},
+		{Org: "Test Org", Name: "Corner Bakery", AddressId: geodom.EncodeAddressId(38.227476, -122.6461669, geodom.DEFAULT_ADDRESS_ID_PRECISION), Tags: []string{"bakery", "coffee"}, Status: string(stdom.STORE_CLOSED)},
+	} {
+		_, err := srv.Client.AddStore(ctx, req)
+		require.NoError(t, err, req.GetName())
+	}
+
+	ssResp, err := srv.Client.SearchStore(ctx, &api.SearchStoreRequest{
+		Org:    "test",
+		Facets: []string{string(stdom.FACET_TAGS), string(stdom.FACET_STATUS)},
+		Limit:  1,
+	})
+	require.NoError(t, err)
+	require.Len(t, ssResp.GetStores(), 1)
+	require.EqualValues(t, 2, ssResp.GetTotal())
+	// facets are ordered by field
+	require.Len(t, ssResp.GetFacets(), 2)
+	require.Equal(t, string(stdom.FACET_STATUS), ssResp.GetFacets()[0].GetField())
+	require.Equal(t, string(stdom.FACET_TAGS), ssResp.GetFacets()[1].GetField())
+	require.Equal(t, "coffee", ssResp.GetFacets()[1].GetBuckets()[0].GetValue())
+	require.EqualValues(t, 2, ssResp.GetFacets()[1].GetBuckets()[0].GetCount())
+
+	ssResp, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{Org: "test", Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, ssResp.GetStores(), 1)
+	require.Empty(t, ssResp.GetFacets())
+
+	_, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{Org: "test", Facets: []string{"name"}})
+	requireCode(t, err, codes.InvalidArgument)
+	_, err = srv.Client.AddStore(ctx, &api.AddStoreRequest{Org: "Test Org", Name: "Kiosk", AddressId: "dacdbddabcadccbdacab", Status: "open"})
+	requireCode(t, err, codes.InvalidArgument)
+}
+
+func TestGRPCHandler_InProcess_StoreStats(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{})
+
+	fairSt := geodom.EncodeAddressId(38.227476, -122.6461669, geodom.DEFAULT_ADDRESS_ID_PRECISION)
+	for _, req := range []*api.AddStoreRequest{
+		{Org: "Test Org", Name: "Test Store", AddressId: "dacdbddabcadccbdacac"},
+		{Org: "Test Org", Name: "Corner Bakery", AddressId: fairSt},
+		{Org: "Other Org", Name: "Kiosk", AddressId: geodom.EncodeAddressId(37.7749, -122.4194, geodom.DEFAULT_ADDRESS_ID_PRECISION)},
+	} {
+		_, err := srv.Client.AddStore(ctx, req)
+		require.NoError(t, err, req.GetName())
+	}
+
+	gsResp, err := srv.Client.GetStoreStats(ctx, &api.GetStoreStatsRequest{Interval: string(stdom.STATS_WEEK), RegionPrecision: 4})
+	require.NoError(t, err)
+	require.EqualValues(t, 3, gsResp.GetTotal())
+	require.Equal(t, "Test Org", gsResp.GetOrgs()[0].GetKey())
+	require.EqualValues(t, 2, gsResp.GetOrgs()[0].GetCount())
+	// the default series ends with this week's additions
+	require.Len(t, gsResp.GetSeries(), 30)
+	require.EqualValues(t, 3, gsResp.GetSeries()[29].GetAdded())
+	require.Len(t, gsResp.GetRegions(), 1)
+	require.Equal(t, fairSt[:4], gsResp.GetRegions()[0].GetRegion())
+	require.InDelta(t, 38.2, gsResp.GetRegions()[0].GetCenter().GetLatitude(), 6)
+
+	gsResp, err = srv.Client.GetStoreStats(ctx, &api.GetStoreStatsRequest{Org: "Other Org"})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, gsResp.GetTotal())
+	require.Empty(t, gsResp.GetRegions())
+
+	_, err = srv.Client.GetStoreStats(ctx, &api.GetStoreStatsRequest{Interval: "year"})
+	requireCode(t, err, codes.InvalidArgument)
+	_, err = srv.Client.GetStoreStats(ctx, &api.GetStoreStatsRequest{
+		From: timestamppb.New(time.Now().AddDate(-2, 0, 0)),
+	})
+	requireCode(t, err, codes.InvalidArgument)
+
+	_, err = srv.NobodyClient.GetStoreStats(ctx, &api.GetStoreStatsRequest{})
+	requireStatus(t, err, codes.Unauthenticated, grpchandler.ERR_UNAUTHORIZED_GET_STORE_STATS)
+}
+
+func TestGRPCHandler_InProcess_ClusterStores(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{})
+
+	for _, req := range []*api.AddStoreRequest{
+		{Org: "Test Org", Name: "Test Store", AddressId: "dacdbddabcadccbdacac"},
+		{Org: "Test Org", Name: "Corner Bakery", AddressId: geodom.EncodeAddressId(38.227476, -122.6461669, geodom.DEFAULT_ADDRESS_ID_PRECISION)},
+		{Org: "Test Org", Name: "Kiosk", AddressId: geodom.EncodeAddressId(37.7749, -122.4194, geodom.DEFAULT_ADDRESS_ID_PRECISION)},
+	} {
+		_, err := srv.Client.AddStore(ctx, req)
+		require.NoError(t, err, req.GetName())
+	}
+	bayArea := &api.BoundingBox{MinLatitude: 37.5, MinLongitude: -123, MaxLatitude: 38.5, MaxLongitude: -122}
+
+	csResp, err := srv.Client.ClusterStores(ctx, &api.ClusterStoresRequest{Bbox: bayArea, Zoom: 4})
+	require.NoError(t, err)
+	count := 0
+	for _, c := range csResp.GetClusters() {
+		require.Len(t, c.GetRegion(), 7)
+		require.NotEmpty(t, c.GetSampleIds())
+		require.Nil(t, c.GetStore())
+		count += int(c.GetCount())
+	}
+	require.Equal(t, 3, count)
+	require.Less(t, len(csResp.GetClusters()), 3)
+
+	// zoomed in, stores are returned individually
+	csResp, err = srv.Client.ClusterStores(ctx, &api.ClusterStoresRequest{Bbox: bayArea, Zoom: 16})
+	require.NoError(t, err)
+	require.Len(t, csResp.GetClusters(), 3)
+	require.Equal(t, "Test Store", csResp.GetClusters()[0].GetStore().GetName())
+	require.InDelta(t, 38.225, csResp.GetClusters()[0].GetCentroid().GetLatitude(), 0.001)
+
+	_, err = srv.Client.ClusterStores(ctx, &api.ClusterStoresRequest{Zoom: 4})
+	requireCode(t, err, codes.InvalidArgument)
+	_, err = srv.Client.ClusterStores(ctx, &api.ClusterStoresRequest{Bbox: bayArea, Zoom: 30})
+	requireCode(t, err, codes.InvalidArgument)
+	_, err = srv.Client.ClusterStores(ctx, &api.ClusterStoresRequest{
+		Bbox: &api.BoundingBox{MinLatitude: 38.5, MinLongitude: -123, MaxLatitude: 37.5, MaxLongitude: -122},
+		Zoom: 4,
+	})
+	requireCode(t, err, codes.InvalidArgument)
+
+	_, err = srv.NobodyClient.ClusterStores(ctx, &api.ClusterStoresRequest{Bbox: bayArea})
+	requireStatus(t, err, codes.Unauthenticated, grpchandler.ERR_UNAUTHORIZED_CLUSTER_STORES)
+}
+
+func TestGRPCHandler_InProcess_WithinSearch(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{})
+
+	for _, req := range []*api.AddStoreRequest{
+		{Org: "Test Org", Name: "Test Store", AddressId: "dacdbddabcadccbdacac"},
+		{Org: "Test Org", Name: "Corner Bakery", AddressId: geodom.EncodeAddressId(38.227476, -122.6461669, geodom.DEFAULT_ADDRESS_ID_PRECISION)},
+		{Org: "Test Org", Name: "Kiosk", AddressId: geodom.EncodeAddressId(37.7749, -122.4194, geodom.DEFAULT_ADDRESS_ID_PRECISION)},
+	} {
+		_, err := srv.Client.AddStore(ctx, req)
+		require.NoError(t, err, req.GetName())
+	}
+	names := func(resp *api.SearchStoreResponse) []string {
+		found := []string{}
+		for _, st := range resp.GetStores() {
+			found = append(found, st.GetStore().GetName())
+		}
+		return found
+	}
+
+	petaluma := `{"type":"Polygon","coordinates":[[[-122.7,38.1],[-122.5,38.1],[-122.5,38.4],[-122.7,38.4],[-122.7,38.1]]]}`
+	ssResp, err := srv.Client.SearchStore(ctx, &api.SearchStoreRequest{
+		Org:    "Test Org",
+		Within: &api.WithinFilter{Geojson: petaluma},
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"Test Store", "Corner Bakery"}, names(ssResp))
+
+	ssResp, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{
+		Within: &api.WithinFilter{Bbox: &api.BoundingBox{MinLatitude: 37.7, MinLongitude: -122.5, MaxLatitude: 37.8, MaxLongitude: -122.4}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Kiosk"}, names(ssResp))
+
+	for name, within := range map[string]*api.WithinFilter{
+		"neither":      {},
+		"both":         {Bbox: &api.BoundingBox{MinLatitude: 37.7, MinLongitude: -122.5, MaxLatitude: 37.8, MaxLongitude: -122.4}, Geojson: petaluma},
+		"bad bbox":     {Bbox: &api.BoundingBox{MinLatitude: 37.8, MinLongitude: -122.5, MaxLatitude: 37.7, MaxLongitude: -122.4}},
+		"bad json":     {Geojson: `{"type":"Polygon","coordinates":`},
+		"point":        {Geojson: `{"type":"Point","coordinates":[-122.6,38.2]}`},
+		"unclosed":     {Geojson: `{"type":"Polygon","coordinates":[[[-122.7,38.1],[-122.5,38.1],[-122.5,38.4],[-122.7,38.4]]]}`},
+		"clockwise":    {Geojson: `{"type":"Polygon","coordinates":[[[-122.7,38.1],[-122.7,38.4],[-122.5,38.4],[-122.5,38.1],[-122.7,38.1]]]}`},
+		"out of range": {Geojson: `{"type":"Polygon","coordinates":[[[-190,38.1],[-122.5,38.1],[-122.5,38.4],[-190,38.1]]]}`},
+		"bow tie":      {Geojson: `{"type":"Polygon","coordinates":[[[-122.7,38.1],[-122.4,38.1],[-122.7,38.2],[-122.5,38.4],[-122.7,38.1]]]}`},
+	} {
+		_, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{Within: within})
+		requireCode(t, err, codes.InvalidArgument)
+		require.Contains(t, status.Convert(err).Message(), "invalid within area", name)
+	}
+}
+
+func TestGRPCHandler_InProcess_NearestSearch(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{})
+
+	for _, req := range []*api.AddStoreRequest{
+		{Org: "Test Org", Name: "Test Store", AddressId: "dacdbddabcadccbdacac"},
+		{Org: "Test Org", Name: "Corner Bakery", AddressId: geodom.EncodeAddressId(38.227476, -122.6461669, geodom.DEFAULT_ADDRESS_ID_PRECISION)},
+		{Org: "Test Org", Name: "Kiosk", AddressId: geodom.EncodeAddressId(37.7749, -122.4194, geodom.DEFAULT_ADDRESS_ID_PRECISION)},
+		{Org: "Other Org", Name: "Outlet", AddressId: geodom.EncodeAddressId(38.2301, -122.6401, geodom.DEFAULT_ADDRESS_ID_PRECISION)},
+	} {
+		_, err := srv.Client.AddStore(ctx, req)
+		require.NoError(t, err, req.GetName())
+	}
+	names := func(resp *api.SearchStoreResponse) []string {
+		found := []string{}
+		for _, st := range resp.GetStores() {
+			found = append(found, st.GetStore().GetName())
+		}
+		return found
+	}
+
+	ssResp, err := srv.Client.SearchStore(ctx, &api.SearchStoreRequest{
+		Org:        "Test Org",
+		AddressStr: "201 Fair St, Petaluma",
+		KNearest:   2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Corner Bakery", "Test Store"}, names(ssResp))
+	require.Equal(t, uint32(2), ssResp.GetTotal())
+	require.InDelta(t, 0, ssResp.GetStores()[0].GetDistance(), 20)
+	require.InDelta(t, 2600, ssResp.GetStores()[1].GetDistance(), 200)
+
+	// the search area grows until enough stores are found, whatever the radius
+	ssResp, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{
+		Latitude:  37.7749,
+		Longitude: -122.4194,
+		KNearest:  3,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Kiosk", "Test Store", "Corner Bakery"}, names(ssResp))
+	require.Less(t, ssResp.GetStores()[1].GetDistance(), ssResp.GetStores()[2].GetDistance())
+
+	ssResp, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{
+		AddressId: geodom.EncodeAddressId(37.7749, -122.4194, geodom.DEFAULT_ADDRESS_ID_PRECISION),
+		KNearest:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, ssResp.GetStores(), 4)
+
+	// distance caps the radius
+	ssResp, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{
+		Latitude:  37.7749,
+		Longitude: -122.4194,
+		Distance:  10000,
+		KNearest:  3,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Kiosk"}, names(ssResp))
+
+	for name, req := range map[string]*api.SearchStoreRequest{
+		"too many": {Org: "Test Org", AddressStr: "201 Fair St, Petaluma", KNearest: 101},
+		"no point": {Org: "Test Org", KNearest: 3},
+		"paged":    {Org: "Test Org", AddressStr: "201 Fair St, Petaluma", KNearest: 3, Limit: 10},
+		"faceted":  {Org: "Test Org", AddressStr: "201 Fair St, Petaluma", KNearest: 3, Facets: []string{"org"}},
+		"fuzzy":    {Name: "Bakery", Fuzzy: true, AddressStr: "201 Fair St, Petaluma", KNearest: 3},
+	} {
+		_, err = srv.Client.SearchStore(ctx, req)
+		requireCode(t, err, codes.InvalidArgument)
+		require.Contains(t, status.Convert(err).Message(), "invalid search parameters", name)
+	}
+}
+
+func TestGRPCHandler_InProcess_ServingStores(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{})
+
+	petaluma := `{"type":"Polygon","coordinates":[[[-122.7,38.1],[-122.5,38.1],[-122.5,38.4],[-122.7,38.4],[-122.7,38.1]]]}`
+	eastSide := `{"type":"MultiPolygon","coordinates":[[[[-122.63,38.2],[-122.6,38.2],[-122.6,38.25],[-122.63,38.25],[-122.63,38.2]]]]}`
+	bayArea := `{"type":"Polygon","coordinates":[[[-123,37.5],[-122,37.5],[-122,38.5],[-123,38.5],[-123,37.5]]]}`
+	ids := map[string]string{}
+	for _, req := range []*api.AddStoreRequest{
+		{Org: "Test Org", Name: "Test Store", AddressId: "dacdbddabcadccbdacac", ServiceArea: eastSide},
+		{Org: "Test Org", Name: "Corner Bakery", AddressId: geodom.EncodeAddressId(38.227476, -122.6461669, geodom.DEFAULT_ADDRESS_ID_PRECISION), ServiceArea: petaluma},
+		{Org: "Test Org", Name: "Kiosk", AddressId: geodom.EncodeAddressId(37.7749, -122.4194, geodom.DEFAULT_ADDRESS_ID_PRECISION), ServiceArea: bayArea},
+		{Org: "Test Org", Name: "Pickup Only", AddressId: geodom.EncodeAddressId(38.2301, -122.6401, geodom.DEFAULT_ADDRESS_ID_PRECISION)},
+	} {
+		resp, err := srv.Client.AddStore(ctx, req)
+		require.NoError(t, err, req.GetName())
+		ids[req.GetName()] = resp.GetId()
+	}
+	names := func(resp *api.FindServingStoresResponse) []string {
+		found := []string{}
+		for _, st := range resp.GetStores() {
+			found = append(found, st.GetStore().GetName())
+		}
+		return found
+	}
+
+	gsResp, err := srv.Client.GetStore(ctx, &api.GetStoreRequest{Id: ids["Corner Bakery"]})
+	require.NoError(t, err)
+	area, err := geodom.ParseGeoJSONArea([]byte(gsResp.GetStore().GetServiceArea()))
+	require.NoError(t, err)
+	require.Len(t, area, 1)
+
+	// ranked by distance from the customer
+	fsResp, err := srv.Client.FindServingStores(ctx, &api.FindServingStoresRequest{AddressStr: "201 Fair St, Petaluma"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Corner Bakery", "Kiosk"}, names(fsResp))
+	require.InDelta(t, 0, fsResp.GetStores()[0].GetDistance(), 20)
+	require.Less(t, fsResp.GetStores()[0].GetDistance(), fsResp.GetStores()[1].GetDistance())
+
+	fsResp, err = srv.Client.FindServingStores(ctx, &api.FindServingStoresRequest{Latitude: 38.225, Longitude: -122.61, Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Test Store", "Corner Bakery"}, names(fsResp))
+
+	fsResp, err = srv.Client.FindServingStores(ctx, &api.FindServingStoresRequest{
+		Org:       "Other Org",
+		AddressId: geodom.EncodeAddressId(37.7749, -122.4194, geodom.DEFAULT_ADDRESS_ID_PRECISION),
+	})
+	require.NoError(t, err)
+	require.Empty(t, fsResp.GetStores())
+
+	// service areas are replaced on update
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: ids["Pickup Only"], ServiceArea: eastSide})
+	require.NoError(t, err)
+	fsResp, err = srv.Client.FindServingStores(ctx, &api.FindServingStoresRequest{Latitude: 38.225, Longitude: -122.61})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Test Store", "Pickup Only", "Corner Bakery", "Kiosk"}, names(fsResp))
+
+	clockwise := `{"type":"Polygon","coordinates":[[[-122.7,38.1],[-122.7,38.4],[-122.5,38.4],[-122.5,38.1],[-122.7,38.1]]]}`
+	_, err = srv.Client.AddStore(ctx, &api.AddStoreRequest{
+		Org:         "Test Org",
+		Name:        "Wrong Way",
+		AddressId:   geodom.EncodeAddressId(38.24, -122.65, geodom.DEFAULT_ADDRESS_ID_PRECISION),
+		ServiceArea: clockwise,
+	})
+	requireCode(t, err, codes.InvalidArgument)
+	require.Contains(t, status.Convert(err).Message(), "invalid service area")
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: ids["Kiosk"], ServiceArea: `{"type":"Point","coordinates":[-122.6,38.2]}`})
+	requireCode(t, err, codes.InvalidArgument)
+
+	_, err = srv.Client.FindServingStores(ctx, &api.FindServingStoresRequest{Org: "Test Org"})
+	requireCode(t, err, codes.InvalidArgument)
+	_, err = srv.Client.FindServingStores(ctx, &api.FindServingStoresRequest{AddressStr: "1 Nowhere Rd, Atlantis"})
+	requireCode(t, err, codes.InvalidArgument)
+
+	_, err = srv.NobodyClient.FindServingStores(ctx, &api.FindServingStoresRequest{AddressStr: "201 Fair St, Petaluma"})
+	requireStatus(t, err, codes.Unauthenticated, grpchandler.ERR_UNAUTHORIZED_FIND_SERVING_STORES)
+}
+
+func TestGRPCHandler_InProcess_RoutedRanking(t *testing.T) {
+	fairSt := geodom.EncodeAddressId(38.227476, -122.6461669, geodom.DEFAULT_ADDRESS_ID_PRECISION)
+	sf := geodom.EncodeAddressId(37.7749, -122.4194, geodom.DEFAULT_ADDRESS_ID_PRECISION)
+	router, err := routing.NewMatrixRouterFromEntries(context.Background(), []*routing.MatrixEntry{
+		// the bakery across the street is around the one-way block
+		{Origin: fairSt, Destination: fairSt, DistanceMeters: 1200, DurationSeconds: 900},
+		{Origin: fairSt, Destination: "dacdbddabcadccbdacac", DistanceMeters: 3100, DurationSeconds: 300},
+	})
+	require.NoError(t, err)
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{Routing: router})
+
+	for _, req := range []*api.AddStoreRequest{
+		{Org: "Test Org", Name: "Test Store", AddressId: "dacdbddabcadccbdacac", ServiceArea: `{"type":"Polygon","coordinates":[[[-122.7,38.1],[-122.5,38.1],[-122.5,38.4],[-122.7,38.4],[-122.7,38.1]]]}`},
+		{Org: "Test Org", Name: "Corner Bakery", AddressId: fairSt, ServiceArea: `{"type":"Polygon","coordinates":[[[-122.7,38.1],[-122.5,38.1],[-122.5,38.4],[-122.7,38.4],[-122.7,38.1]]]}`},
+		{Org: "Test Org", Name: "Kiosk", AddressId: sf, ServiceArea: `{"type":"Polygon","coordinates":[[[-123,37.5],[-122,37.5],[-122,38.5],[-123,38.5],[-123,37.5]]]}`},
+	} {
+		_, err := srv.Client.AddStore(ctx, req)
+		require.NoError(t, err, req.GetName())
+	}
+	names := func(stores []*api.StoreGeo) []string {
+		found := []string{}
+		for _, st := range stores {
+			found = append(found, st.GetStore().GetName())
+		}
+		return found
+	}
+
+	// straight line order is unchanged without a ranking
+	ssResp, err := srv.Client.SearchStore(ctx, &api.SearchStoreRequest{AddressId: fairSt, KNearest: 3})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Corner Bakery", "Test Store", "Kiosk"}, names(ssResp.GetStores()))
+	require.Nil(t, ssResp.GetStores()[0].EtaSeconds)
+
+	// the quicker drive ranks first, the store with no route last
+	ssResp, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{AddressId: fairSt, KNearest: 3, RankBy: string(stdom.RANK_TRAVEL_TIME)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Test Store", "Corner Bakery", "Kiosk"}, names(ssResp.GetStores()))
+	require.Equal(t, uint32(300), ssResp.GetStores()[0].GetEtaSeconds())
+	require.InDelta(t, 3100, ssResp.GetStores()[0].GetDistance(), 1e-3)
+	require.Equal(t, uint32(900), ssResp.GetStores()[1].GetEtaSeconds())
+	require.Nil(t, ssResp.GetStores()[2].EtaSeconds)
+
+	// candidates beyond k are routed, then cut to k
+	ssResp, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{AddressId: fairSt, KNearest: 1, RankBy: string(stdom.RANK_ROAD_DISTANCE)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Corner Bakery"}, names(ssResp.GetStores()))
+	require.InDelta(t, 1200, ssResp.GetStores()[0].GetDistance(), 1e-3)
+
+	fsResp, err := srv.Client.FindServingStores(ctx, &api.FindServingStoresRequest{AddressId: fairSt, RankBy: string(stdom.RANK_TRAVEL_TIME)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Test Store", "Corner Bakery", "Kiosk"}, names(fsResp.GetStores()))
+	require.Equal(t, uint32(300), fsResp.GetStores()[0].GetEtaSeconds())
+
+	for name, req := range map[string]*api.SearchStoreRequest{
+		"unknown ranking": {AddressId: fairSt, KNearest: 3, RankBy: "scenic"},
+		"not nearest":     {Org: "Test Org", RankBy: string(stdom.RANK_TRAVEL_TIME)},
+	} {
+		_, err = srv.Client.SearchStore(ctx, req)
+		requireCode(t, err, codes.InvalidArgument)
+		require.Contains(t, status.Convert(err).Message(), "invalid search parameters", name)
+	}
+	_, err = srv.Client.FindServingStores(ctx, &api.FindServingStoresRequest{AddressId: fairSt, RankBy: "scenic"})
+	requireCode(t, err, codes.InvalidArgument)
+}
+
+func TestGRPCHandler_InProcess_StoreHierarchy(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{})
+
+	add := func(req *api.AddStoreRequest) string {
+		resp, err := srv.Client.AddStore(ctx, req)
+		require.NoError(t, err, req.GetName())
+		return resp.GetId()
+	}
+	flagshipID := add(&api.AddStoreRequest{Org: "Test Org", Name: "Flagship", AddressId: "dacdbddabcadccbdacac", Region: " West/Bay-Area"})
+	satelliteID := add(&api.AddStoreRequest{Org: "Test Org", Name: "Satellite", AddressId: geodom.EncodeAddressId(38.227476, -122.6461669, geodom.DEFAULT_ADDRESS_ID_PRECISION), ParentId: flagshipID})
+	kioskID := add(&api.AddStoreRequest{Org: "Test Org", Name: "Kiosk", AddressId: geodom.EncodeAddressId(38.2301, -122.6401, geodom.DEFAULT_ADDRESS_ID_PRECISION), ParentId: satelliteID, Region: "west/bay-area/petaluma"})
+	add(&api.AddStoreRequest{Org: "Test Org", Name: "Depot", AddressId: geodom.EncodeAddressId(37.7749, -122.4194, geodom.DEFAULT_ADDRESS_ID_PRECISION), Region: "west/sierra"})
+	names := func(stores []*api.Store) []string {
+		found := []string{}
+		for _, st := range stores {
+			found = append(found, st.GetName())
+		}
+		return found
+	}
+
+	// satellites are in their parent's region unless set
+	gsResp, err := srv.Client.GetStore(ctx, &api.GetStoreRequest{Id: satelliteID})
+	require.NoError(t, err)
+	require.Equal(t, flagshipID, gsResp.GetStore().GetParentId())
+	require.Equal(t, "west/bay-area", gsResp.GetStore().GetRegion())
+
+	lcResp, err := srv.Client.ListChildStores(ctx, &api.ListChildStoresRequest{Id: flagshipID})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Satellite"}, names(lcResp.GetStores()))
+	gaResp, err := srv.Client.GetStoreAncestors(ctx, &api.GetStoreAncestorsRequest{Id: kioskID})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Satellite", "Flagship"}, names(gaResp.GetStores()))
+	gaResp, err = srv.Client.GetStoreAncestors(ctx, &api.GetStoreAncestorsRequest{Id: flagshipID})
+	require.NoError(t, err)
+	require.Empty(t, gaResp.GetStores())
+
+	inRegion := func(region string) []string {
+		resp, err := srv.Client.SearchStore(ctx, &api.SearchStoreRequest{Region: region})
+		require.NoError(t, err)
+		found := []string{}
+		for _, st := range resp.GetStores() {
+			found = append(found, st.GetStore().GetName())
+		}
+		return found
+	}
+	require.Equal(t, []string{"Flagship", "Satellite", "Kiosk", "Depot"}, inRegion("West"))
+	require.Equal(t, []string{"Flagship", "Satellite", "Kiosk"}, inRegion("west/bay-area"))
+	_, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{Region: "west//bay-area"})
+	requireCode(t, err, codes.InvalidArgument)
+
+	for name, req := range map[string]*api.UpdateStoreRequest{
+		"own parent":       {Id: flagshipID, ParentId: flagshipID},
+		"cycle":            {Id: flagshipID, ParentId: kioskID},
+		"set and detach":   {Id: kioskID, ParentId: flagshipID, DetachParent: true},
+		"missing parent":   {Id: kioskID, ParentId: "64b64c6f2f8fb814b56fa181"},
+		"invalid region":   {Id: kioskID, Region: "west/bay area"},
+		"parent elsewhere": {Id: kioskID, Org: "Other Org"},
+	} {
+		_, err = srv.Client.UpdateStore(ctx, req)
+		requireCode(t, err, codes.InvalidArgument)
+		require.NotContains(t, status.Convert(err).Message(), "error updating store", name)
+	}
+	_, err = srv.Client.AddStore(ctx, &api.AddStoreRequest{Org: "Other Org", Name: "Franchise", AddressId: geodom.EncodeAddressId(38.24, -122.65, geodom.DEFAULT_ADDRESS_ID_PRECISION), ParentId: flagshipID})
+	requireCode(t, err, codes.InvalidArgument)
+	require.Contains(t, status.Convert(err).Message(), "invalid parent store")
+
+	// stores with satellites can't be deleted or change orgs or regions
+	_, err = srv.Client.DeleteStore(ctx, &api.DeleteStoreRequest{Id: flagshipID})
+	requireCode(t, err, codes.FailedPrecondition)
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: flagshipID, Org: "Other Org"})
+	requireCode(t, err, codes.FailedPrecondition)
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: flagshipID, Region: "west/sierra"})
+	requireCode(t, err, codes.FailedPrecondition)
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: flagshipID, Region: "West/Bay-Area"})
+	require.NoError(t, err)
+
+	// detached satellites keep their own satellites
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: satelliteID, DetachParent: true})
+	require.NoError(t, err)
+	gaResp, err = srv.Client.GetStoreAncestors(ctx, &api.GetStoreAncestorsRequest{Id: kioskID})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Satellite"}, names(gaResp.GetStores()))
+	// and the former parent can hang below them
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: flagshipID, ParentId: kioskID})
+	require.NoError(t, err)
+	gaResp, err = srv.Client.GetStoreAncestors(ctx, &api.GetStoreAncestorsRequest{Id: flagshipID})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Kiosk", "Satellite"}, names(gaResp.GetStores()))
+
+	_, err = srv.NobodyClient.ListChildStores(ctx, &api.ListChildStoresRequest{Id: flagshipID})
+	requireStatus(t, err, codes.Unauthenticated, grpchandler.ERR_UNAUTHORIZED_LIST_CHILD_STORES)
+	_, err = srv.NobodyClient.GetStoreAncestors(ctx, &api.GetStoreAncestorsRequest{Id: flagshipID})
+	requireStatus(t, err, codes.Unauthenticated, grpchandler.ERR_UNAUTHORIZED_GET_STORE_ANCESTORS)
+}
+
+func TestGRPCHandler_InProcess_Capabilities(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{
+		Capabilities: &stdom.CapabilityCatalog{
+			Version: 3,
+			Capabilities: []*stdom.Capability{
+				{Code: "pickup", Name: "Pickup"},
+				{Code: "pharmacy", Name: "Pharmacy"},
+				{Code: "photo_lab", Name: "Photo lab", Deprecated: true},
+			},
+		},
+	})
+
+	gcResp, err := srv.Client.GetCapabilityCatalog(ctx, &api.GetCapabilityCatalogRequest{})
+	require.NoError(t, err)
+	require.Equal(t, uint32(3), gcResp.GetVersion())
+	require.Len(t, gcResp.GetCapabilities(), 3)
+	require.True(t, gcResp.GetCapabilities()[2].GetDeprecated())
+
+	petaluma := `{"type":"Polygon","coordinates":[[[-122.7,38.1],[-122.5,38.1],[-122.5,38.4],[-122.7,38.4],[-122.7,38.1]]]}`
+	ids := map[string]string{}
+	for _, req := range []*api.AddStoreRequest{
+		{Org: "Test Org", Name: "Test Store", AddressId: "dacdbddabcadccbdacac", ServiceArea: petaluma, Capabilities: []string{" Pickup", "pharmacy", "pickup"}},
+		{Org: "Test Org", Name: "Corner Bakery", AddressId: geodom.EncodeAddressId(38.227476, -122.6461669, geodom.DEFAULT_ADDRESS_ID_PRECISION), ServiceArea: petaluma, Capabilities: []string{"pickup"}},
+		{Org: "Test Org", Name: "Kiosk", AddressId: geodom.EncodeAddressId(38.2301, -122.6401, geodom.DEFAULT_ADDRESS_ID_PRECISION), ServiceArea: petaluma},
+	} {
+		resp, err := srv.Client.AddStore(ctx, req)
+		require.NoError(t, err, req.GetName())
+		ids[req.GetName()] = resp.GetId()
+	}
+
+	// capabilities are normalized & deduped
+	gsResp, err := srv.Client.GetStore(ctx, &api.GetStoreRequest{Id: ids["Test Store"]})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pickup", "pharmacy"}, gsResp.GetStore().GetCapabilities())
+
+	offering := func(capabilities ...string) []string {
+		resp, err := srv.Client.SearchStore(ctx, &api.SearchStoreRequest{Capabilities: capabilities})
+		require.NoError(t, err)
+		found := []string{}
+		for _, st := range resp.GetStores() {
+			found = append(found, st.GetStore().GetName())
+		}
+		return found
+	}
+	require.ElementsMatch(t, []string{"Test Store", "Corner Bakery"}, offering("Pickup"))
+	require.Equal(t, []string{"Test Store"}, offering("pickup", "pharmacy"))
+	// deprecated capabilities stay searchable
+	require.Empty(t, offering("photo_lab"))
+
+	fsResp, err := srv.Client.FindServingStores(ctx, &api.FindServingStoresRequest{Latitude: 38.225, Longitude: -122.61, Capabilities: []string{"pharmacy"}})
+	require.NoError(t, err)
+	require.Len(t, fsResp.GetStores(), 1)
+	require.Equal(t, "Test Store", fsResp.GetStores()[0].GetStore().GetName())
+
+	// capabilities are replaced on update
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: ids["Kiosk"], Capabilities: []string{"pharmacy"}})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"Test Store", "Kiosk"}, offering("pharmacy"))
+
+	_, err = srv.Client.AddStore(ctx, &api.AddStoreRequest{
+		Org:          "Test Org",
+		Name:         "Photo Shop",
+		AddressId:    geodom.EncodeAddressId(38.24, -122.65, geodom.DEFAULT_ADDRESS_ID_PRECISION),
+		Capabilities: []string{"photo_lab"},
+	})
+	requireCode(t, err, codes.InvalidArgument)
+	require.Contains(t, status.Convert(err).Message(), "invalid capability")
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: ids["Kiosk"], Capabilities: []string{"delivery"}})
+	requireCode(t, err, codes.InvalidArgument)
+	_, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{Capabilities: []string{"delivery"}})
+	requireCode(t, err, codes.InvalidArgument)
+	_, err = srv.Client.FindServingStores(ctx, &api.FindServingStoresRequest{Latitude: 38.225, Longitude: -122.61, Capabilities: []string{"delivery"}})
+	requireCode(t, err, codes.InvalidArgument)
+
+	_, err = srv.NobodyClient.GetCapabilityCatalog(ctx, &api.GetCapabilityCatalogRequest{})
+	requireStatus(t, err, codes.Unauthenticated, grpchandler.ERR_UNAUTHORIZED_GET_CAPABILITY_CATALOG)
+}
+
+func TestGRPCHandler_InProcess_Closures(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{})
+
+	petaluma := `{"type":"Polygon","coordinates":[[[-122.7,38.1],[-122.5,38.1],[-122.5,38.4],[-122.7,38.4],[-122.7,38.1]]]}`
+	ids := map[string]string{}
+	for _, req := range []*api.AddStoreRequest{
+		{Org: "Test Org", Name: "Test Store", AddressId: "dacdbddabcadccbdacac", ServiceArea: petaluma},
+		{Org: "Test Org", Name: "Corner Bakery", AddressId: geodom.EncodeAddressId(38.227476, -122.6461669, geodom.DEFAULT_ADDRESS_ID_PRECISION), ServiceArea: petaluma},
+	} {
+		resp, err := srv.Client.AddStore(ctx, req)
+		require.NoError(t, err, req.GetName())
+		ids[req.GetName()] = resp.GetId()
+	}
+	getStore := func(name string) *api.Store {
+		resp, err := srv.Client.GetStore(ctx, &api.GetStoreRequest{Id: ids[name]})
+		require.NoError(t, err)
+		return resp.GetStore()
+	}
+	searched := func(include bool) []string {
+		resp, err := srv.Client.SearchStore(ctx, &api.SearchStoreRequest{Org: "Test Org", IncludeTemporarilyClosed: include})
+		require.NoError(t, err)
+		found := []string{}
+		for _, st := range resp.GetStores() {
+			found = append(found, st.GetStore().GetName())
+		}
+		return found
+	}
+
+	// closures in effect close the store right away
+	now := time.Now()
+	scResp, err := srv.Client.ScheduleClosure(ctx, &api.ScheduleClosureRequest{
+		StoreId: ids["Test Store"],
+		Until:   timestamppb.New(now.Add(48 * time.Hour)),
+		Reason:  "storm",
+	})
+	require.NoError(t, err)
+	stormID := scResp.GetClosure().GetId()
+	require.NotEmpty(t, stormID)
+	st := getStore("Test Store")
+	require.Equal(t, string(stdom.STORE_TEMPORARILY_CLOSED), st.GetStatus())
+	require.Len(t, st.GetClosures(), 1)
+	require.Equal(t, "storm", st.GetClosures()[0].GetReason())
+
+	require.Equal(t, []string{"Corner Bakery"}, searched(false))
+	require.Equal(t, []string{"Test Store", "Corner Bakery"}, searched(true))
+	fsResp, err := srv.Client.FindServingStores(ctx, &api.FindServingStoresRequest{Latitude: 38.225, Longitude: -122.61})
+	require.NoError(t, err)
+	require.Len(t, fsResp.GetStores(), 1)
+	fsResp, err = srv.Client.FindServingStores(ctx, &api.FindServingStoresRequest{Latitude: 38.225, Longitude: -122.61, IncludeTemporarilyClosed: true})
+	require.NoError(t, err)
+	require.Len(t, fsResp.GetStores(), 2)
+
+	// scheduled closures close & reopen the store as the scheduler reaches them
+	_, err = srv.Client.ScheduleClosure(ctx, &api.ScheduleClosureRequest{
+		StoreId: ids["Corner Bakery"],
+		From:    timestamppb.New(now.Add(time.Hour)),
+		Until:   timestamppb.New(now.Add(2 * time.Hour)),
+		Reason:  "renovation",
+	})
+	require.NoError(t, err)
+	require.Equal(t, string(stdom.STORE_ACTIVE), getStore("Corner Bakery").GetStatus())
+	changed, err := srv.Scheduler.ApplyScheduledStatusChanges(ctx, now.Add(30*time.Minute), 10, time.Minute)
+	require.NoError(t, err)
+	require.Zero(t, changed)
+	changed, err = srv.Scheduler.ApplyScheduledStatusChanges(ctx, now.Add(90*time.Minute), 10, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 1, changed)
+	require.Equal(t, string(stdom.STORE_TEMPORARILY_CLOSED), getStore("Corner Bakery").GetStatus())
+	changed, err = srv.Scheduler.ApplyScheduledStatusChanges(ctx, now.Add(3*time.Hour), 10, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 1, changed)
+	st = getStore("Corner Bakery")
+	require.Equal(t, string(stdom.STORE_ACTIVE), st.GetStatus())
+	require.Empty(t, st.GetClosures())
+
+	// the temporarily closed status is the closures' to set
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: ids["Corner Bakery"], Status: string(stdom.STORE_TEMPORARILY_CLOSED)})
+	requireCode(t, err, codes.InvalidArgument)
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: ids["Test Store"], Status: string(stdom.STORE_ACTIVE)})
+	require.NoError(t, err)
+	require.Equal(t, string(stdom.STORE_TEMPORARILY_CLOSED), getStore("Test Store").GetStatus())
+	// stores that aren't active keep their status
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: ids["Test Store"], Status: string(stdom.STORE_INACTIVE)})
+	require.NoError(t, err)
+	_, err = srv.Client.CancelClosure(ctx, &api.CancelClosureRequest{StoreId: ids["Test Store"], ClosureId: stormID})
+	require.NoError(t, err)
+	st = getStore("Test Store")
+	require.Equal(t, string(stdom.STORE_INACTIVE), st.GetStatus())
+	require.Empty(t, st.GetClosures())
+
+	_, err = srv.Client.CancelClosure(ctx, &api.CancelClosureRequest{StoreId: ids["Test Store"], ClosureId: stormID})
+	requireCode(t, err, codes.NotFound)
+	_, err = srv.Client.ScheduleClosure(ctx, &api.ScheduleClosureRequest{
+		StoreId: ids["Test Store"],
+		From:    timestamppb.New(now.Add(2 * time.Hour)),
+		Until:   timestamppb.New(now.Add(time.Hour)),
+	})
+	requireCode(t, err, codes.InvalidArgument)
+	require.Contains(t, status.Convert(err).Message(), "invalid store closure")
+
+	_, err = srv.NobodyClient.ScheduleClosure(ctx, &api.ScheduleClosureRequest{StoreId: ids["Test Store"]})
+	requireStatus(t, err, codes.Unauthenticated, grpchandler.ERR_UNAUTHORIZED_SCHEDULE_CLOSURE)
+	_, err = srv.NobodyClient.CancelClosure(ctx, &api.CancelClosureRequest{StoreId: ids["Test Store"], ClosureId: stormID})
+	requireStatus(t, err, codes.Unauthenticated, grpchandler.ERR_UNAUTHORIZED_CANCEL_CLOSURE)
+}
+
+func TestGRPCHandler_InProcess_Attachments(t *testing.T) {
+	bctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+	dir := t.TempDir()
+	bs, err := blobs.NewLocalBlobStore(bctx, blobs.LocalOptions{Dir: dir, BaseURL: "https://cdn.example.com/media"})
+	require.NoError(t, err)
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{Blobs: bs})
+
+	resp, err := srv.Client.AddStore(ctx, &api.AddStoreRequest{Org: "Test Org", Name: "Test Store", AddressId: "dacdbddabcadccbdacac"})
+	require.NoError(t, err)
+	storeID := resp.GetId()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 120, 80))))
+	content := buf.Bytes()
+	upload := func(client api.StoresClient, info *api.UploadAttachmentInfo, chunks ...[]byte) (*api.StoreAttachment, error) {
+		stream, err := client.UploadAttachment(ctx)
+		require.NoError(t, err)
+		if info != nil {
+			if err := stream.Send(&api.UploadAttachmentRequest{Data: &api.UploadAttachmentRequest_Info{Info: info}}); err != nil {
+				_, err = stream.CloseAndRecv()
+				return nil, err
+			}
+		}
+		for _, chunk := range chunks {
+			if err := stream.Send(&api.UploadAttachmentRequest{Data: &api.UploadAttachmentRequest_Chunk{Chunk: chunk}}); err != nil {
+				break
+			}
+		}
+		uaResp, err := stream.CloseAndRecv()
+		return uaResp.GetAttachment(), err
+	}
+
+	// uploads are chunked, their details taken from the content
+	logo, err := upload(srv.Client, &api.UploadAttachmentInfo{StoreId: storeID, Kind: string(stdom.ATTACHMENT_LOGO)}, content[:50], content[50:])
+	require.NoError(t, err)
+	sum := sha256.Sum256(content)
+	require.Equal(t, hex.EncodeToString(sum[:]), logo.GetChecksum())
+	require.Equal(t, "image/png", logo.GetContentType())
+	require.Equal(t, uint32(120), logo.GetWidth())
+	require.Equal(t, uint32(80), logo.GetHeight())
+	blobKey := "stores/" + storeID + "/" + logo.GetId() + ".png"
+	require.Equal(t, "https://cdn.example.com/media/"+blobKey, logo.GetUri())
+	stored, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(blobKey)))
+	require.NoError(t, err)
+	require.Equal(t, content, stored)
+
+	// hosted assets are referenced by URI
+	aaResp, err := srv.Client.AddAttachment(ctx, &api.AddAttachmentRequest{
+		StoreId: storeID,
+		Kind:    string(stdom.ATTACHMENT_PHOTO),
+		Uri:     "https://photos.example.com/front.jpg",
+		Width:   1024,
+		Height:  768,
+	})
+	require.NoError(t, err)
+	photo := aaResp.GetAttachment()
+
+	st, err := srv.Client.GetStore(ctx, &api.GetStoreRequest{Id: storeID})
+	require.NoError(t, err)
+	require.Len(t, st.GetStore().GetAttachments(), 2)
+	require.Equal(t, logo.GetId(), st.GetStore().GetAttachments()[0].GetId())
+
+	raResp, err := srv.Client.ReorderAttachments(ctx, &api.ReorderAttachmentsRequest{StoreId: storeID, AttachmentIds: []string{photo.GetId(), logo.GetId()}})
+	require.NoError(t, err)
+	require.Equal(t, photo.GetId(), raResp.GetAttachments()[0].GetId())
+	st, err = srv.Client.GetStore(ctx, &api.GetStoreRequest{Id: storeID})
+	require.NoError(t, err)
+	require.Equal(t, photo.GetId(), st.GetStore().GetAttachments()[0].GetId())
+
+	// removing uploads deletes their blobs
+	_, err = srv.Client.RemoveAttachment(ctx, &api.RemoveAttachmentRequest{StoreId: storeID, AttachmentId: logo.GetId()})
+	require.NoError(t, err)
+	require.NoFileExists(t, filepath.Join(dir, filepath.FromSlash(blobKey)))
+	_, err = srv.Client.RemoveAttachment(ctx, &api.RemoveAttachmentRequest{StoreId: storeID, AttachmentId: logo.GetId()})
+	requireCode(t, err, codes.NotFound)
+
+	_, err = srv.Client.ReorderAttachments(ctx, &api.ReorderAttachmentsRequest{StoreId: storeID, AttachmentIds: []string{photo.GetId(), logo.GetId()}})
+	requireCode(t, err, codes.InvalidArgument)
+	_, err = srv.Client.AddAttachment(ctx, &api.AddAttachmentRequest{StoreId: storeID, Kind: "video", Uri: "https://photos.example.com/tour.mp4"})
+	requireCode(t, err, codes.InvalidArgument)
+	_, err = srv.Client.AddAttachment(ctx, &api.AddAttachmentRequest{StoreId: storeID, Kind: string(stdom.ATTACHMENT_PHOTO), Uri: "ftp://photos.example.com/front.jpg"})
+	requireCode(t, err, codes.InvalidArgument)
+	_, err = srv.Client.AddAttachment(ctx, &api.AddAttachmentRequest{StoreId: storeID, Kind: string(stdom.ATTACHMENT_PHOTO), Uri: "https://photos.example.com/front.jpg", Checksum: "abc"})
+	requireCode(t, err, codes.InvalidArgument)
+	_, err = upload(srv.Client, &api.UploadAttachmentInfo{StoreId: storeID, Kind: string(stdom.ATTACHMENT_PHOTO)}, []byte("not an image"))
+	requireCode(t, err, codes.InvalidArgument)
+	_, err = upload(srv.Client, nil, content)
+	requireCode(t, err, codes.InvalidArgument)
+
+	_, err = srv.NobodyClient.AddAttachment(ctx, &api.AddAttachmentRequest{StoreId: storeID, Kind: string(stdom.ATTACHMENT_PHOTO), Uri: "https://photos.example.com/front.jpg"})
+	requireStatus(t, err, codes.Unauthenticated, grpchandler.ERR_UNAUTHORIZED_ADD_ATTACHMENT)
+	_, err = srv.NobodyClient.RemoveAttachment(ctx, &api.RemoveAttachmentRequest{StoreId: storeID, AttachmentId: photo.GetId()})
+	requireStatus(t, err, codes.Unauthenticated, grpchandler.ERR_UNAUTHORIZED_REMOVE_ATTACHMENT)
+	_, err = srv.NobodyClient.ReorderAttachments(ctx, &api.ReorderAttachmentsRequest{StoreId: storeID, AttachmentIds: []string{photo.GetId()}})
+	requireStatus(t, err, codes.Unauthenticated, grpchandler.ERR_UNAUTHORIZED_REORDER_ATTACHMENTS)
+	_, err = upload(srv.NobodyClient, &api.UploadAttachmentInfo{StoreId: storeID, Kind: string(stdom.ATTACHMENT_LOGO)}, content)
+	requireStatus(t, err, codes.Unauthenticated, grpchandler.ERR_UNAUTHORIZED_UPLOAD_ATTACHMENT)
+
+	// deleting the store deletes its uploads
+	logo, err = upload(srv.Client, &api.UploadAttachmentInfo{StoreId: storeID, Kind: string(stdom.ATTACHMENT_LOGO)}, content)
+	require.NoError(t, err)
+	blobKey = "stores/" + storeID + "/" + logo.GetId() + ".png"
+	require.FileExists(t, filepath.Join(dir, filepath.FromSlash(blobKey)))
+	_, err = srv.Client.DeleteStore(ctx, &api.DeleteStoreRequest{Id: storeID})
+	require.NoError(t, err)
+	require.NoFileExists(t, filepath.Join(dir, filepath.FromSlash(blobKey)))
+
+	// uploads need blob storage
+	nctx, noBlobs := setupInProcessTest(t, testharness.StoresServerOptions{})
+	resp, err = noBlobs.Client.AddStore(nctx, &api.AddStoreRequest{Org: "Test Org", Name: "Test Store", AddressId: "dacdbddabcadccbdacac"})
+	require.NoError(t, err)
+	stream, err := noBlobs.Client.UploadAttachment(nctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&api.UploadAttachmentRequest{Data: &api.UploadAttachmentRequest_Info{Info: &api.UploadAttachmentInfo{StoreId: resp.GetId(), Kind: string(stdom.ATTACHMENT_LOGO)}}}))
+	_, err = stream.CloseAndRecv()
+	requireCode(t, err, codes.Unimplemented)
+}
+
+func TestGRPCHandler_InProcess_Localization(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{})
+
+	asResp, err := srv.Client.AddStore(ctx, &api.AddStoreRequest{
+		Org:         "Test Org",
+		Name:        "Corner Bakery",
+		AddressId:   "dacdbddabcadccbdacac",
+		Description: "Bread and cakes",
+		Locale:      "en_us",
+		Translations: []*api.StoreTranslation{
+			{Locale: "fr-ca", Name: "Boulangerie du Coin", Description: "Pains et gâteaux"},
+			{Locale: "es", Name: "Panadería de la Esquina"},
+		},
+	})
+	require.NoError(t, err)
+	id := asResp.GetId()
+
+	// locales are normalized, the store's own are returned without a preference
+	gsResp, err := srv.Client.GetStore(ctx, &api.GetStoreRequest{Id: id})
+	require.NoError(t, err)
+	require.Equal(t, "Corner Bakery", gsResp.GetStore().GetName())
+	require.Equal(t, "en-US", gsResp.GetStore().GetLocale())
+	require.Equal(t, "fr-CA", gsResp.GetStore().GetTranslations()[0].GetLocale())
+
+	gsResp, err = srv.Client.GetStore(ctx, &api.GetStoreRequest{Id: id, Locale: "fr"})
+	require.NoError(t, err)
+	require.Equal(t, "Boulangerie du Coin", gsResp.GetStore().GetName())
+	require.Equal(t, "Pains et gâteaux", gsResp.GetStore().GetDescription())
+	require.Equal(t, "fr-CA", gsResp.GetStore().GetLocale())
+
+	// accept-language metadata, after the request's locale
+	mdCtx := metadata.AppendToOutgoingContext(ctx, stdom.ACCEPT_LANGUAGE_HEADER, "de, es-MX;q=0.9, fr;q=0.5")
+	gsResp, err = srv.Client.GetStore(mdCtx, &api.GetStoreRequest{Id: id})
+	require.NoError(t, err)
+	require.Equal(t, "Panadería de la Esquina", gsResp.GetStore().GetName())
+	require.Equal(t, "Bread and cakes", gsResp.GetStore().GetDescription())
+	gsResp, err = srv.Client.GetStore(mdCtx, &api.GetStoreRequest{Id: id, Locale: "fr-CA"})
+	require.NoError(t, err)
+	require.Equal(t, "Boulangerie du Coin", gsResp.GetStore().GetName())
+
+	// name search matches every locale, results are localized
+	ssResp, err := srv.Client.SearchStore(mdCtx, &api.SearchStoreRequest{Name: "boulangerie"})
+	require.NoError(t, err)
+	require.Len(t, ssResp.GetStores(), 1)
+	require.Equal(t, "Panadería de la Esquina", ssResp.GetStores()[0].GetStore().GetName())
+	ssResp, err = srv.Client.SearchStore(ctx, &api.SearchStoreRequest{Name: "Panaderia", Fuzzy: true, Locale: "en"})
+	require.NoError(t, err)
+	require.Len(t, ssResp.GetStores(), 1)
+	require.Equal(t, "Corner Bakery", ssResp.GetStores()[0].GetStore().GetName())
+
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: id, ClearTranslations: true})
+	require.NoError(t, err)
+	gsResp, err = srv.Client.GetStore(ctx, &api.GetStoreRequest{Id: id, Locale: "fr"})
+	require.NoError(t, err)
+	require.Equal(t, "Corner Bakery", gsResp.GetStore().GetName())
+	require.Empty(t, gsResp.GetStore().GetTranslations())
+
+	for _, trs := range [][]*api.StoreTranslation{
+		{{Locale: "fr", Name: "Boulangerie"}, {Locale: "FR", Name: "Boulangerie du Coin"}},
+		{{Locale: "fr"}},
+		{{Locale: "french", Name: "Boulangerie"}},
+	} {
+		_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: id, Translations: trs})
+		requireCode(t, err, codes.InvalidArgument)
+	}
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: id, Locale: "fr", Translations: []*api.StoreTranslation{{Locale: "fr", Name: "Boulangerie"}}})
+	requireCode(t, err, codes.InvalidArgument)
+	// translations are checked against the store's locale, and the locale against its translations
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: id, Translations: []*api.StoreTranslation{{Locale: "en-us", Name: "Bakery"}}})
+	requireStatus(t, err, codes.InvalidArgument, "invalid store translation: duplicate locale en-US")
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: id, Translations: []*api.StoreTranslation{{Locale: "fr", Name: "Boulangerie"}}})
+	require.NoError(t, err)
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: id, Locale: "FR"})
+	requireStatus(t, err, codes.InvalidArgument, "invalid store translation: duplicate locale fr")
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: id, Locale: "fr", Translations: []*api.StoreTranslation{{Locale: "en-US", Name: "Corner Bakery"}}})
+	require.NoError(t, err)
+	_, err = srv.Client.GetStore(ctx, &api.GetStoreRequest{Id: id, Locale: "*"})
+	requireStatus(t, err, codes.InvalidArgument, `invalid locale: "*"`)
+}
+
+func TestGRPCHandler_InProcess_ValidationRules(t *testing.T) {
+	rules := &stdom.ValidationRules{
+		Version:       1,
+		Name:          stdom.NameRules{MinLength: 2, MaxLength: 40, Charset: `\p{L}\p{N} '&.-`},
+		ReservedWords: []string{"admin"},
+		Orgs: []*stdom.OrgRules{
+			{Org: "Acme", NamePattern: `^Acme `, Required: []string{stdom.STORE_DESCRIPTION, stdom.STORE_REGION}},
+		},
+	}
+	require.NoError(t, rules.Compile())
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{ValidationRules: rules})
+
+	violations := func(err error) []string {
+		t.Helper()
+		requireCode(t, err, codes.InvalidArgument)
+		got := []string{}
+		for _, detail := range status.Convert(err).Details() {
+			ve, ok := detail.(*api.StoreValidationError)
+			require.True(t, ok)
+			for _, v := range ve.GetViolations() {
+				got = append(got, v.GetField()+": "+v.GetDescription())
+			}
+		}
+		return got
+	}
+
+	// every violation is returned at once
+	_, err := srv.Client.AddStore(ctx, &api.AddStoreRequest{Org: "Acme", Name: "Admin~Store"})
+	require.Equal(t, []string{
+		"address_id: is required",
+		`name: has disallowed characters "~"`,
+		`name: has reserved word "admin"`,
+		"name: doesn't match the Acme naming pattern ^Acme ",
+		"description: is required for Acme stores",
+		"region: is required for Acme stores",
+	}, violations(err))
+	require.Contains(t, status.Convert(err).Message(), "invalid store")
+
+	_, err = srv.Client.AddStore(ctx, &api.AddStoreRequest{
+		Org:          "Test Org",
+		Name:         "Test Store",
+		AddressId:    "dacdbddabcadccbdacac",
+		Translations: []*api.StoreTranslation{{Locale: "fr", Name: "Magasin <test>"}},
+	})
+	require.Equal(t, []string{`translations[0].name: has disallowed characters "<>"`}, violations(err))
+
+	resp, err := srv.Client.AddStore(ctx, &api.AddStoreRequest{
+		Org:         "Acme",
+		Name:        "Acme Market",
+		AddressId:   "dacdbddabcadccbdacac",
+		Description: "Groceries",
+		Region:      "west",
+	})
+	require.NoError(t, err)
+	acmeID := resp.GetId()
+
+	// updates are checked as applied to the store
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: acmeID, Name: "Market"})
+	require.Equal(t, []string{"name: doesn't match the Acme naming pattern ^Acme "}, violations(err))
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: acmeID, Name: "Acme Market & Deli"})
+	require.NoError(t, err)
+
+	// stores predating the rules can still be updated, unless moved to another org
+	legacyID, err := srv.Repo.AddStore(ctx, &stdom.Store{Org: "Test Org", Name: "Admin's #1 Store", AddressId: "dacdbddabcadccbdacad"})
+	require.NoError(t, err)
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: legacyID, Tags: []string{"hardware"}})
+	require.NoError(t, err)
+	_, err = srv.Client.UpdateStore(ctx, &api.UpdateStoreRequest{Id: legacyID, Org: "Acme", Region: "west"})
+	require.Equal(t, []string{
+		`name: has disallowed characters "#"`,
+		`name: has reserved word "admin"`,
+		"name: doesn't match the Acme naming pattern ^Acme ",
+		"description: is required for Acme stores",
+	}, violations(err))
+}
+
+func TestGRPCHandler_InProcess_OrgAddressUniqueness(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{
+		AddressUniqueness: stdom.ADDRESS_UNIQUE_ORG,
+	})
+
+	asResp, err := srv.Client.AddStore(ctx, &api.AddStoreRequest{
+		Org:       "Test Org",
+		Name:      "Test Store",
+		AddressId: "dacdbddabcadccbdacac",
+	})
+	require.NoError(t, err)
+
+	// other orgs can share the address
+	_, err = srv.Client.AddStore(ctx, &api.AddStoreRequest{
+		Org:       "Other Org",
+		Name:      "Kiosk",
+		AddressId: "dacdbddabcadccbdacac",
+	})
+	require.NoError(t, err)
+
+	_, err = srv.Client.AddStore(ctx, &api.AddStoreRequest{
+		Org:       "Test Org",
+		Name:      "Duplicate Store",
+		AddressId: "dacdbddabcadccbdacac",
+	})
+	requireCode(t, err, codes.AlreadyExists)
+	require.Contains(t, status.Convert(err).Message(), asResp.GetId())
+}
+
+func TestGRPCHandler_InProcess_GeoUnavailable(t *testing.T) {
+	ctx, srv := setupInProcessTest(t, testharness.StoresServerOptions{})
+
+	srv.Geo.SetFailure(status.Error(codes.Unavailable, "geo is down"))
+	_, err := srv.Client.AddStore(ctx, &api.AddStoreRequest{
+		Org:       "Test Org",
+		Name:      "Test Store",
+		AddressId: "dacdbddabcadccbdacac",
+	})
+	requireCode(t, err, codes.Unavailable)
+
+	srv.Geo.SetFailure(nil)
+	_, err = srv.Client.AddStore(ctx, &api.AddStoreRequest{
+		Org:       "Test Org",
+		Name:      "Test Store",
+		AddressId: "dacdbddabcadccbdacac",
+	})
+	require.NoError(t, err)
+}
+
+func setupInProcessTest(t *testing.T, opts testharness.StoresServerOptions) (context.Context, *testharness.StoresServer) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(cancel)
+	ctx = logger.WithLogger(ctx, logger.GetSlogLogger())
+
+	srv, err := testharness.NewStoresServer(ctx, opts)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, srv.Close(ctx))
+	})
+	return ctx, srv
+}
+
+func requireCode(t *testing.T, err error, code codes.Code) {
+	t.Helper()
+	require.Error(t, err)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, code, st.Code(), st.Message())
+}
+
+func requireStatus(t *testing.T, err error, code codes.Code, msg string) {
+	t.Helper()
+	requireCode(t, err, code)
+	require.Equal(t, msg, status.Convert(err).Message())
+}
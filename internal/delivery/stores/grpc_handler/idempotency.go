@@ -0,0 +1,236 @@
+package grpchandler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/comfforts/logger"
+
+	api "github.com/comfforts/comff-stores/api/stores/v1"
+	iddom "github.com/comfforts/comff-stores/internal/domain/idempotency"
+)
+
+const (
+	DEFAULT_IDEMPOTENCY_TTL  = 24 * time.Hour
+	DEFAULT_IDEMPOTENCY_LOCK = time.Minute
+	MAX_IDEMPOTENCY_KEY_LEN  = 255
+	// completing or releasing a key outlives the call, so a canceled client doesn't
+	// leave the key to lapse, up to IDEMPOTENCY_WRITE_TIMEOUT
+	IDEMPOTENCY_WRITE_TIMEOUT = 5 * time.Second
+)
+
+const (
+	ERR_IDEMPOTENCY_KEY_INVALID     = "invalid idempotency key"
+	ERR_IDEMPOTENCY_KEY_REUSED      = "idempotency key reused with a different request"
+	ERR_IDEMPOTENCY_KEY_IN_PROGRESS = "request with the same idempotency key in progress"
+	ERR_IDEMPOTENCY_UNAVAILABLE     = "error checking idempotency key"
+	ERR_IDEMPOTENCY_KEY_UNSUPPORTED = "idempotency keys are not supported on streaming RPCs"
+)
+
+// idempotentMethods are the mutating RPCs honoring the idempotency key header.
+var idempotentMethods = map[string]bool{
+	api.Stores_AddStore_FullMethodName:           true,
+	api.Stores_UpdateStore_FullMethodName:        true,
+	api.Stores_DeleteStore_FullMethodName:        true,
+	api.Stores_RegisterWebhook_FullMethodName:    true,
+	api.Stores_DeleteWebhook_FullMethodName:      true,
+	api.Stores_ScheduleClosure_FullMethodName:    true,
+	api.Stores_CancelClosure_FullMethodName:      true,
+	api.Stores_AddAttachment_FullMethodName:      true,
+	api.Stores_RemoveAttachment_FullMethodName:   true,
+	api.Stores_ReorderAttachments_FullMethodName: true,
+}
+
+// UnaryIdempotencyInterceptor makes mutating RPCs carrying an idempotency key safe to retry.
+// The first call with a key runs the handler & stores its response for ttl, retries with
+// the same key & payload get the stored response replayed, a different payload with the
+// same key is rejected. Failed calls release the key, so they can be retried. When the
+// response can't be stored, the key is held for ttl instead, retries failing as in progress
+// rather than running the call again. Keys are scoped by the caller's certificate subject & the RPC method.
+func UnaryIdempotencyInterceptor(repo iddom.IdempotencyRepo, ttl time.Duration) grpc.UnaryServerInterceptor {
+	if ttl <= 0 {
+		ttl = DEFAULT_IDEMPOTENCY_TTL
+	}
+
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if !idempotentMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		key := firstMetadataValue(md, iddom.IDEMPOTENCY_KEY_HEADER)
+		if key == "" {
+			return handler(ctx, req)
+		}
+
+		l, err := logger.LoggerFromContext(ctx)
+		if err != nil {
+			l = logger.GetSlogLogger()
+		}
+
+		if len(key) > MAX_IDEMPOTENCY_KEY_LEN {
+			return nil, status.New(codes.InvalidArgument, ERR_IDEMPOTENCY_KEY_INVALID).Err()
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		reqHash, err := hashRequest(msg)
+		if err != nil {
+			l.Error("error hashing idempotent request", "error", err.Error())
+			return nil, status.New(codes.Internal, ERR_IDEMPOTENCY_UNAVAILABLE).Err()
+		}
+
+		subj, _ := ctx.Value(subjectContextKey{}).(string)
+		scopedKey := subj + "|" + info.FullMethod + "|" + key
+
+		rec, reserved, err := repo.Reserve(ctx, scopedKey, reqHash, DEFAULT_IDEMPOTENCY_LOCK)
+		if err != nil {
+			l.Error("error reserving idempotency key", "error", err.Error())
+			return nil, status.New(codes.Unavailable, ERR_IDEMPOTENCY_UNAVAILABLE).Err()
+		}
+
+		if !reserved {
+			if rec.RequestHash != reqHash {
+				return nil, status.New(codes.InvalidArgument, ERR_IDEMPOTENCY_KEY_REUSED).Err()
+			}
+			if rec.Status != iddom.RECORD_COMPLETED {
+				return nil, status.New(codes.Aborted, ERR_IDEMPOTENCY_KEY_IN_PROGRESS).Err()
+			}
+
+			var stored anypb.Any
+			if err := proto.Unmarshal(rec.Response, &stored); err != nil {
+				l.Error("error decoding stored idempotent response", "error", err.Error())
+				return nil, status.New(codes.Internal, ERR_IDEMPOTENCY_UNAVAILABLE).Err()
+			}
+			resp, err := stored.UnmarshalNew()
+			if err != nil {
+				l.Error("error decoding stored idempotent response", "error", err.Error())
+				return nil, status.New(codes.Internal, ERR_IDEMPOTENCY_UNAVAILABLE).Err()
+			}
+
+			l.Debug("replaying idempotent response")
+			_ = grpc.SetHeader(ctx, metadata.Pairs(iddom.IDEMPOTENT_REPLAY_HEADER, "true"))
+			return resp, nil
+		}
+
+		// hold the key while the handler runs, however long it takes
+		stopRenewal := renewLock(context.WithoutCancel(ctx), repo, scopedKey, reqHash, DEFAULT_IDEMPOTENCY_LOCK)
+		resp, err := handler(ctx, req)
+		stopRenewal()
+
+		wCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), IDEMPOTENCY_WRITE_TIMEOUT)
+		defer cancel()
+		if err != nil {
+			if rErr := repo.Release(wCtx, scopedKey, reqHash); rErr != nil {
+				l.Error("error releasing idempotency key", "error", rErr.Error())
+			}
+			return resp, err
+		}
+
+		// the change is already committed, a failure to store the response
+		// doesn't fail the call, but the key stays held so retries don't repeat it
+		if cErr := completeKey(wCtx, repo, scopedKey, reqHash, resp, ttl); cErr != nil {
+			l.Error("error storing idempotent response", "error", cErr.Error())
+			if hErr := repo.Extend(wCtx, scopedKey, reqHash, ttl); hErr != nil {
+				l.Error("error holding idempotency key", "error", hErr.Error())
+			}
+		}
+		return resp, nil
+	}
+}
+
+// StreamIdempotencyInterceptor rejects streaming RPCs carrying an idempotency key, their
+// requests aren't deduplicated, so clients don't retry them believing they are.
+func StreamIdempotencyInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		if firstMetadataValue(md, iddom.IDEMPOTENCY_KEY_HEADER) != "" {
+			return status.New(codes.InvalidArgument, ERR_IDEMPOTENCY_KEY_UNSUPPORTED).Err()
+		}
+		return handler(srv, ss)
+	}
+}
+
+// completeKey stores the response for the reserved key.
+func completeKey(ctx context.Context, repo iddom.IdempotencyRepo, key, reqHash string, resp any, ttl time.Duration) error {
+	respMsg, ok := resp.(proto.Message)
+	if !ok {
+		return fmt.Errorf("unexpected response type %T", resp)
+	}
+	stored, err := anypb.New(respMsg)
+	if err != nil {
+		return err
+	}
+	b, err := proto.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return repo.Complete(ctx, key, reqHash, b, ttl)
+}
+
+// renewLock extends the key's lock every third of the lock duration, until the returned
+// stop function is called. A failed renewal is retried on the next tick, the key stays
+// held for the rest of the current lock.
+func renewLock(ctx context.Context, repo iddom.IdempotencyRepo, key, reqHash string, lock time.Duration) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(lock / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := repo.Extend(ctx, key, reqHash, lock); err != nil && ctx.Err() == nil {
+				l, lErr := logger.LoggerFromContext(ctx)
+				if lErr != nil {
+					l = logger.GetSlogLogger()
+				}
+				l.Error("error extending idempotency key lock", "error", err.Error())
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// hashRequest returns a stable hash of the request payload.
+func hashRequest(msg proto.Message) (string, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
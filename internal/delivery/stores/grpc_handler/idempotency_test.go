@@ -0,0 +1,237 @@
+package grpchandler_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/comfforts/logger"
+
+	api "github.com/comfforts/comff-stores/api/stores/v1"
+	grpchandler "github.com/comfforts/comff-stores/internal/delivery/stores/grpc_handler"
+	iddom "github.com/comfforts/comff-stores/internal/domain/idempotency"
+)
+
+func TestIdempotencyInterceptor(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(iddom.IDEMPOTENCY_KEY_HEADER, "key-1"))
+
+	repo := newMemIdempotency()
+	interceptor := grpchandler.UnaryIdempotencyInterceptor(repo, time.Hour)
+	info := &grpc.UnaryServerInfo{FullMethod: api.Stores_AddStore_FullMethodName}
+
+	calls := 0
+	handler := func(ctx context.Context, req any) (any, error) {
+		calls++
+		id := "st-1"
+		return &api.AddStoreResponse{Ok: true, Id: &id}, nil
+	}
+
+	req := &api.AddStoreRequest{Name: "Test Store", Org: "Test Org", AddressId: "dacdbddabcadccbdacac"}
+	resp, err := interceptor(ctx, req, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, "st-1", resp.(*api.AddStoreResponse).GetId())
+
+	// retry with same key & payload replays the stored response
+	resp, err = interceptor(ctx, req, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+	require.Equal(t, "st-1", resp.(*api.AddStoreResponse).GetId())
+
+	// same key with a different payload is rejected
+	_, err = interceptor(ctx, &api.AddStoreRequest{Name: "Other Store", Org: "Test Org", AddressId: "dacdbddabcadccbdacac"}, info, handler)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, 1, calls)
+
+	// calls without a key are not deduplicated
+	_, err = interceptor(logger.WithLogger(context.Background(), logger.GetSlogLogger()), req, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+}
+
+func TestIdempotencyInterceptorReleasesOnError(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(iddom.IDEMPOTENCY_KEY_HEADER, "key-1"))
+
+	repo := newMemIdempotency()
+	interceptor := grpchandler.UnaryIdempotencyInterceptor(repo, time.Hour)
+	info := &grpc.UnaryServerInfo{FullMethod: api.Stores_DeleteStore_FullMethodName}
+	req := &api.DeleteStoreRequest{Id: "st-1"}
+
+	calls := 0
+	failing := func(ctx context.Context, req any) (any, error) {
+		calls++
+		return nil, status.New(codes.Internal, "error deleting store").Err()
+	}
+	_, err := interceptor(ctx, req, info, failing)
+	require.Equal(t, codes.Internal, status.Code(err))
+
+	// a concurrent retry while the first call holds the key is aborted
+	var nestedErr error
+	succeeding := func(ctx context.Context, r any) (any, error) {
+		calls++
+		_, nestedErr = interceptor(ctx, req, info, failing)
+		return &api.DeleteStoreResponse{Ok: true}, nil
+	}
+	resp, err := interceptor(ctx, req, info, succeeding)
+	require.NoError(t, err)
+	require.True(t, resp.(*api.DeleteStoreResponse).GetOk())
+	require.Equal(t, codes.Aborted, status.Code(nestedErr))
+	require.Equal(t, 2, calls)
+}
+
+func TestIdempotencyInterceptorCanceledCall(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(iddom.IDEMPOTENCY_KEY_HEADER, "key-1"))
+
+	repo := newMemIdempotency()
+	interceptor := grpchandler.UnaryIdempotencyInterceptor(repo, time.Hour)
+	info := &grpc.UnaryServerInfo{FullMethod: api.Stores_AddStore_FullMethodName}
+	req := &api.AddStoreRequest{Name: "Test Store", Org: "Test Org", AddressId: "dacdbddabcadccbdacac"}
+
+	// the client gives up after the store is added, the response is still kept
+	calls := 0
+	callCtx, cancel := context.WithCancel(ctx)
+	handler := func(ctx context.Context, req any) (any, error) {
+		calls++
+		cancel()
+		id := "st-1"
+		return &api.AddStoreResponse{Ok: true, Id: &id}, nil
+	}
+	_, err := interceptor(callCtx, req, info, handler)
+	require.NoError(t, err)
+
+	resp, err := interceptor(ctx, req, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+	require.Equal(t, "st-1", resp.(*api.AddStoreResponse).GetId())
+}
+
+func TestIdempotencyInterceptorHoldsUnstoredKeys(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(iddom.IDEMPOTENCY_KEY_HEADER, "key-1"))
+
+	repo := newMemIdempotency()
+	repo.failComplete = true
+	interceptor := grpchandler.UnaryIdempotencyInterceptor(repo, time.Hour)
+	info := &grpc.UnaryServerInfo{FullMethod: api.Stores_AddStore_FullMethodName}
+	req := &api.AddStoreRequest{Name: "Test Store", Org: "Test Org", AddressId: "dacdbddabcadccbdacac"}
+
+	calls := 0
+	handler := func(ctx context.Context, req any) (any, error) {
+		calls++
+		id := "st-1"
+		return &api.AddStoreResponse{Ok: true, Id: &id}, nil
+	}
+	_, err := interceptor(ctx, req, info, handler)
+	require.NoError(t, err)
+
+	// without a stored response, retries aren't run again
+	_, err = interceptor(ctx, req, info, handler)
+	require.Equal(t, codes.Aborted, status.Code(err))
+	require.Equal(t, 1, calls)
+}
+
+func TestStreamIdempotencyInterceptor(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+	interceptor := grpchandler.StreamIdempotencyInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: api.Stores_UploadAttachment_FullMethodName, IsClientStream: true}
+
+	calls := 0
+	handler := func(srv any, ss grpc.ServerStream) error {
+		calls++
+		return nil
+	}
+
+	require.NoError(t, interceptor(nil, &ctxStream{ctx: ctx}, info, handler))
+	require.Equal(t, 1, calls)
+
+	keyed := metadata.NewIncomingContext(ctx, metadata.Pairs(iddom.IDEMPOTENCY_KEY_HEADER, "key-1"))
+	err := interceptor(nil, &ctxStream{ctx: keyed}, info, handler)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, 1, calls)
+}
+
+// ctxStream is a server stream with only a context.
+type ctxStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (cs *ctxStream) Context() context.Context {
+	return cs.ctx
+}
+
+type memIdempotency struct {
+	mu           sync.Mutex
+	records      map[string]*iddom.Record
+	failComplete bool
+}
+
+func newMemIdempotency() *memIdempotency {
+	return &memIdempotency{records: map[string]*iddom.Record{}}
+}
+
+func (mi *memIdempotency) Reserve(ctx context.Context, key, requestHash string, lock time.Duration) (*iddom.Record, bool, error) {
+	mi.mu.Lock()
+	defer mi.mu.Unlock()
+
+	if rec, ok := mi.records[key]; ok {
+		cp := *rec
+		return &cp, false, nil
+	}
+	rec := &iddom.Record{Key: key, RequestHash: requestHash, Status: iddom.RECORD_IN_PROGRESS}
+	mi.records[key] = rec
+	return rec, true, nil
+}
+
+func (mi *memIdempotency) Extend(ctx context.Context, key, requestHash string, lock time.Duration) error {
+	mi.mu.Lock()
+	defer mi.mu.Unlock()
+
+	rec, ok := mi.records[key]
+	if !ok || rec.RequestHash != requestHash || rec.Status != iddom.RECORD_IN_PROGRESS {
+		return errors.New("no idempotency record found")
+	}
+	return nil
+}
+
+func (mi *memIdempotency) Complete(ctx context.Context, key, requestHash string, response []byte, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	mi.mu.Lock()
+	defer mi.mu.Unlock()
+
+	if mi.failComplete {
+		return errors.New("error storing idempotency record")
+	}
+	rec, ok := mi.records[key]
+	if !ok || rec.RequestHash != requestHash || rec.Status != iddom.RECORD_IN_PROGRESS {
+		return errors.New("no idempotency record found")
+	}
+	rec.Status = iddom.RECORD_COMPLETED
+	rec.Response = response
+	return nil
+}
+
+func (mi *memIdempotency) Release(ctx context.Context, key, requestHash string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	mi.mu.Lock()
+	defer mi.mu.Unlock()
+
+	if rec, ok := mi.records[key]; ok && rec.RequestHash == requestHash && rec.Status == iddom.RECORD_IN_PROGRESS {
+		delete(mi.records, key)
+	}
+	return nil
+}
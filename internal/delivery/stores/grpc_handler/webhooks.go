@@ -0,0 +1,200 @@
+package grpchandler
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/comfforts/logger"
+
+	api "github.com/comfforts/comff-stores/api/stores/v1"
+	whdom "github.com/comfforts/comff-stores/internal/domain/webhooks"
+	whrepo "github.com/comfforts/comff-stores/internal/repo/webhooks"
+	whsvc "github.com/comfforts/comff-stores/internal/usecase/services/webhooks"
+)
+
+const (
+	registerWebhookAction      = "register-webhook"
+	deleteWebhookAction        = "delete-webhook"
+	listWebhooksAction         = "list-webhooks"
+	getWebhookDeliveriesAction = "get-webhook-deliveries"
+)
+
+const (
+	ERR_UNAUTHORIZED_REGISTER_WEBHOOK       = "unauthorized to register webhook"
+	ERR_UNAUTHORIZED_DELETE_WEBHOOK         = "unauthorized to delete webhook"
+	ERR_UNAUTHORIZED_LIST_WEBHOOKS          = "unauthorized to list webhooks"
+	ERR_UNAUTHORIZED_GET_WEBHOOK_DELIVERIES = "unauthorized to get webhook deliveries"
+	ERR_WEBHOOKS_DISABLED                   = "webhooks are not enabled on this server"
+)
+
+func (s *grpcServer) RegisterWebhook(ctx context.Context, req *api.RegisterWebhookRequest) (*api.RegisterWebhookResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		registerWebhookAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_REGISTER_WEBHOOK)
+		return nil, st.Err()
+	}
+
+	// webhooks need a persistent repo, servers booted without one don't serve them
+	if s.WebhooksService == nil {
+		st := status.New(codes.Unimplemented, ERR_WEBHOOKS_DISABLED)
+		return nil, st.Err()
+	}
+
+	if req == nil || req.GetUrl() == "" || req.GetSecret() == "" {
+		l.Error("RegisterWebhook called with invalid request: missing url or secret")
+		st := status.New(codes.InvalidArgument, "webhook url and secret are required")
+		return nil, st.Err()
+	}
+
+	id, err := s.WebhooksService.RegisterWebhook(ctx, whdom.MapToRegisterWebhookParams(req))
+	if err != nil {
+		l.Error("error registering webhook", "error", err.Error())
+		if errors.Is(err, whsvc.ErrInvalidWebhookURL) || errors.Is(err, whsvc.ErrInvalidEventType) {
+			st := status.New(codes.InvalidArgument, err.Error())
+			return nil, st.Err()
+		}
+		st := status.New(codes.Internal, "error registering webhook")
+		return nil, st.Err()
+	}
+
+	return &api.RegisterWebhookResponse{
+		Ok: true,
+		Id: &id,
+	}, nil
+}
+
+func (s *grpcServer) DeleteWebhook(ctx context.Context, req *api.DeleteWebhookRequest) (*api.DeleteWebhookResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		deleteWebhookAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_DELETE_WEBHOOK)
+		return nil, st.Err()
+	}
+
+	if s.WebhooksService == nil {
+		st := status.New(codes.Unimplemented, ERR_WEBHOOKS_DISABLED)
+		return nil, st.Err()
+	}
+
+	if req == nil || req.GetId() == "" {
+		l.Error("DeleteWebhook called with invalid request: missing webhook ID")
+		st := status.New(codes.InvalidArgument, "webhook ID is required")
+		return nil, st.Err()
+	}
+
+	if err := s.WebhooksService.DeleteWebhook(ctx, req.GetId()); err != nil {
+		l.Error("error deleting webhook", "error", err.Error(), "webhook_id", req.GetId())
+		if errors.Is(err, whrepo.ErrNoWebhook) {
+			st := status.New(codes.NotFound, err.Error())
+			return nil, st.Err()
+		}
+		st := status.New(codes.Internal, "error deleting webhook")
+		return nil, st.Err()
+	}
+
+	return &api.DeleteWebhookResponse{
+		Ok: true,
+	}, nil
+}
+
+func (s *grpcServer) ListWebhooks(ctx context.Context, req *api.ListWebhooksRequest) (*api.ListWebhooksResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		listWebhooksAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_LIST_WEBHOOKS)
+		return nil, st.Err()
+	}
+
+	if s.WebhooksService == nil {
+		st := status.New(codes.Unimplemented, ERR_WEBHOOKS_DISABLED)
+		return nil, st.Err()
+	}
+
+	subs, err := s.WebhooksService.ListWebhooks(ctx, req.GetOrg())
+	if err != nil {
+		l.Error("error listing webhooks", "error", err.Error())
+		st := status.New(codes.Internal, "error listing webhooks")
+		return nil, st.Err()
+	}
+
+	var webhooks []*api.Webhook
+	for _, sub := range subs {
+		webhooks = append(webhooks, whdom.MapToWebhookProto(sub))
+	}
+
+	return &api.ListWebhooksResponse{
+		Webhooks: webhooks,
+	}, nil
+}
+
+func (s *grpcServer) GetWebhookDeliveries(ctx context.Context, req *api.GetWebhookDeliveriesRequest) (*api.GetWebhookDeliveriesResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// Authorization check
+	if err := s.Authorizer.Authorize(
+		subject(ctx),
+		objectWildcard,
+		getWebhookDeliveriesAction,
+	); err != nil {
+		st := status.New(codes.Unauthenticated, ERR_UNAUTHORIZED_GET_WEBHOOK_DELIVERIES)
+		return nil, st.Err()
+	}
+
+	if s.WebhooksService == nil {
+		st := status.New(codes.Unimplemented, ERR_WEBHOOKS_DISABLED)
+		return nil, st.Err()
+	}
+
+	if req == nil || req.GetWebhookId() == "" {
+		l.Error("GetWebhookDeliveries called with invalid request: missing webhook ID")
+		st := status.New(codes.InvalidArgument, "webhook ID is required")
+		return nil, st.Err()
+	}
+
+	deliveries, err := s.WebhooksService.GetDeliveries(ctx, whdom.MapToDeliveriesQuery(req))
+	if err != nil {
+		l.Error("error getting webhook deliveries", "error", err.Error(), "webhook_id", req.GetWebhookId())
+		st := status.New(codes.Internal, "error getting webhook deliveries")
+		return nil, st.Err()
+	}
+
+	var deliveryProtos []*api.WebhookDelivery
+	for _, d := range deliveries {
+		deliveryProtos = append(deliveryProtos, whdom.MapToWebhookDeliveryProto(d))
+	}
+
+	return &api.GetWebhookDeliveriesResponse{
+		Deliveries: deliveryProtos,
+	}, nil
+}
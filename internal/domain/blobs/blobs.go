@@ -0,0 +1,20 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+const INVALID_BLOB_KEY = "invalid blob key"
+
+var ErrInvalidBlobKey = errors.New(INVALID_BLOB_KEY)
+
+// BlobStore stores blobs, e.g. uploaded store media, by slash separated relative keys.
+type BlobStore interface {
+	// Put writes the blob's content at the key, replacing any blob there, returning
+	// the URI it's served at.
+	Put(ctx context.Context, key string, r io.Reader) (string, error)
+	// Delete removes the blob at the key, missing blobs aren't an error.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,63 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+type EventType string
+
+const (
+	STORE_ADDED   EventType = "store.added"
+	STORE_UPDATED EventType = "store.updated"
+	STORE_DELETED EventType = "store.deleted"
+)
+
+type OutboxStatus string
+
+const (
+	OUTBOX_PENDING   OutboxStatus = "pending"
+	OUTBOX_DELIVERED OutboxStatus = "delivered"
+)
+
+// EventPublisher delivers store domain events to downstream consumers.
+// Delivery is at-least-once, consumers should de-duplicate on event ID.
+type EventPublisher interface {
+	Publish(ctx context.Context, ev *Event) error
+	Close(ctx context.Context) error
+}
+
+// OutboxRepo reads and settles events written to the transactional outbox.
+type OutboxRepo interface {
+	// ClaimPending leases up to limit due entries for the given duration,
+	// so that concurrent relays don't pick up the same entries.
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*OutboxEntry, error)
+	MarkDelivered(ctx context.Context, id string) error
+	MarkFailed(ctx context.Context, id string, nextAttemptAt time.Time, lastErr string) error
+}
+
+// Event is a store change. Org is the store's org after the change, PreviousOrg the org
+// an update moved the store from.
+type Event struct {
+	ID          string       `bson:"id" json:"id"`
+	Type        EventType    `bson:"type" json:"type"`
+	StoreID     string       `bson:"store_id" json:"store_id"`
+	Org         string       `bson:"org" json:"org"`
+	PreviousOrg string       `bson:"previous_org,omitempty" json:"previous_org,omitempty"`
+	Store       *stdom.Store `bson:"store,omitempty" json:"store,omitempty"`
+	OccurredAt  time.Time    `bson:"occurred_at" json:"occurred_at"`
+}
+
+type OutboxEntry struct {
+	ID            string       `bson:"_id,omitempty"`
+	Event         *Event       `bson:"event"`
+	Status        OutboxStatus `bson:"status"`
+	Attempts      int          `bson:"attempts"`
+	NextAttemptAt time.Time    `bson:"next_attempt_at"`
+	LockedUntil   time.Time    `bson:"locked_until"`
+	LastError     string       `bson:"last_error,omitempty"`
+	CreatedAt     time.Time    `bson:"created_at"`
+	DeliveredAt   *time.Time   `bson:"delivered_at,omitempty"`
+}
@@ -0,0 +1,46 @@
+package geo
+
+import "math"
+
+// EARTH_RADIUS_METERS is the mean earth radius distances are measured on.
+const EARTH_RADIUS_METERS = 6371008.8
+
+// DistanceMeters is the great circle distance between two points, by the haversine formula.
+func DistanceMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	rlat1, rlat2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dLat, dLon := rlat2-rlat1, (lon2-lon1)*math.Pi/180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(rlat1)*math.Cos(rlat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EARTH_RADIUS_METERS * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// CircleBounds returns the boxes covering every point within meters of the center, one
+// box or two when the circle crosses the antimeridian. Circles reaching a pole span all
+// longitudes.
+func CircleBounds(lat, lon, meters float64) []*BoundingBox {
+	d := meters / EARTH_RADIUS_METERS * 180 / math.Pi
+	minLat, maxLat := lat-d, lat+d
+	if minLat <= -90 || maxLat >= 90 || d >= 90 {
+		return []*BoundingBox{{MinLat: max(minLat, -90), MinLon: -180, MaxLat: min(maxLat, 90), MaxLon: 180}}
+	}
+
+	// the circle's widest longitude offset, reached north or south of the center
+	sinD, cosLat := math.Sin(meters/EARTH_RADIUS_METERS), math.Cos(lat*math.Pi/180)
+	if sinD >= cosLat {
+		return []*BoundingBox{{MinLat: minLat, MinLon: -180, MaxLat: maxLat, MaxLon: 180}}
+	}
+	dLon := math.Asin(sinD/cosLat) * 180 / math.Pi
+	minLon, maxLon := lon-dLon, lon+dLon
+	switch {
+	case minLon < -180:
+		return []*BoundingBox{
+			{MinLat: minLat, MinLon: -180, MaxLat: maxLat, MaxLon: maxLon},
+			{MinLat: minLat, MinLon: minLon + 360, MaxLat: maxLat, MaxLon: 180},
+		}
+	case maxLon > 180:
+		return []*BoundingBox{
+			{MinLat: minLat, MinLon: minLon, MaxLat: maxLat, MaxLon: 180},
+			{MinLat: minLat, MinLon: -180, MaxLat: maxLat, MaxLon: maxLon - 360},
+		}
+	}
+	return []*BoundingBox{{MinLat: minLat, MinLon: minLon, MaxLat: maxLat, MaxLon: maxLon}}
+}
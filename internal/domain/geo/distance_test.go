@@ -0,0 +1,44 @@
+package geo_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+)
+
+func TestDistanceMeters(t *testing.T) {
+	// Petaluma to San Francisco
+	require.InDelta(t, 54100, geodom.DistanceMeters(38.227476, -122.6461669, 37.7749, -122.4194), 500)
+	require.InDelta(t, 0, geodom.DistanceMeters(37.7749, -122.4194, 37.7749, -122.4194), 1e-6)
+	// half way round the equator
+	require.InDelta(t, 20015114, geodom.DistanceMeters(0, 0, 0, 180), 1)
+}
+
+func TestCircleBounds(t *testing.T) {
+	lat, lon := 38.227476, -122.6461669
+	boxes := geodom.CircleBounds(lat, lon, 10000)
+	require.Len(t, boxes, 1)
+	require.True(t, boxes[0].Valid())
+	// points on the circle are in the box
+	for _, pt := range [][2]float64{{lat + 0.0899, lon}, {lat - 0.0899, lon}, {lat, lon + 0.114}, {lat, lon - 0.114}} {
+		require.LessOrEqual(t, geodom.DistanceMeters(lat, lon, pt[0], pt[1]), 10000.0, pt)
+		require.True(t, boxes[0].Contains(pt[0], pt[1]), pt)
+	}
+	require.False(t, boxes[0].Contains(lat+0.1, lon))
+
+	// crossing the antimeridian
+	boxes = geodom.CircleBounds(-17.7, 179.9, 50000)
+	require.Len(t, boxes, 2)
+	require.True(t, boxes[0].Valid())
+	require.True(t, boxes[1].Valid())
+	require.True(t, boxes[0].Contains(-17.7, 179.95))
+	require.True(t, boxes[1].Contains(-17.7, -179.8))
+
+	// reaching a pole
+	boxes = geodom.CircleBounds(89.5, 10, 100000)
+	require.Len(t, boxes, 1)
+	require.Equal(t, &geodom.BoundingBox{MinLat: 89.5 - 100000/geodom.EARTH_RADIUS_METERS*180/math.Pi, MinLon: -180, MaxLat: 90, MaxLon: 180}, boxes[0])
+}
@@ -0,0 +1,90 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	ERR_GEO_UNAVAILABLE = "geo service unavailable"
+	ERR_INVALID_ADDRESS = "invalid address"
+)
+
+var (
+	// ErrGeoUnavailable is returned, wrapping the cause, when the geocoder
+	// couldn't answer, the request may succeed when retried.
+	ErrGeoUnavailable = errors.New(ERR_GEO_UNAVAILABLE)
+	// ErrInvalidAddress is returned, wrapping the cause, when the geocoder
+	// rejected or couldn't resolve the address.
+	ErrInvalidAddress = errors.New(ERR_INVALID_ADDRESS)
+)
+
+const (
+	DEFAULT_CACHE_SIZE        = 10000
+	DEFAULT_CACHE_TTL         = time.Hour
+	DEFAULT_MAX_RETRIES       = 2
+	DEFAULT_RETRY_BACKOFF     = 100 * time.Millisecond
+	DEFAULT_BREAKER_THRESHOLD = 5
+	DEFAULT_BREAKER_COOLDOWN  = 30 * time.Second
+)
+
+// ResilienceOptions configure the geo client's response cache, retries & circuit breaker.
+type ResilienceOptions struct {
+	CacheSize        int
+	CacheTTL         time.Duration
+	MaxRetries       int
+	RetryBackoff     time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+func DefaultResilienceOptions() ResilienceOptions {
+	return ResilienceOptions{
+		CacheSize:        DEFAULT_CACHE_SIZE,
+		CacheTTL:         DEFAULT_CACHE_TTL,
+		MaxRetries:       DEFAULT_MAX_RETRIES,
+		RetryBackoff:     DEFAULT_RETRY_BACKOFF,
+		BreakerThreshold: DEFAULT_BREAKER_THRESHOLD,
+		BreakerCooldown:  DEFAULT_BREAKER_COOLDOWN,
+	}
+}
+
+// Geocoder resolves addresses & points to locations keyed by address ID,
+// the quadhash of the location (see EncodeAddressId).
+type Geocoder interface {
+	// LocateAddressId validates an address ID, returning its location.
+	LocateAddressId(ctx context.Context, addressId string) (*Location, error)
+	// GeocodeAddress resolves a free form address string.
+	GeocodeAddress(ctx context.Context, addressStr string) (*Location, error)
+	// GeocodeLatLon resolves a point.
+	GeocodeLatLon(ctx context.Context, lat, lon float64) (*Location, error)
+	Close(ctx context.Context) error
+}
+
+type Location struct {
+	AddressId        string
+	Latitude         float64
+	Longitude        float64
+	FormattedAddress string
+}
+
+// BoundingBox is the area between two parallels & two meridians. Boxes crossing
+// the antimeridian aren't supported, split them in two.
+type BoundingBox struct {
+	MinLat float64
+	MinLon float64
+	MaxLat float64
+	MaxLon float64
+}
+
+func (b *BoundingBox) Valid() bool {
+	return b != nil &&
+		b.MinLat >= -90 && b.MaxLat <= 90 && b.MinLat <= b.MaxLat &&
+		b.MinLon >= -180 && b.MaxLon <= 180 && b.MinLon <= b.MaxLon
+}
+
+// Contains reports whether the point is in the box, edges included.
+func (b *BoundingBox) Contains(lat, lon float64) bool {
+	return lat >= b.MinLat && lat <= b.MaxLat && lon >= b.MinLon && lon <= b.MaxLon
+}
@@ -0,0 +1,209 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const ERR_INVALID_GEOJSON = "invalid GeoJSON geometry"
+
+var ErrInvalidGeoJSON = errors.New(ERR_INVALID_GEOJSON)
+
+// Position is a GeoJSON position, longitude first.
+type Position [2]float64
+
+// Ring is a GeoJSON linear ring, closed when its first & last positions are equal.
+type Ring []Position
+
+// Polygon is a GeoJSON polygon, an exterior ring followed by the rings of its holes.
+type Polygon []Ring
+
+// GeoJSONPoint is a point stored as GeoJSON, for mongo 2dsphere indexes & queries.
+type GeoJSONPoint struct {
+	Type        string   `bson:"type" json:"type"`
+	Coordinates Position `bson:"coordinates" json:"coordinates"`
+}
+
+func NewGeoJSONPoint(lat, lon float64) *GeoJSONPoint {
+	return &GeoJSONPoint{Type: "Point", Coordinates: Position{lon, lat}}
+}
+
+// GeoJSONMultiPolygon is an area stored as GeoJSON, for mongo 2dsphere indexes & queries.
+type GeoJSONMultiPolygon struct {
+	Type        string    `bson:"type" json:"type"`
+	Coordinates []Polygon `bson:"coordinates" json:"coordinates"`
+}
+
+func NewGeoJSONMultiPolygon(area []Polygon) *GeoJSONMultiPolygon {
+	return &GeoJSONMultiPolygon{Type: "MultiPolygon", Coordinates: area}
+}
+
+// BoundsPolygon returns the box as a counterclockwise polygon.
+func BoundsPolygon(b *BoundingBox) Polygon {
+	return Polygon{{
+		{b.MinLon, b.MinLat},
+		{b.MaxLon, b.MinLat},
+		{b.MaxLon, b.MaxLat},
+		{b.MinLon, b.MaxLat},
+		{b.MinLon, b.MinLat},
+	}}
+}
+
+// ParseGeoJSONArea parses a GeoJSON Polygon or MultiPolygon geometry into its polygons.
+func ParseGeoJSONArea(data []byte) ([]Polygon, error) {
+	var geom struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+	if err := json.Unmarshal(data, &geom); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidGeoJSON, err)
+	}
+
+	switch geom.Type {
+	case "Polygon":
+		var p Polygon
+		if err := json.Unmarshal(geom.Coordinates, &p); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidGeoJSON, err)
+		}
+		return []Polygon{p}, nil
+	case "MultiPolygon":
+		var ps []Polygon
+		if err := json.Unmarshal(geom.Coordinates, &ps); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidGeoJSON, err)
+		}
+		return ps, nil
+	}
+	return nil, fmt.Errorf("%w: type %q isn't Polygon or MultiPolygon", ErrInvalidGeoJSON, geom.Type)
+}
+
+// Closed reports whether the ring ends where it starts.
+func (r Ring) Closed() bool {
+	return len(r) > 0 && r[0] == r[len(r)-1]
+}
+
+// SignedArea is the ring's planar area in square degrees, positive when counterclockwise.
+func (r Ring) SignedArea() float64 {
+	area := 0.0
+	for i := 0; i+1 < len(r); i++ {
+		area += r[i][0]*r[i+1][1] - r[i+1][0]*r[i][1]
+	}
+	return area / 2
+}
+
+// Contains reports whether the point is inside the closed ring, by ray casting.
+func (r Ring) Contains(lat, lon float64) bool {
+	in := false
+	for i := 0; i+1 < len(r); i++ {
+		a, b := r[i], r[i+1]
+		if (a[1] > lat) != (b[1] > lat) &&
+			lon < a[0]+(lat-a[1])*(b[0]-a[0])/(b[1]-a[1]) {
+			in = !in
+		}
+	}
+	return in
+}
+
+// Contains reports whether the point is inside the exterior ring & outside the holes.
+func (p Polygon) Contains(lat, lon float64) bool {
+	if len(p) == 0 || !p[0].Contains(lat, lon) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if hole.Contains(lat, lon) {
+			return false
+		}
+	}
+	return true
+}
+
+// SelfIntersects reports whether the polygon's rings cross or touch themselves or each
+// other, besides a ring's consecutive edges meeting. Repeated positions are skipped.
+func (p Polygon) SelfIntersects() bool {
+	type edge struct {
+		a, b       Position
+		ring, next int
+	}
+	edges := []edge{}
+	for i, r := range p {
+		start := len(edges)
+		for j := 0; j+1 < len(r); j++ {
+			if r[j] != r[j+1] {
+				edges = append(edges, edge{a: r[j], b: r[j+1], ring: i})
+			}
+		}
+		// each edge's successor, the last wrapping round to the first
+		for j := start; j < len(edges); j++ {
+			edges[j].next = j + 1
+		}
+		if len(edges) > start {
+			edges[len(edges)-1].next = start
+		}
+	}
+
+	for i := range edges {
+		for j := i + 1; j < len(edges); j++ {
+			e, f := edges[i], edges[j]
+			switch {
+			case e.ring == f.ring && e.next == j:
+				if backtracks(e.a, e.b, f.b) {
+					return true
+				}
+			case e.ring == f.ring && f.next == i:
+				if backtracks(f.a, f.b, e.b) {
+					return true
+				}
+			case segmentsIntersect(e.a, e.b, f.a, f.b):
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// backtracks reports whether the edge from b to c doubles back over the edge from a to b.
+func backtracks(a, b, c Position) bool {
+	return orientation(a, b, c) == 0 &&
+		(a[0]-b[0])*(c[0]-b[0])+(a[1]-b[1])*(c[1]-b[1]) > 0
+}
+
+// segmentsIntersect reports whether the segments from a to b & from c to d share a position.
+func segmentsIntersect(a, b, c, d Position) bool {
+	o1, o2 := orientation(a, b, c), orientation(a, b, d)
+	o3, o4 := orientation(c, d, a), orientation(c, d, b)
+	if o1*o2 < 0 && o3*o4 < 0 {
+		return true
+	}
+	return (o1 == 0 && onSegment(a, b, c)) ||
+		(o2 == 0 && onSegment(a, b, d)) ||
+		(o3 == 0 && onSegment(c, d, a)) ||
+		(o4 == 0 && onSegment(c, d, b))
+}
+
+// orientation is the sign of the turn from a to b to c, positive when counterclockwise.
+func orientation(a, b, c Position) float64 {
+	v := (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// onSegment reports whether c, collinear with a & b, is between them.
+func onSegment(a, b, c Position) bool {
+	return min(a[0], b[0]) <= c[0] && c[0] <= max(a[0], b[0]) &&
+		min(a[1], b[1]) <= c[1] && c[1] <= max(a[1], b[1])
+}
+
+// AreaContains reports whether the point is inside any of the polygons.
+func AreaContains(area []Polygon, lat, lon float64) bool {
+	for _, p := range area {
+		if p.Contains(lat, lon) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package geo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+)
+
+func TestParseGeoJSONArea(t *testing.T) {
+	// a square around Petaluma with a hole around downtown
+	area, err := geodom.ParseGeoJSONArea([]byte(`{
+		"type": "Polygon",
+		"coordinates": [
+			[[-122.7, 38.2], [-122.6, 38.2], [-122.6, 38.3], [-122.7, 38.3], [-122.7, 38.2]],
+			[[-122.65, 38.22], [-122.65, 38.23], [-122.64, 38.23], [-122.64, 38.22], [-122.65, 38.22]]
+		]
+	}`))
+	require.NoError(t, err)
+	require.Len(t, area, 1)
+	require.Len(t, area[0], 2)
+	require.True(t, area[0][0].Closed())
+	require.Positive(t, area[0][0].SignedArea())
+	require.Negative(t, area[0][1].SignedArea())
+
+	// 2 Turquoise Ct is inside, 201 Fair St in the hole
+	require.True(t, geodom.AreaContains(area, 38.22507858276367, -122.61660766601562))
+	require.False(t, geodom.AreaContains(area, 38.227476, -122.6461669))
+	require.False(t, geodom.AreaContains(area, 37.7749, -122.4194))
+
+	area, err = geodom.ParseGeoJSONArea([]byte(`{"type": "MultiPolygon", "coordinates": [
+		[[[-122.7, 38.2], [-122.6, 38.2], [-122.6, 38.3], [-122.7, 38.3], [-122.7, 38.2]]],
+		[[[-122.5, 37.7], [-122.4, 37.7], [-122.4, 37.8], [-122.5, 37.8], [-122.5, 37.7]]]
+	]}`))
+	require.NoError(t, err)
+	require.Len(t, area, 2)
+	require.True(t, geodom.AreaContains(area, 37.7749, -122.4194))
+
+	_, err = geodom.ParseGeoJSONArea([]byte(`{"type": "Point", "coordinates": [-122.6, 38.2]}`))
+	require.ErrorIs(t, err, geodom.ErrInvalidGeoJSON)
+	_, err = geodom.ParseGeoJSONArea([]byte(`{"type": "Polygon", "coordinates": [[-122.6, 38.2]]}`))
+	require.ErrorIs(t, err, geodom.ErrInvalidGeoJSON)
+	_, err = geodom.ParseGeoJSONArea([]byte(`not json`))
+	require.ErrorIs(t, err, geodom.ErrInvalidGeoJSON)
+}
+
+func TestBoundsPolygon(t *testing.T) {
+	b := &geodom.BoundingBox{MinLat: 38.2, MinLon: -122.7, MaxLat: 38.3, MaxLon: -122.6}
+	p := geodom.BoundsPolygon(b)
+	require.True(t, p[0].Closed())
+	require.Positive(t, p[0].SignedArea())
+	require.True(t, p.Contains(38.227476, -122.6461669))
+	require.False(t, p.Contains(38.31, -122.65))
+}
+
+func TestPolygonSelfIntersects(t *testing.T) {
+	square := geodom.Ring{{-122.7, 38.2}, {-122.6, 38.2}, {-122.6, 38.3}, {-122.7, 38.3}, {-122.7, 38.2}}
+	hole := geodom.Ring{{-122.65, 38.22}, {-122.65, 38.23}, {-122.64, 38.23}, {-122.64, 38.22}, {-122.65, 38.22}}
+	require.False(t, geodom.Polygon{square}.SelfIntersects())
+	require.False(t, geodom.Polygon{square, hole}.SelfIntersects())
+	// repeated positions aren't edges
+	require.False(t, geodom.Polygon{{{-122.7, 38.2}, {-122.6, 38.2}, {-122.6, 38.2}, {-122.6, 38.3}, {-122.7, 38.2}}}.SelfIntersects())
+
+	// a bow tie crosses itself
+	require.True(t, geodom.Polygon{{{-122.7, 38.2}, {-122.6, 38.3}, {-122.6, 38.2}, {-122.7, 38.3}, {-122.7, 38.2}}}.SelfIntersects())
+	// a spike doubles back over its edge
+	require.True(t, geodom.Polygon{{{-122.7, 38.2}, {-122.6, 38.2}, {-122.65, 38.2}, {-122.65, 38.3}, {-122.7, 38.2}}}.SelfIntersects())
+	// a ring touching itself at a position
+	require.True(t, geodom.Polygon{{{-122.7, 38.2}, {-122.6, 38.2}, {-122.65, 38.25}, {-122.6, 38.3}, {-122.7, 38.3}, {-122.65, 38.25}, {-122.7, 38.2}}}.SelfIntersects())
+	// a hole crossing the exterior ring
+	require.True(t, geodom.Polygon{square, {{-122.65, 38.25}, {-122.65, 38.35}, {-122.64, 38.35}, {-122.64, 38.25}, {-122.65, 38.25}}}.SelfIntersects())
+}
@@ -0,0 +1,150 @@
+package geo
+
+import (
+	"errors"
+	"strings"
+)
+
+// DEFAULT_ADDRESS_ID_PRECISION is the address ID length, each character
+// halves the cell, 20 characters resolve to roughly 2x4 cm at the equator.
+const DEFAULT_ADDRESS_ID_PRECISION = 20
+
+const ERR_INVALID_ADDRESS_ID = "invalid address ID"
+
+var ErrInvalidAddressId = errors.New(ERR_INVALID_ADDRESS_ID)
+
+// Address IDs are quadhashes, each character picks a quadrant of the
+// previous cell, starting from the whole globe:
+//
+//	d | c
+//	--+--
+//	a | b
+//
+// so a shared prefix means a shared enclosing cell.
+const (
+	QUAD_SW = 'a'
+	QUAD_SE = 'b'
+	QUAD_NE = 'c'
+	QUAD_NW = 'd'
+)
+
+// EncodeAddressId returns the quadhash of the point with the given precision.
+func EncodeAddressId(lat, lon float64, precision int) string {
+	minLat, maxLat := -90.0, 90.0
+	minLon, maxLon := -180.0, 180.0
+
+	var sb strings.Builder
+	sb.Grow(precision)
+	for i := 0; i < precision; i++ {
+		midLat := (minLat + maxLat) / 2
+		midLon := (minLon + maxLon) / 2
+
+		north := lat >= midLat
+		east := lon >= midLon
+		switch {
+		case north && east:
+			sb.WriteByte(QUAD_NE)
+		case north:
+			sb.WriteByte(QUAD_NW)
+		case east:
+			sb.WriteByte(QUAD_SE)
+		default:
+			sb.WriteByte(QUAD_SW)
+		}
+
+		if north {
+			minLat = midLat
+		} else {
+			maxLat = midLat
+		}
+		if east {
+			minLon = midLon
+		} else {
+			maxLon = midLon
+		}
+	}
+	return sb.String()
+}
+
+// AddressIdBounds returns the cell covered by the address ID.
+func AddressIdBounds(addressId string) (minLat, minLon, maxLat, maxLon float64, err error) {
+	minLat, maxLat = -90.0, 90.0
+	minLon, maxLon = -180.0, 180.0
+
+	if addressId == "" {
+		return 0, 0, 0, 0, ErrInvalidAddressId
+	}
+	for i := 0; i < len(addressId); i++ {
+		midLat := (minLat + maxLat) / 2
+		midLon := (minLon + maxLon) / 2
+		switch addressId[i] {
+		case QUAD_SW:
+			maxLat, maxLon = midLat, midLon
+		case QUAD_SE:
+			maxLat, minLon = midLat, midLon
+		case QUAD_NE:
+			minLat, minLon = midLat, midLon
+		case QUAD_NW:
+			minLat, maxLon = midLat, midLon
+		default:
+			return 0, 0, 0, 0, ErrInvalidAddressId
+		}
+	}
+	return minLat, minLon, maxLat, maxLon, nil
+}
+
+// DecodeAddressId returns the center point of the address ID's cell.
+func DecodeAddressId(addressId string) (lat, lon float64, err error) {
+	minLat, minLon, maxLat, maxLon, err := AddressIdBounds(addressId)
+	if err != nil {
+		return 0, 0, err
+	}
+	return (minLat + maxLat) / 2, (minLon + maxLon) / 2, nil
+}
+
+// CoverBounds returns the address ID prefixes of the cells intersecting the box, at
+// the precision, or the finest coarser precision with at most maxCells cells. Every
+// address ID in the box has one of the prefixes, the whole globe's is "".
+func CoverBounds(b *BoundingBox, precision, maxCells int) []string {
+	for ; precision > 0; precision-- {
+		if cells, ok := coverCells(b, precision, maxCells); ok {
+			return cells
+		}
+	}
+	return []string{""}
+}
+
+type quadCell struct {
+	id                             string
+	minLat, minLon, maxLat, maxLon float64
+}
+
+func coverCells(b *BoundingBox, precision, maxCells int) ([]string, bool) {
+	cells := []quadCell{{minLat: -90, minLon: -180, maxLat: 90, maxLon: 180}}
+	for i := 0; i < precision; i++ {
+		var next []quadCell
+		for _, c := range cells {
+			midLat, midLon := (c.minLat+c.maxLat)/2, (c.minLon+c.maxLon)/2
+			for _, q := range []quadCell{
+				{c.id + string(QUAD_SW), c.minLat, c.minLon, midLat, midLon},
+				{c.id + string(QUAD_SE), c.minLat, midLon, midLat, c.maxLon},
+				{c.id + string(QUAD_NE), midLat, midLon, c.maxLat, c.maxLon},
+				{c.id + string(QUAD_NW), midLat, c.minLon, c.maxLat, midLon},
+			} {
+				if q.minLat <= b.MaxLat && q.maxLat >= b.MinLat && q.minLon <= b.MaxLon && q.maxLon >= b.MinLon {
+					next = append(next, q)
+				}
+			}
+			if len(next) > maxCells {
+				return nil, false
+			}
+		}
+		cells = next
+	}
+
+	ids := make([]string, 0, len(cells))
+	for _, c := range cells {
+		ids = append(ids, c.id)
+	}
+	return ids, true
+}
@@ -0,0 +1,66 @@
+package geo_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+)
+
+func TestEncodeAddressId(t *testing.T) {
+	// 201 Fair St, Petaluma, CA
+	id := geodom.EncodeAddressId(38.227476, -122.6461669, 18)
+	require.Equal(t, "dacdbddabcadcddabb", id)
+	require.Len(t, geodom.EncodeAddressId(38.227476, -122.6461669, geodom.DEFAULT_ADDRESS_ID_PRECISION), geodom.DEFAULT_ADDRESS_ID_PRECISION)
+}
+
+func TestDecodeAddressId(t *testing.T) {
+	// 2 Turquoise Ct, Petaluma, CA
+	lat, lon, err := geodom.DecodeAddressId("dacdbddabcadccbdacac")
+	require.NoError(t, err)
+	require.Less(t, math.Abs(lat-38.22507858276367), 0.001)
+	require.Less(t, math.Abs(lon+122.61660766601562), 0.001)
+
+	// round trip lands in the same cell
+	id := geodom.EncodeAddressId(lat, lon, 20)
+	require.Equal(t, "dacdbddabcadccbdacac", id)
+
+	_, _, err = geodom.DecodeAddressId("dacdxyz")
+	require.ErrorIs(t, err, geodom.ErrInvalidAddressId)
+	_, _, err = geodom.DecodeAddressId("")
+	require.ErrorIs(t, err, geodom.ErrInvalidAddressId)
+}
+
+func TestCoverBounds(t *testing.T) {
+	// around Petaluma, CA
+	petaluma := &geodom.BoundingBox{MinLat: 38.2, MinLon: -122.7, MaxLat: 38.3, MaxLon: -122.6}
+	require.True(t, petaluma.Valid())
+
+	cells := geodom.CoverBounds(petaluma, 12, 64)
+	require.NotEmpty(t, cells)
+	for _, id := range []string{"dacdbddabcadccbdacac", geodom.EncodeAddressId(38.227476, -122.6461669, 20)} {
+		covered := false
+		for _, c := range cells {
+			require.Len(t, c, 12)
+			if strings.HasPrefix(id, c) {
+				covered = true
+			}
+		}
+		require.True(t, covered, id)
+	}
+
+	// coarser when the box needs too many cells
+	cells = geodom.CoverBounds(petaluma, 20, 16)
+	require.LessOrEqual(t, len(cells), 16)
+	require.Less(t, len(cells[0]), 20)
+
+	// the whole globe
+	require.Len(t, geodom.CoverBounds(&geodom.BoundingBox{MinLat: -90, MinLon: -180, MaxLat: 90, MaxLon: 180}, 1, 4), 4)
+	require.Equal(t, []string{""}, geodom.CoverBounds(&geodom.BoundingBox{MinLat: -90, MinLon: -180, MaxLat: 90, MaxLon: 180}, 3, 2))
+
+	require.False(t, (&geodom.BoundingBox{MinLat: 38.3, MinLon: -122.7, MaxLat: 38.2, MaxLon: -122.6}).Valid())
+	require.False(t, (&geodom.BoundingBox{MinLat: 38.2, MinLon: -190, MaxLat: 38.3, MaxLon: -122.6}).Valid())
+}
@@ -0,0 +1,19 @@
+package geo
+
+import (
+	"context"
+	"time"
+)
+
+// Route is the estimated road distance & travel time from an origin to a destination.
+type Route struct {
+	DistanceMeters float64
+	Duration       time.Duration
+}
+
+// RoutingProvider estimates travel between locations, keyed by address ID & lat/lon.
+type RoutingProvider interface {
+	// Routes returns the route from the origin to each destination, in order, nil
+	// for destinations that can't be reached.
+	Routes(ctx context.Context, origin *Location, destinations []*Location) ([]*Route, error)
+}
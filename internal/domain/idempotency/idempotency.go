@@ -0,0 +1,46 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// IDEMPOTENCY_KEY_HEADER is the gRPC metadata key clients set on mutating RPCs
+// to make retries safe.
+const IDEMPOTENCY_KEY_HEADER = "idempotency-key"
+
+// IDEMPOTENT_REPLAY_HEADER is set on responses replayed from a previous call.
+const IDEMPOTENT_REPLAY_HEADER = "idempotent-replayed"
+
+type RecordStatus string
+
+const (
+	RECORD_IN_PROGRESS RecordStatus = "in_progress"
+	RECORD_COMPLETED   RecordStatus = "completed"
+)
+
+type IdempotencyRepo interface {
+	// Reserve claims the key for a request with the given hash, holding it for lock.
+	// If the key is already held, the existing record is returned with reserved false.
+	Reserve(ctx context.Context, key, requestHash string, lock time.Duration) (rec *Record, reserved bool, err error)
+	// Extend holds a reserved key for the request with the given hash for another lock,
+	// failing with no record when the key is no longer held for it.
+	Extend(ctx context.Context, key, requestHash string, lock time.Duration) error
+	// Complete stores the response for a key reserved for the request with the given hash,
+	// keeping it for ttl, failing with no record when the key is no longer held for it.
+	Complete(ctx context.Context, key, requestHash string, response []byte, ttl time.Duration) error
+	// Release drops a key reserved for the request with the given hash, so the request
+	// can be retried with it. Keys held for other requests are kept.
+	Release(ctx context.Context, key, requestHash string) error
+}
+
+// Record is a client supplied idempotency key & the outcome of its request.
+// Key is scoped by caller & RPC method, Response holds the serialized response.
+type Record struct {
+	Key         string       `bson:"_id"`
+	RequestHash string       `bson:"request_hash"`
+	Status      RecordStatus `bson:"status"`
+	Response    []byte       `bson:"response,omitempty"`
+	CreatedAt   time.Time    `bson:"created_at"`
+	ExpiresAt   time.Time    `bson:"expires_at"`
+}
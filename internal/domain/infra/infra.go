@@ -15,6 +15,12 @@ var (
 
 type CloseFn func(ctx context.Context) error
 
+// Worker is a long running background process with an explicit lifecycle.
+type Worker interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
 type TLSConfig struct {
 	CAFilePath   string
 	CertFilePath string
@@ -24,6 +30,7 @@ type TLSConfig struct {
 type DBStore interface {
 	Store() *mongo.Database
 	EnsureIndexes(ctx context.Context, collectionName string, indexes []mongo.IndexModel) error
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 	Stats(ctx context.Context, db string)
 	Close(ctx context.Context) error
 }
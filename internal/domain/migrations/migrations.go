@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	indom "github.com/comfforts/comff-stores/internal/domain/infra"
+)
+
+// MigrateFn applies or reverts a migration against the database.
+type MigrateFn func(ctx context.Context, db indom.DBStore) error
+
+// Migration is a versioned schema or data change, applied in version order.
+// Up & Down must be idempotent: a step isn't recorded in the same transaction,
+// index builds can't run in one, so a step interrupted before it's recorded
+// runs again.
+type Migration struct {
+	Version int
+	Name    string
+	Up      MigrateFn
+	// Down reverts Up, migrations without it can't be rolled back.
+	Down MigrateFn
+}
+
+// Record is an applied migration.
+type Record struct {
+	Version   int       `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// Status is a migration & whether it's applied.
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	// Unknown is set for applied migrations missing from this build.
+	Unknown bool
+}
+
+// Migrator runs migrations, holding a lock so only one replica migrates at a time.
+type Migrator interface {
+	// Status lists the migrations in version order.
+	Status(ctx context.Context) ([]*Status, error)
+	// Up applies the pending migrations up to version to, all when 0,
+	// returning the applied versions.
+	Up(ctx context.Context, to int) ([]int, error)
+	// Down reverts the latest steps applied migrations, returning the reverted versions.
+	Down(ctx context.Context, steps int) ([]int, error)
+}
@@ -0,0 +1,52 @@
+package stores
+
+import (
+	api "github.com/comfforts/comff-stores/api/stores/v1"
+)
+
+// Capability is a service stores can offer, e.g. pickup or pharmacy.
+type Capability struct {
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Deprecated capabilities stay on the stores offering them, they can't be newly assigned.
+	Deprecated bool `json:"deprecated,omitempty"`
+}
+
+// CapabilityCatalog is the central catalog of capabilities, its version is bumped on every change.
+type CapabilityCatalog struct {
+	Version      int           `json:"version"`
+	Capabilities []*Capability `json:"capabilities"`
+}
+
+// Lookup returns the catalog's capability with the code.
+func (cc *CapabilityCatalog) Lookup(code string) (*Capability, bool) {
+	if cc == nil {
+		return nil, false
+	}
+	for _, c := range cc.Capabilities {
+		if c.Code == code {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+func MapToCapabilityCatalogProto(cc *CapabilityCatalog) *api.GetCapabilityCatalogResponse {
+	resp := &api.GetCapabilityCatalogResponse{
+		Capabilities: []*api.Capability{},
+	}
+	if cc == nil {
+		return resp
+	}
+	resp.Version = uint32(cc.Version)
+	for _, c := range cc.Capabilities {
+		resp.Capabilities = append(resp.Capabilities, &api.Capability{
+			Code:        c.Code,
+			Name:        c.Name,
+			Description: c.Description,
+			Deprecated:  c.Deprecated,
+		})
+	}
+	return resp
+}
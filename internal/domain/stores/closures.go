@@ -0,0 +1,137 @@
+package stores
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	api "github.com/comfforts/comff-stores/api/stores/v1"
+)
+
+// StoreClosure temporarily closes a store from From, reopening it at Until when set,
+// e.g. for a renovation or bad weather.
+type StoreClosure struct {
+	ID     string     `bson:"id" json:"id"`
+	From   time.Time  `bson:"from" json:"from"`
+	Until  *time.Time `bson:"until,omitempty" json:"until,omitempty"`
+	Reason string     `bson:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// ClosedAt reports whether the closure has the store closed at the time.
+func (sc *StoreClosure) ClosedAt(at time.Time) bool {
+	return !sc.From.After(at) && !sc.Ended(at)
+}
+
+// Ended reports whether the store has reopened from the closure by the time.
+func (sc *StoreClosure) Ended(at time.Time) bool {
+	return sc.Until != nil && !sc.Until.After(at)
+}
+
+// StoreSchedule is a store's closures & when they next change its status.
+type StoreSchedule struct {
+	Closures       []*StoreClosure
+	StatusChangeAt *time.Time
+}
+
+type ScheduleClosureParams struct {
+	// From is when the store closes, now when zero.
+	From time.Time
+	// Until is when the store reopens, it stays closed until the closure is canceled when nil.
+	Until  *time.Time
+	Reason string
+}
+
+// FollowsClosures reports whether closures decide the store's status, only active &
+// temporarily closed stores follow them.
+func FollowsClosures(status StoreStatus) bool {
+	return status == "" || status == STORE_ACTIVE || status == STORE_TEMPORARILY_CLOSED
+}
+
+// ScheduledStatus returns the store's status at the time under its closures, and its
+// schedule, the closures that haven't ended & when they next change its status.
+func ScheduledStatus(status StoreStatus, closures []*StoreClosure, at time.Time) (StoreStatus, *StoreSchedule) {
+	schedule := &StoreSchedule{Closures: []*StoreClosure{}}
+	for _, c := range closures {
+		if !c.Ended(at) {
+			schedule.Closures = append(schedule.Closures, c)
+		}
+	}
+	if !FollowsClosures(status) {
+		return status, schedule
+	}
+
+	status = closureStatus(schedule.Closures, at)
+	// the earliest closure boundary the status changes at
+	for _, c := range schedule.Closures {
+		for _, t := range []*time.Time{&c.From, c.Until} {
+			if t == nil || !t.After(at) || (schedule.StatusChangeAt != nil && !t.Before(*schedule.StatusChangeAt)) {
+				continue
+			}
+			if closureStatus(schedule.Closures, *t) != status {
+				change := *t
+				schedule.StatusChangeAt = &change
+			}
+		}
+	}
+	return status, schedule
+}
+
+// SameSchedule reports whether the store already has the schedule's closures & next
+// status change.
+func SameSchedule(st *Store, schedule *StoreSchedule) bool {
+	if !sameTime(st.StatusChangeAt, schedule.StatusChangeAt) || len(st.Closures) != len(schedule.Closures) {
+		return false
+	}
+	for i, c := range st.Closures {
+		sc := schedule.Closures[i]
+		if c.ID != sc.ID || !c.From.Equal(sc.From) || !sameTime(c.Until, sc.Until) || c.Reason != sc.Reason {
+			return false
+		}
+	}
+	return true
+}
+
+func sameTime(a, b *time.Time) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && a.Equal(*b))
+}
+
+func closureStatus(closures []*StoreClosure, at time.Time) StoreStatus {
+	for _, c := range closures {
+		if c.ClosedAt(at) {
+			return STORE_TEMPORARILY_CLOSED
+		}
+	}
+	return STORE_ACTIVE
+}
+
+func MapToScheduleClosureParams(req *api.ScheduleClosureRequest) *ScheduleClosureParams {
+	if req == nil {
+		return nil
+	}
+	params := &ScheduleClosureParams{
+		Reason: req.GetReason(),
+	}
+	if req.GetFrom() != nil {
+		params.From = req.GetFrom().AsTime()
+	}
+	if req.GetUntil() != nil {
+		until := req.GetUntil().AsTime()
+		params.Until = &until
+	}
+	return params
+}
+
+func MapToStoreClosureProto(sc *StoreClosure) *api.StoreClosure {
+	if sc == nil {
+		return nil
+	}
+	scProto := &api.StoreClosure{
+		Id:     sc.ID,
+		From:   timestamppb.New(sc.From),
+		Reason: sc.Reason,
+	}
+	if sc.Until != nil {
+		scProto.Until = timestamppb.New(*sc.Until)
+	}
+	return scProto
+}
@@ -0,0 +1,69 @@
+package stores_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+func TestScheduledStatus(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	at := func(hours int) *time.Time {
+		t := now.Add(time.Duration(hours) * time.Hour)
+		return &t
+	}
+	ended := &stdom.StoreClosure{ID: "ended", From: *at(-48), Until: at(-24)}
+	renovation := &stdom.StoreClosure{ID: "renovation", From: *at(24), Until: at(72)}
+	// overlaps the renovation, extending it
+	storm := &stdom.StoreClosure{ID: "storm", From: *at(48), Until: at(96)}
+	flood := &stdom.StoreClosure{ID: "flood", From: *at(-1)}
+
+	status, schedule := stdom.ScheduledStatus(stdom.STORE_ACTIVE, []*stdom.StoreClosure{ended, renovation, storm}, now)
+	require.Equal(t, stdom.STORE_ACTIVE, status)
+	require.Equal(t, []*stdom.StoreClosure{renovation, storm}, schedule.Closures)
+	require.Equal(t, at(24), schedule.StatusChangeAt)
+
+	// reopens once both closures end
+	status, schedule = stdom.ScheduledStatus(stdom.STORE_ACTIVE, []*stdom.StoreClosure{renovation, storm}, *at(24))
+	require.Equal(t, stdom.STORE_TEMPORARILY_CLOSED, status)
+	require.Equal(t, at(96), schedule.StatusChangeAt)
+
+	status, schedule = stdom.ScheduledStatus(stdom.STORE_TEMPORARILY_CLOSED, []*stdom.StoreClosure{renovation, storm}, *at(96))
+	require.Equal(t, stdom.STORE_ACTIVE, status)
+	require.Empty(t, schedule.Closures)
+	require.Nil(t, schedule.StatusChangeAt)
+
+	// open ended closures last until canceled
+	status, schedule = stdom.ScheduledStatus("", []*stdom.StoreClosure{flood, renovation}, now)
+	require.Equal(t, stdom.STORE_TEMPORARILY_CLOSED, status)
+	require.Nil(t, schedule.StatusChangeAt)
+
+	// only active stores follow their closures
+	status, schedule = stdom.ScheduledStatus(stdom.STORE_INACTIVE, []*stdom.StoreClosure{flood, renovation}, now)
+	require.Equal(t, stdom.STORE_INACTIVE, status)
+	require.Len(t, schedule.Closures, 2)
+	require.Nil(t, schedule.StatusChangeAt)
+}
+
+func TestSameSchedule(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	until := now.Add(24 * time.Hour)
+	renovation := &stdom.StoreClosure{ID: "renovation", From: now, Until: &until}
+	st := &stdom.Store{Closures: []*stdom.StoreClosure{renovation}, StatusChangeAt: &until}
+
+	// as read back, in another location
+	readUntil := until.Local()
+	read := &stdom.StoreClosure{ID: "renovation", From: now.Local(), Until: &readUntil}
+	require.True(t, stdom.SameSchedule(st, &stdom.StoreSchedule{Closures: []*stdom.StoreClosure{read}, StatusChangeAt: &readUntil}))
+
+	require.False(t, stdom.SameSchedule(st, &stdom.StoreSchedule{Closures: []*stdom.StoreClosure{renovation}}))
+	require.False(t, stdom.SameSchedule(st, &stdom.StoreSchedule{StatusChangeAt: &until}))
+	extended := now.Add(48 * time.Hour)
+	require.False(t, stdom.SameSchedule(st, &stdom.StoreSchedule{
+		Closures:       []*stdom.StoreClosure{{ID: "renovation", From: now, Until: &extended}},
+		StatusChangeAt: &until,
+	}))
+}
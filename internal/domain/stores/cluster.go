@@ -0,0 +1,71 @@
+package stores
+
+import (
+	api "github.com/comfforts/comff-stores/api/stores/v1"
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+)
+
+type ClusterStoresParams struct {
+	Org    string
+	Bounds *geodom.BoundingBox
+	Zoom   int
+}
+
+// ClusterStoresQuery groups the stores of an org, all orgs when empty, in the bounds
+// by address ID prefix of Precision. With Individual stores aren't grouped, up to
+// Limit are returned, each as its own cluster.
+type ClusterStoresQuery struct {
+	Org        string
+	Bounds     *geodom.BoundingBox
+	Precision  int
+	Individual bool
+	Limit      int
+}
+
+// StoreCluster is the stores in an address ID prefix's cell, centered at their
+// centroid, with a sample of their IDs. Individual stores are clusters of one
+// with Store set.
+type StoreCluster struct {
+	Region    string
+	Latitude  float64
+	Longitude float64
+	Count     int
+	SampleIDs []string
+	Store     *Store
+}
+
+func MapToClusterStoresParams(req *api.ClusterStoresRequest) *ClusterStoresParams {
+	if req == nil {
+		return nil
+	}
+	return &ClusterStoresParams{
+		Org:    req.GetOrg(),
+		Bounds: mapToBoundingBox(req.GetBbox()),
+		Zoom:   int(req.GetZoom()),
+	}
+}
+
+func mapToBoundingBox(bbox *api.BoundingBox) *geodom.BoundingBox {
+	if bbox == nil {
+		return nil
+	}
+	return &geodom.BoundingBox{
+		MinLat: bbox.GetMinLatitude(),
+		MinLon: bbox.GetMinLongitude(),
+		MaxLat: bbox.GetMaxLatitude(),
+		MaxLon: bbox.GetMaxLongitude(),
+	}
+}
+
+func MapToStoreClusterProto(c *StoreCluster) *api.StoreCluster {
+	if c == nil {
+		return nil
+	}
+	return &api.StoreCluster{
+		Region:    c.Region,
+		Centroid:  &api.Point{Latitude: c.Latitude, Longitude: c.Longitude},
+		Count:     uint32(c.Count),
+		SampleIds: c.SampleIDs,
+		Store:     MapToStoreProto(c.Store),
+	}
+}
@@ -0,0 +1,125 @@
+package stores
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DEFAULT_FUZZY_MIN_SIMILARITY is the name similarity fuzzy searches match at, unless set.
+const DEFAULT_FUZZY_MIN_SIMILARITY = 0.6
+
+// fuzzy search candidates share at least FUZZY_MIN_TRIGRAM_SHARE of the query's trigrams,
+// only the FUZZY_MAX_CANDIDATES sharing the most are scored
+const (
+	FUZZY_MIN_TRIGRAM_SHARE = 0.25
+	FUZZY_MAX_CANDIDATES    = 1000
+)
+
+// NameTrigrams returns the sorted unique trigrams of a store name's words, each
+// word padded with two leading & one trailing space, so short words & word
+// starts get trigrams too. Fuzzy search candidates share MinTrigramOverlap of the
+// query's trigrams.
+func NameTrigrams(name string) []string {
+	set := map[string]bool{}
+	for _, w := range nameWords(name) {
+		padded := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+
+	trigrams := make([]string, 0, len(set))
+	for tg := range set {
+		trigrams = append(trigrams, tg)
+	}
+	sort.Strings(trigrams)
+	return trigrams
+}
+
+// MinTrigramOverlap is the number of the query's trigrams a fuzzy search candidate shares, at least one.
+func MinTrigramOverlap(trigrams []string) int {
+	return max(1, int(math.Ceil(float64(len(trigrams))*FUZZY_MIN_TRIGRAM_SHARE)))
+}
+
+// TrigramOverlap counts the trigrams shared by two sorted trigram lists.
+func TrigramOverlap(a, b []string) int {
+	n := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			n++
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return n
+}
+
+// NameSimilarity scores how closely a store name matches a query, from 0 to 1,
+// by edit distance. It's the better of the whole name's similarity & the average
+// similarity of each query word to its closest name word, so a misspelled part
+// of a longer name still matches.
+func NameSimilarity(query, name string) float64 {
+	qWords, nWords := nameWords(query), nameWords(name)
+	if len(qWords) == 0 || len(nWords) == 0 {
+		return 0
+	}
+
+	whole := similarity(strings.Join(qWords, " "), strings.Join(nWords, " "))
+
+	total := 0.0
+	for _, qw := range qWords {
+		best := 0.0
+		for _, nw := range nWords {
+			if sim := similarity(qw, nw); sim > best {
+				best = sim
+			}
+		}
+		total += best
+	}
+	return max(whole, total/float64(len(qWords)))
+}
+
+// similarity is one less the edit distance relative to the longer string.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(editDistance(ra, rb))/float64(longest)
+}
+
+// editDistance is the Levenshtein distance, the fewest single character
+// insertions, deletions & substitutions turning a into b.
+func editDistance(a, b []rune) int {
+	prev, curr := make([]int, len(b)+1), make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// nameWords returns a name's lower cased words, letters & digits only.
+func nameWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,43 @@
+package stores_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+func TestNameTrigrams(t *testing.T) {
+	require.Equal(t, []string{"  a", "  c", " ab", " ca", "ab ", "cab"}, stdom.NameTrigrams("Cab, ab"))
+	require.Empty(t, stdom.NameTrigrams(" - "))
+
+	// a misspelling shares trigrams with the name
+	shared := 0
+	name := map[string]bool{}
+	for _, tg := range stdom.NameTrigrams("Petaluma Market") {
+		name[tg] = true
+	}
+	for _, tg := range stdom.NameTrigrams("Petluma Markt") {
+		if name[tg] {
+			shared++
+		}
+	}
+	require.Greater(t, shared, 4)
+	require.Equal(t, shared, stdom.TrigramOverlap(stdom.NameTrigrams("Petaluma Market"), stdom.NameTrigrams("Petluma Markt")))
+	require.GreaterOrEqual(t, shared, stdom.MinTrigramOverlap(stdom.NameTrigrams("Petluma Markt")))
+
+	// candidates share at least a quarter of the query's trigrams, & one
+	require.Equal(t, 1, stdom.MinTrigramOverlap(nil))
+	require.Equal(t, 2, stdom.MinTrigramOverlap(stdom.NameTrigrams("Bakery")))
+	require.Less(t, stdom.TrigramOverlap(stdom.NameTrigrams("Corner Bakery"), stdom.NameTrigrams("Petluma Markt")), stdom.MinTrigramOverlap(stdom.NameTrigrams("Petluma Markt")))
+}
+
+func TestNameSimilarity(t *testing.T) {
+	require.Equal(t, 1.0, stdom.NameSimilarity("petaluma market", "Petaluma Market"))
+	require.Greater(t, stdom.NameSimilarity("Petluma Markt", "Petaluma Market"), stdom.DEFAULT_FUZZY_MIN_SIMILARITY)
+	// a misspelled word of a longer name
+	require.Greater(t, stdom.NameSimilarity("Markt", "Petaluma Farmers Market"), stdom.DEFAULT_FUZZY_MIN_SIMILARITY)
+	require.Less(t, stdom.NameSimilarity("Hardware", "Petaluma Market"), stdom.DEFAULT_FUZZY_MIN_SIMILARITY)
+	require.Zero(t, stdom.NameSimilarity("", "Petaluma Market"))
+}
@@ -0,0 +1,30 @@
+package stores
+
+import (
+	"strings"
+	"unicode"
+)
+
+// regions are paths of region names, region first then district, e.g. west/bay-area
+const REGION_SEPARATOR = "/"
+
+// NormalizeRegion trims & lower cases a region path's names, false when a name is
+// empty or has characters other than letters, digits, '-' & '_'.
+func NormalizeRegion(region string) (string, bool) {
+	names := strings.Split(region, REGION_SEPARATOR)
+	for i, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || strings.IndexFunc(name, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_'
+		}) >= 0 {
+			return "", false
+		}
+		names[i] = name
+	}
+	return strings.Join(names, REGION_SEPARATOR), true
+}
+
+// InRegion reports whether the region path is the root region or one of its sub regions.
+func InRegion(region, root string) bool {
+	return region == root || strings.HasPrefix(region, root+REGION_SEPARATOR)
+}
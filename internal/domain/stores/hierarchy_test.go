@@ -0,0 +1,27 @@
+package stores_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+func TestNormalizeRegion(t *testing.T) {
+	region, ok := stdom.NormalizeRegion(" West / Bay-Area/north_bay")
+	require.True(t, ok)
+	require.Equal(t, "west/bay-area/north_bay", region)
+
+	for _, bad := range []string{"", "west//bay-area", "west/", "/west", "west/bay area", "west/bay.area"} {
+		_, ok := stdom.NormalizeRegion(bad)
+		require.False(t, ok, bad)
+	}
+}
+
+func TestInRegion(t *testing.T) {
+	require.True(t, stdom.InRegion("west", "west"))
+	require.True(t, stdom.InRegion("west/bay-area", "west"))
+	require.False(t, stdom.InRegion("western", "west"))
+	require.False(t, stdom.InRegion("west", "west/bay-area"))
+}
@@ -0,0 +1,207 @@
+package stores
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	api "github.com/comfforts/comff-stores/api/stores/v1"
+)
+
+// ACCEPT_LANGUAGE_HEADER is the gRPC metadata key carrying a caller's preferred locales,
+// in the HTTP Accept-Language format, e.g. fr-CA, fr;q=0.8, en;q=0.5.
+const ACCEPT_LANGUAGE_HEADER = "accept-language"
+
+// StoreTranslation is a store's name & description in a locale.
+type StoreTranslation struct {
+	Locale      string `bson:"locale" json:"locale"`
+	Name        string `bson:"name" json:"name"`
+	Description string `bson:"description,omitempty" json:"description,omitempty"`
+}
+
+// localePattern matches lower cased BCP 47 style tags, a language & its subtags.
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{1,8})*$`)
+
+// NormalizeLocale returns the locale tag in canonical case, the language lower cased,
+// a script title cased & a region upper cased, e.g. zh_hant_tw is zh-Hant-TW.
+func NormalizeLocale(tag string) (string, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if !localePattern.MatchString(tag) {
+		return "", false
+	}
+	subtags := strings.Split(tag, "-")
+	for i := 1; i < len(subtags); i++ {
+		switch {
+		case len(subtags[i]) == 4 && isAlpha(subtags[i]):
+			subtags[i] = strings.ToUpper(subtags[i][:1]) + subtags[i][1:]
+		case len(subtags[i]) == 2 && isAlpha(subtags[i]):
+			subtags[i] = strings.ToUpper(subtags[i])
+		}
+	}
+	return strings.Join(subtags, "-"), true
+}
+
+// ParseAcceptLanguage returns the normalized locales of an Accept-Language value, the
+// most preferred first. Invalid & wildcard tags, and those with q=0, are left out.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+	var ranges []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		locale, ok := NormalizeLocale(tag)
+		if !ok {
+			continue
+		}
+		q := 1.0
+		for _, p := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(p), "=")
+			if !ok || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = v
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		ranges = append(ranges, weighted{locale: locale, q: q})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+
+	locales := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		locales = append(locales, r.locale)
+	}
+	return locales
+}
+
+// MatchLocale returns the available locale best matching the preferred locales, the
+// most preferred first. Each preferred locale matches exactly, then by its parent
+// locales, e.g. fr for fr-CA, then by any locale of its language, before the next
+// preferred locale is tried.
+func MatchLocale(available, preferred []string) (string, bool) {
+	for _, p := range preferred {
+		for tag := p; tag != ""; {
+			for _, a := range available {
+				if strings.EqualFold(a, tag) {
+					return a, true
+				}
+			}
+			i := strings.LastIndex(tag, "-")
+			if i < 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+		lang := localeLanguage(p)
+		for _, a := range available {
+			if strings.EqualFold(localeLanguage(a), lang) {
+				return a, true
+			}
+		}
+	}
+	return "", false
+}
+
+// LocalizeStore sets the store's name, description & locale to those of its translation
+// best matching the preferred locales. The store's own are kept when they match best,
+// or nothing matches. Translations without a description keep the store's.
+func LocalizeStore(st *Store, preferred []string) {
+	if st == nil || len(st.Translations) == 0 || len(preferred) == 0 {
+		return
+	}
+	available := make([]string, 0, len(st.Translations)+1)
+	if st.Locale != "" {
+		available = append(available, st.Locale)
+	}
+	for _, tr := range st.Translations {
+		available = append(available, tr.Locale)
+	}
+	locale, ok := MatchLocale(available, preferred)
+	if !ok || locale == st.Locale {
+		return
+	}
+	for _, tr := range st.Translations {
+		if tr.Locale != locale {
+			continue
+		}
+		st.Name, st.Locale = tr.Name, tr.Locale
+		if tr.Description != "" {
+			st.Description = tr.Description
+		}
+		return
+	}
+}
+
+// StoreNames returns the store's name & its translated names.
+func StoreNames(st *Store) []string {
+	names := []string{st.Name}
+	for _, tr := range st.Translations {
+		names = append(names, tr.Name)
+	}
+	return names
+}
+
+// StoreNameTrigrams returns the sorted unique trigrams of all the store's names.
+func StoreNameTrigrams(st *Store) []string {
+	if len(st.Translations) == 0 {
+		return NameTrigrams(st.Name)
+	}
+	return NameTrigrams(strings.Join(StoreNames(st), " "))
+}
+
+// StoreNameSimilarity is the NameSimilarity of the store's closest matching name.
+func StoreNameSimilarity(query string, st *Store) float64 {
+	best := 0.0
+	for _, name := range StoreNames(st) {
+		best = max(best, NameSimilarity(query, name))
+	}
+	return best
+}
+
+func localeLanguage(locale string) string {
+	lang, _, _ := strings.Cut(locale, "-")
+	return lang
+}
+
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func MapToStoreTranslations(trs []*api.StoreTranslation) []*StoreTranslation {
+	if len(trs) == 0 {
+		return nil
+	}
+	translations := make([]*StoreTranslation, 0, len(trs))
+	for _, tr := range trs {
+		translations = append(translations, &StoreTranslation{
+			Locale:      tr.GetLocale(),
+			Name:        tr.GetName(),
+			Description: tr.GetDescription(),
+		})
+	}
+	return translations
+}
+
+func MapToStoreTranslationProto(tr *StoreTranslation) *api.StoreTranslation {
+	if tr == nil {
+		return nil
+	}
+	return &api.StoreTranslation{
+		Locale:      tr.Locale,
+		Name:        tr.Name,
+		Description: tr.Description,
+	}
+}
@@ -0,0 +1,87 @@
+package stores_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	for tag, want := range map[string]string{
+		"en":         "en",
+		"EN_us":      "en-US",
+		"zh-hant-tw": "zh-Hant-TW",
+		"es-419":     "es-419",
+	} {
+		got, ok := stdom.NormalizeLocale(tag)
+		require.True(t, ok, tag)
+		require.Equal(t, want, got, tag)
+	}
+	for _, tag := range []string{"", "*", "e", "english", "en--us", "en-"} {
+		_, ok := stdom.NormalizeLocale(tag)
+		require.False(t, ok, tag)
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	require.Equal(t, []string{"fr-CA", "fr", "en"}, stdom.ParseAcceptLanguage("en;q=0.5, fr-ca, fr;q=0.8, *;q=0.1, de;q=0"))
+	require.Empty(t, stdom.ParseAcceptLanguage(""))
+}
+
+func TestMatchLocale(t *testing.T) {
+	available := []string{"en-US", "fr-FR", "fr", "pt-BR"}
+	for _, tc := range []struct {
+		preferred []string
+		want      string
+	}{
+		{[]string{"fr-FR"}, "fr-FR"},
+		// parent locales before other regions of the language
+		{[]string{"fr-CA"}, "fr"},
+		{[]string{"en-GB"}, "en-US"},
+		{[]string{"de", "pt-PT"}, "pt-BR"},
+	} {
+		got, ok := stdom.MatchLocale(available, tc.preferred)
+		require.True(t, ok, tc.preferred)
+		require.Equal(t, tc.want, got, tc.preferred)
+	}
+	_, ok := stdom.MatchLocale(available, []string{"de", "ja"})
+	require.False(t, ok)
+}
+
+func TestLocalizeStore(t *testing.T) {
+	store := func() *stdom.Store {
+		return &stdom.Store{
+			Name:        "Corner Bakery",
+			Description: "Bread and cakes",
+			Locale:      "en-US",
+			Translations: []*stdom.StoreTranslation{
+				{Locale: "fr-CA", Name: "Boulangerie du Coin", Description: "Pains et gâteaux"},
+				{Locale: "es", Name: "Panadería de la Esquina"},
+			},
+		}
+	}
+
+	st := store()
+	stdom.LocalizeStore(st, []string{"fr"})
+	require.Equal(t, "Boulangerie du Coin", st.Name)
+	require.Equal(t, "Pains et gâteaux", st.Description)
+	require.Equal(t, "fr-CA", st.Locale)
+
+	// translations without a description keep the store's
+	st = store()
+	stdom.LocalizeStore(st, []string{"es-MX"})
+	require.Equal(t, "Panadería de la Esquina", st.Name)
+	require.Equal(t, "Bread and cakes", st.Description)
+
+	// the store's own locale & unmatched locales keep the defaults
+	for _, preferred := range [][]string{{"en", "fr"}, {"ja"}} {
+		st = store()
+		stdom.LocalizeStore(st, preferred)
+		require.Equal(t, "Corner Bakery", st.Name, preferred)
+		require.Equal(t, "en-US", st.Locale, preferred)
+	}
+
+	require.Equal(t, 1.0, stdom.StoreNameSimilarity("boulangerie du coin", store()))
+}
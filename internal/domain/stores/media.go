@@ -0,0 +1,94 @@
+package stores
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	api "github.com/comfforts/comff-stores/api/stores/v1"
+)
+
+type AttachmentKind string
+
+const (
+	ATTACHMENT_LOGO  AttachmentKind = "logo"
+	ATTACHMENT_PHOTO AttachmentKind = "photo"
+)
+
+func (ak AttachmentKind) Valid() bool {
+	switch ak {
+	case ATTACHMENT_LOGO, ATTACHMENT_PHOTO:
+		return true
+	}
+	return false
+}
+
+// StoreAttachment is a store's media asset, referenced by URI. Uploaded assets are
+// kept in blob storage at BlobKey.
+type StoreAttachment struct {
+	ID   string         `bson:"id" json:"id"`
+	Kind AttachmentKind `bson:"kind" json:"kind"`
+	URI  string         `bson:"uri" json:"uri"`
+	// Checksum is the hex encoded SHA-256 of the content.
+	Checksum    string    `bson:"checksum,omitempty" json:"checksum,omitempty"`
+	Width       int       `bson:"width,omitempty" json:"width,omitempty"`
+	Height      int       `bson:"height,omitempty" json:"height,omitempty"`
+	ContentType string    `bson:"content_type,omitempty" json:"content_type,omitempty"`
+	BlobKey     string    `bson:"blob_key,omitempty" json:"-"`
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+}
+
+type AddAttachmentParams struct {
+	Kind AttachmentKind
+	// URI is the asset's http or https URI.
+	URI         string
+	Checksum    string
+	Width       int
+	Height      int
+	ContentType string
+}
+
+type UploadAttachmentParams struct {
+	Kind AttachmentKind
+}
+
+func MapToAddAttachmentParams(req *api.AddAttachmentRequest) *AddAttachmentParams {
+	if req == nil {
+		return nil
+	}
+	return &AddAttachmentParams{
+		Kind:        AttachmentKind(req.GetKind()),
+		URI:         req.GetUri(),
+		Checksum:    req.GetChecksum(),
+		Width:       int(req.GetWidth()),
+		Height:      int(req.GetHeight()),
+		ContentType: req.GetContentType(),
+	}
+}
+
+func MapToStoreAttachmentProto(sa *StoreAttachment) *api.StoreAttachment {
+	if sa == nil {
+		return nil
+	}
+	saProto := &api.StoreAttachment{
+		Id:          sa.ID,
+		Kind:        string(sa.Kind),
+		Uri:         sa.URI,
+		Checksum:    sa.Checksum,
+		Width:       uint32(sa.Width),
+		Height:      uint32(sa.Height),
+		ContentType: sa.ContentType,
+	}
+	if !sa.CreatedAt.IsZero() {
+		saProto.CreatedAt = timestamppb.New(sa.CreatedAt)
+	}
+	return saProto
+}
+
+func MapToStoreAttachmentProtos(attachments []*StoreAttachment) []*api.StoreAttachment {
+	protos := make([]*api.StoreAttachment, 0, len(attachments))
+	for _, sa := range attachments {
+		protos = append(protos, MapToStoreAttachmentProto(sa))
+	}
+	return protos
+}
@@ -0,0 +1,53 @@
+package stores
+
+import (
+	api "github.com/comfforts/comff-stores/api/stores/v1"
+)
+
+// FindServingStoresParams locates the customer by address ID, address string or point.
+type FindServingStoresParams struct {
+	Org            string
+	AddressId      string
+	AddressStr     string
+	Latitude       float64
+	Longitude      float64
+	IncludeAddress bool
+	Limit          int
+	// RankBy orders the stores, straight line distance when empty.
+	RankBy RankBy
+	// Capabilities matches stores offering all of the capabilities.
+	Capabilities []string
+	// IncludeTemporarilyClosed matches stores closed by a closure too.
+	IncludeTemporarilyClosed bool
+	// Locales are the preferred locales the stores are localized to.
+	Locales []string
+}
+
+// ServingStoresQuery matches the stores of an org, all orgs when empty, with service
+// areas containing the point, offering all of the capabilities. Temporarily closed
+// stores are left out unless included.
+type ServingStoresQuery struct {
+	Org                      string
+	Latitude                 float64
+	Longitude                float64
+	Capabilities             []string
+	IncludeTemporarilyClosed bool
+}
+
+func MapToFindServingStoresParams(req *api.FindServingStoresRequest) *FindServingStoresParams {
+	if req == nil {
+		return nil
+	}
+	return &FindServingStoresParams{
+		Org:                      req.GetOrg(),
+		AddressId:                req.GetAddressId(),
+		AddressStr:               req.GetAddressStr(),
+		Latitude:                 req.GetLatitude(),
+		Longitude:                req.GetLongitude(),
+		IncludeAddress:           req.GetIncludeAddress(),
+		Limit:                    int(req.GetLimit()),
+		RankBy:                   RankBy(req.GetRankBy()),
+		Capabilities:             req.GetCapabilities(),
+		IncludeTemporarilyClosed: req.GetIncludeTemporarilyClosed(),
+	}
+}
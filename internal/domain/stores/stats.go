@@ -0,0 +1,162 @@
+package stores
+
+import (
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	api "github.com/comfforts/comff-stores/api/stores/v1"
+)
+
+// StatsInterval is the width of a store stats series bucket.
+type StatsInterval string
+
+const (
+	STATS_DAY   StatsInterval = "day"
+	STATS_WEEK  StatsInterval = "week"
+	STATS_MONTH StatsInterval = "month"
+)
+
+func (i StatsInterval) Valid() bool {
+	switch i {
+	case STATS_DAY, STATS_WEEK, STATS_MONTH:
+		return true
+	}
+	return false
+}
+
+// BucketStart returns the start of the bucket t falls in, in UTC. Weeks start on Monday.
+func (i StatsInterval) BucketStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch i {
+	case STATS_WEEK:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case STATS_MONTH:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// Next returns the start of the bucket after the one starting at start.
+func (i StatsInterval) Next(start time.Time) time.Time {
+	switch i {
+	case STATS_WEEK:
+		return start.AddDate(0, 0, 7)
+	case STATS_MONTH:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// StoreDeletion records a deleted store, kept for stats after the store is gone.
+type StoreDeletion struct {
+	ID        string    `bson:"_id,omitempty" json:"id,omitempty"`
+	StoreID   string    `bson:"store_id" json:"store_id"`
+	Org       string    `bson:"org" json:"org"`
+	AddressId string    `bson:"address_id" json:"address_id"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	DeletedAt time.Time `bson:"deleted_at" json:"deleted_at"`
+}
+
+type StoreStatsParams struct {
+	Org             string
+	From            time.Time
+	To              time.Time
+	Interval        StatsInterval
+	RegionPrecision int
+}
+
+// StoreStatsQuery counts the stores of an org, all orgs when empty. The series
+// buckets additions & deletions from From, a bucket start, until To. Regions
+// roll up current stores by address ID prefix of RegionPrecision, none when 0.
+type StoreStatsQuery struct {
+	Org             string
+	From            time.Time
+	To              time.Time
+	Interval        StatsInterval
+	RegionPrecision int
+}
+
+// StoreStats are the current store counts & the series of additions & deletions.
+type StoreStats struct {
+	Total   int
+	Orgs    []*StatsCount
+	Series  []*StatsBucket
+	Regions []*RegionCount
+}
+
+type StatsCount struct {
+	Key   string
+	Count int
+}
+
+type StatsBucket struct {
+	Start   time.Time
+	Added   int
+	Deleted int
+}
+
+// RegionCount counts the stores in an address ID prefix's cell, centered at Latitude, Longitude.
+type RegionCount struct {
+	Region    string
+	Count     int
+	Latitude  float64
+	Longitude float64
+}
+
+// SortStatsCounts orders counts by descending count, then key.
+func SortStatsCounts(counts []*StatsCount) {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Key < counts[j].Key
+	})
+}
+
+func MapToStoreStatsParams(req *api.GetStoreStatsRequest) *StoreStatsParams {
+	if req == nil {
+		return nil
+	}
+	params := &StoreStatsParams{
+		Org:             req.GetOrg(),
+		Interval:        StatsInterval(req.GetInterval()),
+		RegionPrecision: int(req.GetRegionPrecision()),
+	}
+	if req.GetFrom() != nil {
+		params.From = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		params.To = req.GetTo().AsTime()
+	}
+	return params
+}
+
+func MapToStoreStatsResponse(stats *StoreStats) *api.GetStoreStatsResponse {
+	if stats == nil {
+		return nil
+	}
+	resp := &api.GetStoreStatsResponse{
+		Total: uint32(stats.Total),
+	}
+	for _, c := range stats.Orgs {
+		resp.Orgs = append(resp.Orgs, &api.StatsCount{Key: c.Key, Count: uint32(c.Count)})
+	}
+	for _, b := range stats.Series {
+		resp.Series = append(resp.Series, &api.StatsBucket{
+			Start:   timestamppb.New(b.Start),
+			Added:   uint32(b.Added),
+			Deleted: uint32(b.Deleted),
+		})
+	}
+	for _, r := range stats.Regions {
+		resp.Regions = append(resp.Regions, &api.RegionCount{
+			Region: r.Region,
+			Count:  uint32(r.Count),
+			Center: &api.Point{Latitude: r.Latitude, Longitude: r.Longitude},
+		})
+	}
+	return resp
+}
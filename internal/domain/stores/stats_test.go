@@ -0,0 +1,36 @@
+package stores_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+func TestStatsIntervalBuckets(t *testing.T) {
+	// a Wednesday afternoon
+	ts := time.Date(2025, time.October, 15, 14, 30, 0, 0, time.UTC)
+
+	day := stdom.STATS_DAY.BucketStart(ts)
+	require.Equal(t, time.Date(2025, time.October, 15, 0, 0, 0, 0, time.UTC), day)
+	require.Equal(t, time.Date(2025, time.October, 16, 0, 0, 0, 0, time.UTC), stdom.STATS_DAY.Next(day))
+
+	// weeks start on Monday, Sundays end them
+	week := stdom.STATS_WEEK.BucketStart(ts)
+	require.Equal(t, time.Date(2025, time.October, 13, 0, 0, 0, 0, time.UTC), week)
+	require.Equal(t, week, stdom.STATS_WEEK.BucketStart(time.Date(2025, time.October, 19, 23, 0, 0, 0, time.UTC)))
+	require.Equal(t, week, stdom.STATS_WEEK.BucketStart(week))
+	require.Equal(t, time.Date(2025, time.October, 20, 0, 0, 0, 0, time.UTC), stdom.STATS_WEEK.Next(week))
+
+	month := stdom.STATS_MONTH.BucketStart(ts)
+	require.Equal(t, time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC), month)
+	require.Equal(t, time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC), stdom.STATS_MONTH.Next(month))
+
+	// buckets are in UTC
+	pdt := time.FixedZone("PDT", -7*60*60)
+	require.Equal(t, time.Date(2025, time.October, 16, 0, 0, 0, 0, time.UTC), stdom.STATS_DAY.BucketStart(time.Date(2025, time.October, 15, 20, 0, 0, 0, pdt)))
+
+	require.False(t, stdom.StatsInterval("year").Valid())
+}
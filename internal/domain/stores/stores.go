@@ -2,50 +2,392 @@ package stores
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	api "github.com/comfforts/comff-stores/api/stores/v1"
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+)
+
+// AddressUniqueness is the rule for stores sharing an address ID.
+type AddressUniqueness string
+
+const (
+	// ADDRESS_UNIQUE_GLOBAL allows one store per address ID.
+	ADDRESS_UNIQUE_GLOBAL AddressUniqueness = "global"
+	// ADDRESS_UNIQUE_ORG allows one store per address ID within an org.
+	ADDRESS_UNIQUE_ORG AddressUniqueness = "org"
+	// ADDRESS_UNIQUE_NONE allows any number of stores per address ID.
+	ADDRESS_UNIQUE_NONE AddressUniqueness = "none"
 )
 
+func (au AddressUniqueness) Valid() bool {
+	switch au {
+	case ADDRESS_UNIQUE_GLOBAL, ADDRESS_UNIQUE_ORG, ADDRESS_UNIQUE_NONE:
+		return true
+	}
+	return false
+}
+
+// StoreStatus is a store's lifecycle status, stores without one are active.
+type StoreStatus string
+
+const (
+	STORE_ACTIVE   StoreStatus = "active"
+	STORE_INACTIVE StoreStatus = "inactive"
+	STORE_CLOSED   StoreStatus = "closed"
+	// STORE_TEMPORARILY_CLOSED stores are closed by a closure, set & cleared by the schedule.
+	STORE_TEMPORARILY_CLOSED StoreStatus = "temporarily_closed"
+)
+
+func (ss StoreStatus) Valid() bool {
+	switch ss {
+	case STORE_ACTIVE, STORE_INACTIVE, STORE_CLOSED, STORE_TEMPORARILY_CLOSED:
+		return true
+	}
+	return false
+}
+
+// FacetField is a store field search results can be bucket counted by.
+type FacetField string
+
+const (
+	FACET_ORG          FacetField = "org"
+	FACET_STATUS       FacetField = "status"
+	FACET_TAGS         FacetField = "tags"
+	FACET_CAPABILITIES FacetField = "capabilities"
+)
+
+func (ff FacetField) Valid() bool {
+	switch ff {
+	case FACET_ORG, FACET_STATUS, FACET_TAGS, FACET_CAPABILITIES:
+		return true
+	}
+	return false
+}
+
+// RankBy orders nearest stores, by straight line distance unless by a route's travel
+// time or road distance.
+type RankBy string
+
+const (
+	RANK_DISTANCE      RankBy = "distance"
+	RANK_TRAVEL_TIME   RankBy = "travel_time"
+	RANK_ROAD_DISTANCE RankBy = "road_distance"
+)
+
+func (rb RankBy) Valid() bool {
+	switch rb {
+	case RANK_DISTANCE, RANK_TRAVEL_TIME, RANK_ROAD_DISTANCE:
+		return true
+	}
+	return false
+}
+
+// Routed reports whether ranking needs the stores' routes.
+func (rb RankBy) Routed() bool {
+	return rb == RANK_TRAVEL_TIME || rb == RANK_ROAD_DISTANCE
+}
+
+const ERR_DUPLICATE_STORE = "duplicate store"
+
+var ErrDuplicateStore = errors.New(ERR_DUPLICATE_STORE)
+
+// DuplicateStoreError is a store conflicting with an existing store under
+// the address uniqueness rule, it matches ErrDuplicateStore.
+type DuplicateStoreError struct {
+	ExistingID string
+	AddressId  string
+	// Org is set for per org uniqueness.
+	Org string
+}
+
+func (e *DuplicateStoreError) Error() string {
+	if e.Org != "" {
+		return fmt.Sprintf("%s: address %s already used by store %s in org %s", ERR_DUPLICATE_STORE, e.AddressId, e.ExistingID, e.Org)
+	}
+	return fmt.Sprintf("%s: address %s already used by store %s", ERR_DUPLICATE_STORE, e.AddressId, e.ExistingID)
+}
+
+func (e *DuplicateStoreError) Unwrap() error {
+	return ErrDuplicateStore
+}
+
+const ERR_STORE_CONFLICT = "store changed by another update"
+
+// ErrStoreConflict is a conditional update of a store another update changed first.
+var ErrStoreConflict = errors.New(ERR_STORE_CONFLICT)
+
 type StoresRepo interface {
 	AddStore(ctx context.Context, store *Store) (string, error)
 	GetStore(ctx context.Context, idHex string) (*Store, error)
-	DeleteStore(ctx context.Context, idHex string) error
+	// DeleteStore deletes the store, when ifVersion is set only at that version, failing
+	// with ErrStoreConflict otherwise.
+	DeleteStore(ctx context.Context, idHex string, ifVersion *int64) error
 	UpdateStore(ctx context.Context, idHex string, params *UpdateStoreQuery) error
-	SearchStores(ctx context.Context, params *SearchStoreQuery) ([]*Store, error)
+	SearchStores(ctx context.Context, params *SearchStoreQuery) (*SearchStoreResult, error)
+	GetAddressHistory(ctx context.Context, idHex string) ([]*AddressChange, error)
+	GetStoreStats(ctx context.Context, params *StoreStatsQuery) (*StoreStats, error)
+	ClusterStores(ctx context.Context, params *ClusterStoresQuery) ([]*StoreCluster, error)
+	FindServingStores(ctx context.Context, params *ServingStoresQuery) ([]*Store, error)
+	ListChildStores(ctx context.Context, parentID string) ([]*Store, error)
+	// ClaimStatusChangesDue claims up to limit stores with a scheduled status change at
+	// or before the time for the lease, the most overdue first. Claimed stores aren't
+	// claimed again until the lease expires or their schedule is updated.
+	ClaimStatusChangesDue(ctx context.Context, at time.Time, limit int, lease time.Duration) ([]*Store, error)
 	Close(ctx context.Context) error
 }
 
 type StoresService interface {
 	AddStore(ctx context.Context, st *AddStoreParams) (string, error)
-	GetStore(ctx context.Context, id string) (*Store, error)
+	GetStore(ctx context.Context, id string, opts *GetStoreOptions) (*Store, error)
 	DeleteStore(ctx context.Context, id string) error
 	UpdateStore(ctx context.Context, id string, params *UpdateStoreParams) error
-	SearchStores(ctx context.Context, params *SearchStoreParams) ([]*Store, error)
+	SearchStores(ctx context.Context, params *SearchStoreParams) (*SearchStoreResult, error)
+	GetAddressHistory(ctx context.Context, id string) ([]*AddressChange, error)
+	GetStoreStats(ctx context.Context, params *StoreStatsParams) (*StoreStats, error)
+	ClusterStores(ctx context.Context, params *ClusterStoresParams) ([]*StoreCluster, error)
+	FindServingStores(ctx context.Context, params *FindServingStoresParams) ([]*Store, error)
+	ListChildStores(ctx context.Context, id string, opts *GetStoreOptions) ([]*Store, error)
+	GetStoreAncestors(ctx context.Context, id string) ([]*Store, error)
+	GetCapabilityCatalog(ctx context.Context) (*CapabilityCatalog, error)
+	ScheduleClosure(ctx context.Context, id string, params *ScheduleClosureParams) (*StoreClosure, error)
+	CancelClosure(ctx context.Context, id, closureID string) error
+	AddAttachment(ctx context.Context, id string, params *AddAttachmentParams) (*StoreAttachment, error)
+	// UploadAttachment stores the content read from r in blob storage & attaches it.
+	UploadAttachment(ctx context.Context, id string, params *UploadAttachmentParams, r io.Reader) (*StoreAttachment, error)
+	RemoveAttachment(ctx context.Context, id, attachmentID string) error
+	// ReorderAttachments orders the store's attachments as the IDs, all of them.
+	ReorderAttachments(ctx context.Context, id string, attachmentIDs []string) ([]*StoreAttachment, error)
+	StatusScheduler
+}
+
+// StatusScheduler applies the status changes stores' closures schedule.
+type StatusScheduler interface {
+	// ApplyScheduledStatusChanges applies up to limit status changes due at the time,
+	// claiming them for the lease, returning the number of stores changed.
+	ApplyScheduledStatusChanges(ctx context.Context, at time.Time, limit int, lease time.Duration) (int, error)
 }
 
 type Store struct {
-	ID        string `bson:"_id,omitempty" json:"id,omitempty"`
-	Name      string `bson:"name" json:"name"`
-	Org       string `bson:"org" json:"org"`
-	AddressId string `bson:"address_id" json:"address_id"`
+	ID          string      `bson:"_id,omitempty" json:"id,omitempty"`
+	Name        string      `bson:"name" json:"name"`
+	Org         string      `bson:"org" json:"org"`
+	AddressId   string      `bson:"address_id" json:"address_id"`
+	Description string      `bson:"description,omitempty" json:"description,omitempty"`
+	Tags        []string    `bson:"tags,omitempty" json:"tags,omitempty"`
+	Status      StoreStatus `bson:"status,omitempty" json:"status,omitempty"`
+	// CreatedAt is set by the repo when the store is added.
+	CreatedAt time.Time `bson:"created_at,omitempty" json:"created_at"`
+	// Address is resolved from geo on request, never persisted.
+	Address *Address `bson:"-" json:"address,omitempty"`
+	// Location is the address ID's point, maintained by the repo on write for area searches.
+	Location *geodom.GeoJSONPoint `bson:"location,omitempty" json:"-"`
+	// ServiceArea is the area the store serves, if any.
+	ServiceArea *geodom.GeoJSONMultiPolygon `bson:"service_area,omitempty" json:"service_area,omitempty"`
+	// ParentID is the store this store is a satellite of, in the same org.
+	ParentID string `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	// Region is the store's normalized region path.
+	Region string `bson:"region,omitempty" json:"region,omitempty"`
+	// Capabilities are the capability catalog codes of the services the store offers.
+	Capabilities []string `bson:"capabilities,omitempty" json:"capabilities,omitempty"`
+	// Closures are the store's scheduled & current temporary closures, ended ones are dropped.
+	Closures []*StoreClosure `bson:"closures,omitempty" json:"closures,omitempty"`
+	// StatusChangeAt is when the closures next change the store's status, if ever.
+	StatusChangeAt *time.Time `bson:"status_change_at,omitempty" json:"status_change_at,omitempty"`
+	// StatusClaimedUntil is when a scheduler's claim on the due status change expires.
+	StatusClaimedUntil *time.Time `bson:"status_claimed_until,omitempty" json:"-"`
+	// Locale is the locale of Name & Description, the matched translation's when localized.
+	Locale string `bson:"locale,omitempty" json:"locale,omitempty"`
+	// Translations are the store's name & description in other locales.
+	Translations []*StoreTranslation `bson:"translations,omitempty" json:"translations,omitempty"`
+	// Attachments are the store's photos & logos, in display order.
+	Attachments []*StoreAttachment `bson:"attachments,omitempty" json:"attachments,omitempty"`
+	// NameTrigrams index the name & its translations for fuzzy search, maintained by
+	// the repo on write.
+	NameTrigrams []string `bson:"name_trigrams,omitempty" json:"-"`
+	// Version counts the store's updates, for conditional updates, maintained by the repo.
+	Version int64 `bson:"version,omitempty" json:"-"`
+	// ParentChain are the versions of the parent & its ancestors the added store was
+	// checked against, the add fails with ErrStoreConflict when any of them changed.
+	// It's never persisted.
+	ParentChain []*StoreVersion `bson:"-" json:"-"`
+	// Score is the text search relevance or fuzzy name similarity, set on query &
+	// fuzzy searches, never persisted.
+	Score float64 `bson:"score,omitempty" json:"score,omitempty"`
+	// Distance is meters from the searched point, set on nearest searches, never persisted.
+	Distance float64 `bson:"-" json:"distance,omitempty"`
+	// Route is from the searched point, set on routed nearest searches, never persisted.
+	Route *geodom.Route `bson:"-" json:"route,omitempty"`
+}
+
+// Address is a store's postal address & coordinates, as resolved by geo from its address ID.
+type Address struct {
+	FormattedAddress string  `json:"formatted_address,omitempty"`
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+}
+
+// AddressChange records a store's address ID being set, on creation & on every relocation.
+type AddressChange struct {
+	ID                string    `bson:"_id,omitempty" json:"id,omitempty"`
+	StoreID           string    `bson:"store_id" json:"store_id"`
+	AddressId         string    `bson:"address_id" json:"address_id"`
+	PreviousAddressId string    `bson:"previous_address_id,omitempty" json:"previous_address_id,omitempty"`
+	ChangedAt         time.Time `bson:"changed_at" json:"changed_at"`
+}
+
+type GetStoreOptions struct {
+	IncludeAddress bool
+	// Locales are the preferred locales, the most preferred first, the store's name &
+	// description are localized to.
+	Locales []string
 }
 
 type AddStoreParams struct {
-	Name      string
-	Org       string
-	AddressId string
+	Name        string
+	Org         string
+	AddressId   string
+	Description string
+	Tags        []string
+	Status      StoreStatus
+	// ServiceArea is a GeoJSON Polygon or MultiPolygon.
+	ServiceArea string
+	ParentID    string
+	// Region is a region path, the parent's when unset.
+	Region       string
+	Capabilities []string
+	// Locale is the locale of Name & Description.
+	Locale       string
+	Translations []*StoreTranslation
 }
 
 type UpdateStoreParams struct {
-	Name      string
-	Org       string
-	AddressId string
+	Name        string
+	Org         string
+	AddressId   string
+	Description string
+	Tags        []string
+	Status      StoreStatus
+	// ServiceArea is a GeoJSON Polygon or MultiPolygon, replacing the store's.
+	ServiceArea string
+	ParentID    string
+	Region      string
+	// DetachParent removes the store's parent.
+	DetachParent bool
+	// Capabilities replace the store's, when set.
+	Capabilities []string
+	Locale       string
+	// Translations replace the store's, when set.
+	Translations []*StoreTranslation
+	// ClearTranslations removes the store's translations.
+	ClearTranslations bool
 }
 
 type UpdateStoreQuery struct {
-	Name      string
-	Org       string
-	AddressId string
+	Name         string
+	Org          string
+	AddressId    string
+	Description  string
+	Tags         []string
+	Status       StoreStatus
+	ServiceArea  *geodom.GeoJSONMultiPolygon
+	ParentID     string
+	Region       string
+	DetachParent bool
+	Capabilities []string
+	Locale       string
+	// Translations replace the store's, when set, ClearTranslations removes them.
+	Translations      []*StoreTranslation
+	ClearTranslations bool
+	// Attachments replace the store's, when set, ClearAttachments removes them.
+	Attachments      []*StoreAttachment
+	ClearAttachments bool
+	// Schedule replaces the store's closures & next status change, when set.
+	Schedule *StoreSchedule
+	// IfVersion updates the store only at the version, failing with ErrStoreConflict
+	// when another update changed it first.
+	IfVersion *int64
+	// ParentChain are the versions of the new parent & its ancestors the update was
+	// checked against, the update fails with ErrStoreConflict when any of them changed.
+	ParentChain []*StoreVersion
+}
+
+// StoreVersion is a store at a version.
+type StoreVersion struct {
+	ID      string
+	Version int64
+}
+
+// ApplyUpdate returns a copy of the store with the query's changes, its name trigrams &
+// location reindexed & its version counted.
+func ApplyUpdate(st *Store, q *UpdateStoreQuery) *Store {
+	updated := *st
+	if q.Name != "" {
+		updated.Name = q.Name
+	}
+	if q.Org != "" {
+		updated.Org = q.Org
+	}
+	if q.AddressId != "" && q.AddressId != st.AddressId {
+		updated.AddressId = q.AddressId
+		updated.Location = nil
+		if lat, lon, err := geodom.DecodeAddressId(q.AddressId); err == nil {
+			updated.Location = geodom.NewGeoJSONPoint(lat, lon)
+		}
+	}
+	if q.Description != "" {
+		updated.Description = q.Description
+	}
+	if len(q.Tags) > 0 {
+		updated.Tags = q.Tags
+	}
+	if q.Status != "" {
+		updated.Status = q.Status
+	}
+	if q.ServiceArea != nil {
+		updated.ServiceArea = q.ServiceArea
+	}
+	if q.DetachParent {
+		updated.ParentID = ""
+	}
+	if q.ParentID != "" {
+		updated.ParentID = q.ParentID
+	}
+	if q.Region != "" {
+		updated.Region = q.Region
+	}
+	if len(q.Capabilities) > 0 {
+		updated.Capabilities = q.Capabilities
+	}
+	if q.Schedule != nil {
+		updated.Closures, updated.StatusChangeAt, updated.StatusClaimedUntil = nil, q.Schedule.StatusChangeAt, nil
+		if len(q.Schedule.Closures) > 0 {
+			updated.Closures = q.Schedule.Closures
+		}
+	}
+	if q.Locale != "" {
+		updated.Locale = q.Locale
+	}
+	if len(q.Translations) > 0 {
+		updated.Translations = q.Translations
+	} else if q.ClearTranslations {
+		updated.Translations = nil
+	}
+	if len(q.Attachments) > 0 {
+		updated.Attachments = q.Attachments
+	} else if q.ClearAttachments {
+		updated.Attachments = nil
+	}
+	updated.NameTrigrams = StoreNameTrigrams(&updated)
+	updated.Version++
+	return &updated
 }
 
 type SearchStoreParams struct {
@@ -56,12 +398,84 @@ type SearchStoreParams struct {
 	Latitude   float64
 	Longitude  float64
 	Distance   uint32
+	// IncludeAddress resolves each matched store's address from geo.
+	IncludeAddress bool
+	// Query is free text matched against name, org, tags & description, ranking by relevance.
+	Query string
+	// Fuzzy matches Name by similarity instead of prefix, at MinSimilarity or the default.
+	Fuzzy         bool
+	MinSimilarity float64
+	// Facets are the fields to bucket count the matching stores by.
+	Facets []FacetField
+	Limit  int
+	Offset int
+	// Within is the area stores are searched in.
+	Within *WithinParams
+	// KNearest returns this many stores nearest the address or point, ordered by
+	// distance, within Distance meters when set.
+	KNearest int
+	// RankBy orders the nearest stores, straight line distance when empty.
+	RankBy RankBy
+	// Region matches stores in the region path & its sub regions.
+	Region string
+	// Capabilities matches stores offering all of the capabilities.
+	Capabilities []string
+	// IncludeTemporarilyClosed matches stores closed by a closure too.
+	IncludeTemporarilyClosed bool
+	// Locales are the preferred locales matched stores are localized to.
+	Locales []string
+}
+
+// NearPoint is a point stores are searched around, within MaxDistance meters when set.
+type NearPoint struct {
+	Latitude    float64
+	Longitude   float64
+	MaxDistance float64
+}
+
+// WithinParams is a search area, a bounding box or a GeoJSON Polygon or MultiPolygon.
+type WithinParams struct {
+	Bounds  *geodom.BoundingBox
+	GeoJSON string
 }
 
 type SearchStoreQuery struct {
 	Org       string
 	Name      string
 	AddressId string
+	Query     string
+	// Fuzzy matches stores sharing MinTrigramOverlap of Name's trigrams, with a
+	// NameSimilarity of at least MinSimilarity, ranked by it.
+	Fuzzy         bool
+	MinSimilarity float64
+	Facets        []FacetField
+	// Limit & Offset page the matching stores, all of them when Limit is 0.
+	Limit  int
+	Offset int
+	// Within matches stores located in any of the polygons.
+	Within []geodom.Polygon
+	// Near matches located stores, nearest the point first, with their Distance set.
+	// Limit & Offset page them, their count is the page's.
+	Near *NearPoint
+	// Region matches stores in the normalized region path & its sub regions.
+	Region string
+	// Capabilities matches stores offering all of the capabilities.
+	Capabilities []string
+	// IncludeTemporarilyClosed matches stores closed by a closure too.
+	IncludeTemporarilyClosed bool
+}
+
+// SearchStoreResult is a page of matching stores, with the total & facet counts of all of them.
+type SearchStoreResult struct {
+	Stores []*Store
+	Total  int
+	// Facets are bucket counts by value for the requested fields, largest first.
+	Facets map[FacetField][]*FacetBucket
+}
+
+type FacetBucket struct {
+	Value string
+	Count int
 }
 
 func MapToAddStoreParams(st *api.AddStoreRequest) *AddStoreParams {
@@ -69,9 +483,18 @@ func MapToAddStoreParams(st *api.AddStoreRequest) *AddStoreParams {
 		return nil
 	}
 	return &AddStoreParams{
-		Name:      st.GetName(),
-		Org:       st.GetOrg(),
-		AddressId: st.GetAddressId(),
+		Name:         st.GetName(),
+		Org:          st.GetOrg(),
+		AddressId:    st.GetAddressId(),
+		Description:  st.GetDescription(),
+		Tags:         st.GetTags(),
+		Status:       StoreStatus(st.GetStatus()),
+		ServiceArea:  st.GetServiceArea(),
+		ParentID:     st.GetParentId(),
+		Region:       st.GetRegion(),
+		Capabilities: st.GetCapabilities(),
+		Locale:       st.GetLocale(),
+		Translations: MapToStoreTranslations(st.GetTranslations()),
 	}
 }
 
@@ -79,11 +502,68 @@ func MapToStoreProto(store *Store) *api.Store {
 	if store == nil {
 		return nil
 	}
-	return &api.Store{
-		Id:        store.ID,
-		Name:      store.Name,
-		Org:       store.Org,
-		AddressId: store.AddressId,
+	stProto := &api.Store{
+		Id:           store.ID,
+		Name:         store.Name,
+		Org:          store.Org,
+		AddressId:    store.AddressId,
+		Address:      MapToAddressProto(store.Address),
+		Description:  store.Description,
+		Tags:         store.Tags,
+		Status:       string(store.Status),
+		ParentId:     store.ParentID,
+		Region:       store.Region,
+		Capabilities: store.Capabilities,
+		Locale:       store.Locale,
+	}
+	for _, c := range store.Closures {
+		stProto.Closures = append(stProto.Closures, MapToStoreClosureProto(c))
+	}
+	for _, tr := range store.Translations {
+		stProto.Translations = append(stProto.Translations, MapToStoreTranslationProto(tr))
+	}
+	for _, sa := range store.Attachments {
+		stProto.Attachments = append(stProto.Attachments, MapToStoreAttachmentProto(sa))
+	}
+	if !store.CreatedAt.IsZero() {
+		stProto.CreatedAt = timestamppb.New(store.CreatedAt)
+	}
+	if store.ServiceArea != nil {
+		if area, err := json.Marshal(store.ServiceArea); err == nil {
+			stProto.ServiceArea = string(area)
+		}
+	}
+	return stProto
+}
+
+func MapToAddressProto(addr *Address) *api.Address {
+	if addr == nil {
+		return nil
+	}
+	return &api.Address{
+		FormattedAddress: addr.FormattedAddress,
+		Latitude:         addr.Latitude,
+		Longitude:        addr.Longitude,
+	}
+}
+
+func MapToGetStoreOptions(req *api.GetStoreRequest) *GetStoreOptions {
+	if req == nil {
+		return nil
+	}
+	return &GetStoreOptions{
+		IncludeAddress: req.GetIncludeAddress(),
+	}
+}
+
+func MapToAddressChangeProto(ch *AddressChange) *api.AddressChange {
+	if ch == nil {
+		return nil
+	}
+	return &api.AddressChange{
+		AddressId:         ch.AddressId,
+		PreviousAddressId: ch.PreviousAddressId,
+		ChangedAt:         timestamppb.New(ch.ChangedAt),
 	}
 }
 
@@ -92,9 +572,20 @@ func MapToUpdateStoreParams(st *api.UpdateStoreRequest) *UpdateStoreParams {
 		return nil
 	}
 	return &UpdateStoreParams{
-		Name:      st.GetName(),
-		Org:       st.GetOrg(),
-		AddressId: st.GetAddressId(),
+		Name:              st.GetName(),
+		Org:               st.GetOrg(),
+		AddressId:         st.GetAddressId(),
+		Description:       st.GetDescription(),
+		Tags:              st.GetTags(),
+		Status:            StoreStatus(st.GetStatus()),
+		ServiceArea:       st.GetServiceArea(),
+		ParentID:          st.GetParentId(),
+		Region:            st.GetRegion(),
+		DetachParent:      st.GetDetachParent(),
+		Capabilities:      st.GetCapabilities(),
+		Locale:            st.GetLocale(),
+		Translations:      MapToStoreTranslations(st.GetTranslations()),
+		ClearTranslations: st.GetClearTranslations(),
 	}
 }
 
@@ -102,13 +593,61 @@ func MapToSearchStoreParams(st *api.SearchStoreRequest) *SearchStoreParams {
 	if st == nil {
 		return nil
 	}
+	var facets []FacetField
+	for _, f := range st.GetFacets() {
+		facets = append(facets, FacetField(f))
+	}
 	return &SearchStoreParams{
-		Org:        st.GetOrg(),
-		Name:       st.GetName(),
-		AddressId:  st.GetAddressId(),
-		AddressStr: st.GetAddressStr(),
-		Latitude:   st.GetLatitude(),
-		Longitude:  st.GetLongitude(),
-		Distance:   st.GetDistance(),
+		Org:                      st.GetOrg(),
+		Name:                     st.GetName(),
+		AddressId:                st.GetAddressId(),
+		AddressStr:               st.GetAddressStr(),
+		Latitude:                 st.GetLatitude(),
+		Longitude:                st.GetLongitude(),
+		Distance:                 st.GetDistance(),
+		IncludeAddress:           st.GetIncludeAddress(),
+		Query:                    st.GetQuery(),
+		Fuzzy:                    st.GetFuzzy(),
+		MinSimilarity:            st.GetMinSimilarity(),
+		Facets:                   facets,
+		Limit:                    int(st.GetLimit()),
+		Offset:                   int(st.GetOffset()),
+		Within:                   mapToWithinParams(st.GetWithin()),
+		KNearest:                 int(st.GetKNearest()),
+		RankBy:                   RankBy(st.GetRankBy()),
+		Region:                   st.GetRegion(),
+		Capabilities:             st.GetCapabilities(),
+		IncludeTemporarilyClosed: st.GetIncludeTemporarilyClosed(),
+	}
+}
+
+func mapToWithinParams(w *api.WithinFilter) *WithinParams {
+	if w == nil {
+		return nil
+	}
+	return &WithinParams{
+		Bounds:  mapToBoundingBox(w.GetBbox()),
+		GeoJSON: w.GetGeojson(),
+	}
+}
+
+func MapToFacetProtos(facets map[FacetField][]*FacetBucket) []*api.Facet {
+	fields := make([]string, 0, len(facets))
+	for field := range facets {
+		fields = append(fields, string(field))
+	}
+	sort.Strings(fields)
+
+	protos := make([]*api.Facet, 0, len(fields))
+	for _, field := range fields {
+		facet := &api.Facet{Field: field}
+		for _, b := range facets[FacetField(field)] {
+			facet.Buckets = append(facet.Buckets, &api.FacetBucket{
+				Value: b.Value,
+				Count: uint32(b.Count),
+			})
+		}
+		protos = append(protos, facet)
 	}
+	return protos
 }
@@ -0,0 +1,45 @@
+package stores_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+func TestApplyUpdate(t *testing.T) {
+	addressId := geodom.EncodeAddressId(38.2, -122.6, geodom.DEFAULT_ADDRESS_ID_PRECISION)
+	st := &stdom.Store{
+		Name:         "Corner Bakery",
+		Org:          "Bakeries",
+		AddressId:    "unlocated",
+		ParentID:     "parent",
+		Translations: []*stdom.StoreTranslation{{Locale: "fr", Name: "Boulangerie du Coin"}},
+	}
+	st.NameTrigrams = stdom.StoreNameTrigrams(st)
+
+	updated := stdom.ApplyUpdate(st, &stdom.UpdateStoreQuery{
+		Name:              "Bakery",
+		AddressId:         addressId,
+		DetachParent:      true,
+		ClearTranslations: true,
+	})
+	require.Equal(t, "Bakery", updated.Name)
+	require.Equal(t, "Bakeries", updated.Org)
+	require.Empty(t, updated.ParentID)
+	require.Empty(t, updated.Translations)
+	require.Equal(t, stdom.NameTrigrams("Bakery"), updated.NameTrigrams)
+	require.NotNil(t, updated.Location)
+	require.InDelta(t, 38.2, updated.Location.Coordinates[1], 0.001)
+
+	// the store itself is unchanged
+	require.Equal(t, "Corner Bakery", st.Name)
+	require.Len(t, st.Translations, 1)
+	require.Nil(t, st.Location)
+
+	// an empty schedule clears the closures
+	st.Closures = []*stdom.StoreClosure{{ID: "c1"}}
+	require.Nil(t, stdom.ApplyUpdate(st, &stdom.UpdateStoreQuery{Schedule: &stdom.StoreSchedule{}}).Closures)
+}
@@ -0,0 +1,264 @@
+package stores
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	api "github.com/comfforts/comff-stores/api/stores/v1"
+)
+
+// store fields every store requires, by their API field names
+const (
+	STORE_NAME       = "name"
+	STORE_ORG        = "org"
+	STORE_ADDRESS_ID = "address_id"
+)
+
+// store attributes orgs can require, by their API field names
+const (
+	STORE_DESCRIPTION  = "description"
+	STORE_TAGS         = "tags"
+	STORE_REGION       = "region"
+	STORE_SERVICE_AREA = "service_area"
+	STORE_PARENT_ID    = "parent_id"
+	STORE_CAPABILITIES = "capabilities"
+	STORE_LOCALE       = "locale"
+	STORE_TRANSLATIONS = "translations"
+)
+
+var requirableAttributes = []string{
+	STORE_DESCRIPTION,
+	STORE_TAGS,
+	STORE_REGION,
+	STORE_SERVICE_AREA,
+	STORE_PARENT_ID,
+	STORE_CAPABILITIES,
+	STORE_LOCALE,
+	STORE_TRANSLATIONS,
+}
+
+// ValidationRules are the rules stores are validated against when added & updated,
+// besides their name, org & address ID being required. Compile them before use.
+type ValidationRules struct {
+	Version int       `json:"version"`
+	Name    NameRules `json:"name"`
+	// ReservedWords can't be used in store names, words & phrases matched case insensitively.
+	ReservedWords []string    `json:"reserved_words,omitempty"`
+	Orgs          []*OrgRules `json:"orgs,omitempty"`
+
+	charset  *regexp.Regexp
+	reserved []string
+}
+
+// NameRules apply to store names & their translations. Lengths are in characters,
+// zero for no limit. Charset is a regular expression character class of the characters
+// names can have, e.g. `\p{L}\p{N} '&.-`, any when empty.
+type NameRules struct {
+	MinLength int    `json:"min_length,omitempty"`
+	MaxLength int    `json:"max_length,omitempty"`
+	Charset   string `json:"charset,omitempty"`
+}
+
+// OrgRules apply to an org's stores. NamePattern is a regular expression store names
+// must match, Required the store attributes they must have.
+type OrgRules struct {
+	Org         string   `json:"org"`
+	NamePattern string   `json:"name_pattern,omitempty"`
+	Required    []string `json:"required,omitempty"`
+
+	namePattern *regexp.Regexp
+}
+
+// FieldViolation is a store field breaking a validation rule.
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// Compile checks the rules are well formed & prepares them for validation.
+func (vr *ValidationRules) Compile() error {
+	if vr.Version < 1 {
+		return fmt.Errorf("version %d", vr.Version)
+	}
+	if vr.Name.MinLength < 0 || vr.Name.MaxLength < 0 || (vr.Name.MaxLength > 0 && vr.Name.MinLength > vr.Name.MaxLength) {
+		return fmt.Errorf("name lengths %d to %d", vr.Name.MinLength, vr.Name.MaxLength)
+	}
+	vr.charset = nil
+	if vr.Name.Charset != "" {
+		charset, err := regexp.Compile("^[" + vr.Name.Charset + "]$")
+		if err != nil {
+			return fmt.Errorf("name charset: %w", err)
+		}
+		vr.charset = charset
+	}
+
+	vr.reserved = []string{}
+	for _, word := range vr.ReservedWords {
+		phrase := strings.Join(nameWords(word), " ")
+		if phrase == "" {
+			return fmt.Errorf("reserved word %q", word)
+		}
+		vr.reserved = append(vr.reserved, phrase)
+	}
+
+	orgs := map[string]bool{}
+	for _, or := range vr.Orgs {
+		if or == nil || or.Org == "" {
+			return errors.New("org rules without an org")
+		}
+		if orgs[or.Org] {
+			return fmt.Errorf("duplicate org rules for %s", or.Org)
+		}
+		orgs[or.Org] = true
+
+		or.namePattern = nil
+		if or.NamePattern != "" {
+			namePattern, err := regexp.Compile(or.NamePattern)
+			if err != nil {
+				return fmt.Errorf("%s name pattern: %w", or.Org, err)
+			}
+			or.namePattern = namePattern
+		}
+		for _, attr := range or.Required {
+			if !slices.Contains(requirableAttributes, attr) {
+				return fmt.Errorf("%s requires unknown attribute %q", or.Org, attr)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate returns all of the store's violations of the rules, none when valid.
+// Without rules only the store's name, org & address ID are checked.
+func (vr *ValidationRules) Validate(st *Store) []*FieldViolation {
+	violations := []*FieldViolation{}
+	for _, f := range []struct{ field, value string }{
+		{STORE_NAME, st.Name},
+		{STORE_ORG, st.Org},
+		{STORE_ADDRESS_ID, st.AddressId},
+	} {
+		if strings.TrimSpace(f.value) == "" {
+			violations = append(violations, &FieldViolation{Field: f.field, Description: "is required"})
+		}
+	}
+	if vr == nil {
+		return violations
+	}
+
+	if strings.TrimSpace(st.Name) != "" {
+		violations = append(violations, vr.nameViolations(STORE_NAME, st.Name)...)
+	}
+	for i, tr := range st.Translations {
+		if tr != nil && tr.Name != "" {
+			violations = append(violations, vr.nameViolations(fmt.Sprintf("%s[%d].%s", STORE_TRANSLATIONS, i, STORE_NAME), tr.Name)...)
+		}
+	}
+
+	or := vr.orgRules(st.Org)
+	if or == nil {
+		return violations
+	}
+	if or.namePattern != nil && strings.TrimSpace(st.Name) != "" && !or.namePattern.MatchString(st.Name) {
+		violations = append(violations, &FieldViolation{
+			Field:       STORE_NAME,
+			Description: fmt.Sprintf("doesn't match the %s naming pattern %s", st.Org, or.NamePattern),
+		})
+	}
+	for _, attr := range or.Required {
+		if !hasAttribute(st, attr) {
+			violations = append(violations, &FieldViolation{
+				Field:       attr,
+				Description: fmt.Sprintf("is required for %s stores", st.Org),
+			})
+		}
+	}
+	return violations
+}
+
+// nameViolations checks the name's length, characters & words.
+func (vr *ValidationRules) nameViolations(field, name string) []*FieldViolation {
+	violations := []*FieldViolation{}
+	if n := utf8.RuneCountInString(name); n < vr.Name.MinLength || (vr.Name.MaxLength > 0 && n > vr.Name.MaxLength) {
+		desc := fmt.Sprintf("must be at least %d characters", vr.Name.MinLength)
+		if vr.Name.MaxLength > 0 {
+			desc = fmt.Sprintf("must be %d to %d characters", vr.Name.MinLength, vr.Name.MaxLength)
+		}
+		violations = append(violations, &FieldViolation{Field: field, Description: desc})
+	}
+
+	if vr.charset != nil {
+		disallowed := []string{}
+		for _, r := range name {
+			if c := string(r); !vr.charset.MatchString(c) && !slices.Contains(disallowed, c) {
+				disallowed = append(disallowed, c)
+			}
+		}
+		if len(disallowed) > 0 {
+			violations = append(violations, &FieldViolation{
+				Field:       field,
+				Description: fmt.Sprintf("has disallowed characters %q", strings.Join(disallowed, "")),
+			})
+		}
+	}
+
+	words := " " + strings.Join(nameWords(name), " ") + " "
+	for _, phrase := range vr.reserved {
+		if strings.Contains(words, " "+phrase+" ") {
+			violations = append(violations, &FieldViolation{
+				Field:       field,
+				Description: fmt.Sprintf("has reserved word %q", phrase),
+			})
+		}
+	}
+	return violations
+}
+
+// orgRules returns the org's rules, nil when it has none.
+func (vr *ValidationRules) orgRules(org string) *OrgRules {
+	for _, or := range vr.Orgs {
+		if or.Org == org {
+			return or
+		}
+	}
+	return nil
+}
+
+// hasAttribute tells whether the store has the requirable attribute set.
+func hasAttribute(st *Store, attr string) bool {
+	switch attr {
+	case STORE_DESCRIPTION:
+		return strings.TrimSpace(st.Description) != ""
+	case STORE_TAGS:
+		return len(st.Tags) > 0
+	case STORE_REGION:
+		return st.Region != ""
+	case STORE_SERVICE_AREA:
+		return st.ServiceArea != nil
+	case STORE_PARENT_ID:
+		return st.ParentID != ""
+	case STORE_CAPABILITIES:
+		return len(st.Capabilities) > 0
+	case STORE_LOCALE:
+		return st.Locale != ""
+	case STORE_TRANSLATIONS:
+		return len(st.Translations) > 0
+	}
+	return false
+}
+
+func MapToStoreValidationErrorProto(violations []*FieldViolation) *api.StoreValidationError {
+	veProto := &api.StoreValidationError{
+		Violations: make([]*api.StoreFieldViolation, 0, len(violations)),
+	}
+	for _, v := range violations {
+		veProto.Violations = append(veProto.Violations, &api.StoreFieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	return veProto
+}
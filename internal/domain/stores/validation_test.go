@@ -0,0 +1,77 @@
+package stores_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+func TestValidationRules_Compile(t *testing.T) {
+	for name, rules := range map[string]*stdom.ValidationRules{
+		"unversioned":       {},
+		"negative length":   {Version: 1, Name: stdom.NameRules{MinLength: -1}},
+		"min over max":      {Version: 1, Name: stdom.NameRules{MinLength: 10, MaxLength: 5}},
+		"bad charset":       {Version: 1, Name: stdom.NameRules{Charset: `\p{Nope}`}},
+		"empty reserved":    {Version: 1, ReservedWords: []string{" - "}},
+		"org without name":  {Version: 1, Orgs: []*stdom.OrgRules{{NamePattern: "^A"}}},
+		"duplicate org":     {Version: 1, Orgs: []*stdom.OrgRules{{Org: "Acme"}, {Org: "Acme"}}},
+		"bad name pattern":  {Version: 1, Orgs: []*stdom.OrgRules{{Org: "Acme", NamePattern: "("}}},
+		"unknown attribute": {Version: 1, Orgs: []*stdom.OrgRules{{Org: "Acme", Required: []string{"phone"}}}},
+	} {
+		require.Error(t, rules.Compile(), name)
+	}
+}
+
+func TestValidationRules_Validate(t *testing.T) {
+	rules := &stdom.ValidationRules{
+		Version:       1,
+		Name:          stdom.NameRules{MinLength: 2, MaxLength: 20, Charset: `\p{L}\p{N} '&.-`},
+		ReservedWords: []string{"Admin", "store of"},
+		Orgs: []*stdom.OrgRules{
+			{Org: "Acme", NamePattern: `^Acme `, Required: []string{stdom.STORE_DESCRIPTION, stdom.STORE_REGION}},
+		},
+	}
+	require.NoError(t, rules.Compile())
+
+	fields := func(violations []*stdom.FieldViolation) []string {
+		got := []string{}
+		for _, v := range violations {
+			got = append(got, v.Field+": "+v.Description)
+		}
+		return got
+	}
+
+	require.Empty(t, rules.Validate(&stdom.Store{Name: "Corner Bakery", Org: "Test Org", AddressId: "dacdbddabcadccbdacac"}))
+	require.Empty(t, rules.Validate(&stdom.Store{Name: "Acme Market", Org: "Acme", AddressId: "dacdbddabcadccbdacac", Description: "Groceries", Region: "west"}))
+
+	// every violation at once
+	require.Equal(t, []string{
+		"org: is required",
+		"address_id: is required",
+		"name: must be 2 to 20 characters",
+		`name: has disallowed characters "~!"`,
+		`name: has reserved word "admin"`,
+		`name: has reserved word "store of"`,
+		`translations[1].name: has reserved word "admin"`,
+	}, fields(rules.Validate(&stdom.Store{
+		Name: "The ~Admin~ Store of Things!",
+		Translations: []*stdom.StoreTranslation{
+			{Locale: "fr", Name: "Le Magasin"},
+			{Locale: "es", Name: "Tienda admin"},
+		},
+	})))
+	// reserved words match whole words
+	require.Empty(t, rules.Validate(&stdom.Store{Name: "Administrators", Org: "Test Org", AddressId: "dacdbddabcadccbdacac"}))
+
+	require.Equal(t, []string{
+		"name: doesn't match the Acme naming pattern ^Acme ",
+		"description: is required for Acme stores",
+		"region: is required for Acme stores",
+	}, fields(rules.Validate(&stdom.Store{Name: "Market", Org: "Acme", AddressId: "dacdbddabcadccbdacac"})))
+
+	// without rules names, orgs & address IDs are still required
+	var none *stdom.ValidationRules
+	require.Equal(t, []string{"name: is required"}, fields(none.Validate(&stdom.Store{Name: " ", Org: "Test Org", AddressId: "dacdbddabcadccbdacac"})))
+}
@@ -0,0 +1,220 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	api "github.com/comfforts/comff-stores/api/stores/v1"
+)
+
+const (
+	SIGNATURE_HEADER   = "X-Comff-Signature"
+	TIMESTAMP_HEADER   = "X-Comff-Timestamp"
+	EVENT_TYPE_HEADER  = "X-Comff-Event"
+	DELIVERY_ID_HEADER = "X-Comff-Delivery"
+)
+
+const (
+	ERR_NO_WEBHOOK = "no webhook found"
+	ERR_CLAIM_LOST = "webhook delivery no longer claimed"
+)
+
+var (
+	ErrNoWebhook = errors.New(ERR_NO_WEBHOOK)
+	ErrClaimLost = errors.New(ERR_CLAIM_LOST)
+)
+
+type DeliveryStatus string
+
+const (
+	DELIVERY_PENDING   DeliveryStatus = "pending"
+	DELIVERY_DELIVERED DeliveryStatus = "delivered"
+	DELIVERY_DEAD      DeliveryStatus = "dead"
+)
+
+type WebhooksRepo interface {
+	AddSubscription(ctx context.Context, sub *Subscription) (string, error)
+	GetSubscription(ctx context.Context, id string) (*Subscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	ListSubscriptions(ctx context.Context, org string) ([]*Subscription, error)
+	// MatchSubscriptions returns subscriptions interested in the event type for any of the orgs.
+	MatchSubscriptions(ctx context.Context, eventType string, orgs []string) ([]*Subscription, error)
+
+	// EnqueueDeliveries adds pending deliveries, ignoring ones already enqueued
+	// for the same subscription & event.
+	EnqueueDeliveries(ctx context.Context, deliveries []*Delivery) error
+	// ClaimDeliveries leases up to limit due deliveries for the given duration, each
+	// with a new Claim the claimant settles it with.
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*Delivery, error)
+	// ExtendClaim leases the claimed delivery for another lease, failing with ErrClaimLost
+	// when it's no longer pending under the claim.
+	ExtendClaim(ctx context.Context, id, claim string, lease time.Duration) error
+	// MarkDelivered records the claimed delivery's success, failing with ErrClaimLost
+	// when it's no longer pending under the claim.
+	MarkDelivered(ctx context.Context, id, claim string, statusCode int) error
+	// MarkFailed records a failed attempt of the claimed delivery, with a nil nextAttemptAt
+	// the delivery is dead-lettered. It fails with ErrClaimLost when the delivery is no
+	// longer pending under the claim.
+	MarkFailed(ctx context.Context, id, claim string, statusCode int, lastErr string, nextAttemptAt *time.Time) error
+	ListDeliveries(ctx context.Context, params *DeliveriesQuery) ([]*Delivery, error)
+}
+
+type WebhooksService interface {
+	RegisterWebhook(ctx context.Context, params *RegisterWebhookParams) (string, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	ListWebhooks(ctx context.Context, org string) ([]*Subscription, error)
+	GetDeliveries(ctx context.Context, params *DeliveriesQuery) ([]*Delivery, error)
+}
+
+// Subscription is a partner webhook registered for store change events.
+// An empty Org matches all orgs, empty EventTypes match all event types.
+type Subscription struct {
+	ID         string    `bson:"_id,omitempty"`
+	URL        string    `bson:"url"`
+	EventTypes []string  `bson:"event_types"`
+	Org        string    `bson:"org"`
+	Secret     string    `bson:"secret"`
+	CreatedAt  time.Time `bson:"created_at"`
+}
+
+// Delivery is a single webhook POST of an event to a subscription.
+// Payload holds the exact request body so retries are byte-identical.
+type Delivery struct {
+	ID             string         `bson:"_id,omitempty"`
+	SubscriptionID string         `bson:"webhook_id"`
+	EventID        string         `bson:"event_id"`
+	EventType      string         `bson:"event_type"`
+	Payload        []byte         `bson:"payload"`
+	Status         DeliveryStatus `bson:"status"`
+	Attempts       int            `bson:"attempts"`
+	NextAttemptAt  time.Time      `bson:"next_attempt_at"`
+	LockedUntil    time.Time      `bson:"locked_until"`
+	Claim          string         `bson:"claim,omitempty"`
+	LastStatusCode int            `bson:"last_status_code,omitempty"`
+	LastError      string         `bson:"last_error,omitempty"`
+	CreatedAt      time.Time      `bson:"created_at"`
+	DeliveredAt    *time.Time     `bson:"delivered_at,omitempty"`
+}
+
+type RegisterWebhookParams struct {
+	URL        string
+	EventTypes []string
+	Org        string
+	Secret     string
+}
+
+type DeliveriesQuery struct {
+	SubscriptionID string
+	Status         DeliveryStatus
+	Limit          int
+}
+
+// SignPayload returns the hex encoded HMAC-SHA256 signature of "<timestamp>.<body>",
+// prefixed with the algorithm, as sent in the SIGNATURE_HEADER.
+func SignPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks a signature produced by SignPayload in constant time.
+func VerifySignature(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignPayload(secret, timestamp, body)), []byte(signature))
+}
+
+// nonPublicPrefixes are special purpose IPv4 ranges netip doesn't classify, this network,
+// shared address space, IETF protocol assignments, benchmarking & reserved.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// PublicAddr tells whether webhooks can be delivered to the address, one that isn't
+// loopback, private, link-local, multicast, unspecified or otherwise special purpose.
+func PublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, p := range nonPublicPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func MapToRegisterWebhookParams(req *api.RegisterWebhookRequest) *RegisterWebhookParams {
+	if req == nil {
+		return nil
+	}
+	return &RegisterWebhookParams{
+		URL:        req.GetUrl(),
+		EventTypes: req.GetEventTypes(),
+		Org:        req.GetOrg(),
+		Secret:     req.GetSecret(),
+	}
+}
+
+func MapToDeliveriesQuery(req *api.GetWebhookDeliveriesRequest) *DeliveriesQuery {
+	if req == nil {
+		return nil
+	}
+	return &DeliveriesQuery{
+		SubscriptionID: req.GetWebhookId(),
+		Status:         DeliveryStatus(req.GetStatus()),
+		Limit:          int(req.GetLimit()),
+	}
+}
+
+func MapToWebhookProto(sub *Subscription) *api.Webhook {
+	if sub == nil {
+		return nil
+	}
+	return &api.Webhook{
+		Id:         sub.ID,
+		Url:        sub.URL,
+		EventTypes: sub.EventTypes,
+		Org:        sub.Org,
+		CreatedAt:  timestamppb.New(sub.CreatedAt),
+	}
+}
+
+func MapToWebhookDeliveryProto(d *Delivery) *api.WebhookDelivery {
+	if d == nil {
+		return nil
+	}
+	dp := &api.WebhookDelivery{
+		Id:            d.ID,
+		WebhookId:     d.SubscriptionID,
+		EventId:       d.EventID,
+		EventType:     d.EventType,
+		Status:        string(d.Status),
+		Attempts:      uint32(d.Attempts),
+		LastError:     d.LastError,
+		CreatedAt:     timestamppb.New(d.CreatedAt),
+		NextAttemptAt: timestamppb.New(d.NextAttemptAt),
+	}
+	if d.LastStatusCode != 0 {
+		code := int32(d.LastStatusCode)
+		dp.LastStatusCode = &code
+	}
+	if d.DeliveredAt != nil {
+		dp.DeliveredAt = timestamppb.New(*d.DeliveredAt)
+	}
+	return dp
+}
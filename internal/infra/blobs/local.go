@@ -0,0 +1,115 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/comfforts/logger"
+
+	blobdom "github.com/comfforts/comff-stores/internal/domain/blobs"
+)
+
+var _ blobdom.BlobStore = (*localBlobStore)(nil)
+
+var ErrMissingBlobDir = errors.New("missing blob directory")
+
+// LocalOptions configure the local blob store. Blobs are served at BaseURL joined with
+// their key when set, at their file:// URI otherwise.
+type LocalOptions struct {
+	Dir     string
+	BaseURL string
+}
+
+// localBlobStore keeps blobs as files under a directory, for local development, tests
+// & single host deployments. Blobs are written to a temporary file & renamed into place,
+// so readers never see partial content.
+type localBlobStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalBlobStore returns a local blob store, creating its directory if needed.
+func NewLocalBlobStore(ctx context.Context, opts LocalOptions) (*localBlobStore, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if opts.Dir == "" {
+		return nil, ErrMissingBlobDir
+	}
+	dir, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		l.Error("error creating blob directory", "error", err.Error(), "dir", dir)
+		return nil, err
+	}
+	if opts.BaseURL != "" {
+		if _, err := url.Parse(opts.BaseURL); err != nil {
+			return nil, err
+		}
+	}
+
+	l.Info("initialized local blob store", "dir", dir)
+	return &localBlobStore{dir: dir, baseURL: strings.TrimSuffix(opts.BaseURL, "/")}, nil
+}
+
+func (lb *localBlobStore) Put(ctx context.Context, key string, r io.Reader) (string, error) {
+	name, err := lb.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return "", err
+	}
+
+	if lb.baseURL != "" {
+		return lb.baseURL + "/" + key, nil
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(name)}).String(), nil
+}
+
+func (lb *localBlobStore) Delete(ctx context.Context, key string) error {
+	name, err := lb.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path returns the blob's file, keys are clean relative paths that stay in the directory.
+func (lb *localBlobStore) path(key string) (string, error) {
+	if key == "" || path.Clean(key) != key || path.IsAbs(key) || key == "." || key == ".." || strings.HasPrefix(key, "../") || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("%w: %q", blobdom.ErrInvalidBlobKey, key)
+	}
+	return filepath.Join(lb.dir, filepath.FromSlash(key)), nil
+}
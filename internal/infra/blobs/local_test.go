@@ -0,0 +1,61 @@
+package blobs_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/comfforts/logger"
+
+	blobdom "github.com/comfforts/comff-stores/internal/domain/blobs"
+	"github.com/comfforts/comff-stores/internal/infra/blobs"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+	dir := t.TempDir()
+
+	bs, err := blobs.NewLocalBlobStore(ctx, blobs.LocalOptions{Dir: dir})
+	require.NoError(t, err)
+
+	uri, err := bs.Put(ctx, "stores/s1/logo.png", strings.NewReader("logo"))
+	require.NoError(t, err)
+	name := filepath.Join(dir, "stores", "s1", "logo.png")
+	require.Equal(t, "file://"+filepath.ToSlash(name), uri)
+	data, err := os.ReadFile(name)
+	require.NoError(t, err)
+	require.Equal(t, "logo", string(data))
+
+	// replaced in place, without leftover temporary files
+	_, err = bs.Put(ctx, "stores/s1/logo.png", strings.NewReader("new logo"))
+	require.NoError(t, err)
+	data, err = os.ReadFile(name)
+	require.NoError(t, err)
+	require.Equal(t, "new logo", string(data))
+	entries, err := os.ReadDir(filepath.Dir(name))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	require.NoError(t, bs.Delete(ctx, "stores/s1/logo.png"))
+	require.NoFileExists(t, name)
+	require.NoError(t, bs.Delete(ctx, "stores/s1/logo.png"))
+
+	// keys stay in the directory
+	for _, key := range []string{"", ".", "../escape", "/abs", "stores/../../escape", "stores//s1", `stores\s1`} {
+		_, err := bs.Put(ctx, key, strings.NewReader("x"))
+		require.ErrorIs(t, err, blobdom.ErrInvalidBlobKey, key)
+	}
+
+	served, err := blobs.NewLocalBlobStore(ctx, blobs.LocalOptions{Dir: dir, BaseURL: "https://cdn.example.com/media/"})
+	require.NoError(t, err)
+	uri, err = served.Put(ctx, "stores/s1/photo.jpg", strings.NewReader("photo"))
+	require.NoError(t, err)
+	require.Equal(t, "https://cdn.example.com/media/stores/s1/photo.jpg", uri)
+
+	_, err = blobs.NewLocalBlobStore(ctx, blobs.LocalOptions{})
+	require.ErrorIs(t, err, blobs.ErrMissingBlobDir)
+}
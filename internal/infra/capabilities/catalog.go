@@ -0,0 +1,75 @@
+package capabilities
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/comfforts/logger"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+const ERR_INVALID_CATALOG = "invalid capability catalog"
+
+var ErrInvalidCatalog = errors.New(ERR_INVALID_CATALOG)
+
+// capability codes are lower snake case, e.g. ev_charging
+var codePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// defaultCatalog is the built-in capability catalog.
+//
+//go:embed catalog.json
+var defaultCatalog []byte
+
+// LoadCatalog returns the capability catalog from the JSON file at path, or the
+// built-in catalog when empty.
+func LoadCatalog(ctx context.Context, path string) (*stdom.CapabilityCatalog, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	data := defaultCatalog
+	if path != "" {
+		if data, err = os.ReadFile(path); err != nil {
+			l.Error("error reading capability catalog", "error", err.Error(), "path", path)
+			return nil, err
+		}
+	}
+
+	var catalog stdom.CapabilityCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		l.Error("error decoding capability catalog", "error", err.Error(), "path", path)
+		return nil, err
+	}
+	if err := validateCatalog(&catalog); err != nil {
+		l.Error("error validating capability catalog", "error", err.Error(), "path", path)
+		return nil, err
+	}
+
+	l.Info("loaded capability catalog", "version", catalog.Version, "capabilities", len(catalog.Capabilities), "path", path)
+	return &catalog, nil
+}
+
+// validateCatalog checks the catalog is versioned & its codes are well formed & unique.
+func validateCatalog(catalog *stdom.CapabilityCatalog) error {
+	if catalog.Version < 1 {
+		return fmt.Errorf("%w: version %d", ErrInvalidCatalog, catalog.Version)
+	}
+	codes := map[string]bool{}
+	for _, c := range catalog.Capabilities {
+		if c == nil || !codePattern.MatchString(c.Code) || c.Name == "" {
+			return fmt.Errorf("%w: malformed capability", ErrInvalidCatalog)
+		}
+		if codes[c.Code] {
+			return fmt.Errorf("%w: duplicate capability %s", ErrInvalidCatalog, c.Code)
+		}
+		codes[c.Code] = true
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package capabilities_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/comfforts/logger"
+
+	"github.com/comfforts/comff-stores/internal/infra/capabilities"
+)
+
+func TestLoadCatalog(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+
+	catalog, err := capabilities.LoadCatalog(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, catalog.Version)
+	for _, code := range []string{"pickup", "delivery", "pharmacy", "ev_charging"} {
+		_, ok := catalog.Lookup(code)
+		require.True(t, ok, code)
+	}
+	_, ok := catalog.Lookup("Pickup")
+	require.False(t, ok)
+
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+		return path
+	}
+
+	catalog, err = capabilities.LoadCatalog(ctx, write("v2.json", `{"version":2,"capabilities":[
+		{"code":"pickup","name":"Pickup"},
+		{"code":"photo_lab","name":"Photo lab","deprecated":true}
+	]}`))
+	require.NoError(t, err)
+	require.Equal(t, 2, catalog.Version)
+	photo, ok := catalog.Lookup("photo_lab")
+	require.True(t, ok)
+	require.True(t, photo.Deprecated)
+
+	for name, data := range map[string]string{
+		"unversioned": `{"capabilities":[{"code":"pickup","name":"Pickup"}]}`,
+		"bad code":    `{"version":1,"capabilities":[{"code":"EV Charging","name":"EV charging"}]}`,
+		"unnamed":     `{"version":1,"capabilities":[{"code":"pickup"}]}`,
+		"duplicate":   `{"version":1,"capabilities":[{"code":"pickup","name":"Pickup"},{"code":"pickup","name":"Pickup"}]}`,
+	} {
+		_, err := capabilities.LoadCatalog(ctx, write(name+".json", data))
+		require.ErrorIs(t, err, capabilities.ErrInvalidCatalog, name)
+	}
+	_, err = capabilities.LoadCatalog(ctx, filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+}
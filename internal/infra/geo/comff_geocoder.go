@@ -0,0 +1,74 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	geo_v1 "github.com/comfforts/comff-geo/api/geo/v1"
+	geocl "github.com/comfforts/comff-geo/clients/go"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+)
+
+var _ geodom.Geocoder = (*comffGeocoder)(nil)
+
+// comffGeocoder adapts the comff-geo client to the Geocoder interface.
+type comffGeocoder struct {
+	client geocl.Client
+}
+
+func NewComffGeocoder(gc geocl.Client) (*comffGeocoder, error) {
+	if gc == nil {
+		return nil, ErrMissingGeoClient
+	}
+	return &comffGeocoder{
+		client: gc,
+	}, nil
+}
+
+func (cg *comffGeocoder) LocateAddressId(ctx context.Context, addressId string) (*geodom.Location, error) {
+	resp, err := cg.client.GetGeoLocation(ctx, &geo_v1.GeoLocationRequest{
+		AddressId: addressId,
+	})
+	return mapGeoResponse(resp, err)
+}
+
+func (cg *comffGeocoder) GeocodeAddress(ctx context.Context, addressStr string) (*geodom.Location, error) {
+	resp, err := cg.client.GeoLocate(ctx, &geo_v1.GeoRequest{
+		AddressStr: addressStr,
+	})
+	return mapGeoResponse(resp, err)
+}
+
+func (cg *comffGeocoder) GeocodeLatLon(ctx context.Context, lat, lon float64) (*geodom.Location, error) {
+	resp, err := cg.client.GeoLocate(ctx, &geo_v1.GeoRequest{
+		Latitude:  lat,
+		Longitude: lon,
+	})
+	return mapGeoResponse(resp, err)
+}
+
+func (cg *comffGeocoder) Close(ctx context.Context) error {
+	return cg.client.Close(ctx)
+}
+
+func mapGeoResponse(resp *geo_v1.GeoResponse, err error) (*geodom.Location, error) {
+	if err != nil {
+		if errors.Is(err, geodom.ErrGeoUnavailable) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", geodom.ErrInvalidAddress, err)
+	}
+
+	pt := resp.GetPoint()
+	if pt == nil || pt.GetHash() == "" {
+		return nil, geodom.ErrInvalidAddress
+	}
+	return &geodom.Location{
+		AddressId:        pt.GetHash(),
+		Latitude:         pt.GetLatitude(),
+		Longitude:        pt.GetLongitude(),
+		FormattedAddress: pt.GetFormattedAddress(),
+	}, nil
+}
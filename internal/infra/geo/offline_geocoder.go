@@ -0,0 +1,199 @@
+package geo
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/comfforts/logger"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+)
+
+// MIN_OFFLINE_ADDRESS_ID_LEN is the shortest address ID accepted offline,
+// shorter ones are cells too coarse to be an address.
+const MIN_OFFLINE_ADDRESS_ID_LEN = 12
+
+const ERR_ADDRESS_NOT_FOUND = "address not found in geo fixtures"
+
+var ErrAddressNotFound = errors.New(ERR_ADDRESS_NOT_FOUND)
+
+// defaultFixtures are the addresses from pkg/utils/test/data_sets.go.
+//
+//go:embed fixtures/addresses.json
+var defaultFixtures []byte
+
+var _ geodom.Geocoder = (*offlineGeocoder)(nil)
+
+// FixtureAddress is a known address in the offline geocoder fixture file.
+type FixtureAddress struct {
+	Street     string  `json:"street"`
+	City       string  `json:"city"`
+	State      string  `json:"state"`
+	PostalCode string  `json:"postal_code"`
+	Country    string  `json:"country"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+}
+
+func (fa *FixtureAddress) formatted() string {
+	return fmt.Sprintf("%s, %s, %s %s, %s", fa.Street, fa.City, fa.State, fa.PostalCode, fa.Country)
+}
+
+// offlineGeocoder is a Geocoder for local development & tests, needing no geo service.
+// Address IDs are computed locally from lat/lon, address strings are resolved
+// against a fixture file, by full address, street or, as the centroid of the
+// matching fixtures, by postal code or city.
+type offlineGeocoder struct {
+	precision int
+	byId      map[string]*geodom.Location
+	byAddress map[string]*geodom.Location
+	byArea    map[string]*geodom.Location
+}
+
+// NewOfflineGeocoder returns an offline geocoder loading addresses from fixturesPath,
+// or from the built-in fixtures when empty.
+func NewOfflineGeocoder(ctx context.Context, fixturesPath string) (*offlineGeocoder, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	data := defaultFixtures
+	if fixturesPath != "" {
+		if data, err = os.ReadFile(fixturesPath); err != nil {
+			l.Error("error reading geo fixtures", "error", err.Error(), "path", fixturesPath)
+			return nil, err
+		}
+	}
+
+	var fixtures []*FixtureAddress
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		l.Error("error decoding geo fixtures", "error", err.Error(), "path", fixturesPath)
+		return nil, err
+	}
+
+	og := newOfflineGeocoder(fixtures)
+	l.Info("initialized offline geocoder", "addresses", len(fixtures), "path", fixturesPath)
+	return og, nil
+}
+
+// NewOfflineGeocoderFromFixtures returns an offline geocoder resolving the given addresses.
+func NewOfflineGeocoderFromFixtures(ctx context.Context, fixtures []*FixtureAddress) (*offlineGeocoder, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	og := newOfflineGeocoder(fixtures)
+	l.Info("initialized offline geocoder", "addresses", len(fixtures))
+	return og, nil
+}
+
+func newOfflineGeocoder(fixtures []*FixtureAddress) *offlineGeocoder {
+	og := &offlineGeocoder{
+		precision: geodom.DEFAULT_ADDRESS_ID_PRECISION,
+		byId:      map[string]*geodom.Location{},
+		byAddress: map[string]*geodom.Location{},
+		byArea:    map[string]*geodom.Location{},
+	}
+
+	areas := map[string][]*FixtureAddress{}
+	for _, fa := range fixtures {
+		loc := og.location(fa.Latitude, fa.Longitude, fa.formatted())
+		og.byId[loc.AddressId] = loc
+		og.byAddress[normalizeAddress(fa.formatted())] = loc
+		og.byAddress[normalizeAddress(fa.Street+" "+fa.City)] = loc
+		og.byAddress[normalizeAddress(fa.Street+" "+fa.PostalCode)] = loc
+		if fa.PostalCode != "" {
+			k := normalizeAddress(fa.PostalCode)
+			areas[k] = append(areas[k], fa)
+		}
+		if fa.City != "" {
+			k := normalizeAddress(fa.City + " " + fa.State)
+			areas[k] = append(areas[k], fa)
+			k = normalizeAddress(fa.City)
+			areas[k] = append(areas[k], fa)
+		}
+	}
+	for k, fas := range areas {
+		var lat, lon float64
+		for _, fa := range fas {
+			lat += fa.Latitude
+			lon += fa.Longitude
+		}
+		n := float64(len(fas))
+		og.byArea[k] = og.location(lat/n, lon/n, "")
+	}
+	return og
+}
+
+func (og *offlineGeocoder) LocateAddressId(ctx context.Context, addressId string) (*geodom.Location, error) {
+	if len(addressId) < MIN_OFFLINE_ADDRESS_ID_LEN {
+		return nil, fmt.Errorf("%w: %w", geodom.ErrInvalidAddress, geodom.ErrInvalidAddressId)
+	}
+	if loc, ok := og.byId[addressId]; ok {
+		return copyLocation(loc), nil
+	}
+	lat, lon, err := geodom.DecodeAddressId(addressId)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", geodom.ErrInvalidAddress, err)
+	}
+	return &geodom.Location{
+		AddressId: addressId,
+		Latitude:  lat,
+		Longitude: lon,
+	}, nil
+}
+
+func (og *offlineGeocoder) GeocodeAddress(ctx context.Context, addressStr string) (*geodom.Location, error) {
+	k := normalizeAddress(addressStr)
+	if loc, ok := og.byAddress[k]; ok {
+		return copyLocation(loc), nil
+	}
+	if loc, ok := og.byArea[k]; ok {
+		return copyLocation(loc), nil
+	}
+	return nil, fmt.Errorf("%w: %w", geodom.ErrInvalidAddress, ErrAddressNotFound)
+}
+
+func (og *offlineGeocoder) GeocodeLatLon(ctx context.Context, lat, lon float64) (*geodom.Location, error) {
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return nil, geodom.ErrInvalidAddress
+	}
+	return og.location(lat, lon, ""), nil
+}
+
+func (og *offlineGeocoder) Close(ctx context.Context) error {
+	return nil
+}
+
+func (og *offlineGeocoder) location(lat, lon float64, formatted string) *geodom.Location {
+	return &geodom.Location{
+		AddressId:        geodom.EncodeAddressId(lat, lon, og.precision),
+		Latitude:         lat,
+		Longitude:        lon,
+		FormattedAddress: formatted,
+	}
+}
+
+func copyLocation(loc *geodom.Location) *geodom.Location {
+	cp := *loc
+	return &cp
+}
+
+// normalizeAddress lower cases, drops punctuation & collapses whitespace.
+func normalizeAddress(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
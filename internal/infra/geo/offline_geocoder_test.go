@@ -0,0 +1,81 @@
+package geo_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/comfforts/logger"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	geoinfra "github.com/comfforts/comff-stores/internal/infra/geo"
+	testutils "github.com/comfforts/comff-stores/pkg/utils/test"
+)
+
+func TestOfflineGeocoder(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+
+	og, err := geoinfra.NewOfflineGeocoder(ctx, "")
+	require.NoError(t, err)
+
+	// every data set address resolves by full address & to its lat/lon address ID
+	dests := append(testutils.BuildPetalumaSet1(), testutils.BuildPetalumaSet2()...)
+	for _, dest := range dests {
+		loc, err := og.GeocodeAddress(ctx, dest.GetStreet()+", "+dest.GetCity()+", "+dest.GetState()+" "+dest.GetPostalCode()+", "+dest.GetCountry())
+		require.NoError(t, err, dest.GetStreet())
+
+		ll, err := og.GeocodeLatLon(ctx, dest.GetLatitude(), dest.GetLongitude())
+		require.NoError(t, err)
+		require.Equal(t, ll.AddressId, loc.AddressId)
+		require.Len(t, loc.AddressId, geodom.DEFAULT_ADDRESS_ID_PRECISION)
+	}
+
+	loc, err := og.GeocodeAddress(ctx, "201 fair st  PETALUMA")
+	require.NoError(t, err)
+	require.Equal(t, "dacdbddabcadcddabb", loc.AddressId[:18])
+
+	// postal code resolves to the centroid of its addresses
+	loc, err = og.GeocodeAddress(ctx, "94954")
+	require.NoError(t, err)
+	require.Equal(t, "dacdbddab", loc.AddressId[:9])
+
+	_, err = og.GeocodeAddress(ctx, "1 Nowhere Rd, Atlantis")
+	require.ErrorIs(t, err, geodom.ErrInvalidAddress)
+
+	loc, err = og.LocateAddressId(ctx, "dacdbddabcadccbdacac")
+	require.NoError(t, err)
+	require.Equal(t, "dacdbddabcadccbdacac", loc.AddressId)
+
+	_, err = og.LocateAddressId(ctx, "dacd")
+	require.ErrorIs(t, err, geodom.ErrInvalidAddress)
+	_, err = og.LocateAddressId(ctx, "dacdbddabcadccbdxyz")
+	require.ErrorIs(t, err, geodom.ErrInvalidAddress)
+}
+
+func TestOfflineGeocoderFixturesFile(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+
+	path := filepath.Join(t.TempDir(), "addresses.json")
+	b, err := json.Marshal([]*geoinfra.FixtureAddress{
+		{Street: "1 Main St", City: "Springfield", State: "IL", PostalCode: "62701", Country: "USA", Latitude: 39.8017, Longitude: -89.6436},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, b, 0o644))
+
+	og, err := geoinfra.NewOfflineGeocoder(ctx, path)
+	require.NoError(t, err)
+
+	loc, err := og.GeocodeAddress(ctx, "1 Main St, Springfield")
+	require.NoError(t, err)
+	require.Equal(t, geodom.EncodeAddressId(39.8017, -89.6436, geodom.DEFAULT_ADDRESS_ID_PRECISION), loc.AddressId)
+
+	_, err = og.GeocodeAddress(ctx, "2 Turquoise Ct, Petaluma")
+	require.ErrorIs(t, err, geodom.ErrInvalidAddress)
+
+	_, err = geoinfra.NewOfflineGeocoder(ctx, filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
@@ -0,0 +1,235 @@
+package geo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	geo_v1 "github.com/comfforts/comff-geo/api/geo/v1"
+	geocl "github.com/comfforts/comff-geo/clients/go"
+	"github.com/comfforts/logger"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+)
+
+const (
+	ERR_MISSING_GEO_CLIENT = "missing geo client"
+	ERR_CIRCUIT_OPEN       = "geo circuit breaker open"
+)
+
+var (
+	ErrMissingGeoClient = errors.New(ERR_MISSING_GEO_CLIENT)
+	// ErrGeoUnavailable is returned, wrapping the cause, when geo couldn't answer,
+	// as opposed to geo rejecting the request, e.g. for an invalid address.
+	ErrGeoUnavailable = geodom.ErrGeoUnavailable
+	ErrCircuitOpen    = errors.New(ERR_CIRCUIT_OPEN)
+)
+
+// resilientClient decorates a geo client with an LRU+TTL response cache,
+// bounded jittered retries of transient failures & a circuit breaker.
+// Transient failures surface as ErrGeoUnavailable, geo's own rejections
+// are passed through unchanged.
+type resilientClient struct {
+	geocl.Client
+	opts    geodom.ResilienceOptions
+	cache   *lruCache[*geo_v1.GeoResponse]
+	breaker *circuitBreaker
+}
+
+func NewResilientClient(ctx context.Context, gc geocl.Client, opts geodom.ResilienceOptions) (*resilientClient, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if gc == nil {
+		return nil, ErrMissingGeoClient
+	}
+
+	defaults := geodom.DefaultResilienceOptions()
+	if opts.CacheSize <= 0 {
+		opts.CacheSize = defaults.CacheSize
+	}
+	if opts.CacheTTL <= 0 {
+		opts.CacheTTL = defaults.CacheTTL
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = defaults.RetryBackoff
+	}
+	if opts.BreakerThreshold <= 0 {
+		opts.BreakerThreshold = defaults.BreakerThreshold
+	}
+	if opts.BreakerCooldown <= 0 {
+		opts.BreakerCooldown = defaults.BreakerCooldown
+	}
+
+	l.Info(
+		"initialized resilient geo client",
+		"cache_size", opts.CacheSize,
+		"cache_ttl", opts.CacheTTL.String(),
+		"max_retries", opts.MaxRetries,
+		"breaker_threshold", opts.BreakerThreshold,
+	)
+	return &resilientClient{
+		Client:  gc,
+		opts:    opts,
+		cache:   newLRUCache[*geo_v1.GeoResponse](opts.CacheSize, opts.CacheTTL),
+		breaker: newCircuitBreaker(opts.BreakerThreshold, opts.BreakerCooldown),
+	}, nil
+}
+
+func (rc *resilientClient) GeoLocate(ctx context.Context, in *geo_v1.GeoRequest, opts ...grpc.CallOption) (*geo_v1.GeoResponse, error) {
+	ctx, span := startSpan(ctx, "stores.geo.locate")
+	defer span.End()
+
+	resp, err := rc.call(ctx, span, locateKey(in), func(ctx context.Context) (*geo_v1.GeoResponse, error) {
+		return rc.Client.GeoLocate(ctx, in, opts...)
+	})
+	finishSpan(span, err)
+	return resp, err
+}
+
+func (rc *resilientClient) GetGeoLocation(ctx context.Context, in *geo_v1.GeoLocationRequest, opts ...grpc.CallOption) (*geo_v1.GeoResponse, error) {
+	ctx, span := startSpan(ctx, "stores.geo.location")
+	defer span.End()
+
+	resp, err := rc.call(ctx, span, "location:"+in.GetAddressId(), func(ctx context.Context) (*geo_v1.GeoResponse, error) {
+		return rc.Client.GetGeoLocation(ctx, in, opts...)
+	})
+	finishSpan(span, err)
+	return resp, err
+}
+
+// call serves key from cache or runs fn, retrying transient failures while the breaker allows.
+func (rc *resilientClient) call(
+	ctx context.Context,
+	span trace.Span,
+	key string,
+	fn func(ctx context.Context) (*geo_v1.GeoResponse, error),
+) (*geo_v1.GeoResponse, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if resp, ok := rc.cache.Get(key); ok {
+		span.SetAttributes(attribute.Bool("cache_hit", true))
+		return resp, nil
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= rc.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("%w: %w", ErrGeoUnavailable, ctx.Err())
+			case <-time.After(rc.backoff(attempt)):
+			}
+		}
+
+		if !rc.breaker.Allow() {
+			return nil, fmt.Errorf("%w: %w", ErrGeoUnavailable, ErrCircuitOpen)
+		}
+
+		resp, err := rc.try(ctx, fn)
+		if err == nil {
+			rc.cache.Add(key, resp)
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: %w", ErrGeoUnavailable, ctx.Err())
+		}
+		if !isTransient(err) {
+			return nil, err
+		}
+
+		lastErr = err
+		l.Warn("transient geo error", "error", err.Error(), "attempt", attempt+1, "breaker", rc.breaker.State().String())
+	}
+	return nil, fmt.Errorf("%w: %w", ErrGeoUnavailable, lastErr)
+}
+
+// try runs fn, the breaker having allowed it, & records the outcome on every exit path.
+func (rc *resilientClient) try(
+	ctx context.Context,
+	fn func(ctx context.Context) (*geo_v1.GeoResponse, error),
+) (resp *geo_v1.GeoResponse, err error) {
+	// neutral unless the call completes, so a half-open trial slot is never kept
+	outcome := rc.breaker.Release
+	defer func() { outcome() }()
+
+	resp, err = fn(ctx)
+	switch {
+	case err == nil:
+		outcome = rc.breaker.Success
+	case ctx.Err() != nil:
+		// caller gave up, says nothing about geo's health
+	case !isTransient(err):
+		// geo answered, the request itself was rejected
+		outcome = rc.breaker.Success
+	default:
+		outcome = rc.breaker.Failure
+	}
+	return resp, err
+}
+
+// backoff returns an exponential backoff, jittered between half and full value.
+func (rc *resilientClient) backoff(attempt int) time.Duration {
+	d := rc.opts.RetryBackoff << (attempt - 1)
+	return d/2 + rand.N(d/2+1)
+}
+
+// isTransient reports whether err is a failure to reach or get an answer from geo.
+func isTransient(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return true
+	}
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Internal, codes.Unknown:
+		return true
+	}
+	return false
+}
+
+// locateKey returns the cache key for a geocode request.
+func locateKey(in *geo_v1.GeoRequest) string {
+	k := fmt.Sprintf(
+		"%s|%s|%s|%s|%s|%s|%v|%v",
+		in.GetStreet(), in.GetCity(), in.GetState(), in.GetPostalCode(), in.GetCountry(),
+		strings.ToLower(strings.TrimSpace(in.GetAddressStr())), in.GetLatitude(), in.GetLongitude(),
+	)
+	sum := sha256.Sum256([]byte(k))
+	return "locate:" + hex.EncodeToString(sum[:])
+}
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer("stores-geo").Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func finishSpan(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(otelcodes.Error, err.Error())
+}
@@ -0,0 +1,182 @@
+package geo_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	geo_v1 "github.com/comfforts/comff-geo/api/geo/v1"
+	geocl "github.com/comfforts/comff-geo/clients/go"
+	"github.com/comfforts/logger"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	geoinfra "github.com/comfforts/comff-stores/internal/infra/geo"
+)
+
+func TestResilientClientCachesAndRetries(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+
+	fc := &fakeGeoClient{
+		errs: []error{status.Error(codes.Unavailable, "geo down")},
+	}
+	rc, err := geoinfra.NewResilientClient(ctx, fc, geodom.ResilienceOptions{
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	req := &geo_v1.GeoLocationRequest{AddressId: "dacdbddabcadccbdacac"}
+	resp, err := rc.GetGeoLocation(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, "dacdbddabcadccbdacac", resp.GetPoint().GetHash())
+	require.Equal(t, 2, fc.callCount())
+
+	// served from cache
+	_, err = rc.GetGeoLocation(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, 2, fc.callCount())
+}
+
+func TestResilientClientInvalidAddress(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+
+	fc := &fakeGeoClient{
+		errs: []error{status.Error(codes.InvalidArgument, "invalid address")},
+	}
+	rc, err := geoinfra.NewResilientClient(ctx, fc, geodom.ResilienceOptions{
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	_, err = rc.GetGeoLocation(ctx, &geo_v1.GeoLocationRequest{AddressId: "bad"})
+	require.Error(t, err)
+	require.NotErrorIs(t, err, geoinfra.ErrGeoUnavailable)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, 1, fc.callCount())
+}
+
+func TestResilientClientCircuitBreaker(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+
+	down := status.Error(codes.Unavailable, "geo down")
+	fc := &fakeGeoClient{
+		errs: []error{down, down, down},
+	}
+	rc, err := geoinfra.NewResilientClient(ctx, fc, geodom.ResilienceOptions{
+		MaxRetries:       0,
+		RetryBackoff:     time.Millisecond,
+		BreakerThreshold: 2,
+		BreakerCooldown:  50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = rc.GeoLocate(ctx, &geo_v1.GeoRequest{AddressStr: "92612"})
+		require.ErrorIs(t, err, geoinfra.ErrGeoUnavailable)
+	}
+	require.Equal(t, 2, fc.callCount())
+
+	// open circuit fails fast without calling geo
+	_, err = rc.GeoLocate(ctx, &geo_v1.GeoRequest{AddressStr: "92612"})
+	require.ErrorIs(t, err, geoinfra.ErrGeoUnavailable)
+	require.ErrorIs(t, err, geoinfra.ErrCircuitOpen)
+	require.Equal(t, 2, fc.callCount())
+
+	// after cooldown a failed trial call re-opens the circuit
+	time.Sleep(60 * time.Millisecond)
+	_, err = rc.GeoLocate(ctx, &geo_v1.GeoRequest{AddressStr: "92612"})
+	require.ErrorIs(t, err, geoinfra.ErrGeoUnavailable)
+	require.Equal(t, 3, fc.callCount())
+	_, err = rc.GeoLocate(ctx, &geo_v1.GeoRequest{AddressStr: "92612"})
+	require.ErrorIs(t, err, geoinfra.ErrCircuitOpen)
+
+	// and a successful trial call closes it
+	time.Sleep(60 * time.Millisecond)
+	_, err = rc.GeoLocate(ctx, &geo_v1.GeoRequest{AddressStr: "92612"})
+	require.NoError(t, err)
+	require.Equal(t, 4, fc.callCount())
+}
+
+func TestResilientClientCancelledTrial(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+
+	down := status.Error(codes.Unavailable, "geo down")
+	fc := &fakeGeoClient{
+		errs: []error{down, context.Canceled},
+	}
+	rc, err := geoinfra.NewResilientClient(ctx, fc, geodom.ResilienceOptions{
+		MaxRetries:       0,
+		RetryBackoff:     time.Millisecond,
+		BreakerThreshold: 1,
+		BreakerCooldown:  50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	_, err = rc.GeoLocate(ctx, &geo_v1.GeoRequest{AddressStr: "92612"})
+	require.ErrorIs(t, err, geoinfra.ErrGeoUnavailable)
+
+	// the caller giving up on the trial call leaves the circuit half-open
+	time.Sleep(60 * time.Millisecond)
+	cctx, cancel := context.WithCancel(ctx)
+	fc.onCall = cancel
+	_, err = rc.GeoLocate(cctx, &geo_v1.GeoRequest{AddressStr: "92612"})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 2, fc.callCount())
+
+	// & the next call gets the trial
+	fc.onCall = nil
+	_, err = rc.GeoLocate(ctx, &geo_v1.GeoRequest{AddressStr: "92612"})
+	require.NoError(t, err)
+	require.Equal(t, 3, fc.callCount())
+}
+
+// fakeGeoClient fails with errs in order, then succeeds echoing the address ID.
+type fakeGeoClient struct {
+	geocl.Client
+
+	mu     sync.Mutex
+	errs   []error
+	calls  int
+	onCall func()
+}
+
+func (fc *fakeGeoClient) GeoLocate(ctx context.Context, in *geo_v1.GeoRequest, opts ...grpc.CallOption) (*geo_v1.GeoResponse, error) {
+	return fc.respond("dacdbddabcadccbdacac")
+}
+
+func (fc *fakeGeoClient) GetGeoLocation(ctx context.Context, in *geo_v1.GeoLocationRequest, opts ...grpc.CallOption) (*geo_v1.GeoResponse, error) {
+	return fc.respond(in.GetAddressId())
+}
+
+func (fc *fakeGeoClient) Close(ctx context.Context) error {
+	return nil
+}
+
+func (fc *fakeGeoClient) respond(hash string) (*geo_v1.GeoResponse, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	fc.calls++
+	if fc.onCall != nil {
+		fc.onCall()
+	}
+	if len(fc.errs) > 0 {
+		err := fc.errs[0]
+		fc.errs = fc.errs[1:]
+		return nil, err
+	}
+	return &geo_v1.GeoResponse{Point: &geo_v1.Point{Hash: hash}}, nil
+}
+
+func (fc *fakeGeoClient) callCount() int {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.calls
+}
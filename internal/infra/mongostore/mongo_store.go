@@ -144,6 +144,22 @@ func (ms *MongoStore) EnsureIndexes(
 	return nil
 }
 
+// WithTransaction runs fn inside a multi-document transaction.
+// The context passed to fn carries the session & must be used for all operations
+// that are part of the transaction. Requires a replica set deployment.
+func (ms *MongoStore) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	sess, err := ms.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(ctx)
+
+	_, err = sess.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (any, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
 func (ms *MongoStore) AddCollectionDoc(
 	ctx context.Context,
 	collectionName string,
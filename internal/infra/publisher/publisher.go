@@ -0,0 +1,130 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	evdom "github.com/comfforts/comff-stores/internal/domain/events"
+)
+
+const (
+	ERR_MISSING_EVENT     = "missing event"
+	ERR_MISSING_FILE_PATH = "missing publisher file path"
+	ERR_PUBLISHER_CLOSED  = "publisher closed"
+)
+
+var (
+	ErrMissingEvent    = errors.New(ERR_MISSING_EVENT)
+	ErrMissingFilePath = errors.New(ERR_MISSING_FILE_PATH)
+	ErrPublisherClosed = errors.New(ERR_PUBLISHER_CLOSED)
+)
+
+var _ evdom.EventPublisher = (*writerPublisher)(nil)
+
+// writerPublisher writes events as JSON lines to an io.Writer.
+// Meant for local development & debugging, not for production delivery.
+type writerPublisher struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	closed bool
+}
+
+// NewStdoutPublisher returns a publisher writing events to stdout.
+func NewStdoutPublisher() *writerPublisher {
+	return &writerPublisher{
+		w: os.Stdout,
+	}
+}
+
+// NewFilePublisher returns a publisher appending events to the file at path,
+// creating it if needed.
+func NewFilePublisher(path string) (*writerPublisher, error) {
+	if path == "" {
+		return nil, ErrMissingFilePath
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &writerPublisher{
+		w:      f,
+		closer: f,
+	}, nil
+}
+
+func (p *writerPublisher) Publish(ctx context.Context, ev *evdom.Event) error {
+	if ev == nil {
+		return ErrMissingEvent
+	}
+
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrPublisherClosed
+	}
+	_, err = p.w.Write(b)
+	return err
+}
+
+func (p *writerPublisher) Close(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	if p.closer != nil {
+		return p.closer.Close()
+	}
+	return nil
+}
+
+var _ evdom.EventPublisher = (multiPublisher)(nil)
+
+// multiPublisher publishes each event to all of its publishers. A failure of
+// any publisher fails the publish, so the relay retries the event for all of
+// them; publishers are expected to tolerate duplicates.
+type multiPublisher []evdom.EventPublisher
+
+// NewMultiPublisher returns a publisher fanning events out to the given publishers.
+func NewMultiPublisher(pubs ...evdom.EventPublisher) multiPublisher {
+	return multiPublisher(pubs)
+}
+
+func (mp multiPublisher) Publish(ctx context.Context, ev *evdom.Event) error {
+	if ev == nil {
+		return ErrMissingEvent
+	}
+
+	var errs []error
+	for _, p := range mp {
+		if err := p.Publish(ctx, ev); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (mp multiPublisher) Close(ctx context.Context) error {
+	var errs []error
+	for _, p := range mp {
+		if err := p.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,76 @@
+package publisher_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	evdom "github.com/comfforts/comff-stores/internal/domain/events"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+	"github.com/comfforts/comff-stores/internal/infra/publisher"
+)
+
+func TestFilePublisher(t *testing.T) {
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	pub, err := publisher.NewFilePublisher(path)
+	require.NoError(t, err)
+
+	evs := []*evdom.Event{
+		{
+			ID:      "ev-1",
+			Type:    evdom.STORE_ADDED,
+			StoreID: "st-1",
+			Org:     "Test Org",
+			Store: &stdom.Store{
+				ID:        "st-1",
+				Name:      "Test Store",
+				Org:       "Test Org",
+				AddressId: "dacdbddabcadccbdacac",
+			},
+			OccurredAt: time.Now().UTC(),
+		},
+		{
+			ID:         "ev-2",
+			Type:       evdom.STORE_DELETED,
+			StoreID:    "st-1",
+			Org:        "Test Org",
+			OccurredAt: time.Now().UTC(),
+		},
+	}
+	for _, ev := range evs {
+		require.NoError(t, pub.Publish(ctx, ev))
+	}
+	require.NoError(t, pub.Close(ctx))
+	require.ErrorIs(t, pub.Publish(ctx, evs[0]), publisher.ErrPublisherClosed)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	got := []*evdom.Event{}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var ev evdom.Event
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &ev))
+		got = append(got, &ev)
+	}
+	require.NoError(t, sc.Err())
+	require.Len(t, got, 2)
+	require.Equal(t, "ev-1", got[0].ID)
+	require.Equal(t, "Test Store", got[0].Store.Name)
+	require.Equal(t, evdom.STORE_DELETED, got[1].Type)
+	require.Nil(t, got[1].Store)
+}
+
+func TestFilePublisherMissingPath(t *testing.T) {
+	_, err := publisher.NewFilePublisher("")
+	require.ErrorIs(t, err, publisher.ErrMissingFilePath)
+}
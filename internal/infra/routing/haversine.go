@@ -0,0 +1,52 @@
+package routing
+
+import (
+	"context"
+	"time"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+)
+
+// roads wind, DEFAULT_CIRCUITY is the typical ratio of road to straight line distance
+const (
+	DEFAULT_CIRCUITY  = 1.3
+	DEFAULT_SPEED_KPH = 40
+)
+
+var _ geodom.RoutingProvider = (*haversineRouter)(nil)
+
+// HaversineOptions tune the haversine router's estimates, zero values use the defaults.
+type HaversineOptions struct {
+	Circuity float64
+	SpeedKph float64
+}
+
+// haversineRouter estimates routes from great circle distances, scaled by the circuity
+// & driven at an average speed. Every destination can be reached.
+type haversineRouter struct {
+	circuity float64
+	speedKph float64
+}
+
+func NewHaversineRouter(opts HaversineOptions) *haversineRouter {
+	hr := &haversineRouter{circuity: opts.Circuity, speedKph: opts.SpeedKph}
+	if hr.circuity <= 0 {
+		hr.circuity = DEFAULT_CIRCUITY
+	}
+	if hr.speedKph <= 0 {
+		hr.speedKph = DEFAULT_SPEED_KPH
+	}
+	return hr
+}
+
+func (hr *haversineRouter) Routes(ctx context.Context, origin *geodom.Location, destinations []*geodom.Location) ([]*geodom.Route, error) {
+	routes := make([]*geodom.Route, 0, len(destinations))
+	for _, dest := range destinations {
+		meters := geodom.DistanceMeters(origin.Latitude, origin.Longitude, dest.Latitude, dest.Longitude) * hr.circuity
+		routes = append(routes, &geodom.Route{
+			DistanceMeters: meters,
+			Duration:       time.Duration(meters / (hr.speedKph * 1000) * float64(time.Hour)),
+		})
+	}
+	return routes, nil
+}
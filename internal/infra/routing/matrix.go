@@ -0,0 +1,89 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/comfforts/logger"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+)
+
+var _ geodom.RoutingProvider = (*matrixRouter)(nil)
+
+// MatrixEntry is a known route in the routing matrix file, between the cells of the
+// origin & destination address ID prefixes.
+type MatrixEntry struct {
+	Origin          string  `json:"origin"`
+	Destination     string  `json:"destination"`
+	DistanceMeters  float64 `json:"distance_meters"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// matrixRouter is a RoutingProvider for local development & tests, answering from a
+// fixed matrix of routes. A pair of locations takes the route of the entry with the
+// longest matching prefixes, pairs no entry matches can't be reached.
+type matrixRouter struct {
+	entries []*MatrixEntry
+}
+
+// NewMatrixRouter returns a matrix router loading its entries from the JSON file at path.
+func NewMatrixRouter(ctx context.Context, path string) (*matrixRouter, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		l.Error("error reading routing matrix", "error", err.Error(), "path", path)
+		return nil, err
+	}
+	var entries []*MatrixEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		l.Error("error decoding routing matrix", "error", err.Error(), "path", path)
+		return nil, err
+	}
+	return NewMatrixRouterFromEntries(ctx, entries)
+}
+
+// NewMatrixRouterFromEntries returns a matrix router answering from the given entries.
+func NewMatrixRouterFromEntries(ctx context.Context, entries []*MatrixEntry) (*matrixRouter, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	l.Info("initialized matrix router", "routes", len(entries))
+	return &matrixRouter{entries: entries}, nil
+}
+
+func (mr *matrixRouter) Routes(ctx context.Context, origin *geodom.Location, destinations []*geodom.Location) ([]*geodom.Route, error) {
+	routes := make([]*geodom.Route, 0, len(destinations))
+	for _, dest := range destinations {
+		routes = append(routes, mr.route(origin.AddressId, dest.AddressId))
+	}
+	return routes, nil
+}
+
+func (mr *matrixRouter) route(origin, destination string) *geodom.Route {
+	var best *MatrixEntry
+	for _, e := range mr.entries {
+		if !strings.HasPrefix(origin, e.Origin) || !strings.HasPrefix(destination, e.Destination) {
+			continue
+		}
+		if best == nil || len(e.Origin)+len(e.Destination) > len(best.Origin)+len(best.Destination) {
+			best = e
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return &geodom.Route{
+		DistanceMeters: best.DistanceMeters,
+		Duration:       time.Duration(best.DurationSeconds * float64(time.Second)),
+	}
+}
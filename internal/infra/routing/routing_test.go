@@ -0,0 +1,75 @@
+package routing_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/comfforts/logger"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	"github.com/comfforts/comff-stores/internal/infra/routing"
+)
+
+func location(lat, lon float64) *geodom.Location {
+	return &geodom.Location{
+		AddressId: geodom.EncodeAddressId(lat, lon, geodom.DEFAULT_ADDRESS_ID_PRECISION),
+		Latitude:  lat,
+		Longitude: lon,
+	}
+}
+
+func TestHaversineRouter(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+
+	fairSt, sf := location(38.227476, -122.6461669), location(37.7749, -122.4194)
+	straight := geodom.DistanceMeters(fairSt.Latitude, fairSt.Longitude, sf.Latitude, sf.Longitude)
+
+	routes, err := routing.NewHaversineRouter(routing.HaversineOptions{}).Routes(ctx, fairSt, []*geodom.Location{sf, fairSt})
+	require.NoError(t, err)
+	require.Len(t, routes, 2)
+	require.InDelta(t, straight*routing.DEFAULT_CIRCUITY, routes[0].DistanceMeters, 1e-6)
+	// about 70km at 40km/h
+	require.InDelta(t, (106 * time.Minute).Seconds(), routes[0].Duration.Seconds(), 60)
+	require.Zero(t, routes[1].DistanceMeters)
+
+	routes, err = routing.NewHaversineRouter(routing.HaversineOptions{Circuity: 1, SpeedKph: 100}).Routes(ctx, fairSt, []*geodom.Location{sf})
+	require.NoError(t, err)
+	require.InDelta(t, straight, routes[0].DistanceMeters, 1e-6)
+	require.InDelta(t, straight/100000*3600, routes[0].Duration.Seconds(), 1e-3)
+}
+
+func TestMatrixRouter(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+
+	fairSt, turquoise, sf := location(38.227476, -122.6461669), location(38.22507858276367, -122.61660766601562), location(37.7749, -122.4194)
+	entries := []*routing.MatrixEntry{
+		// anywhere around Petaluma to the city
+		{Origin: fairSt.AddressId[:8], Destination: sf.AddressId[:8], DistanceMeters: 62000, DurationSeconds: 3000},
+		// across the river
+		{Origin: fairSt.AddressId[:8], Destination: turquoise.AddressId[:8], DistanceMeters: 9000, DurationSeconds: 900},
+		{Origin: fairSt.AddressId, Destination: turquoise.AddressId, DistanceMeters: 7500, DurationSeconds: 720},
+	}
+	path := filepath.Join(t.TempDir(), "matrix.json")
+	data, err := json.Marshal(entries)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	mr, err := routing.NewMatrixRouter(ctx, path)
+	require.NoError(t, err)
+	routes, err := mr.Routes(ctx, fairSt, []*geodom.Location{sf, turquoise, location(40.7128, -74.006)})
+	require.NoError(t, err)
+	require.Equal(t, &geodom.Route{DistanceMeters: 62000, Duration: 50 * time.Minute}, routes[0])
+	// the longest matching prefixes win
+	require.Equal(t, &geodom.Route{DistanceMeters: 7500, Duration: 12 * time.Minute}, routes[1])
+	// no route to New York
+	require.Nil(t, routes[2])
+
+	_, err = routing.NewMatrixRouter(ctx, filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
@@ -0,0 +1,49 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/comfforts/logger"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+const ERR_INVALID_RULES = "invalid store validation rules"
+
+var ErrInvalidRules = errors.New(ERR_INVALID_RULES)
+
+// LoadRules returns the compiled store validation rules from the JSON file at path,
+// or no rules when empty, only the store's name, org & address ID being required.
+func LoadRules(ctx context.Context, path string) (*stdom.ValidationRules, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if path == "" {
+		l.Info("no store validation rules configured")
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		l.Error("error reading store validation rules", "error", err.Error(), "path", path)
+		return nil, err
+	}
+
+	var rules stdom.ValidationRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		l.Error("error decoding store validation rules", "error", err.Error(), "path", path)
+		return nil, err
+	}
+	if err := rules.Compile(); err != nil {
+		l.Error("error compiling store validation rules", "error", err.Error(), "path", path)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRules, err)
+	}
+
+	l.Info("loaded store validation rules", "version", rules.Version, "orgs", len(rules.Orgs), "path", path)
+	return &rules, nil
+}
@@ -0,0 +1,64 @@
+package validation_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/comfforts/logger"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+	"github.com/comfforts/comff-stores/internal/infra/validation"
+)
+
+func TestLoadRules(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+
+	rules, err := validation.LoadRules(ctx, "")
+	require.NoError(t, err)
+	require.Nil(t, rules)
+	require.Empty(t, rules.Validate(&stdom.Store{Name: strings.Repeat("x", 101), Org: "Test Org", AddressId: "dacdbddabcadccbdacac"}))
+
+	// the example rules
+	rules, err = validation.LoadRules(ctx, "rules.json")
+	require.NoError(t, err)
+	require.Equal(t, 1, rules.Version)
+	for _, name := range []string{"Corner Bakery", "Panadería de la Esquina", "Joe's Bar & Grill", "7-Eleven #42", "Café (Downtown)"} {
+		require.Empty(t, rules.Validate(&stdom.Store{Name: name, Org: "Test Org", AddressId: "dacdbddabcadccbdacac"}), name)
+	}
+	for _, name := range []string{strings.Repeat("x", 101), "Bakery <script>"} {
+		require.NotEmpty(t, rules.Validate(&stdom.Store{Name: name, Org: "Test Org", AddressId: "dacdbddabcadccbdacac"}), name)
+	}
+
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+		return path
+	}
+
+	rules, err = validation.LoadRules(ctx, write("acme.json", `{"version":2,"reserved_words":["admin"],"orgs":[
+		{"org":"Acme","name_pattern":"^Acme ","required":["description","region"]}
+	]}`))
+	require.NoError(t, err)
+	require.Equal(t, 2, rules.Version)
+	require.Len(t, rules.Validate(&stdom.Store{Name: "Admin", Org: "Acme", AddressId: "dacdbddabcadccbdacac"}), 4)
+
+	for name, data := range map[string]string{
+		"unversioned":  `{"name":{"max_length":100}}`,
+		"bad pattern":  `{"version":1,"orgs":[{"org":"Acme","name_pattern":"("}]}`,
+		"bad required": `{"version":1,"orgs":[{"org":"Acme","required":["phone"]}]}`,
+	} {
+		_, err := validation.LoadRules(ctx, write(name+".json", data))
+		require.ErrorIs(t, err, validation.ErrInvalidRules, name)
+	}
+
+	_, err = validation.LoadRules(ctx, write("malformed.json", `{"version":`))
+	require.Error(t, err)
+	_, err = validation.LoadRules(ctx, filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+}
@@ -0,0 +1,196 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/comfforts/logger"
+
+	iddom "github.com/comfforts/comff-stores/internal/domain/idempotency"
+	indom "github.com/comfforts/comff-stores/internal/domain/infra"
+)
+
+const IDEMPOTENCY_COLLECTION = "stores.idempotency_keys"
+
+const (
+	ERR_MISSING_REQUIRED = "missing required parameters"
+	ERR_NO_RECORD        = "no idempotency record found"
+)
+
+var (
+	ErrMissingRequired = errors.New(ERR_MISSING_REQUIRED)
+	ErrNoRecord        = errors.New(ERR_NO_RECORD)
+)
+
+type idempotencyRepo struct {
+	indom.DBStore
+}
+
+func NewIdempotencyRepo(ctx context.Context, rc indom.DBStore) (*idempotencyRepo, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// records are removed by mongo once expired
+	if err = rc.EnsureIndexes(ctx, IDEMPOTENCY_COLLECTION, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "expires_at", Value: 1},
+			},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}); err != nil {
+		l.Error("error adding idempotency indexes", "error", err.Error())
+		return nil, err
+	}
+
+	l.Info("initialized idempotency repo")
+	return &idempotencyRepo{
+		DBStore: rc,
+	}, nil
+}
+
+func (ir *idempotencyRepo) Reserve(ctx context.Context, key, requestHash string, lock time.Duration) (*iddom.Record, bool, error) {
+	ctx, span := startSpan(ctx, "stores.idempotency.reserve")
+	defer span.End()
+
+	if key == "" || requestHash == "" {
+		finishSpan(span, ErrMissingRequired)
+		return nil, false, ErrMissingRequired
+	}
+
+	coll := ir.Store().Collection(IDEMPOTENCY_COLLECTION)
+	now := time.Now().UTC()
+
+	// the TTL monitor runs periodically, so expired records may linger;
+	// replace them in place, only when still expired
+	filter := bson.M{
+		"_id":        key,
+		"expires_at": bson.M{"$lte": now},
+	}
+	rec := &iddom.Record{
+		Key:         key,
+		RequestHash: requestHash,
+		Status:      iddom.RECORD_IN_PROGRESS,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(lock),
+	}
+	_, err := coll.ReplaceOne(ctx, filter, rec, options.Replace().SetUpsert(true))
+	if err == nil {
+		return rec, true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		finishSpan(span, err)
+		return nil, false, err
+	}
+
+	// key held by an unexpired record
+	var existing iddom.Record
+	if err := coll.FindOne(ctx, bson.M{"_id": key}).Decode(&existing); err != nil {
+		if err == mongo.ErrNoDocuments {
+			finishSpan(span, ErrNoRecord)
+			return nil, false, ErrNoRecord
+		}
+		finishSpan(span, err)
+		return nil, false, err
+	}
+	return &existing, false, nil
+}
+
+func (ir *idempotencyRepo) Extend(ctx context.Context, key, requestHash string, lock time.Duration) error {
+	ctx, span := startSpan(ctx, "stores.idempotency.extend")
+	defer span.End()
+
+	if key == "" || requestHash == "" {
+		finishSpan(span, ErrMissingRequired)
+		return ErrMissingRequired
+	}
+
+	res, err := ir.Store().Collection(IDEMPOTENCY_COLLECTION).UpdateOne(ctx, bson.M{
+		"_id":          key,
+		"request_hash": requestHash,
+		"status":       iddom.RECORD_IN_PROGRESS,
+	}, bson.M{
+		"$set": bson.M{"expires_at": time.Now().UTC().Add(lock)},
+	})
+	if err != nil {
+		finishSpan(span, err)
+		return err
+	}
+	if res.MatchedCount == 0 {
+		finishSpan(span, ErrNoRecord)
+		return ErrNoRecord
+	}
+	return nil
+}
+
+func (ir *idempotencyRepo) Complete(ctx context.Context, key, requestHash string, response []byte, ttl time.Duration) error {
+	ctx, span := startSpan(ctx, "stores.idempotency.complete")
+	defer span.End()
+
+	if key == "" || requestHash == "" {
+		finishSpan(span, ErrMissingRequired)
+		return ErrMissingRequired
+	}
+
+	res, err := ir.Store().Collection(IDEMPOTENCY_COLLECTION).UpdateOne(ctx, bson.M{
+		"_id":          key,
+		"request_hash": requestHash,
+		"status":       iddom.RECORD_IN_PROGRESS,
+	}, bson.M{
+		"$set": bson.M{
+			"status":     iddom.RECORD_COMPLETED,
+			"response":   response,
+			"expires_at": time.Now().UTC().Add(ttl),
+		},
+	})
+	if err != nil {
+		finishSpan(span, err)
+		return err
+	}
+	if res.MatchedCount == 0 {
+		finishSpan(span, ErrNoRecord)
+		return ErrNoRecord
+	}
+	return nil
+}
+
+func (ir *idempotencyRepo) Release(ctx context.Context, key, requestHash string) error {
+	ctx, span := startSpan(ctx, "stores.idempotency.release")
+	defer span.End()
+
+	if key == "" || requestHash == "" {
+		finishSpan(span, ErrMissingRequired)
+		return ErrMissingRequired
+	}
+
+	_, err := ir.Store().Collection(IDEMPOTENCY_COLLECTION).DeleteOne(ctx, bson.M{
+		"_id":          key,
+		"request_hash": requestHash,
+		"status":       iddom.RECORD_IN_PROGRESS,
+	})
+	finishSpan(span, err)
+	return err
+}
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer("stores-idempotency").Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func finishSpan(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(otelcodes.Error, err.Error())
+}
@@ -0,0 +1,104 @@
+package idempotency_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/comfforts/logger"
+
+	iddom "github.com/comfforts/comff-stores/internal/domain/idempotency"
+	"github.com/comfforts/comff-stores/internal/infra/mongostore"
+	idrepo "github.com/comfforts/comff-stores/internal/repo/idempotency"
+	envutils "github.com/comfforts/comff-stores/pkg/utils/environ"
+)
+
+func TestIdempotencyRepo(t *testing.T) {
+	// Initialize logger
+	l := logger.GetSlogLogger()
+	l.Debug("TestIdempotencyRepo Logger initialized")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	nmCfg := envutils.BuildMongoStoreConfig(true)
+	cl, err := mongostore.NewMongoStore(ctx, nmCfg)
+	require.NoError(t, err)
+
+	ir, err := idrepo.NewIdempotencyRepo(ctx, cl)
+	require.NoError(t, err)
+
+	defer func() {
+		err := ir.Close(ctx)
+		require.NoError(t, err)
+	}()
+
+	key := fmt.Sprintf("test|AddStore|%d", time.Now().UnixNano())
+	defer func() {
+		// completed records aren't released, remove them
+		_, err := ir.Store().Collection(idrepo.IDEMPOTENCY_COLLECTION).DeleteOne(ctx, bson.M{"_id": key})
+		require.NoError(t, err)
+	}()
+
+	rec, reserved, err := ir.Reserve(ctx, key, "hash-1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.Equal(t, iddom.RECORD_IN_PROGRESS, rec.Status)
+
+	// the key is held while in progress
+	rec, reserved, err = ir.Reserve(ctx, key, "hash-1", time.Minute)
+	require.NoError(t, err)
+	require.False(t, reserved)
+	require.Equal(t, iddom.RECORD_IN_PROGRESS, rec.Status)
+
+	// the lock is only extended for the request holding it
+	require.NoError(t, ir.Extend(ctx, key, "hash-1", time.Minute))
+	require.ErrorIs(t, ir.Extend(ctx, key, "hash-2", time.Minute), idrepo.ErrNoRecord)
+
+	// only the request holding the key completes it
+	require.ErrorIs(t, ir.Complete(ctx, key, "hash-2", []byte("other"), time.Hour), idrepo.ErrNoRecord)
+
+	// completed responses are replayed & no longer completed, released or extended
+	require.NoError(t, ir.Complete(ctx, key, "hash-1", []byte("response"), time.Hour))
+	require.ErrorIs(t, ir.Complete(ctx, key, "hash-1", []byte("other"), time.Hour), idrepo.ErrNoRecord)
+	require.NoError(t, ir.Release(ctx, key, "hash-1"))
+	require.ErrorIs(t, ir.Extend(ctx, key, "hash-1", time.Minute), idrepo.ErrNoRecord)
+	rec, reserved, err = ir.Reserve(ctx, key, "hash-1", time.Minute)
+	require.NoError(t, err)
+	require.False(t, reserved)
+	require.Equal(t, iddom.RECORD_COMPLETED, rec.Status)
+	require.Equal(t, []byte("response"), rec.Response)
+
+	// released keys can be reserved again
+	relKey := key + "|released"
+	_, reserved, err = ir.Reserve(ctx, relKey, "hash-1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.NoError(t, ir.Release(ctx, relKey, "hash-1"))
+	_, reserved, err = ir.Reserve(ctx, relKey, "hash-1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.NoError(t, ir.Release(ctx, relKey, "hash-1"))
+
+	// an expired lock, not yet removed by mongo, is taken over
+	expKey := key + "|expired"
+	_, reserved, err = ir.Reserve(ctx, expKey, "hash-1", time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, reserved)
+	time.Sleep(10 * time.Millisecond)
+	rec, reserved, err = ir.Reserve(ctx, expKey, "hash-2", time.Minute)
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.Equal(t, "hash-2", rec.RequestHash)
+	// the expired holder can't complete or release the new reservation
+	require.ErrorIs(t, ir.Extend(ctx, expKey, "hash-1", time.Minute), idrepo.ErrNoRecord)
+	require.ErrorIs(t, ir.Complete(ctx, expKey, "hash-1", []byte("response"), time.Hour), idrepo.ErrNoRecord)
+	require.NoError(t, ir.Release(ctx, expKey, "hash-1"))
+	require.NoError(t, ir.Extend(ctx, expKey, "hash-2", time.Minute))
+	require.NoError(t, ir.Release(ctx, expKey, "hash-2"))
+}
@@ -0,0 +1,409 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/comfforts/logger"
+
+	indom "github.com/comfforts/comff-stores/internal/domain/infra"
+	migdom "github.com/comfforts/comff-stores/internal/domain/migrations"
+)
+
+const (
+	MIGRATIONS_COLLECTION      = "stores.migrations"
+	MIGRATIONS_LOCK_COLLECTION = "stores.migrations_lock"
+)
+
+// the lock is a single document, held by the replica that inserted it until it expires
+const MIGRATIONS_LOCK_ID = "migrations"
+
+const (
+	DEFAULT_LOCK_LEASE = time.Minute
+	DEFAULT_LOCK_WAIT  = 5 * time.Minute
+	LOCK_POLL_INTERVAL = time.Second
+)
+
+const (
+	ERR_INVALID_MIGRATIONS = "invalid migrations"
+	ERR_LOCK_TIMEOUT       = "timed out waiting for migrations lock"
+	ERR_IRREVERSIBLE       = "migration can't be reverted"
+	ERR_UNKNOWN_MIGRATION  = "applied migration unknown to this build"
+	ERR_LOCK_LOST          = "migrations lock lost"
+	ERR_INVALID_STEPS      = "steps to revert must be at least 1"
+)
+
+var (
+	ErrInvalidMigrations = errors.New(ERR_INVALID_MIGRATIONS)
+	ErrLockTimeout       = errors.New(ERR_LOCK_TIMEOUT)
+	ErrIrreversible      = errors.New(ERR_IRREVERSIBLE)
+	ErrUnknownMigration  = errors.New(ERR_UNKNOWN_MIGRATION)
+	ErrLockLost          = errors.New(ERR_LOCK_LOST)
+	ErrInvalidSteps      = errors.New(ERR_INVALID_STEPS)
+)
+
+// MigratorOptions configure the migrations lock, zero values use the defaults.
+type MigratorOptions struct {
+	// Owner identifies the lock holder, defaults to host name & pid.
+	Owner string
+	// LockLease is how long the lock is held without renewal, it's renewed while migrating.
+	LockLease time.Duration
+	// LockWait is how long to wait for another replica's lock.
+	LockWait time.Duration
+}
+
+type migrator struct {
+	indom.DBStore
+	migrations []migdom.Migration
+	owner      string
+	lease      time.Duration
+	wait       time.Duration
+}
+
+// NewMigrator returns a migrator recording applied migrations in stores.migrations.
+// Migrations must have unique positive versions & an Up function.
+func NewMigrator(ctx context.Context, rc indom.DBStore, migrations []migdom.Migration, opts MigratorOptions) (*migrator, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	sorted := append([]migdom.Migration{}, migrations...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	for i, m := range sorted {
+		if m.Version <= 0 || m.Up == nil || (i > 0 && sorted[i-1].Version == m.Version) {
+			l.Error("error initializing migrator", "error", ERR_INVALID_MIGRATIONS, "version", m.Version, "name", m.Name)
+			return nil, fmt.Errorf("%w: version %d %q", ErrInvalidMigrations, m.Version, m.Name)
+		}
+	}
+
+	if opts.Owner == "" {
+		host, _ := os.Hostname()
+		opts.Owner = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if opts.LockLease <= 0 {
+		opts.LockLease = DEFAULT_LOCK_LEASE
+	}
+	if opts.LockWait <= 0 {
+		opts.LockWait = DEFAULT_LOCK_WAIT
+	}
+
+	l.Info("initialized migrator", "migrations", len(sorted), "owner", opts.Owner)
+	return &migrator{
+		DBStore:    rc,
+		migrations: sorted,
+		owner:      opts.Owner,
+		lease:      opts.LockLease,
+		wait:       opts.LockWait,
+	}, nil
+}
+
+func (m *migrator) Status(ctx context.Context) ([]*migdom.Status, error) {
+	ctx, span := startSpan(ctx, "stores.migrations.status")
+	defer span.End()
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	statuses := []*migdom.Status{}
+	for _, mg := range m.migrations {
+		st := &migdom.Status{
+			Version: mg.Version,
+			Name:    mg.Name,
+		}
+		if rec, ok := applied[mg.Version]; ok {
+			st.Applied = true
+			st.AppliedAt = rec.AppliedAt
+			delete(applied, mg.Version)
+		}
+		statuses = append(statuses, st)
+	}
+	for _, rec := range applied {
+		statuses = append(statuses, &migdom.Status{
+			Version:   rec.Version,
+			Name:      rec.Name,
+			Applied:   true,
+			AppliedAt: rec.AppliedAt,
+			Unknown:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+func (m *migrator) Up(ctx context.Context, to int) ([]int, error) {
+	ctx, span := startSpan(ctx, "stores.migrations.up", attribute.Int("to", to))
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	versions := []int{}
+	err = m.withLock(ctx, func(ctx context.Context) error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+
+		coll := m.Store().Collection(MIGRATIONS_COLLECTION)
+		for _, mg := range m.migrations {
+			if to > 0 && mg.Version > to {
+				break
+			}
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+
+			l.Info("applying migration", "version", mg.Version, "name", mg.Name)
+			if err := mg.Up(ctx, m.DBStore); err != nil {
+				l.Error("error applying migration", "version", mg.Version, "name", mg.Name, "error", err.Error())
+				return fmt.Errorf("migration %d %q: %w", mg.Version, mg.Name, err)
+			}
+			// Up isn't atomic with its record, an Up interrupted before it's recorded is
+			// re-run, so migrations must be idempotent (see migdom.Migration)
+			if _, err := coll.ReplaceOne(ctx, bson.M{"_id": mg.Version}, &migdom.Record{
+				Version:   mg.Version,
+				Name:      mg.Name,
+				AppliedAt: time.Now().UTC(),
+			}, options.Replace().SetUpsert(true)); err != nil {
+				return err
+			}
+			versions = append(versions, mg.Version)
+		}
+		return nil
+	})
+	if err != nil {
+		finishSpan(span, err)
+		return versions, err
+	}
+	return versions, nil
+}
+
+func (m *migrator) Down(ctx context.Context, steps int) ([]int, error) {
+	ctx, span := startSpan(ctx, "stores.migrations.down", attribute.Int("steps", steps))
+	defer span.End()
+
+	if steps < 1 {
+		finishSpan(span, ErrInvalidSteps)
+		return nil, ErrInvalidSteps
+	}
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	known := map[int]migdom.Migration{}
+	for _, mg := range m.migrations {
+		known[mg.Version] = mg
+	}
+
+	versions := []int{}
+	err = m.withLock(ctx, func(ctx context.Context) error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+		latest := make([]int, 0, len(applied))
+		for v := range applied {
+			latest = append(latest, v)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(latest)))
+		if steps < len(latest) {
+			latest = latest[:steps]
+		}
+
+		coll := m.Store().Collection(MIGRATIONS_COLLECTION)
+		for _, v := range latest {
+			mg, ok := known[v]
+			if !ok {
+				return fmt.Errorf("%w: version %d %q", ErrUnknownMigration, v, applied[v].Name)
+			}
+			if mg.Down == nil {
+				return fmt.Errorf("%w: version %d %q", ErrIrreversible, v, mg.Name)
+			}
+
+			l.Info("reverting migration", "version", mg.Version, "name", mg.Name)
+			if err := mg.Down(ctx, m.DBStore); err != nil {
+				l.Error("error reverting migration", "version", mg.Version, "name", mg.Name, "error", err.Error())
+				return fmt.Errorf("migration %d %q: %w", mg.Version, mg.Name, err)
+			}
+			if _, err := coll.DeleteOne(ctx, bson.M{"_id": v}); err != nil {
+				return err
+			}
+			versions = append(versions, v)
+		}
+		return nil
+	})
+	if err != nil {
+		finishSpan(span, err)
+		return versions, err
+	}
+	return versions, nil
+}
+
+// applied returns the applied migration records by version.
+func (m *migrator) applied(ctx context.Context) (map[int]*migdom.Record, error) {
+	cur, err := m.Store().Collection(MIGRATIONS_COLLECTION).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var recs []*migdom.Record
+	if err := cur.All(ctx, &recs); err != nil {
+		return nil, err
+	}
+	applied := make(map[int]*migdom.Record, len(recs))
+	for _, rec := range recs {
+		applied[rec.Version] = rec
+	}
+	return applied, nil
+}
+
+// withLock runs fn holding the migrations lock, renewing its lease until fn returns.
+// When a renewal fails fn's context is cancelled, another replica may take the lock
+// once the lease expires, and withLock returns ErrLockLost.
+func (m *migrator) withLock(ctx context.Context, fn func(ctx context.Context) error) error {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if err := m.acquire(ctx); err != nil {
+		l.Error("error acquiring migrations lock", "owner", m.owner, "error", err.Error())
+		return err
+	}
+
+	fnCtx, lost := context.WithCancelCause(ctx)
+	defer lost(nil)
+	renewCtx, stopRenew := context.WithCancel(ctx)
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		ticker := time.NewTicker(m.lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-renewCtx.Done():
+				return
+			case <-ticker.C:
+				if err := m.renew(renewCtx); err != nil && renewCtx.Err() == nil {
+					l.Error("error renewing migrations lock, stopping migrations", "owner", m.owner, "error", err.Error())
+					lost(fmt.Errorf("%w: %w", ErrLockLost, err))
+					return
+				}
+			}
+		}
+	}()
+
+	err = fn(fnCtx)
+
+	stopRenew()
+	<-renewed
+	if cause := context.Cause(fnCtx); errors.Is(cause, ErrLockLost) {
+		err = cause
+	}
+	if rErr := m.release(context.WithoutCancel(ctx)); rErr != nil {
+		l.Error("error releasing migrations lock", "owner", m.owner, "error", rErr.Error())
+	}
+	return err
+}
+
+// acquire polls for the migrations lock until it's free or expired, for up to the lock wait.
+func (m *migrator) acquire(ctx context.Context) error {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	coll := m.Store().Collection(MIGRATIONS_LOCK_COLLECTION)
+	deadline := time.Now().Add(m.wait)
+	for {
+		now := time.Now().UTC()
+		// matches only an expired lock, a held one fails the upsert with a duplicate key
+		_, err := coll.UpdateOne(
+			ctx,
+			bson.M{"_id": MIGRATIONS_LOCK_ID, "locked_until": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{
+				"owner":        m.owner,
+				"locked_at":    now,
+				"locked_until": now.Add(m.lease),
+			}},
+			options.Update().SetUpsert(true),
+		)
+		if err == nil {
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+
+		if time.Now().After(deadline) {
+			return ErrLockTimeout
+		}
+		l.Info("waiting for migrations lock", "owner", m.owner)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(LOCK_POLL_INTERVAL):
+		}
+	}
+}
+
+// renew extends the lease, failing with ErrLockLost once another replica holds the lock.
+func (m *migrator) renew(ctx context.Context) error {
+	res, err := m.Store().Collection(MIGRATIONS_LOCK_COLLECTION).UpdateOne(
+		ctx,
+		bson.M{"_id": MIGRATIONS_LOCK_ID, "owner": m.owner},
+		bson.M{"$set": bson.M{"locked_until": time.Now().UTC().Add(m.lease)}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+func (m *migrator) release(ctx context.Context) error {
+	_, err := m.Store().Collection(MIGRATIONS_LOCK_COLLECTION).DeleteOne(
+		ctx,
+		bson.M{"_id": MIGRATIONS_LOCK_ID, "owner": m.owner},
+	)
+	return err
+}
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer("stores-migrations").Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func finishSpan(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(otelcodes.Error, err.Error())
+}
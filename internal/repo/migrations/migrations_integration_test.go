@@ -0,0 +1,171 @@
+package migrations_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/comfforts/logger"
+
+	indom "github.com/comfforts/comff-stores/internal/domain/infra"
+	migdom "github.com/comfforts/comff-stores/internal/domain/migrations"
+	"github.com/comfforts/comff-stores/internal/infra/mongostore"
+	mgrepo "github.com/comfforts/comff-stores/internal/repo/migrations"
+	envutils "github.com/comfforts/comff-stores/pkg/utils/environ"
+)
+
+func TestMigrator(t *testing.T) {
+	// Initialize logger
+	l := logger.GetSlogLogger()
+	l.Debug("TestMigrator Logger initialized")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	nmCfg := envutils.BuildMongoStoreConfig(true)
+	cl, err := mongostore.NewMongoStore(ctx, nmCfg)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, cl.Close(ctx))
+	}()
+
+	// versions above any applied by earlier runs, so they're the latest applied
+	base := int(time.Now().Unix())
+	ups, downs := map[int]*atomic.Int32{}, map[int]*atomic.Int32{}
+	migs := []migdom.Migration{}
+	for i := 1; i <= 3; i++ {
+		v := base + i
+		ups[v], downs[v] = &atomic.Int32{}, &atomic.Int32{}
+		mg := migdom.Migration{
+			Version: v,
+			Name:    "test migration",
+			Up: func(ctx context.Context, db indom.DBStore) error {
+				ups[v].Add(1)
+				return nil
+			},
+		}
+		if i > 1 {
+			mg.Down = func(ctx context.Context, db indom.DBStore) error {
+				downs[v].Add(1)
+				return nil
+			}
+		}
+		migs = append(migs, mg)
+	}
+
+	status := func(mg migdom.Migrator) map[int]bool {
+		statuses, err := mg.Status(ctx)
+		require.NoError(t, err)
+		applied := map[int]bool{}
+		for _, st := range statuses {
+			if st.Version > base {
+				applied[st.Version] = st.Applied
+			}
+		}
+		return applied
+	}
+
+	// replicas migrating at once apply each migration once
+	var wg sync.WaitGroup
+	migrators := []migdom.Migrator{}
+	for _, owner := range []string{"replica-a", "replica-b", "replica-c"} {
+		mg, err := mgrepo.NewMigrator(ctx, cl, migs, mgrepo.MigratorOptions{Owner: owner})
+		require.NoError(t, err)
+		migrators = append(migrators, mg)
+	}
+	for _, mg := range migrators {
+		wg.Add(1)
+		go func(mg migdom.Migrator) {
+			defer wg.Done()
+			_, err := mg.Up(ctx, base+2)
+			require.NoError(t, err)
+		}(mg)
+	}
+	wg.Wait()
+	require.Equal(t, map[int]bool{base + 1: true, base + 2: true, base + 3: false}, status(migrators[0]))
+	for v := base + 1; v <= base+2; v++ {
+		require.EqualValues(t, 1, ups[v].Load())
+	}
+
+	applied, err := migrators[0].Up(ctx, 0)
+	require.NoError(t, err)
+	require.Equal(t, []int{base + 3}, applied)
+
+	reverted, err := migrators[1].Down(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, []int{base + 3, base + 2}, reverted)
+	require.EqualValues(t, 1, downs[base+3].Load())
+	require.Equal(t, map[int]bool{base + 1: true, base + 2: false, base + 3: false}, status(migrators[1]))
+
+	// the first migration has no down
+	_, err = migrators[1].Down(ctx, 1)
+	require.ErrorIs(t, err, mgrepo.ErrIrreversible)
+
+	// a held lock keeps other replicas waiting
+	started, finish := make(chan struct{}), make(chan struct{})
+	blocking := append(migs, migdom.Migration{
+		Version: base + 4,
+		Name:    "slow migration",
+		Up: func(ctx context.Context, db indom.DBStore) error {
+			close(started)
+			<-finish
+			return nil
+		},
+		Down: func(ctx context.Context, db indom.DBStore) error { return nil },
+	})
+	slow, err := mgrepo.NewMigrator(ctx, cl, blocking, mgrepo.MigratorOptions{Owner: "replica-slow"})
+	require.NoError(t, err)
+	waiting, err := mgrepo.NewMigrator(ctx, cl, blocking, mgrepo.MigratorOptions{Owner: "replica-waiting", LockWait: 2 * time.Second})
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		_, err := slow.Up(ctx, 0)
+		done <- err
+	}()
+	<-started
+	_, err = waiting.Up(ctx, 0)
+	require.ErrorIs(t, err, mgrepo.ErrLockTimeout)
+	close(finish)
+	require.NoError(t, <-done)
+
+	// losing the lock mid migration cancels it, leaving it unrecorded
+	stolen := append(migs, migdom.Migration{
+		Version: base + 5,
+		Name:    "migration losing its lock",
+		Up: func(ctx context.Context, db indom.DBStore) error {
+			if _, err := db.Store().Collection(mgrepo.MIGRATIONS_LOCK_COLLECTION).DeleteOne(ctx, map[string]any{"_id": mgrepo.MIGRATIONS_LOCK_ID}); err != nil {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(10 * time.Second):
+				return nil
+			}
+		},
+	})
+	losing, err := mgrepo.NewMigrator(ctx, cl, stolen, mgrepo.MigratorOptions{Owner: "replica-losing", LockLease: 300 * time.Millisecond})
+	require.NoError(t, err)
+	_, err = losing.Up(ctx, 0)
+	require.ErrorIs(t, err, mgrepo.ErrLockLost)
+	statuses, err := losing.Status(ctx)
+	require.NoError(t, err)
+	for _, st := range statuses {
+		if st.Version == base+5 {
+			require.False(t, st.Applied)
+		}
+	}
+
+	// the test versions are unknown to other migrators, clean them up
+	_, err = slow.Down(ctx, 4)
+	require.ErrorIs(t, err, mgrepo.ErrIrreversible)
+	_, err = cl.Store().Collection(mgrepo.MIGRATIONS_COLLECTION).DeleteOne(ctx, map[string]any{"_id": base + 1})
+	require.NoError(t, err)
+}
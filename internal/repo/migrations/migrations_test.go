@@ -0,0 +1,49 @@
+package migrations_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/comfforts/logger"
+
+	indom "github.com/comfforts/comff-stores/internal/domain/infra"
+	migdom "github.com/comfforts/comff-stores/internal/domain/migrations"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+	mgrepo "github.com/comfforts/comff-stores/internal/repo/migrations"
+	strepo "github.com/comfforts/comff-stores/internal/repo/stores"
+)
+
+func TestNewMigratorValidation(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+	up := func(ctx context.Context, db indom.DBStore) error { return nil }
+
+	for name, migs := range map[string][]migdom.Migration{
+		"zero version":      {{Version: 0, Name: "zero", Up: up}},
+		"duplicate version": {{Version: 1, Name: "one", Up: up}, {Version: 2, Name: "two", Up: up}, {Version: 1, Name: "again", Up: up}},
+		"missing up":        {{Version: 1, Name: "one"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := mgrepo.NewMigrator(ctx, nil, migs, mgrepo.MigratorOptions{})
+			require.ErrorIs(t, err, mgrepo.ErrInvalidMigrations)
+		})
+	}
+
+	// the migrator doesn't touch the database until run
+	_, err := mgrepo.NewMigrator(ctx, nil, strepo.Migrations(stdom.ADDRESS_UNIQUE_GLOBAL), mgrepo.MigratorOptions{})
+	require.NoError(t, err)
+}
+
+func TestMigratorDownValidation(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+	mg, err := mgrepo.NewMigrator(ctx, nil, strepo.Migrations(stdom.ADDRESS_UNIQUE_GLOBAL), mgrepo.MigratorOptions{})
+	require.NoError(t, err)
+
+	// steps are checked before the database is touched
+	for _, steps := range []int{0, -1} {
+		reverted, err := mg.Down(ctx, steps)
+		require.ErrorIs(t, err, mgrepo.ErrInvalidSteps)
+		require.Empty(t, reverted)
+	}
+}
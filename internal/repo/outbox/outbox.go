@@ -0,0 +1,214 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/comfforts/logger"
+
+	evdom "github.com/comfforts/comff-stores/internal/domain/events"
+	indom "github.com/comfforts/comff-stores/internal/domain/infra"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+const OUTBOX_COLLECTION = "stores.outbox"
+
+// delivered entries are kept for OUTBOX_RETENTION, then expired by a TTL index
+const OUTBOX_RETENTION = 7 * 24 * time.Hour
+
+const (
+	ERR_MISSING_REQUIRED = "missing required parameters"
+	ERR_DECODING_REC_ID  = "error decoding record ID"
+	ERR_NO_ENTRY         = "no outbox entry found"
+)
+
+var (
+	ErrMissingRequired = errors.New(ERR_MISSING_REQUIRED)
+	ErrDecodeRecId     = errors.New(ERR_DECODING_REC_ID)
+	ErrNoEntry         = errors.New(ERR_NO_ENTRY)
+)
+
+// AppendEvent writes a store event into the outbox collection, prevOrg being the org an
+// update moved the store from, empty otherwise. Callers pass in the transaction context
+// so that the event is committed atomically with the store change that produced it.
+func AppendEvent(ctx context.Context, db *mongo.Database, evType evdom.EventType, st *stdom.Store, prevOrg string) error {
+	if st == nil || st.ID == "" {
+		return ErrMissingRequired
+	}
+
+	now := time.Now().UTC()
+	oid := primitive.NewObjectID()
+	entry := bson.M{
+		"_id": oid,
+		"event": &evdom.Event{
+			ID:          oid.Hex(),
+			Type:        evType,
+			StoreID:     st.ID,
+			Org:         st.Org,
+			PreviousOrg: prevOrg,
+			Store:       st,
+			OccurredAt:  now,
+		},
+		"status":          evdom.OUTBOX_PENDING,
+		"attempts":        0,
+		"next_attempt_at": now,
+		"locked_until":    time.Time{},
+		"created_at":      now,
+	}
+
+	_, err := db.Collection(OUTBOX_COLLECTION).InsertOne(ctx, entry)
+	return err
+}
+
+type outboxRepo struct {
+	indom.DBStore
+}
+
+func NewOutboxRepo(ctx context.Context, rc indom.DBStore) (*outboxRepo, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// ensure outbox indexes
+	if err = rc.EnsureIndexes(ctx, OUTBOX_COLLECTION, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "next_attempt_at", Value: 1},
+			},
+		},
+		{
+			// only delivered entries have a delivery time, pending ones never expire
+			Keys:    bson.D{{Key: "delivered_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(OUTBOX_RETENTION.Seconds())),
+		},
+	}); err != nil {
+		l.Error("error adding outbox indexes", "error", err.Error())
+		return nil, err
+	}
+
+	l.Info("initialized outbox repo")
+	return &outboxRepo{
+		DBStore: rc,
+	}, nil
+}
+
+func (or *outboxRepo) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*evdom.OutboxEntry, error) {
+	ctx, span := startSpan(ctx, "stores.outbox.claim")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	coll := or.Store().Collection(OUTBOX_COLLECTION)
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	entries := []*evdom.OutboxEntry{}
+	for len(entries) < limit {
+		now := time.Now().UTC()
+		filter := bson.M{
+			"status":          evdom.OUTBOX_PENDING,
+			"next_attempt_at": bson.M{"$lte": now},
+			"locked_until":    bson.M{"$lte": now},
+		}
+		update := bson.M{"$set": bson.M{"locked_until": now.Add(lease)}}
+
+		var entry evdom.OutboxEntry
+		if err := coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&entry); err != nil {
+			if err == mongo.ErrNoDocuments {
+				break
+			}
+			l.Error("ClaimPending error", "error", err.Error())
+			finishSpan(span, err)
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+func (or *outboxRepo) MarkDelivered(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "stores.outbox.delivered")
+	defer span.End()
+
+	now := time.Now().UTC()
+	err := or.settle(ctx, id, bson.M{
+		"$set": bson.M{
+			"status":       evdom.OUTBOX_DELIVERED,
+			"delivered_at": now,
+			"locked_until": time.Time{},
+		},
+		"$inc": bson.M{"attempts": 1},
+	})
+	finishSpan(span, err)
+	return err
+}
+
+func (or *outboxRepo) MarkFailed(ctx context.Context, id string, nextAttemptAt time.Time, lastErr string) error {
+	ctx, span := startSpan(ctx, "stores.outbox.failed")
+	defer span.End()
+
+	err := or.settle(ctx, id, bson.M{
+		"$set": bson.M{
+			"next_attempt_at": nextAttemptAt.UTC(),
+			"last_error":      lastErr,
+			"locked_until":    time.Time{},
+		},
+		"$inc": bson.M{"attempts": 1},
+	})
+	finishSpan(span, err)
+	return err
+}
+
+func (or *outboxRepo) settle(ctx context.Context, id string, update bson.M) error {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if id == "" {
+		return ErrMissingRequired
+	}
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		l.Error("outbox entry error invalid id", "error", err.Error())
+		return ErrDecodeRecId
+	}
+
+	res, err := or.Store().Collection(OUTBOX_COLLECTION).UpdateOne(ctx, bson.M{"_id": objID}, update)
+	if err != nil {
+		l.Error("error updating outbox entry", "error", err.Error(), "id", id)
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNoEntry
+	}
+	return nil
+}
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer("stores-outbox").Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func finishSpan(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(otelcodes.Error, err.Error())
+}
@@ -0,0 +1,63 @@
+package stores
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/comfforts/logger"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+// ClaimStatusChangesDue claims up to limit stores with a status change scheduled at or
+// before the time for the lease, matched on the sparse status change index, the most
+// overdue first.
+func (sr *storesRepo) ClaimStatusChangesDue(ctx context.Context, at time.Time, limit int, lease time.Duration) ([]*stdom.Store, error) {
+	ctx, span := startSpan(ctx, "stores.repo.status_changes")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("claiming due store status changes")
+
+	if limit <= 0 || lease <= 0 {
+		finishSpan(span, ErrMissingRequired)
+		return nil, ErrMissingRequired
+	}
+
+	coll := sr.Store().Collection(STORES_COLLECTION)
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "status_change_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	stores := []*stdom.Store{}
+	for len(stores) < limit {
+		now := time.Now().UTC()
+		filter := bson.M{
+			"status_change_at": bson.M{"$lte": at},
+			"$or": bson.A{
+				bson.M{"status_claimed_until": bson.M{"$exists": false}},
+				bson.M{"status_claimed_until": bson.M{"$lte": now}},
+			},
+		}
+		update := bson.M{"$set": bson.M{"status_claimed_until": now.Add(lease)}}
+
+		var st stdom.Store
+		if err := coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&st); err != nil {
+			if err == mongo.ErrNoDocuments {
+				break
+			}
+			l.Error("ClaimStatusChangesDue error", "error", err.Error())
+			finishSpan(span, err)
+			return nil, err
+		}
+		stores = append(stores, &st)
+	}
+	return stores, nil
+}
@@ -0,0 +1,203 @@
+package stores
+
+import (
+	"context"
+	"regexp"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/comfforts/logger"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+const (
+	// CLUSTER_SAMPLE_SIZE is the most store IDs returned with a cluster.
+	CLUSTER_SAMPLE_SIZE = 5
+	// clusters are rolled up from cells this many characters finer, weighting
+	// the centroid by the cells' stores & dropping cells outside the bounds
+	CLUSTER_CELL_REFINEMENT = 4
+	// MAX_COVER_CELLS is the most address ID prefixes a bounds filter matches.
+	MAX_COVER_CELLS = 256
+)
+
+// clusterCell counts the stores of a cluster's finer cell.
+type clusterCell struct {
+	Prefix string   `bson:"_id"`
+	Count  int      `bson:"count"`
+	IDs    []string `bson:"ids"`
+}
+
+// ClusterStores groups the stores in the bounds, counting them by finer cell in mongo
+// & rolling the cells up into clusters, or returns the stores individually.
+func (sr *storesRepo) ClusterStores(ctx context.Context, params *stdom.ClusterStoresQuery) ([]*stdom.StoreCluster, error) {
+	ctx, span := startSpan(ctx, "stores.repo.cluster")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("clustering stores")
+
+	if err := validateClusterQuery(params); err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	filter := boundsFilter(params.Bounds, cellPrecision(params))
+	if params.Org != "" {
+		filter["org"] = params.Org
+	}
+	coll := sr.Store().Collection(STORES_COLLECTION)
+
+	if params.Individual {
+		cursor, err := coll.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+		if err != nil {
+			l.Error("ClusterStores error", "error", err.Error())
+			finishSpan(span, err)
+			return nil, err
+		}
+		defer cursor.Close(ctx)
+
+		clusters := []*stdom.StoreCluster{}
+		for len(clusters) < params.Limit && cursor.Next(ctx) {
+			var st stdom.Store
+			if err := cursor.Decode(&st); err != nil {
+				l.Error("ClusterStores error decoding store", "error", err.Error())
+				continue
+			}
+			if c, ok := storeCluster(&st, params.Bounds); ok {
+				clusters = append(clusters, c)
+			}
+		}
+		if err := cursor.Err(); err != nil {
+			l.Error("ClusterStores error", "error", err.Error())
+			finishSpan(span, err)
+			return nil, err
+		}
+		return clusters, nil
+	}
+
+	cursor, err := coll.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$substrCP": bson.A{"$address_id", 0, cellPrecision(params)}},
+			"count": bson.M{"$sum": 1},
+			"ids":   bson.M{"$firstN": bson.M{"input": bson.M{"$toString": "$_id"}, "n": CLUSTER_SAMPLE_SIZE}},
+		}}},
+	})
+	if err != nil {
+		l.Error("ClusterStores error", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	cells := []*clusterCell{}
+	if err := cursor.All(ctx, &cells); err != nil {
+		l.Error("ClusterStores error decoding cells", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	return rollupClusters(cells, params), nil
+}
+
+func validateClusterQuery(params *stdom.ClusterStoresQuery) error {
+	if params == nil || params.Bounds == nil {
+		return ErrMissingRequired
+	}
+	if !params.Bounds.Valid() ||
+		params.Precision < 1 ||
+		params.Precision > geodom.DEFAULT_ADDRESS_ID_PRECISION ||
+		(params.Individual && params.Limit < 1) {
+		return ErrInvalidClusterQuery
+	}
+	return nil
+}
+
+// cellPrecision is the address ID prefix length of the cells clusters are rolled up from.
+func cellPrecision(params *stdom.ClusterStoresQuery) int {
+	return min(params.Precision+CLUSTER_CELL_REFINEMENT, geodom.DEFAULT_ADDRESS_ID_PRECISION)
+}
+
+// boundsFilter matches address IDs in the cells covering the bounds.
+func boundsFilter(b *geodom.BoundingBox, precision int) bson.M {
+	return bson.M{"address_id": bson.M{"$in": prefixPatterns(geodom.CoverBounds(b, precision, MAX_COVER_CELLS))}}
+}
+
+// prefixPatterns are anchored prefix matches, which use the address ID index.
+func prefixPatterns(prefixes []string) bson.A {
+	patterns := bson.A{}
+	for _, p := range prefixes {
+		patterns = append(patterns, primitive.Regex{Pattern: "^" + regexp.QuoteMeta(p)})
+	}
+	return patterns
+}
+
+// storeCluster returns a store as its own cluster when its address is in the bounds.
+func storeCluster(st *stdom.Store, b *geodom.BoundingBox) (*stdom.StoreCluster, bool) {
+	lat, lon, err := geodom.DecodeAddressId(st.AddressId)
+	if err != nil || !b.Contains(lat, lon) {
+		return nil, false
+	}
+	return &stdom.StoreCluster{
+		Region:    st.AddressId,
+		Latitude:  lat,
+		Longitude: lon,
+		Count:     1,
+		SampleIDs: []string{st.ID},
+		Store:     st,
+	}, true
+}
+
+// rollupClusters groups the cells in the bounds into clusters by address ID prefix,
+// largest first. Cells that aren't address ID prefixes are dropped.
+func rollupClusters(cells []*clusterCell, params *stdom.ClusterStoresQuery) []*stdom.StoreCluster {
+	// cells come unordered, sorting them keeps samples stable
+	sort.Slice(cells, func(i, j int) bool {
+		return cells[i].Prefix < cells[j].Prefix
+	})
+
+	byRegion := map[string]*stdom.StoreCluster{}
+	for _, cell := range cells {
+		lat, lon, err := geodom.DecodeAddressId(cell.Prefix)
+		if err != nil || !params.Bounds.Contains(lat, lon) {
+			continue
+		}
+
+		region := cell.Prefix[:min(params.Precision, len(cell.Prefix))]
+		c, ok := byRegion[region]
+		if !ok {
+			c = &stdom.StoreCluster{Region: region}
+			byRegion[region] = c
+		}
+		// running count weighted centroid
+		c.Count += cell.Count
+		w := float64(cell.Count) / float64(c.Count)
+		c.Latitude += (lat - c.Latitude) * w
+		c.Longitude += (lon - c.Longitude) * w
+		for _, id := range cell.IDs {
+			if len(c.SampleIDs) < CLUSTER_SAMPLE_SIZE {
+				c.SampleIDs = append(c.SampleIDs, id)
+			}
+		}
+	}
+
+	clusters := make([]*stdom.StoreCluster, 0, len(byRegion))
+	for _, c := range byRegion {
+		clusters = append(clusters, c)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Count != clusters[j].Count {
+			return clusters[i].Count > clusters[j].Count
+		}
+		return clusters[i].Region < clusters[j].Region
+	})
+	return clusters
+}
@@ -0,0 +1,1075 @@
+package stores_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+	strepo "github.com/comfforts/comff-stores/internal/repo/stores"
+)
+
+// runStoresRepoConformance checks the StoresRepo contract, run against every implementation
+// so they can't drift apart. Names & address IDs are made unique per run, so the suite
+// can run against a shared database.
+func runStoresRepoConformance(t *testing.T, ctx context.Context, sr stdom.StoresRepo) {
+	run := fmt.Sprintf("Conf%d", time.Now().UnixNano())
+	addr := func(s string) string { return run + "-" + s }
+
+	t.Run("add requires fields", func(t *testing.T) {
+		_, err := sr.AddStore(ctx, &stdom.Store{Name: run + " Store", Org: run + " Org"})
+		require.ErrorIs(t, err, strepo.ErrMissingRequired)
+		_, err = sr.AddStore(ctx, nil)
+		require.ErrorIs(t, err, strepo.ErrMissingRequired)
+	})
+
+	t.Run("get validates id", func(t *testing.T) {
+		_, err := sr.GetStore(ctx, "")
+		require.ErrorIs(t, err, strepo.ErrMissingRequired)
+		_, err = sr.GetStore(ctx, "not-an-id")
+		require.ErrorIs(t, err, strepo.ErrDecodeRecId)
+		_, err = sr.GetStore(ctx, primitive.NewObjectID().Hex())
+		require.ErrorIs(t, err, strepo.ErrNoStore)
+	})
+
+	t.Run("crud", func(t *testing.T) {
+		id, err := sr.AddStore(ctx, &stdom.Store{Name: run + " Alpha Store", Org: run + " Org A", AddressId: addr("a1")})
+		require.NoError(t, err)
+		require.NotEmpty(t, id)
+
+		st, err := sr.GetStore(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, id, st.ID)
+		require.Equal(t, run+" Alpha Store", st.Name)
+		require.Equal(t, run+" Org A", st.Org)
+		require.Equal(t, addr("a1"), st.AddressId)
+
+		// address IDs are unique
+		_, err = sr.AddStore(ctx, &stdom.Store{Name: run + " Other Store", Org: run + " Org A", AddressId: addr("a1")})
+		require.ErrorIs(t, err, strepo.ErrDuplicateStore)
+
+		otherID, err := sr.AddStore(ctx, &stdom.Store{Name: run + " Beta Store", Org: run + " Org B", AddressId: addr("b1")})
+		require.NoError(t, err)
+
+		require.ErrorIs(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{}), strepo.ErrMissingRequired)
+		require.ErrorIs(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{AddressId: addr("b1")}), strepo.ErrDuplicateStore)
+		require.ErrorIs(t, sr.UpdateStore(ctx, primitive.NewObjectID().Hex(), &stdom.UpdateStoreQuery{Name: "x"}), strepo.ErrNoStore)
+		require.ErrorIs(t, sr.UpdateStore(ctx, "not-an-id", &stdom.UpdateStoreQuery{Name: "x"}), strepo.ErrDecodeRecId)
+
+		require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{Name: run + " Alpha Prime"}))
+		st, err = sr.GetStore(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, run+" Alpha Prime", st.Name)
+		require.Equal(t, addr("a1"), st.AddressId)
+
+		require.NoError(t, sr.DeleteStore(ctx, id, nil))
+		require.ErrorIs(t, sr.DeleteStore(ctx, id, nil), strepo.ErrNoStore)
+		_, err = sr.GetStore(ctx, id)
+		require.ErrorIs(t, err, strepo.ErrNoStore)
+
+		// the deleted store's address is free again
+		id, err = sr.AddStore(ctx, &stdom.Store{Name: run + " Alpha Store", Org: run + " Org A", AddressId: addr("a1")})
+		require.NoError(t, err)
+
+		require.NoError(t, sr.DeleteStore(ctx, id, nil))
+		require.NoError(t, sr.DeleteStore(ctx, otherID, nil))
+	})
+
+	t.Run("address history", func(t *testing.T) {
+		id, err := sr.AddStore(ctx, &stdom.Store{Name: run + " Moving Store", Org: run + " Org A", AddressId: addr("m1")})
+		require.NoError(t, err)
+
+		// name only & same address updates aren't relocations
+		require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{Name: run + " Moved Store"}))
+		require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{AddressId: addr("m1")}))
+		require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{AddressId: addr("m2")}))
+		require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{AddressId: addr("m3")}))
+		require.NoError(t, sr.DeleteStore(ctx, id, nil))
+
+		// kept after delete
+		changes, err := sr.GetAddressHistory(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, changes, 3)
+		for i, want := range [][2]string{{addr("m1"), ""}, {addr("m2"), addr("m1")}, {addr("m3"), addr("m2")}} {
+			require.Equal(t, id, changes[i].StoreID)
+			require.Equal(t, want[0], changes[i].AddressId)
+			require.Equal(t, want[1], changes[i].PreviousAddressId)
+			require.False(t, changes[i].ChangedAt.IsZero())
+		}
+
+		changes, err = sr.GetAddressHistory(ctx, primitive.NewObjectID().Hex())
+		require.NoError(t, err)
+		require.Empty(t, changes)
+		_, err = sr.GetAddressHistory(ctx, "not-an-id")
+		require.ErrorIs(t, err, strepo.ErrDecodeRecId)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		ids := []string{}
+		for i, st := range []*stdom.Store{
+			{Name: run + " Coffee Corner", Org: run + " Org A", AddressId: addr("dacd-1")},
+			{Name: run + " Coffee House", Org: run + " Org B", AddressId: addr("dacd-2")},
+			{Name: run + " Tea Room", Org: run + " Org A", AddressId: addr("dabc-1")},
+		} {
+			id, err := sr.AddStore(ctx, st)
+			require.NoError(t, err, i)
+			ids = append(ids, id)
+		}
+		defer func() {
+			for _, id := range ids {
+				require.NoError(t, sr.DeleteStore(ctx, id, nil))
+			}
+		}()
+
+		names := func(q *stdom.SearchStoreQuery) []string {
+			res, err := sr.SearchStores(ctx, q)
+			require.NoError(t, err)
+			ns := []string{}
+			for _, st := range res.Stores {
+				ns = append(ns, strings.TrimPrefix(st.Name, run+" "))
+			}
+			return ns
+		}
+
+		// prefix matches are case-insensitive
+		require.ElementsMatch(t, []string{"Coffee Corner", "Coffee House"}, names(&stdom.SearchStoreQuery{Name: strings.ToLower(run + " coffee")}))
+		require.ElementsMatch(t, []string{"Coffee Corner", "Tea Room"}, names(&stdom.SearchStoreQuery{Org: strings.ToUpper(run + " org a")}))
+		require.ElementsMatch(t, []string{"Coffee Corner", "Coffee House"}, names(&stdom.SearchStoreQuery{AddressId: addr("dacd")}))
+		require.ElementsMatch(t, []string{"Coffee Corner"}, names(&stdom.SearchStoreQuery{Name: run + " Coffee", Org: run + " Org A"}))
+		// only prefixes match
+		require.Empty(t, names(&stdom.SearchStoreQuery{Name: "Coffee " + run}))
+		require.Empty(t, names(&stdom.SearchStoreQuery{Name: run + " Room"}))
+	})
+
+	t.Run("text search", func(t *testing.T) {
+		org := run + " Org T"
+		ids := map[string]string{}
+		for i, st := range []*stdom.Store{
+			{Name: run + " Blue Bottle Coffee", Tags: []string{"espresso"}, Description: "Third wave roaster"},
+			{Name: run + " Corner Bakery", Tags: []string{"coffee", "pastries"}, Description: "Bread and cakes"},
+			{Name: run + " Tea House", Description: "Loose leaf teas, coffee on request"},
+			{Name: run + " Book Nook"},
+		} {
+			st.Org, st.AddressId = org, addr(fmt.Sprintf("t%d", i))
+			id, err := sr.AddStore(ctx, st)
+			require.NoError(t, err, i)
+			ids[strings.TrimPrefix(st.Name, run+" ")] = id
+		}
+		defer func() {
+			for _, id := range ids {
+				require.NoError(t, sr.DeleteStore(ctx, id, nil))
+			}
+		}()
+
+		search := func(q *stdom.SearchStoreQuery) []string {
+			res, err := sr.SearchStores(ctx, q)
+			require.NoError(t, err)
+			ns := []string{}
+			for i, st := range res.Stores {
+				require.Positive(t, st.Score)
+				if i > 0 {
+					require.GreaterOrEqual(t, res.Stores[i-1].Score, st.Score)
+				}
+				ns = append(ns, strings.TrimPrefix(st.Name, run+" "))
+			}
+			return ns
+		}
+
+		// matches anywhere in the name, tags & description, ranked name first
+		require.Equal(t, []string{"Blue Bottle Coffee", "Corner Bakery", "Tea House"}, search(&stdom.SearchStoreQuery{Query: "Coffee", Org: org}))
+		require.Equal(t, []string{"Blue Bottle Coffee"}, search(&stdom.SearchStoreQuery{Query: "bottle", Org: org}))
+		require.Equal(t, []string{"Blue Bottle Coffee"}, search(&stdom.SearchStoreQuery{Query: "espresso", Org: org}))
+		require.ElementsMatch(t, []string{"Corner Bakery", "Tea House"}, search(&stdom.SearchStoreQuery{Query: "bread teas", Org: org}))
+		// combined with the other filters
+		require.Equal(t, []string{"Corner Bakery"}, search(&stdom.SearchStoreQuery{Query: "coffee", Org: org, AddressId: addr("t1")}))
+		require.Empty(t, search(&stdom.SearchStoreQuery{Query: "coffee", Org: run + " Org Z"}))
+
+		require.NoError(t, sr.UpdateStore(ctx, ids["Book Nook"], &stdom.UpdateStoreQuery{Tags: []string{"books", "coffee"}, Description: "Used books"}))
+		st, err := sr.GetStore(ctx, ids["Book Nook"])
+		require.NoError(t, err)
+		require.Equal(t, []string{"books", "coffee"}, st.Tags)
+		require.Equal(t, "Used books", st.Description)
+		require.Contains(t, search(&stdom.SearchStoreQuery{Query: "coffee", Org: org}), "Book Nook")
+	})
+
+	t.Run("fuzzy search", func(t *testing.T) {
+		org := run + " Org F"
+		ids := map[string]string{}
+		for i, name := range []string{"Petaluma Market", "Petaluma Hardware", "Santa Rosa Market"} {
+			id, err := sr.AddStore(ctx, &stdom.Store{Name: name, Org: org, AddressId: addr(fmt.Sprintf("f%d", i))})
+			require.NoError(t, err, i)
+			ids[name] = id
+		}
+		defer func() {
+			for _, id := range ids {
+				require.NoError(t, sr.DeleteStore(ctx, id, nil))
+			}
+		}()
+
+		search := func(name string, minSimilarity float64) []string {
+			res, err := sr.SearchStores(ctx, &stdom.SearchStoreQuery{Name: name, Org: org, Fuzzy: true, MinSimilarity: minSimilarity})
+			require.NoError(t, err)
+			ns := []string{}
+			for i, st := range res.Stores {
+				require.GreaterOrEqual(t, st.Score, minSimilarity)
+				if i > 0 {
+					require.GreaterOrEqual(t, res.Stores[i-1].Score, st.Score)
+				}
+				ns = append(ns, st.Name)
+			}
+			return ns
+		}
+
+		// misspellings match, closest first
+		require.Equal(t, "Petaluma Market", search("Petluma Markt", stdom.DEFAULT_FUZZY_MIN_SIMILARITY)[0])
+		require.Equal(t, []string{"Petaluma Market"}, search("Petluma Markt", 0.85))
+		require.Empty(t, search("Bakery", stdom.DEFAULT_FUZZY_MIN_SIMILARITY))
+		// not a prefix search
+		require.Empty(t, search("Petal", 0.9))
+
+		// trigrams follow renames
+		require.NoError(t, sr.UpdateStore(ctx, ids["Petaluma Hardware"], &stdom.UpdateStoreQuery{Name: "Sonoma Lumber"}))
+		require.Equal(t, []string{"Sonoma Lumber"}, search("Sonomma Lumbr", stdom.DEFAULT_FUZZY_MIN_SIMILARITY))
+	})
+
+	t.Run("facets and paging", func(t *testing.T) {
+		org := run + " Org P"
+		ids := []string{}
+		for i, st := range []*stdom.Store{
+			{Name: "Paging Cafe", Tags: []string{"coffee", "wifi"}},
+			{Name: "Paging Deli", Tags: []string{"coffee"}, Status: stdom.STORE_INACTIVE},
+			{Name: "Paging Diner", Tags: []string{"breakfast", "coffee"}},
+			{Name: "Paging Market"},
+			{Name: "Paging Bakery", Tags: []string{"breakfast"}, Status: stdom.STORE_CLOSED},
+		} {
+			st.Org, st.AddressId = org, addr(fmt.Sprintf("p%d", i))
+			id, err := sr.AddStore(ctx, st)
+			require.NoError(t, err, i)
+			ids = append(ids, id)
+		}
+		defer func() {
+			for _, id := range ids {
+				require.NoError(t, sr.DeleteStore(ctx, id, nil))
+			}
+		}()
+
+		// status defaults to active
+		st, err := sr.GetStore(ctx, ids[0])
+		require.NoError(t, err)
+		require.Equal(t, stdom.STORE_ACTIVE, st.Status)
+
+		facets := []stdom.FacetField{stdom.FACET_ORG, stdom.FACET_STATUS, stdom.FACET_TAGS}
+		res, err := sr.SearchStores(ctx, &stdom.SearchStoreQuery{Org: org, Facets: facets, Limit: 2})
+		require.NoError(t, err)
+		require.Equal(t, 5, res.Total)
+		require.Len(t, res.Stores, 2)
+		require.Equal(t, []*stdom.FacetBucket{{Value: org, Count: 5}}, res.Facets[stdom.FACET_ORG])
+		require.Equal(t, []*stdom.FacetBucket{
+			{Value: string(stdom.STORE_ACTIVE), Count: 3},
+			{Value: string(stdom.STORE_CLOSED), Count: 1},
+			{Value: string(stdom.STORE_INACTIVE), Count: 1},
+		}, res.Facets[stdom.FACET_STATUS])
+		require.Equal(t, []*stdom.FacetBucket{
+			{Value: "coffee", Count: 3},
+			{Value: "breakfast", Count: 2},
+			{Value: "wifi", Count: 1},
+		}, res.Facets[stdom.FACET_TAGS])
+
+		// pages don't overlap & cover every match
+		seen := map[string]bool{}
+		for offset := 0; offset < 6; offset += 2 {
+			page, err := sr.SearchStores(ctx, &stdom.SearchStoreQuery{Org: org, Limit: 2, Offset: offset})
+			require.NoError(t, err)
+			require.Equal(t, 5, page.Total)
+			require.Empty(t, page.Facets)
+			for _, st := range page.Stores {
+				require.False(t, seen[st.ID], st.Name)
+				seen[st.ID] = true
+			}
+		}
+		require.Len(t, seen, 5)
+
+		// status updates show in facets
+		require.NoError(t, sr.UpdateStore(ctx, ids[1], &stdom.UpdateStoreQuery{Status: stdom.STORE_ACTIVE}))
+		res, err = sr.SearchStores(ctx, &stdom.SearchStoreQuery{Org: org, Facets: []stdom.FacetField{stdom.FACET_STATUS}})
+		require.NoError(t, err)
+		require.Len(t, res.Stores, 5)
+		require.Equal(t, 4, res.Facets[stdom.FACET_STATUS][0].Count)
+
+		// fuzzy matches are faceted too
+		res, err = sr.SearchStores(ctx, &stdom.SearchStoreQuery{Name: "Pagng Diner", Org: org, Fuzzy: true, Facets: facets, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, res.Stores, 1)
+		require.Equal(t, "Paging Diner", res.Stores[0].Name)
+		require.Equal(t, res.Total, res.Facets[stdom.FACET_ORG][0].Count)
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		org := run + " Org S"
+		ids := map[string]string{}
+		for _, a := range []string{"sa1", "sa2", "sb1"} {
+			id, err := sr.AddStore(ctx, &stdom.Store{Name: run + " Stats " + a, Org: org, AddressId: addr(a)})
+			require.NoError(t, err, a)
+			ids[a] = id
+		}
+		defer func() {
+			for _, a := range []string{"sa1", "sa2"} {
+				require.NoError(t, sr.DeleteStore(ctx, ids[a], nil))
+			}
+		}()
+		require.NoError(t, sr.DeleteStore(ctx, ids["sb1"], nil))
+
+		st, err := sr.GetStore(ctx, ids["sa1"])
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now(), st.CreatedAt, time.Minute)
+
+		// a day either side, in case the run crosses midnight
+		from := stdom.STATS_DAY.BucketStart(time.Now()).AddDate(0, 0, -1)
+		stats, err := sr.GetStoreStats(ctx, &stdom.StoreStatsQuery{
+			Org:             org,
+			From:            from,
+			To:              from.AddDate(0, 0, 3),
+			Interval:        stdom.STATS_DAY,
+			RegionPrecision: len(addr("sa")),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, stats.Total)
+		require.Equal(t, []*stdom.StatsCount{{Key: org, Count: 2}}, stats.Orgs)
+		require.Len(t, stats.Regions, 1)
+		require.Equal(t, addr("sa"), stats.Regions[0].Region)
+		require.Equal(t, 2, stats.Regions[0].Count)
+
+		require.Len(t, stats.Series, 3)
+		added, deleted := 0, 0
+		for i, b := range stats.Series {
+			require.True(t, from.AddDate(0, 0, i).Equal(b.Start), b.Start)
+			added += b.Added
+			deleted += b.Deleted
+		}
+		// deleted stores were added too
+		require.Equal(t, 3, added)
+		require.Equal(t, 1, deleted)
+
+		_, err = sr.GetStoreStats(ctx, nil)
+		require.ErrorIs(t, err, strepo.ErrMissingRequired)
+		_, err = sr.GetStoreStats(ctx, &stdom.StoreStatsQuery{From: from, To: from, Interval: stdom.STATS_DAY})
+		require.ErrorIs(t, err, strepo.ErrInvalidStatsQuery)
+	})
+
+	t.Run("clusters", func(t *testing.T) {
+		org := run + " Org C"
+		// in the southern ocean, centered in a precision 12 cell so nearby stores share it,
+		// jittered so runs don't share address IDs
+		jitter := float64(time.Now().UnixNano()%10000) * 1e-7
+		lat, lon, err := geodom.DecodeAddressId(geodom.EncodeAddressId(-60.3, 100.3, 12))
+		require.NoError(t, err)
+		ids := []string{}
+		for i, pt := range [][2]float64{
+			{lat + jitter, lon},
+			{lat + jitter + 0.001, lon},
+			{lat + jitter, lon + 0.001},
+			{lat + jitter, lon + 3},
+		} {
+			id, err := sr.AddStore(ctx, &stdom.Store{
+				Name:      fmt.Sprintf("%s Pin %d", run, i),
+				Org:       org,
+				AddressId: geodom.EncodeAddressId(pt[0], pt[1], geodom.DEFAULT_ADDRESS_ID_PRECISION),
+			})
+			require.NoError(t, err, i)
+			ids = append(ids, id)
+		}
+		defer func() {
+			for _, id := range ids {
+				require.NoError(t, sr.DeleteStore(ctx, id, nil))
+			}
+		}()
+
+		bounds := &geodom.BoundingBox{MinLat: lat - 1, MinLon: lon - 1, MaxLat: lat + 1, MaxLon: lon + 4}
+		clusters, err := sr.ClusterStores(ctx, &stdom.ClusterStoresQuery{Org: org, Bounds: bounds, Precision: 8})
+		require.NoError(t, err)
+		require.Len(t, clusters, 2)
+		require.Equal(t, 3, clusters[0].Count)
+		require.Len(t, clusters[0].Region, 8)
+		require.ElementsMatch(t, ids[:3], clusters[0].SampleIDs)
+		require.InDelta(t, lat, clusters[0].Latitude, 0.01)
+		require.InDelta(t, lon, clusters[0].Longitude, 0.01)
+		require.Nil(t, clusters[0].Store)
+		require.Equal(t, []string{ids[3]}, clusters[1].SampleIDs)
+
+		// stores outside the bounds aren't clustered
+		bounds.MaxLon = lon + 1
+		clusters, err = sr.ClusterStores(ctx, &stdom.ClusterStoresQuery{Org: org, Bounds: bounds, Precision: 8})
+		require.NoError(t, err)
+		require.Len(t, clusters, 1)
+
+		// individually
+		clusters, err = sr.ClusterStores(ctx, &stdom.ClusterStoresQuery{Org: org, Bounds: bounds, Precision: 20, Individual: true, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, clusters, 3)
+		for i, c := range clusters {
+			require.Equal(t, 1, c.Count)
+			require.Equal(t, ids[i], c.Store.ID)
+			require.Equal(t, c.Store.AddressId, c.Region)
+		}
+		clusters, err = sr.ClusterStores(ctx, &stdom.ClusterStoresQuery{Org: org, Bounds: bounds, Precision: 20, Individual: true, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, clusters, 2)
+
+		_, err = sr.ClusterStores(ctx, nil)
+		require.ErrorIs(t, err, strepo.ErrMissingRequired)
+		_, err = sr.ClusterStores(ctx, &stdom.ClusterStoresQuery{Bounds: bounds})
+		require.ErrorIs(t, err, strepo.ErrInvalidClusterQuery)
+	})
+
+	t.Run("within", func(t *testing.T) {
+		org := run + " Org W"
+		// in the southern ocean, jittered so runs don't share address IDs
+		lat, lon := -61.3+float64(time.Now().UnixNano()%10000)*1e-7, 101.3
+		ids := []string{}
+		for i, pt := range [][2]float64{{lat, lon}, {lat + 0.05, lon}, {lat + 0.5, lon}} {
+			id, err := sr.AddStore(ctx, &stdom.Store{
+				Name:      fmt.Sprintf("%s Territory %d", run, i),
+				Org:       org,
+				AddressId: geodom.EncodeAddressId(pt[0], pt[1], geodom.DEFAULT_ADDRESS_ID_PRECISION),
+			})
+			require.NoError(t, err, i)
+			ids = append(ids, id)
+		}
+		defer func() {
+			for _, id := range ids {
+				require.NoError(t, sr.DeleteStore(ctx, id, nil))
+			}
+		}()
+
+		st, err := sr.GetStore(ctx, ids[0])
+		require.NoError(t, err)
+		require.NotNil(t, st.Location)
+		require.InDelta(t, lat, st.Location.Coordinates[1], 0.001)
+
+		square := func(lat, lon, half float64) geodom.Ring {
+			return geodom.BoundsPolygon(&geodom.BoundingBox{MinLat: lat - half, MinLon: lon - half, MaxLat: lat + half, MaxLon: lon + half})[0]
+		}
+		hole := square(lat+0.05, lon, 0.02)
+		slices.Reverse(hole)
+		within := func(area ...geodom.Polygon) []string {
+			res, err := sr.SearchStores(ctx, &stdom.SearchStoreQuery{Org: org, Within: area})
+			require.NoError(t, err)
+			found := []string{}
+			for _, st := range res.Stores {
+				found = append(found, st.ID)
+			}
+			return found
+		}
+
+		require.ElementsMatch(t, ids, within(geodom.Polygon{square(lat+0.25, lon, 0.4)}))
+		require.ElementsMatch(t, ids[:2], within(geodom.Polygon{square(lat, lon, 0.1)}))
+		// holes are excluded
+		require.ElementsMatch(t, ids[:1], within(geodom.Polygon{square(lat, lon, 0.1), hole}))
+		// any of a multipolygon's polygons
+		require.ElementsMatch(t, []string{ids[0], ids[2]}, within(geodom.Polygon{square(lat, lon, 0.02)}, geodom.Polygon{square(lat+0.5, lon, 0.02)}))
+
+		// locations follow address changes
+		require.NoError(t, sr.UpdateStore(ctx, ids[2], &stdom.UpdateStoreQuery{
+			AddressId: geodom.EncodeAddressId(lat-0.05, lon, geodom.DEFAULT_ADDRESS_ID_PRECISION),
+		}))
+		require.ElementsMatch(t, ids, within(geodom.Polygon{square(lat, lon, 0.1)}))
+	})
+
+	t.Run("service areas", func(t *testing.T) {
+		org := run + " Org S"
+		// in the southern ocean, jittered so runs don't share address IDs
+		lat, lon := -62.3+float64(time.Now().UnixNano()%10000)*1e-7, 102.3
+		square := func(lat, lon, half float64) geodom.Polygon {
+			return geodom.BoundsPolygon(&geodom.BoundingBox{MinLat: lat - half, MinLon: lon - half, MaxLat: lat + half, MaxLon: lon + half})
+		}
+		ids := []string{}
+		for i, area := range []*geodom.GeoJSONMultiPolygon{
+			geodom.NewGeoJSONMultiPolygon([]geodom.Polygon{square(lat, lon, 0.1)}),
+			geodom.NewGeoJSONMultiPolygon([]geodom.Polygon{square(lat, lon+0.05, 0.02), square(lat+0.5, lon, 0.1)}),
+			nil,
+		} {
+			id, err := sr.AddStore(ctx, &stdom.Store{
+				Name:        fmt.Sprintf("%s Serving %d", run, i),
+				Org:         org,
+				AddressId:   geodom.EncodeAddressId(lat+float64(i)*0.001, lon, geodom.DEFAULT_ADDRESS_ID_PRECISION),
+				ServiceArea: area,
+			})
+			require.NoError(t, err, i)
+			ids = append(ids, id)
+		}
+		defer func() {
+			for _, id := range ids {
+				require.NoError(t, sr.DeleteStore(ctx, id, nil))
+			}
+		}()
+
+		st, err := sr.GetStore(ctx, ids[1])
+		require.NoError(t, err)
+		require.NotNil(t, st.ServiceArea)
+		require.Len(t, st.ServiceArea.Coordinates, 2)
+
+		serving := func(lat, lon float64) []string {
+			stores, err := sr.FindServingStores(ctx, &stdom.ServingStoresQuery{Org: org, Latitude: lat, Longitude: lon})
+			require.NoError(t, err)
+			found := []string{}
+			for _, st := range stores {
+				found = append(found, st.ID)
+			}
+			return found
+		}
+		require.ElementsMatch(t, ids[:2], serving(lat, lon+0.05))
+		require.ElementsMatch(t, ids[:1], serving(lat, lon-0.05))
+		// any of the area's polygons
+		require.ElementsMatch(t, ids[1:2], serving(lat+0.5, lon))
+		require.Empty(t, serving(lat-0.5, lon))
+
+		stores, err := sr.FindServingStores(ctx, &stdom.ServingStoresQuery{Org: run + " Org None", Latitude: lat, Longitude: lon})
+		require.NoError(t, err)
+		require.Empty(t, stores)
+
+		require.NoError(t, sr.UpdateStore(ctx, ids[2], &stdom.UpdateStoreQuery{
+			ServiceArea: geodom.NewGeoJSONMultiPolygon([]geodom.Polygon{square(lat-0.5, lon, 0.1)}),
+		}))
+		require.ElementsMatch(t, ids[2:], serving(lat-0.5, lon))
+
+		_, err = sr.FindServingStores(ctx, &stdom.ServingStoresQuery{Latitude: 91, Longitude: lon})
+		require.ErrorIs(t, err, strepo.ErrInvalidServingQuery)
+	})
+
+	t.Run("hierarchy", func(t *testing.T) {
+		org := run + " Org H"
+		region := strings.ToLower(run) + "/west"
+		parentID, err := sr.AddStore(ctx, &stdom.Store{Name: run + " Flagship", Org: org, AddressId: addr("h1"), Region: region})
+		require.NoError(t, err)
+		childID, err := sr.AddStore(ctx, &stdom.Store{Name: run + " Satellite", Org: org, AddressId: addr("h2"), Region: region + "/bay-area", ParentID: parentID})
+		require.NoError(t, err)
+		otherID, err := sr.AddStore(ctx, &stdom.Store{Name: run + " Outpost", Org: org, AddressId: addr("h3"), Region: region + "ern"})
+		require.NoError(t, err)
+		defer func() {
+			for _, id := range []string{childID, parentID, otherID} {
+				require.NoError(t, sr.DeleteStore(ctx, id, nil))
+			}
+		}()
+
+		st, err := sr.GetStore(ctx, childID)
+		require.NoError(t, err)
+		require.Equal(t, parentID, st.ParentID)
+		require.Equal(t, region+"/bay-area", st.Region)
+
+		children, err := sr.ListChildStores(ctx, parentID)
+		require.NoError(t, err)
+		require.Len(t, children, 1)
+		require.Equal(t, childID, children[0].ID)
+		children, err = sr.ListChildStores(ctx, childID)
+		require.NoError(t, err)
+		require.Empty(t, children)
+		_, err = sr.ListChildStores(ctx, "not-an-id")
+		require.ErrorIs(t, err, strepo.ErrDecodeRecId)
+
+		inRegion := func(region string) []string {
+			res, err := sr.SearchStores(ctx, &stdom.SearchStoreQuery{Org: org, Region: region})
+			require.NoError(t, err)
+			found := []string{}
+			for _, st := range res.Stores {
+				found = append(found, st.ID)
+			}
+			return found
+		}
+		// sub regions match, regions sharing a prefix don't
+		require.Equal(t, []string{parentID, childID}, inRegion(region))
+		require.Equal(t, []string{childID}, inRegion(region+"/bay-area"))
+		require.Empty(t, inRegion(region+"/bay"))
+
+		require.NoError(t, sr.UpdateStore(ctx, childID, &stdom.UpdateStoreQuery{DetachParent: true, Region: region}))
+		st, err = sr.GetStore(ctx, childID)
+		require.NoError(t, err)
+		require.Empty(t, st.ParentID)
+		require.Equal(t, region, st.Region)
+		children, err = sr.ListChildStores(ctx, parentID)
+		require.NoError(t, err)
+		require.Empty(t, children)
+
+		require.NoError(t, sr.UpdateStore(ctx, otherID, &stdom.UpdateStoreQuery{ParentID: parentID}))
+		children, err = sr.ListChildStores(ctx, parentID)
+		require.NoError(t, err)
+		require.Len(t, children, 1)
+		require.Equal(t, otherID, children[0].ID)
+	})
+
+	t.Run("capabilities", func(t *testing.T) {
+		org := run + " Org C"
+		// in the south atlantic, jittered so runs don't share address IDs
+		lat, lon := -48.7+float64(time.Now().UnixNano()%10000)*1e-7, -21.4
+		area := geodom.NewGeoJSONMultiPolygon([]geodom.Polygon{geodom.BoundsPolygon(&geodom.BoundingBox{
+			MinLat: lat - 0.1, MinLon: lon - 0.1, MaxLat: lat + 0.1, MaxLon: lon + 0.1,
+		})})
+		ids := []string{}
+		for i, capabilities := range [][]string{
+			{"pickup", "pharmacy"},
+			{"pickup"},
+			nil,
+		} {
+			id, err := sr.AddStore(ctx, &stdom.Store{
+				Name:         fmt.Sprintf("%s Capable %d", run, i),
+				Org:          org,
+				AddressId:    geodom.EncodeAddressId(lat+float64(i)*0.001, lon, geodom.DEFAULT_ADDRESS_ID_PRECISION),
+				ServiceArea:  area,
+				Capabilities: capabilities,
+			})
+			require.NoError(t, err, i)
+			ids = append(ids, id)
+		}
+		defer func() {
+			for _, id := range ids {
+				require.NoError(t, sr.DeleteStore(ctx, id, nil))
+			}
+		}()
+
+		st, err := sr.GetStore(ctx, ids[0])
+		require.NoError(t, err)
+		require.Equal(t, []string{"pickup", "pharmacy"}, st.Capabilities)
+
+		offering := func(capabilities ...string) []string {
+			res, err := sr.SearchStores(ctx, &stdom.SearchStoreQuery{Org: org, Capabilities: capabilities})
+			require.NoError(t, err)
+			found := []string{}
+			for _, st := range res.Stores {
+				found = append(found, st.ID)
+			}
+			return found
+		}
+		// stores offer all of the capabilities
+		require.ElementsMatch(t, ids[:2], offering("pickup"))
+		require.ElementsMatch(t, ids[:1], offering("pickup", "pharmacy"))
+		require.Empty(t, offering("pickup", "delivery"))
+		require.ElementsMatch(t, ids, offering())
+
+		res, err := sr.SearchStores(ctx, &stdom.SearchStoreQuery{Org: org, Facets: []stdom.FacetField{stdom.FACET_CAPABILITIES}})
+		require.NoError(t, err)
+		require.Equal(t, []*stdom.FacetBucket{
+			{Value: "pickup", Count: 2},
+			{Value: "pharmacy", Count: 1},
+		}, res.Facets[stdom.FACET_CAPABILITIES])
+
+		stores, err := sr.FindServingStores(ctx, &stdom.ServingStoresQuery{Org: org, Latitude: lat, Longitude: lon, Capabilities: []string{"pharmacy"}})
+		require.NoError(t, err)
+		require.Len(t, stores, 1)
+		require.Equal(t, ids[0], stores[0].ID)
+
+		// updates replace the store's capabilities
+		require.NoError(t, sr.UpdateStore(ctx, ids[0], &stdom.UpdateStoreQuery{Capabilities: []string{"delivery"}}))
+		require.ElementsMatch(t, ids[1:2], offering("pickup"))
+		require.ElementsMatch(t, ids[:1], offering("delivery"))
+	})
+
+	t.Run("closures", func(t *testing.T) {
+		org := run + " Org T"
+		// in the north pacific, jittered so runs don't share address IDs
+		lat, lon := 31.2+float64(time.Now().UnixNano()%10000)*1e-7, -160.8
+		area := geodom.NewGeoJSONMultiPolygon([]geodom.Polygon{geodom.BoundsPolygon(&geodom.BoundingBox{
+			MinLat: lat - 0.1, MinLon: lon - 0.1, MaxLat: lat + 0.1, MaxLon: lon + 0.1,
+		})})
+		ids := []string{}
+		for i := range 3 {
+			id, err := sr.AddStore(ctx, &stdom.Store{
+				Name:        fmt.Sprintf("%s Closing %d", run, i),
+				Org:         org,
+				AddressId:   geodom.EncodeAddressId(lat+float64(i)*0.001, lon, geodom.DEFAULT_ADDRESS_ID_PRECISION),
+				ServiceArea: area,
+			})
+			require.NoError(t, err, i)
+			ids = append(ids, id)
+		}
+		defer func() {
+			for _, id := range ids {
+				require.NoError(t, sr.DeleteStore(ctx, id, nil))
+			}
+		}()
+
+		// far in the past, clear of real schedules
+		base := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(time.Now().UnixNano()%1000) * time.Second)
+		until := base.Add(48 * time.Hour)
+		closure := &stdom.StoreClosure{ID: "c1", From: base, Until: &until, Reason: "renovation"}
+		for i, id := range ids[:2] {
+			changeAt := base.Add(time.Duration(1-i) * time.Hour)
+			require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{
+				Status:   stdom.STORE_TEMPORARILY_CLOSED,
+				Schedule: &stdom.StoreSchedule{Closures: []*stdom.StoreClosure{closure}, StatusChangeAt: &changeAt},
+			}))
+		}
+
+		st, err := sr.GetStore(ctx, ids[0])
+		require.NoError(t, err)
+		require.Equal(t, stdom.STORE_TEMPORARILY_CLOSED, st.Status)
+		require.Len(t, st.Closures, 1)
+		require.Equal(t, "renovation", st.Closures[0].Reason)
+		require.True(t, st.Closures[0].From.Equal(base))
+		require.True(t, st.Closures[0].Until.Equal(until))
+		require.True(t, st.StatusChangeAt.Equal(base.Add(time.Hour)))
+
+		due, err := sr.ClaimStatusChangesDue(ctx, base.Add(30*time.Minute), 1, time.Millisecond)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		time.Sleep(10 * time.Millisecond)
+		due, err = sr.ClaimStatusChangesDue(ctx, base.Add(30*time.Minute), 10, time.Millisecond)
+		require.NoError(t, err)
+		require.NotContains(t, storeIDs(due), ids[0])
+		require.Contains(t, storeIDs(due), ids[1])
+		require.NotNil(t, due[slices.Index(storeIDs(due), ids[1])].StatusClaimedUntil)
+		time.Sleep(10 * time.Millisecond)
+
+		// claimed stores are skipped until the lease expires
+		due, err = sr.ClaimStatusChangesDue(ctx, base.Add(time.Hour), 100, time.Minute)
+		require.NoError(t, err)
+		dueIDs := storeIDs(due)
+		require.Contains(t, dueIDs, ids[0])
+		require.Less(t, slices.Index(dueIDs, ids[1]), slices.Index(dueIDs, ids[0]))
+		due, err = sr.ClaimStatusChangesDue(ctx, base.Add(time.Hour), 100, time.Minute)
+		require.NoError(t, err)
+		require.NotContains(t, storeIDs(due), ids[0])
+		require.NotContains(t, storeIDs(due), ids[1])
+		// or the store is rescheduled
+		changeAt := base.Add(time.Hour)
+		require.NoError(t, sr.UpdateStore(ctx, ids[0], &stdom.UpdateStoreQuery{
+			Schedule: &stdom.StoreSchedule{Closures: []*stdom.StoreClosure{closure}, StatusChangeAt: &changeAt},
+		}))
+		due, err = sr.ClaimStatusChangesDue(ctx, base.Add(time.Hour), 100, time.Minute)
+		require.NoError(t, err)
+		require.Contains(t, storeIDs(due), ids[0])
+		_, err = sr.ClaimStatusChangesDue(ctx, base, 0, time.Minute)
+		require.ErrorIs(t, err, strepo.ErrMissingRequired)
+		_, err = sr.ClaimStatusChangesDue(ctx, base, 10, 0)
+		require.ErrorIs(t, err, strepo.ErrMissingRequired)
+
+		// temporarily closed stores are left out unless included
+		res, err := sr.SearchStores(ctx, &stdom.SearchStoreQuery{Org: org})
+		require.NoError(t, err)
+		require.Equal(t, ids[2:], storeIDs(res.Stores))
+		res, err = sr.SearchStores(ctx, &stdom.SearchStoreQuery{Org: org, IncludeTemporarilyClosed: true})
+		require.NoError(t, err)
+		require.Equal(t, ids, storeIDs(res.Stores))
+		stores, err := sr.FindServingStores(ctx, &stdom.ServingStoresQuery{Org: org, Latitude: lat, Longitude: lon})
+		require.NoError(t, err)
+		require.Equal(t, ids[2:], storeIDs(stores))
+		stores, err = sr.FindServingStores(ctx, &stdom.ServingStoresQuery{Org: org, Latitude: lat, Longitude: lon, IncludeTemporarilyClosed: true})
+		require.NoError(t, err)
+		require.Equal(t, ids, storeIDs(stores))
+
+		// empty schedules clear the closures
+		require.NoError(t, sr.UpdateStore(ctx, ids[0], &stdom.UpdateStoreQuery{Status: stdom.STORE_ACTIVE, Schedule: &stdom.StoreSchedule{}}))
+		st, err = sr.GetStore(ctx, ids[0])
+		require.NoError(t, err)
+		require.Equal(t, stdom.STORE_ACTIVE, st.Status)
+		require.Empty(t, st.Closures)
+		require.Nil(t, st.StatusChangeAt)
+		due, err = sr.ClaimStatusChangesDue(ctx, base.Add(time.Hour), 10, time.Minute)
+		require.NoError(t, err)
+		require.NotContains(t, storeIDs(due), ids[0])
+	})
+
+	t.Run("conditional updates", func(t *testing.T) {
+		id, err := sr.AddStore(ctx, &stdom.Store{Name: run + " Versioned", Org: run + " Org V", AddressId: addr("v0")})
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, sr.DeleteStore(ctx, id, nil))
+		}()
+
+		st, err := sr.GetStore(ctx, id)
+		require.NoError(t, err)
+		version := st.Version
+		require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{Description: "first", IfVersion: &version}))
+		st, err = sr.GetStore(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, version+1, st.Version)
+
+		// the store changed since the version
+		err = sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{Description: "stale", IfVersion: &version})
+		require.ErrorIs(t, err, strepo.ErrStoreConflict)
+		st, err = sr.GetStore(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, "first", st.Description)
+
+		// unconditional updates count too
+		require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{Description: "second"}))
+		st, err = sr.GetStore(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, version+2, st.Version)
+
+		// writes checked against a parent chain count an update of each store in it,
+		// failing when one changed since
+		parentID, err := sr.AddStore(ctx, &stdom.Store{Name: run + " Versioned Parent", Org: run + " Org V", AddressId: addr("v1")})
+		require.NoError(t, err)
+		childID := ""
+		defer func() {
+			if childID != "" {
+				require.NoError(t, sr.DeleteStore(ctx, childID, nil))
+			}
+			require.NoError(t, sr.DeleteStore(ctx, parentID, nil))
+		}()
+		parent, err := sr.GetStore(ctx, parentID)
+		require.NoError(t, err)
+		stale := []*stdom.StoreVersion{{ID: parentID, Version: parent.Version}}
+		require.NoError(t, sr.UpdateStore(ctx, parentID, &stdom.UpdateStoreQuery{Description: "parent"}))
+		parent, err = sr.GetStore(ctx, parentID)
+		require.NoError(t, err)
+		_, err = sr.AddStore(ctx, &stdom.Store{Name: run + " Versioned Child", Org: run + " Org V", AddressId: addr("v2"), ParentID: parentID, ParentChain: stale})
+		require.ErrorIs(t, err, strepo.ErrStoreConflict)
+		childID, err = sr.AddStore(ctx, &stdom.Store{
+			Name:        run + " Versioned Child",
+			Org:         run + " Org V",
+			AddressId:   addr("v2"),
+			ParentID:    parentID,
+			ParentChain: []*stdom.StoreVersion{{ID: parentID, Version: parent.Version}},
+		})
+		require.NoError(t, err)
+		parent, err = sr.GetStore(ctx, parentID)
+		require.NoError(t, err)
+		err = sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{ParentID: parentID, ParentChain: stale})
+		require.ErrorIs(t, err, strepo.ErrStoreConflict)
+		require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{DetachParent: true, ParentChain: []*stdom.StoreVersion{{ID: parentID, Version: parent.Version}}}))
+		st, err = sr.GetStore(ctx, parentID)
+		require.NoError(t, err)
+		require.Equal(t, parent.Version+1, st.Version)
+
+		// deletes at a version fail once the store changed since
+		delID, err := sr.AddStore(ctx, &stdom.Store{Name: run + " Versioned Delete", Org: run + " Org V", AddressId: addr("v3")})
+		require.NoError(t, err)
+		del, err := sr.GetStore(ctx, delID)
+		require.NoError(t, err)
+		staleVersion := del.Version
+		require.NoError(t, sr.UpdateStore(ctx, delID, &stdom.UpdateStoreQuery{Description: "changed"}))
+		require.ErrorIs(t, sr.DeleteStore(ctx, delID, &staleVersion), strepo.ErrStoreConflict)
+		del, err = sr.GetStore(ctx, delID)
+		require.NoError(t, err)
+		require.NoError(t, sr.DeleteStore(ctx, delID, &del.Version))
+		require.ErrorIs(t, sr.DeleteStore(ctx, delID, &del.Version), strepo.ErrNoStore)
+	})
+
+	t.Run("translations", func(t *testing.T) {
+		org := run + " Org L"
+		id, err := sr.AddStore(ctx, &stdom.Store{
+			Name:        "Corner Bakery",
+			Org:         org,
+			AddressId:   addr("l0"),
+			Description: "Bread and cakes",
+			Locale:      "en-US",
+			Translations: []*stdom.StoreTranslation{
+				{Locale: "fr-CA", Name: "Boulangerie du Coin", Description: "Pains et gâteaux"},
+			},
+		})
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, sr.DeleteStore(ctx, id, nil))
+		}()
+
+		st, err := sr.GetStore(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, "en-US", st.Locale)
+		require.Equal(t, []*stdom.StoreTranslation{
+			{Locale: "fr-CA", Name: "Boulangerie du Coin", Description: "Pains et gâteaux"},
+		}, st.Translations)
+
+		search := func(q *stdom.SearchStoreQuery) []string {
+			q.Org = org
+			res, err := sr.SearchStores(ctx, q)
+			require.NoError(t, err)
+			return storeIDs(res.Stores)
+		}
+
+		// names match in any locale
+		require.Equal(t, []string{id}, search(&stdom.SearchStoreQuery{Name: "corner"}))
+		require.Equal(t, []string{id}, search(&stdom.SearchStoreQuery{Name: "boulangerie"}))
+		require.Equal(t, []string{id}, search(&stdom.SearchStoreQuery{Name: "Boulangrie du Coin", Fuzzy: true, MinSimilarity: stdom.DEFAULT_FUZZY_MIN_SIMILARITY}))
+		require.Equal(t, []string{id}, search(&stdom.SearchStoreQuery{Query: "gâteaux"}))
+
+		// translations are replaced & cleared, reindexing the names
+		require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{
+			Translations: []*stdom.StoreTranslation{{Locale: "es", Name: "Panadería de la Esquina"}},
+		}))
+		require.Empty(t, search(&stdom.SearchStoreQuery{Name: "boulangerie"}))
+		require.Equal(t, []string{id}, search(&stdom.SearchStoreQuery{Name: "Panaderia de la Esquina", Fuzzy: true, MinSimilarity: 0.9}))
+
+		require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{ClearTranslations: true}))
+		st, err = sr.GetStore(ctx, id)
+		require.NoError(t, err)
+		require.Empty(t, st.Translations)
+		require.Equal(t, "Corner Bakery", st.Name)
+		require.Empty(t, search(&stdom.SearchStoreQuery{Name: "Panaderia de la Esquina", Fuzzy: true, MinSimilarity: 0.9}))
+	})
+
+	t.Run("attachments", func(t *testing.T) {
+		id, err := sr.AddStore(ctx, &stdom.Store{
+			Name:      run + " Gallery",
+			Org:       run + " Org M",
+			AddressId: addr("m0"),
+		})
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, sr.DeleteStore(ctx, id, nil))
+		}()
+
+		createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		logo := &stdom.StoreAttachment{
+			ID: "a1", Kind: stdom.ATTACHMENT_LOGO, URI: "https://cdn.example.com/logo.png",
+			Width: 64, Height: 64, ContentType: "image/png", BlobKey: "stores/" + id + "/a1.png", CreatedAt: createdAt,
+		}
+		photo := &stdom.StoreAttachment{
+			ID: "a2", Kind: stdom.ATTACHMENT_PHOTO, URI: "https://cdn.example.com/front.jpg", CreatedAt: createdAt,
+		}
+		require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{Attachments: []*stdom.StoreAttachment{logo, photo}}))
+		st, err := sr.GetStore(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, st.Attachments, 2)
+		require.Equal(t, "a1", st.Attachments[0].ID)
+		require.Equal(t, logo.BlobKey, st.Attachments[0].BlobKey)
+		require.Equal(t, 64, st.Attachments[0].Width)
+		require.True(t, st.Attachments[0].CreatedAt.Equal(createdAt))
+
+		// replaced in the given order
+		require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{Attachments: []*stdom.StoreAttachment{photo, logo}}))
+		st, err = sr.GetStore(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, "a2", st.Attachments[0].ID)
+		require.Equal(t, "a1", st.Attachments[1].ID)
+
+		require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{ClearAttachments: true}))
+		st, err = sr.GetStore(ctx, id)
+		require.NoError(t, err)
+		require.Empty(t, st.Attachments)
+	})
+
+	t.Run("nearest", func(t *testing.T) {
+		org := run + " Org N"
+		// in the southern ocean, jittered so runs don't share address IDs
+		lat, lon := -62.3+float64(time.Now().UnixNano()%10000)*1e-7, 102.3
+		ids := []string{}
+		for i, pt := range [][2]float64{{lat + 0.3, lon}, {lat + 0.01, lon}, {lat + 0.03, lon}} {
+			id, err := sr.AddStore(ctx, &stdom.Store{
+				Name:      fmt.Sprintf("%s Nearby %d", run, i),
+				Org:       org,
+				AddressId: geodom.EncodeAddressId(pt[0], pt[1], geodom.DEFAULT_ADDRESS_ID_PRECISION),
+			})
+			require.NoError(t, err, i)
+			ids = append(ids, id)
+		}
+		// unlocated stores aren't near anything
+		id, err := sr.AddStore(ctx, &stdom.Store{Name: run + " Nearby Unlocated", Org: org, AddressId: run + "-nearby"})
+		require.NoError(t, err)
+		ids = append(ids, id)
+		defer func() {
+			for _, id := range ids {
+				require.NoError(t, sr.DeleteStore(ctx, id, nil))
+			}
+		}()
+
+		nearest := func(params *stdom.SearchStoreQuery) []*stdom.Store {
+			params.Org = org
+			res, err := sr.SearchStores(ctx, params)
+			require.NoError(t, err)
+			require.Equal(t, len(res.Stores), res.Total)
+			return res.Stores
+		}
+		stores := nearest(&stdom.SearchStoreQuery{Near: &stdom.NearPoint{Latitude: lat, Longitude: lon}})
+		require.Len(t, stores, 3)
+		require.Equal(t, []string{ids[1], ids[2], ids[0]}, []string{stores[0].ID, stores[1].ID, stores[2].ID})
+		require.InDelta(t, 1112, stores[0].Distance, 100)
+		require.InDelta(t, 3336, stores[1].Distance, 100)
+
+		stores = nearest(&stdom.SearchStoreQuery{Near: &stdom.NearPoint{Latitude: lat, Longitude: lon}, Limit: 1})
+		require.Len(t, stores, 1)
+		require.Equal(t, ids[1], stores[0].ID)
+
+		stores = nearest(&stdom.SearchStoreQuery{Near: &stdom.NearPoint{Latitude: lat, Longitude: lon, MaxDistance: 5000}})
+		require.Len(t, stores, 2)
+
+		stores = nearest(&stdom.SearchStoreQuery{Name: fmt.Sprintf("%s Nearby 0", run), Near: &stdom.NearPoint{Latitude: lat, Longitude: lon}})
+		require.Len(t, stores, 1)
+		require.Equal(t, ids[0], stores[0].ID)
+	})
+}
+
+// runAddressUniquenessConformance checks a StoresRepo enforces its address uniqueness rule.
+func runAddressUniquenessConformance(t *testing.T, ctx context.Context, sr stdom.StoresRepo, uniq stdom.AddressUniqueness) {
+	run := fmt.Sprintf("Uniq%d", time.Now().UnixNano())
+	addr := func(s string) string { return run + "-" + s }
+
+	ids := []string{}
+	defer func() {
+		for _, id := range ids {
+			require.NoError(t, sr.DeleteStore(ctx, id, nil))
+		}
+	}()
+	add := func(name, org, addressId string) (string, error) {
+		id, err := sr.AddStore(ctx, &stdom.Store{Name: run + " " + name, Org: run + " " + org, AddressId: addressId})
+		if err == nil {
+			ids = append(ids, id)
+		}
+		return id, err
+	}
+	requireDuplicate := func(err error, existingID string) {
+		t.Helper()
+		require.ErrorIs(t, err, strepo.ErrDuplicateStore)
+		var dupErr *stdom.DuplicateStoreError
+		require.ErrorAs(t, err, &dupErr)
+		require.Equal(t, existingID, dupErr.ExistingID)
+		require.Contains(t, err.Error(), existingID)
+	}
+
+	firstID, err := add("First Store", "Org A", addr("a1"))
+	require.NoError(t, err)
+	otherID, err := add("Other Store", "Org B", addr("b1"))
+	require.NoError(t, err)
+
+	// same org, same address
+	_, err = add("Same Org Store", "Org A", addr("a1"))
+	switch uniq {
+	case stdom.ADDRESS_UNIQUE_NONE:
+		require.NoError(t, err)
+	default:
+		requireDuplicate(err, firstID)
+	}
+
+	// other org, same address
+	otherOrgID, err := add("Other Org Store", "Org B", addr("a1"))
+	switch uniq {
+	case stdom.ADDRESS_UNIQUE_GLOBAL:
+		requireDuplicate(err, firstID)
+	default:
+		require.NoError(t, err)
+	}
+
+	// moving a store onto a used address
+	err = sr.UpdateStore(ctx, otherID, &stdom.UpdateStoreQuery{AddressId: addr("a1")})
+	switch uniq {
+	case stdom.ADDRESS_UNIQUE_GLOBAL:
+		requireDuplicate(err, firstID)
+	case stdom.ADDRESS_UNIQUE_ORG:
+		requireDuplicate(err, otherOrgID)
+	default:
+		require.NoError(t, err)
+	}
+
+	// moving a store into an org using its address
+	err = sr.UpdateStore(ctx, firstID, &stdom.UpdateStoreQuery{Org: run + " Org B"})
+	switch uniq {
+	case stdom.ADDRESS_UNIQUE_ORG:
+		requireDuplicate(err, otherOrgID)
+	default:
+		require.NoError(t, err)
+	}
+}
+
+func storeIDs(stores []*stdom.Store) []string {
+	ids := []string{}
+	for _, st := range stores {
+		ids = append(ids, st.ID)
+	}
+	return ids
+}
@@ -0,0 +1,68 @@
+package stores
+
+import (
+	"sort"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+// pageAndFacet pages already matched & ordered stores, counting the facets of all of them.
+// It's the in-process counterpart of the mongo $facet search pipeline.
+func pageAndFacet(matched []*stdom.Store, params *stdom.SearchStoreQuery) *stdom.SearchStoreResult {
+	result := &stdom.SearchStoreResult{
+		Total: len(matched),
+	}
+
+	if len(params.Facets) > 0 {
+		result.Facets = map[stdom.FacetField][]*stdom.FacetBucket{}
+		for _, field := range params.Facets {
+			counts := map[string]int{}
+			for _, st := range matched {
+				for _, v := range facetValues(st, field) {
+					counts[v]++
+				}
+			}
+			result.Facets[field] = sortedBuckets(counts)
+		}
+	}
+
+	start := min(params.Offset, len(matched))
+	end := len(matched)
+	if params.Limit > 0 {
+		end = min(start+params.Limit, end)
+	}
+	result.Stores = matched[start:end]
+	return result
+}
+
+func facetValues(st *stdom.Store, field stdom.FacetField) []string {
+	switch field {
+	case stdom.FACET_ORG:
+		return []string{st.Org}
+	case stdom.FACET_STATUS:
+		if st.Status == "" {
+			return []string{string(stdom.STORE_ACTIVE)}
+		}
+		return []string{string(st.Status)}
+	case stdom.FACET_TAGS:
+		return st.Tags
+	case stdom.FACET_CAPABILITIES:
+		return st.Capabilities
+	}
+	return nil
+}
+
+// sortedBuckets orders facet buckets by descending count, then value.
+func sortedBuckets(counts map[string]int) []*stdom.FacetBucket {
+	buckets := make([]*stdom.FacetBucket, 0, len(counts))
+	for v, n := range counts {
+		buckets = append(buckets, &stdom.FacetBucket{Value: v, Count: n})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Count != buckets[j].Count {
+			return buckets[i].Count > buckets[j].Count
+		}
+		return buckets[i].Value < buckets[j].Value
+	})
+	return buckets
+}
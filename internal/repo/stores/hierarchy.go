@@ -0,0 +1,45 @@
+package stores
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/comfforts/logger"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+// ListChildStores returns the stores with the parent, in the order they were added.
+func (sr *storesRepo) ListChildStores(ctx context.Context, parentID string) ([]*stdom.Store, error) {
+	ctx, span := startSpan(ctx, "stores.repo.children")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("listing child stores")
+
+	if err := validateID(parentID); err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	cursor, err := sr.Store().Collection(STORES_COLLECTION).Find(ctx, bson.M{"parent_id": parentID}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		l.Error("ListChildStores error", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	children := []*stdom.Store{}
+	if err := cursor.All(ctx, &children); err != nil {
+		l.Error("ListChildStores error decoding stores", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	return children, nil
+}
@@ -0,0 +1,752 @@
+package stores
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/comfforts/logger"
+
+	evdom "github.com/comfforts/comff-stores/internal/domain/events"
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+	"github.com/comfforts/comff-stores/internal/infra/observability"
+	obrepo "github.com/comfforts/comff-stores/internal/repo/outbox"
+)
+
+var (
+	_ stdom.StoresRepo = (*memStoresRepo)(nil)
+	_ evdom.OutboxRepo = (*memStoresRepo)(nil)
+)
+
+// memStoresRepo is an in-memory StoresRepo for tests & local development,
+// with the same semantics as the mongo backed storesRepo, kept in line by
+// the repo conformance suite. Store changes are recorded in an in-memory
+// outbox, so it also serves as the OutboxRepo for the outbox relay.
+type memStoresRepo struct {
+	metrics    observability.Metrics
+	uniqueness stdom.AddressUniqueness
+
+	mu      sync.RWMutex
+	stores  map[string]*stdom.Store
+	order   []string
+	history map[string][]*stdom.AddressChange
+	deleted []*stdom.StoreDeletion
+	outbox  map[string]*evdom.OutboxEntry
+}
+
+// NewMemoryStoresRepo returns an empty in-memory stores repo enforcing the
+// address uniqueness rule, global when empty.
+func NewMemoryStoresRepo(ctx context.Context, mt observability.Metrics, uniq stdom.AddressUniqueness) (*memStoresRepo, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if uniq == "" {
+		uniq = stdom.ADDRESS_UNIQUE_GLOBAL
+	}
+	if !uniq.Valid() {
+		l.Error("error initializing in-memory stores repo", "error", ERR_INVALID_UNIQUENESS, "uniqueness", uniq)
+		return nil, ErrInvalidUniqueness
+	}
+
+	l.Info("initialized in-memory stores repo", "uniqueness", uniq)
+	return &memStoresRepo{
+		metrics:    mt,
+		uniqueness: uniq,
+		stores:     map[string]*stdom.Store{},
+		history:    map[string][]*stdom.AddressChange{},
+		outbox:     map[string]*evdom.OutboxEntry{},
+	}, nil
+}
+
+func (mr *memStoresRepo) AddStore(ctx context.Context, st *stdom.Store) (string, error) {
+	ctx, span := startSpan(ctx, "stores.memrepo.add")
+	defer span.End()
+
+	if st == nil || st.AddressId == "" || st.Name == "" || st.Org == "" {
+		finishSpan(span, ErrMissingRequired)
+		return "", ErrMissingRequired
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if err := mr.addressTaken(st.AddressId, st.Org, ""); err != nil {
+		finishSpan(span, err)
+		return "", err
+	}
+	if err := mr.touchVersions(st.ParentChain); err != nil {
+		finishSpan(span, err)
+		return "", err
+	}
+
+	added := *st
+	added.ParentChain = nil
+	added.ID = primitive.NewObjectID().Hex()
+	added.Address = nil
+	added.Score = 0
+	added.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+	added.Location = storeLocation(added.AddressId)
+	if added.Status == "" {
+		added.Status = stdom.STORE_ACTIVE
+	}
+	added.NameTrigrams = stdom.StoreNameTrigrams(&added)
+	mr.stores[added.ID] = &added
+	mr.order = append(mr.order, added.ID)
+	mr.appendAddressChange(added.ID, added.AddressId, "")
+	mr.appendEvent(evdom.STORE_ADDED, &added, "")
+	return added.ID, nil
+}
+
+func (mr *memStoresRepo) GetStore(ctx context.Context, idHex string) (*stdom.Store, error) {
+	ctx, span := startSpan(ctx, "stores.memrepo.get")
+	defer span.End()
+
+	if err := validateID(idHex); err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	st, ok := mr.stores[idHex]
+	if !ok {
+		finishSpan(span, ErrNoStore)
+		return nil, ErrNoStore
+	}
+	cp := *st
+	return &cp, nil
+}
+
+func (mr *memStoresRepo) DeleteStore(ctx context.Context, idHex string, ifVersion *int64) error {
+	ctx, span := startSpan(ctx, "stores.memrepo.delete")
+	defer span.End()
+
+	if err := validateID(idHex); err != nil {
+		finishSpan(span, err)
+		return err
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	st, ok := mr.stores[idHex]
+	if !ok {
+		finishSpan(span, ErrNoStore)
+		return ErrNoStore
+	}
+	if ifVersion != nil && *ifVersion != st.Version {
+		finishSpan(span, ErrStoreConflict)
+		return ErrStoreConflict
+	}
+	delete(mr.stores, idHex)
+	for i, id := range mr.order {
+		if id == idHex {
+			mr.order = append(mr.order[:i], mr.order[i+1:]...)
+			break
+		}
+	}
+	mr.deleted = append(mr.deleted, &stdom.StoreDeletion{
+		ID:        primitive.NewObjectID().Hex(),
+		StoreID:   st.ID,
+		Org:       st.Org,
+		AddressId: st.AddressId,
+		CreatedAt: st.CreatedAt,
+		DeletedAt: time.Now().UTC(),
+	})
+	mr.appendEvent(evdom.STORE_DELETED, st, "")
+	return nil
+}
+
+func (mr *memStoresRepo) UpdateStore(ctx context.Context, idHex string, params *stdom.UpdateStoreQuery) error {
+	ctx, span := startSpan(ctx, "stores.memrepo.update")
+	defer span.End()
+
+	if err := validateID(idHex); err != nil {
+		finishSpan(span, err)
+		return err
+	}
+	if params == nil || (params.Name == "" && params.Org == "" && params.AddressId == "" && params.Description == "" && len(params.Tags) == 0 && params.Status == "" && params.ServiceArea == nil && params.ParentID == "" && params.Region == "" && !params.DetachParent && len(params.Capabilities) == 0 && params.Schedule == nil && params.Locale == "" && len(params.Translations) == 0 && !params.ClearTranslations && len(params.Attachments) == 0 && !params.ClearAttachments) {
+		finishSpan(span, ErrMissingRequired)
+		return ErrMissingRequired
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	st, ok := mr.stores[idHex]
+	if !ok {
+		finishSpan(span, ErrNoStore)
+		return ErrNoStore
+	}
+
+	if params.IfVersion != nil && *params.IfVersion != st.Version {
+		finishSpan(span, ErrStoreConflict)
+		return ErrStoreConflict
+	}
+	updated := stdom.ApplyUpdate(st, params)
+	if err := mr.addressTaken(updated.AddressId, updated.Org, idHex); err != nil {
+		finishSpan(span, err)
+		return err
+	}
+	if err := mr.touchVersions(params.ParentChain); err != nil {
+		finishSpan(span, err)
+		return err
+	}
+	if updated.AddressId != st.AddressId {
+		mr.appendAddressChange(idHex, updated.AddressId, st.AddressId)
+	}
+	mr.stores[idHex] = updated
+	prevOrg := ""
+	if updated.Org != st.Org {
+		prevOrg = st.Org
+	}
+	mr.appendEvent(evdom.STORE_UPDATED, updated, prevOrg)
+	return nil
+}
+
+// touchVersions counts an update of each of the stores still at their versions, all or
+// none of them.
+func (mr *memStoresRepo) touchVersions(chain []*stdom.StoreVersion) error {
+	for _, sv := range chain {
+		if st, ok := mr.stores[sv.ID]; !ok || st.Version != sv.Version {
+			return ErrStoreConflict
+		}
+	}
+	for _, sv := range chain {
+		touched := *mr.stores[sv.ID]
+		touched.Version++
+		mr.stores[sv.ID] = &touched
+	}
+	return nil
+}
+
+func (mr *memStoresRepo) SearchStores(ctx context.Context, params *stdom.SearchStoreQuery) (*stdom.SearchStoreResult, error) {
+	ctx, span := startSpan(ctx, "stores.memrepo.search")
+	defer span.End()
+
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	fuzzy := params.Fuzzy && params.Name != ""
+	var trigrams []string
+	if fuzzy {
+		trigrams = stdom.NameTrigrams(params.Name)
+	}
+
+	var storesList []*stdom.Store
+	var overlaps []int
+	for _, id := range mr.order {
+		st := mr.stores[id]
+		if !hasPrefixFold(st.Org, params.Org) ||
+			(!fuzzy && !slices.ContainsFunc(stdom.StoreNames(st), func(name string) bool {
+				return hasPrefixFold(name, params.Name)
+			})) ||
+			!hasPrefixFold(st.AddressId, params.AddressId) {
+			continue
+		}
+		if params.Region != "" && !stdom.InRegion(st.Region, params.Region) {
+			continue
+		}
+		if !hasAll(st.Capabilities, params.Capabilities) {
+			continue
+		}
+		if !params.IncludeTemporarilyClosed && st.Status == stdom.STORE_TEMPORARILY_CLOSED {
+			continue
+		}
+		if len(params.Within) > 0 &&
+			(st.Location == nil || !geodom.AreaContains(params.Within, st.Location.Coordinates[1], st.Location.Coordinates[0])) {
+			continue
+		}
+		cp := *st
+		if near := params.Near; near != nil {
+			if st.Location == nil {
+				continue
+			}
+			cp.Distance = geodom.DistanceMeters(near.Latitude, near.Longitude, st.Location.Coordinates[1], st.Location.Coordinates[0])
+			if near.MaxDistance > 0 && cp.Distance > near.MaxDistance {
+				continue
+			}
+		}
+		if fuzzy {
+			overlap := stdom.TrigramOverlap(st.NameTrigrams, trigrams)
+			if overlap < stdom.MinTrigramOverlap(trigrams) {
+				continue
+			}
+			overlaps = append(overlaps, overlap)
+		}
+		if params.Query != "" {
+			if cp.Score = textScore(st, params.Query); cp.Score == 0 {
+				continue
+			}
+		}
+		storesList = append(storesList, &cp)
+	}
+	if fuzzy {
+		storesList = fuzzyScored(topCandidates(storesList, overlaps), params)
+	}
+	if params.Query != "" || fuzzy {
+		sortByScore(storesList)
+	}
+	if params.Near != nil {
+		sortByDistance(storesList)
+		result := pageAndFacet(storesList, &stdom.SearchStoreQuery{Limit: params.Limit, Offset: params.Offset})
+		result.Total = len(result.Stores)
+		return result, nil
+	}
+	return pageAndFacet(storesList, params), nil
+}
+
+// topCandidates keeps the FUZZY_MAX_CANDIDATES fuzzy candidates sharing the most trigrams,
+// in their original order.
+func topCandidates(candidates []*stdom.Store, overlaps []int) []*stdom.Store {
+	if len(candidates) <= stdom.FUZZY_MAX_CANDIDATES {
+		return candidates
+	}
+	idx := make([]int, len(candidates))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return overlaps[idx[i]] > overlaps[idx[j]]
+	})
+	idx = idx[:stdom.FUZZY_MAX_CANDIDATES]
+	sort.Ints(idx)
+
+	top := make([]*stdom.Store, 0, len(idx))
+	for _, i := range idx {
+		top = append(top, candidates[i])
+	}
+	return top
+}
+
+// fuzzyScored scores the candidates, keeping those at least as similar as the threshold.
+func fuzzyScored(candidates []*stdom.Store, params *stdom.SearchStoreQuery) []*stdom.Store {
+	scored := []*stdom.Store{}
+	for _, st := range candidates {
+		if st.Score = stdom.StoreNameSimilarity(params.Name, st); st.Score >= params.MinSimilarity {
+			scored = append(scored, st)
+		}
+	}
+	return scored
+}
+
+func (mr *memStoresRepo) GetAddressHistory(ctx context.Context, idHex string) ([]*stdom.AddressChange, error) {
+	ctx, span := startSpan(ctx, "stores.memrepo.address_history")
+	defer span.End()
+
+	if err := validateID(idHex); err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	changes := make([]*stdom.AddressChange, 0, len(mr.history[idHex]))
+	for _, ch := range mr.history[idHex] {
+		cp := *ch
+		changes = append(changes, &cp)
+	}
+	return changes, nil
+}
+
+func (mr *memStoresRepo) GetStoreStats(ctx context.Context, params *stdom.StoreStatsQuery) (*stdom.StoreStats, error) {
+	ctx, span := startSpan(ctx, "stores.memrepo.stats")
+	defer span.End()
+
+	if err := validateStatsQuery(params); err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	series, buckets := newStatsSeries(params)
+	inRange := func(t time.Time) (*stdom.StatsBucket, bool) {
+		if t.Before(params.From) || !t.Before(params.To) {
+			return nil, false
+		}
+		b, ok := buckets[params.Interval.BucketStart(t).Unix()]
+		return b, ok
+	}
+
+	stats := &stdom.StoreStats{Series: series}
+	orgs, regions := map[string]int{}, map[string]int{}
+	for _, st := range mr.stores {
+		if params.Org != "" && st.Org != params.Org {
+			continue
+		}
+		stats.Total++
+		orgs[st.Org]++
+		if params.RegionPrecision > 0 {
+			region := []rune(st.AddressId)
+			regions[string(region[:min(params.RegionPrecision, len(region))])]++
+		}
+		if b, ok := inRange(st.CreatedAt); ok {
+			b.Added++
+		}
+	}
+	for _, del := range mr.deleted {
+		if params.Org != "" && del.Org != params.Org {
+			continue
+		}
+		if b, ok := inRange(del.CreatedAt); ok {
+			b.Added++
+		}
+		if b, ok := inRange(del.DeletedAt); ok {
+			b.Deleted++
+		}
+	}
+
+	stats.Orgs = statsCounts(orgs)
+	if params.RegionPrecision > 0 {
+		stats.Regions = regionCounts(regions)
+	}
+	return stats, nil
+}
+
+func (mr *memStoresRepo) ClusterStores(ctx context.Context, params *stdom.ClusterStoresQuery) ([]*stdom.StoreCluster, error) {
+	ctx, span := startSpan(ctx, "stores.memrepo.cluster")
+	defer span.End()
+
+	if err := validateClusterQuery(params); err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	clusters := []*stdom.StoreCluster{}
+	cells := map[string]*clusterCell{}
+	precision := cellPrecision(params)
+	for _, id := range mr.order {
+		st := mr.stores[id]
+		if params.Org != "" && st.Org != params.Org {
+			continue
+		}
+		if params.Individual {
+			if len(clusters) == params.Limit {
+				break
+			}
+			cp := *st
+			if c, ok := storeCluster(&cp, params.Bounds); ok {
+				clusters = append(clusters, c)
+			}
+			continue
+		}
+
+		prefix := st.AddressId[:min(precision, len(st.AddressId))]
+		cell, ok := cells[prefix]
+		if !ok {
+			cell = &clusterCell{Prefix: prefix}
+			cells[prefix] = cell
+		}
+		cell.Count++
+		if len(cell.IDs) < CLUSTER_SAMPLE_SIZE {
+			cell.IDs = append(cell.IDs, st.ID)
+		}
+	}
+	if params.Individual {
+		return clusters, nil
+	}
+
+	cellList := make([]*clusterCell, 0, len(cells))
+	for _, cell := range cells {
+		cellList = append(cellList, cell)
+	}
+	return rollupClusters(cellList, params), nil
+}
+
+func (mr *memStoresRepo) FindServingStores(ctx context.Context, params *stdom.ServingStoresQuery) ([]*stdom.Store, error) {
+	ctx, span := startSpan(ctx, "stores.memrepo.serving")
+	defer span.End()
+
+	if err := validateServingQuery(params); err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	stores := []*stdom.Store{}
+	for _, id := range mr.order {
+		st := mr.stores[id]
+		if (params.Org != "" && st.Org != params.Org) ||
+			!hasAll(st.Capabilities, params.Capabilities) ||
+			(!params.IncludeTemporarilyClosed && st.Status == stdom.STORE_TEMPORARILY_CLOSED) ||
+			st.ServiceArea == nil ||
+			!geodom.AreaContains(st.ServiceArea.Coordinates, params.Latitude, params.Longitude) {
+			continue
+		}
+		cp := *st
+		stores = append(stores, &cp)
+	}
+	return stores, nil
+}
+
+func (mr *memStoresRepo) ListChildStores(ctx context.Context, parentID string) ([]*stdom.Store, error) {
+	ctx, span := startSpan(ctx, "stores.memrepo.children")
+	defer span.End()
+
+	if err := validateID(parentID); err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	children := []*stdom.Store{}
+	for _, id := range mr.order {
+		if st := mr.stores[id]; st.ParentID == parentID {
+			cp := *st
+			children = append(children, &cp)
+		}
+	}
+	return children, nil
+}
+
+func (mr *memStoresRepo) ClaimStatusChangesDue(ctx context.Context, at time.Time, limit int, lease time.Duration) ([]*stdom.Store, error) {
+	ctx, span := startSpan(ctx, "stores.memrepo.status_changes")
+	defer span.End()
+
+	if limit <= 0 || lease <= 0 {
+		finishSpan(span, ErrMissingRequired)
+		return nil, ErrMissingRequired
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	now := time.Now().UTC()
+	due := []*stdom.Store{}
+	for _, id := range mr.order {
+		st := mr.stores[id]
+		if st.StatusChangeAt != nil && !st.StatusChangeAt.After(at) &&
+			(st.StatusClaimedUntil == nil || !st.StatusClaimedUntil.After(now)) {
+			due = append(due, st)
+		}
+	}
+	// the most overdue first, stable so ties stay in ID order
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].StatusChangeAt.Before(*due[j].StatusChangeAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimedUntil := now.Add(lease)
+	claimed := []*stdom.Store{}
+	for _, st := range due {
+		cp := *st
+		cp.StatusClaimedUntil = &claimedUntil
+		mr.stores[st.ID] = &cp
+		claimedCp := cp
+		claimed = append(claimed, &claimedCp)
+	}
+	return claimed, nil
+}
+
+func (mr *memStoresRepo) Close(ctx context.Context) error {
+	return nil
+}
+
+func (mr *memStoresRepo) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*evdom.OutboxEntry, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	due := []*evdom.OutboxEntry{}
+	now := time.Now().UTC()
+	for _, e := range mr.outbox {
+		if e.Status == evdom.OUTBOX_PENDING && !e.NextAttemptAt.After(now) && !e.LockedUntil.After(now) {
+			due = append(due, e)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*evdom.OutboxEntry, 0, len(due))
+	for _, e := range due {
+		e.LockedUntil = now.Add(lease)
+		cp := *e
+		claimed = append(claimed, &cp)
+	}
+	return claimed, nil
+}
+
+func (mr *memStoresRepo) MarkDelivered(ctx context.Context, id string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.outbox[id]; !ok {
+		return obrepo.ErrNoEntry
+	}
+	// nothing reads delivered entries, drop them to bound memory
+	delete(mr.outbox, id)
+	return nil
+}
+
+func (mr *memStoresRepo) MarkFailed(ctx context.Context, id string, nextAttemptAt time.Time, lastErr string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	e, ok := mr.outbox[id]
+	if !ok {
+		return obrepo.ErrNoEntry
+	}
+	e.NextAttemptAt = nextAttemptAt.UTC()
+	e.LastError = lastErr
+	e.Attempts++
+	e.LockedUntil = time.Time{}
+	return nil
+}
+
+// appendEvent records a store change in the outbox, prevOrg being the org an update
+// moved the store from. Callers hold the lock.
+func (mr *memStoresRepo) appendEvent(evType evdom.EventType, st *stdom.Store, prevOrg string) {
+	now := time.Now().UTC()
+	id := primitive.NewObjectID().Hex()
+	cp := *st
+	mr.outbox[id] = &evdom.OutboxEntry{
+		ID: id,
+		Event: &evdom.Event{
+			ID:          id,
+			Type:        evType,
+			StoreID:     st.ID,
+			Org:         st.Org,
+			PreviousOrg: prevOrg,
+			Store:       &cp,
+			OccurredAt:  now,
+		},
+		Status:        evdom.OUTBOX_PENDING,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+// appendAddressChange records a store's new address ID, callers hold the lock.
+func (mr *memStoresRepo) appendAddressChange(storeID, addressId, prevAddressId string) {
+	mr.history[storeID] = append(mr.history[storeID], &stdom.AddressChange{
+		ID:                primitive.NewObjectID().Hex(),
+		StoreID:           storeID,
+		AddressId:         addressId,
+		PreviousAddressId: prevAddressId,
+		ChangedAt:         time.Now().UTC(),
+	})
+}
+
+// addressTaken returns a DuplicateStoreError when another store has the address ID
+// under the uniqueness rule, callers hold the lock.
+func (mr *memStoresRepo) addressTaken(addressId, org, exceptID string) error {
+	if mr.uniqueness == stdom.ADDRESS_UNIQUE_NONE {
+		return nil
+	}
+	if mr.uniqueness != stdom.ADDRESS_UNIQUE_ORG {
+		org = ""
+	}
+	for _, id := range mr.order {
+		st := mr.stores[id]
+		if id == exceptID || st.AddressId != addressId || (org != "" && st.Org != org) {
+			continue
+		}
+		return &stdom.DuplicateStoreError{
+			ExistingID: id,
+			AddressId:  addressId,
+			Org:        org,
+		}
+	}
+	return nil
+}
+
+func validateID(idHex string) error {
+	if idHex == "" {
+		return ErrMissingRequired
+	}
+	if _, err := primitive.ObjectIDFromHex(idHex); err != nil {
+		return ErrDecodeRecId
+	}
+	return nil
+}
+
+// textScore approximates the mongo text index: any query term matching a word in name, tags,
+// org, description or their translations scores the field's weight, for each occurrence. Words are matched
+// case-insensitively after stripping plural endings, skipping common stop words.
+func textScore(st *stdom.Store, query string) float64 {
+	terms := map[string]bool{}
+	for _, t := range textWords(query) {
+		terms[t] = true
+	}
+
+	score := 0.0
+	fields := map[string]string{
+		"name":        st.Name,
+		"tags":        strings.Join(st.Tags, " "),
+		"org":         st.Org,
+		"description": st.Description,
+	}
+	for _, tr := range st.Translations {
+		fields[TRANSLATION_NAMES_FIELD] += " " + tr.Name
+		fields[TRANSLATION_DESCRIPTIONS_FIELD] += " " + tr.Description
+	}
+	for field, text := range fields {
+		for _, w := range textWords(text) {
+			if terms[w] {
+				score += float64(textSearchWeights[field])
+			}
+		}
+	}
+	return score
+}
+
+func textWords(s string) []string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	stemmed := words[:0]
+	for _, w := range words {
+		if textStopWords[w] {
+			continue
+		}
+		if len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") {
+			w = w[:len(w)-1]
+		}
+		stemmed = append(stemmed, w)
+	}
+	return stemmed
+}
+
+var textStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "at": true, "by": true, "for": true,
+	"in": true, "of": true, "on": true, "or": true, "the": true, "to": true,
+}
+
+// hasAll reports whether values has every one of wanted.
+func hasAll(values, wanted []string) bool {
+	for _, w := range wanted {
+		if !slices.Contains(values, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(s), strings.ToLower(prefix))
+}
@@ -0,0 +1,76 @@
+package stores_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/comfforts/logger"
+
+	evdom "github.com/comfforts/comff-stores/internal/domain/events"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+	strepo "github.com/comfforts/comff-stores/internal/repo/stores"
+)
+
+func TestMemoryStoresRepoConformance(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+
+	sr, err := strepo.NewMemoryStoresRepo(ctx, nil, stdom.ADDRESS_UNIQUE_GLOBAL)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, sr.Close(ctx))
+	}()
+
+	runStoresRepoConformance(t, ctx, sr)
+}
+
+func TestMemoryStoresRepoAddressUniqueness(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+
+	for _, uniq := range []stdom.AddressUniqueness{stdom.ADDRESS_UNIQUE_GLOBAL, stdom.ADDRESS_UNIQUE_ORG, stdom.ADDRESS_UNIQUE_NONE} {
+		t.Run(string(uniq), func(t *testing.T) {
+			sr, err := strepo.NewMemoryStoresRepo(ctx, nil, uniq)
+			require.NoError(t, err)
+			runAddressUniquenessConformance(t, ctx, sr, uniq)
+		})
+	}
+
+	_, err := strepo.NewMemoryStoresRepo(ctx, nil, "regional")
+	require.ErrorIs(t, err, strepo.ErrInvalidUniqueness)
+}
+
+func TestMemoryStoresOutbox(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.GetSlogLogger())
+
+	sr, err := strepo.NewMemoryStoresRepo(ctx, nil, stdom.ADDRESS_UNIQUE_GLOBAL)
+	require.NoError(t, err)
+
+	id, err := sr.AddStore(ctx, &stdom.Store{Name: "Outbox Store", Org: "Test Org", AddressId: "dacdbddabcadccbdacac"})
+	require.NoError(t, err)
+	require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{Name: "Updated Outbox Store"}))
+	require.NoError(t, sr.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{Org: "Other Org"}))
+	require.NoError(t, sr.DeleteStore(ctx, id, nil))
+
+	entries, err := sr.ClaimPending(ctx, 100, time.Minute)
+	require.NoError(t, err)
+	evTypes, prevOrgs := []evdom.EventType{}, []string{}
+	for _, entry := range entries {
+		require.Equal(t, id, entry.Event.StoreID)
+		evTypes = append(evTypes, entry.Event.Type)
+		prevOrgs = append(prevOrgs, entry.Event.PreviousOrg)
+	}
+	require.ElementsMatch(t, []evdom.EventType{evdom.STORE_ADDED, evdom.STORE_UPDATED, evdom.STORE_UPDATED, evdom.STORE_DELETED}, evTypes)
+	// only the update moving the store to another org has its previous org
+	require.ElementsMatch(t, []string{"", "", "Test Org", ""}, prevOrgs)
+
+	// leased entries aren't claimed again
+	again, err := sr.ClaimPending(ctx, 100, time.Minute)
+	require.NoError(t, err)
+	require.Empty(t, again)
+
+	for _, entry := range entries {
+		require.NoError(t, sr.MarkDelivered(ctx, entry.ID))
+	}
+}
@@ -0,0 +1,513 @@
+package stores
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	indom "github.com/comfforts/comff-stores/internal/domain/infra"
+	migdom "github.com/comfforts/comff-stores/internal/domain/migrations"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+const (
+	ADDRESS_HISTORY_INDEX = "store_id_1_changed_at_1"
+	STORES_TEXT_INDEX     = "stores_text"
+	NAME_TRIGRAMS_INDEX   = "name_trigrams_1"
+	ORG_CREATED_AT_INDEX  = "org_1_created_at_1"
+	ORG_DELETED_AT_INDEX  = "org_1_deleted_at_1"
+	LOCATION_INDEX        = "location_2dsphere"
+	SERVICE_AREA_INDEX    = "service_area_2dsphere"
+	PARENT_INDEX          = "parent_id_1"
+	REGION_INDEX          = "region_1"
+	CAPABILITIES_INDEX    = "capabilities_1"
+	STATUS_CHANGE_INDEX   = "status_change_at_1"
+)
+
+// translated names & descriptions are text searched as their own fields
+const (
+	TRANSLATION_NAMES_FIELD        = "translations.name"
+	TRANSLATION_DESCRIPTIONS_FIELD = "translations.description"
+)
+
+// text search field weights, name matches rank highest, in any locale
+var textSearchWeights = map[string]int{
+	"name":                         10,
+	"tags":                         5,
+	"org":                          2,
+	"description":                  1,
+	TRANSLATION_NAMES_FIELD:        10,
+	TRANSLATION_DESCRIPTIONS_FIELD: 1,
+}
+
+// text index fields, before & after translations
+var (
+	textIndexFields          = []string{"name", "tags", "org", "description"}
+	localizedTextIndexFields = append(slices.Clone(textIndexFields), TRANSLATION_NAMES_FIELD, TRANSLATION_DESCRIPTIONS_FIELD)
+)
+
+// Migrations are the stores collections' versioned migrations, the address indexes
+// enforcing the uniqueness rule, global when empty.
+func Migrations(uniq stdom.AddressUniqueness) []migdom.Migration {
+	if uniq == "" {
+		uniq = stdom.ADDRESS_UNIQUE_GLOBAL
+	}
+	return []migdom.Migration{
+		{
+			Version: 1,
+			Name:    "address history index",
+			Up: func(ctx context.Context, db indom.DBStore) error {
+				return db.EnsureIndexes(ctx, ADDRESS_HISTORY_COLLECTION, []mongo.IndexModel{
+					{
+						Keys: bson.D{
+							{Key: "store_id", Value: 1},
+							{Key: "changed_at", Value: 1},
+						},
+					},
+				})
+			},
+			Down: func(ctx context.Context, db indom.DBStore) error {
+				_, err := db.Store().Collection(ADDRESS_HISTORY_COLLECTION).Indexes().DropOne(ctx, ADDRESS_HISTORY_INDEX)
+				return ignoreMissingIndex(err)
+			},
+		},
+		{
+			Version: 2,
+			Name:    "backfill address history",
+			Up:      backfillAddressHistory,
+			Down: func(ctx context.Context, db indom.DBStore) error {
+				_, err := db.Store().Collection(ADDRESS_HISTORY_COLLECTION).DeleteMany(ctx, bson.M{"backfilled": true})
+				return err
+			},
+		},
+		{
+			Version: 3,
+			Name:    "stores text index",
+			Up: func(ctx context.Context, db indom.DBStore) error {
+				return ensureTextIndex(ctx, db, textIndexFields)
+			},
+			Down: func(ctx context.Context, db indom.DBStore) error {
+				_, err := db.Store().Collection(STORES_COLLECTION).Indexes().DropOne(ctx, STORES_TEXT_INDEX)
+				return ignoreMissingIndex(err)
+			},
+		},
+		{
+			Version: 4,
+			Name:    "store name trigrams",
+			Up: func(ctx context.Context, db indom.DBStore) error {
+				if err := backfillNameTrigrams(ctx, db); err != nil {
+					return err
+				}
+				return db.EnsureIndexes(ctx, STORES_COLLECTION, []mongo.IndexModel{
+					{
+						Keys:    bson.D{{Key: "name_trigrams", Value: 1}},
+						Options: options.Index().SetName(NAME_TRIGRAMS_INDEX),
+					},
+				})
+			},
+			Down: func(ctx context.Context, db indom.DBStore) error {
+				coll := db.Store().Collection(STORES_COLLECTION)
+				if _, err := coll.Indexes().DropOne(ctx, NAME_TRIGRAMS_INDEX); ignoreMissingIndex(err) != nil {
+					return err
+				}
+				_, err := coll.UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{"name_trigrams": ""}})
+				return err
+			},
+		},
+		{
+			Version: 5,
+			Name:    "store creation times & stats indexes",
+			Up: func(ctx context.Context, db indom.DBStore) error {
+				if err := backfillCreatedAt(ctx, db); err != nil {
+					return err
+				}
+				if err := db.EnsureIndexes(ctx, STORES_COLLECTION, []mongo.IndexModel{
+					{
+						Keys:    bson.D{{Key: "org", Value: 1}, {Key: "created_at", Value: 1}},
+						Options: options.Index().SetName(ORG_CREATED_AT_INDEX),
+					},
+				}); err != nil {
+					return err
+				}
+				return db.EnsureIndexes(ctx, STORE_DELETIONS_COLLECTION, []mongo.IndexModel{
+					{
+						Keys:    bson.D{{Key: "org", Value: 1}, {Key: "deleted_at", Value: 1}},
+						Options: options.Index().SetName(ORG_DELETED_AT_INDEX),
+					},
+				})
+			},
+			// backfilled creation times are the store ID's timestamp, so they're kept
+			Down: func(ctx context.Context, db indom.DBStore) error {
+				if _, err := db.Store().Collection(STORES_COLLECTION).Indexes().DropOne(ctx, ORG_CREATED_AT_INDEX); ignoreMissingIndex(err) != nil {
+					return err
+				}
+				_, err := db.Store().Collection(STORE_DELETIONS_COLLECTION).Indexes().DropOne(ctx, ORG_DELETED_AT_INDEX)
+				return ignoreMissingIndex(err)
+			},
+		},
+		{
+			Version: 6,
+			Name:    "store locations",
+			Up: func(ctx context.Context, db indom.DBStore) error {
+				if err := backfillLocations(ctx, db); err != nil {
+					return err
+				}
+				return db.EnsureIndexes(ctx, STORES_COLLECTION, []mongo.IndexModel{
+					{
+						Keys:    bson.D{{Key: "location", Value: "2dsphere"}},
+						Options: options.Index().SetName(LOCATION_INDEX),
+					},
+				})
+			},
+			Down: func(ctx context.Context, db indom.DBStore) error {
+				coll := db.Store().Collection(STORES_COLLECTION)
+				if _, err := coll.Indexes().DropOne(ctx, LOCATION_INDEX); ignoreMissingIndex(err) != nil {
+					return err
+				}
+				_, err := coll.UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{"location": ""}})
+				return err
+			},
+		},
+		{
+			Version: 7,
+			Name:    "service area index",
+			Up: func(ctx context.Context, db indom.DBStore) error {
+				return db.EnsureIndexes(ctx, STORES_COLLECTION, []mongo.IndexModel{
+					{
+						Keys:    bson.D{{Key: "service_area", Value: "2dsphere"}},
+						Options: options.Index().SetName(SERVICE_AREA_INDEX),
+					},
+				})
+			},
+			// service areas are the stores' own, they're kept
+			Down: func(ctx context.Context, db indom.DBStore) error {
+				_, err := db.Store().Collection(STORES_COLLECTION).Indexes().DropOne(ctx, SERVICE_AREA_INDEX)
+				return ignoreMissingIndex(err)
+			},
+		},
+		{
+			Version: 8,
+			Name:    "store hierarchy indexes",
+			Up: func(ctx context.Context, db indom.DBStore) error {
+				return db.EnsureIndexes(ctx, STORES_COLLECTION, []mongo.IndexModel{
+					{
+						Keys:    bson.D{{Key: "parent_id", Value: 1}},
+						Options: options.Index().SetName(PARENT_INDEX),
+					},
+					{
+						Keys:    bson.D{{Key: "region", Value: 1}},
+						Options: options.Index().SetName(REGION_INDEX),
+					},
+				})
+			},
+			// parents & regions are the stores' own, they're kept
+			Down: func(ctx context.Context, db indom.DBStore) error {
+				indexes := db.Store().Collection(STORES_COLLECTION).Indexes()
+				if _, err := indexes.DropOne(ctx, PARENT_INDEX); ignoreMissingIndex(err) != nil {
+					return err
+				}
+				_, err := indexes.DropOne(ctx, REGION_INDEX)
+				return ignoreMissingIndex(err)
+			},
+		},
+		{
+			Version: 9,
+			Name:    "store capabilities index",
+			Up: func(ctx context.Context, db indom.DBStore) error {
+				return db.EnsureIndexes(ctx, STORES_COLLECTION, []mongo.IndexModel{
+					{
+						Keys:    bson.D{{Key: "capabilities", Value: 1}},
+						Options: options.Index().SetName(CAPABILITIES_INDEX),
+					},
+				})
+			},
+			// capabilities are the stores' own, they're kept
+			Down: func(ctx context.Context, db indom.DBStore) error {
+				_, err := db.Store().Collection(STORES_COLLECTION).Indexes().DropOne(ctx, CAPABILITIES_INDEX)
+				return ignoreMissingIndex(err)
+			},
+		},
+		{
+			Version: 10,
+			Name:    "store status change index",
+			Up: func(ctx context.Context, db indom.DBStore) error {
+				return db.EnsureIndexes(ctx, STORES_COLLECTION, []mongo.IndexModel{
+					{
+						// only stores with scheduled status changes are indexed
+						Keys:    bson.D{{Key: "status_change_at", Value: 1}},
+						Options: options.Index().SetName(STATUS_CHANGE_INDEX).SetSparse(true),
+					},
+				})
+			},
+			// closures are the stores' own, they're kept
+			Down: func(ctx context.Context, db indom.DBStore) error {
+				_, err := db.Store().Collection(STORES_COLLECTION).Indexes().DropOne(ctx, STATUS_CHANGE_INDEX)
+				return ignoreMissingIndex(err)
+			},
+		},
+		{
+			// a collection has one text index, it's rebuilt with the translated fields
+			Version: 11,
+			Name:    "localized stores text index",
+			Up: func(ctx context.Context, db indom.DBStore) error {
+				return rebuildTextIndex(ctx, db, localizedTextIndexFields)
+			},
+			// translations are the stores' own, they're kept
+			Down: func(ctx context.Context, db indom.DBStore) error {
+				return rebuildTextIndex(ctx, db, textIndexFields)
+			},
+		},
+		{
+			// address indexes used to be migrated at startup, by the global rule when unset
+			Version: 12,
+			Name:    "address uniqueness indexes",
+			Up: func(ctx context.Context, db indom.DBStore) error {
+				return migrateAddressIndexes(ctx, db, uniq)
+			},
+			Down: func(ctx context.Context, db indom.DBStore) error {
+				return migrateAddressIndexes(ctx, db, stdom.ADDRESS_UNIQUE_GLOBAL)
+			},
+		},
+	}
+}
+
+// addressIndexes returns the address indexes of the uniqueness rule. address_id is
+// always indexed for search.
+func addressIndexes(uniq stdom.AddressUniqueness) []mongo.IndexModel {
+	switch uniq {
+	case stdom.ADDRESS_UNIQUE_GLOBAL:
+		return []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "address_id", Value: 1}},
+				Options: options.Index().SetName(ADDRESS_UNIQUE_INDEX).SetUnique(true),
+			},
+		}
+	case stdom.ADDRESS_UNIQUE_ORG:
+		return []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "org", Value: 1}, {Key: "address_id", Value: 1}},
+				Options: options.Index().SetName(ORG_ADDRESS_UNIQUE_INDEX).SetUnique(true),
+			},
+			{
+				Keys:    bson.D{{Key: "address_id", Value: 1}},
+				Options: options.Index().SetName(ADDRESS_SEARCH_INDEX),
+			},
+		}
+	default:
+		return []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "address_id", Value: 1}},
+				Options: options.Index().SetName(ADDRESS_SEARCH_INDEX),
+			},
+		}
+	}
+}
+
+// migrateAddressIndexes moves the address indexes to the uniqueness rule, creating the
+// rule's indexes before dropping the other rules', so addresses stay indexed throughout.
+// Tightening the rule fails while existing stores violate it.
+func migrateAddressIndexes(ctx context.Context, db indom.DBStore, uniq stdom.AddressUniqueness) error {
+	wanted := addressIndexes(uniq)
+	if err := db.EnsureIndexes(ctx, STORES_COLLECTION, wanted); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("%w: %w", ErrUniquenessViolations, err)
+		}
+		return err
+	}
+
+	wantedNames := map[string]bool{}
+	for _, idx := range wanted {
+		wantedNames[*idx.Options.Name] = true
+	}
+	indexes := db.Store().Collection(STORES_COLLECTION).Indexes()
+	for _, name := range []string{ADDRESS_UNIQUE_INDEX, ORG_ADDRESS_UNIQUE_INDEX, ADDRESS_SEARCH_INDEX} {
+		if wantedNames[name] {
+			continue
+		}
+		if _, err := indexes.DropOne(ctx, name); ignoreMissingIndex(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasAddressIndexes tells whether the stores collection has exactly the uniqueness rule's
+// address indexes.
+func hasAddressIndexes(ctx context.Context, db indom.DBStore, uniq stdom.AddressUniqueness) (bool, error) {
+	specs, err := db.Store().Collection(STORES_COLLECTION).Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return false, err
+	}
+	have := map[string]bool{}
+	for _, spec := range specs {
+		switch spec.Name {
+		case ADDRESS_UNIQUE_INDEX, ORG_ADDRESS_UNIQUE_INDEX, ADDRESS_SEARCH_INDEX:
+			have[spec.Name] = true
+		}
+	}
+	wanted := addressIndexes(uniq)
+	if len(have) != len(wanted) {
+		return false, nil
+	}
+	for _, idx := range wanted {
+		if !have[*idx.Options.Name] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ensureTextIndex creates the stores text index on the fields, weighted for relevance.
+func ensureTextIndex(ctx context.Context, db indom.DBStore, fields []string) error {
+	keys, weights := bson.D{}, bson.D{}
+	for _, field := range fields {
+		keys = append(keys, bson.E{Key: field, Value: "text"})
+		weights = append(weights, bson.E{Key: field, Value: textSearchWeights[field]})
+	}
+	return db.EnsureIndexes(ctx, STORES_COLLECTION, []mongo.IndexModel{
+		{
+			Keys:    keys,
+			Options: options.Index().SetName(STORES_TEXT_INDEX).SetWeights(weights),
+		},
+	})
+}
+
+// rebuildTextIndex replaces the stores text index with one on the fields.
+func rebuildTextIndex(ctx context.Context, db indom.DBStore, fields []string) error {
+	_, err := db.Store().Collection(STORES_COLLECTION).Indexes().DropOne(ctx, STORES_TEXT_INDEX)
+	if err := ignoreMissingIndex(err); err != nil {
+		return err
+	}
+	return ensureTextIndex(ctx, db, fields)
+}
+
+// backfillNameTrigrams sets the name trigrams of stores written before fuzzy search.
+func backfillNameTrigrams(ctx context.Context, db indom.DBStore) error {
+	coll := db.Store().Collection(STORES_COLLECTION)
+	cur, err := coll.Find(ctx, bson.M{"name_trigrams": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var st struct {
+			ID   primitive.ObjectID `bson:"_id"`
+			Name string             `bson:"name"`
+		}
+		if err := cur.Decode(&st); err != nil {
+			return err
+		}
+		if _, err := coll.UpdateByID(ctx, st.ID, bson.M{"$set": bson.M{"name_trigrams": stdom.NameTrigrams(st.Name)}}); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+// backfillLocations sets the location of stores written before area search from their
+// address ID, stores whose address ID isn't a quadhash are left without one.
+func backfillLocations(ctx context.Context, db indom.DBStore) error {
+	coll := db.Store().Collection(STORES_COLLECTION)
+	cur, err := coll.Find(ctx, bson.M{"location": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var st struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			AddressId string             `bson:"address_id"`
+		}
+		if err := cur.Decode(&st); err != nil {
+			return err
+		}
+		loc := storeLocation(st.AddressId)
+		if loc == nil {
+			continue
+		}
+		if _, err := coll.UpdateByID(ctx, st.ID, bson.M{"$set": bson.M{"location": loc}}); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+// backfillCreatedAt sets the creation time of stores added before it was kept to
+// their ID's timestamp.
+func backfillCreatedAt(ctx context.Context, db indom.DBStore) error {
+	coll := db.Store().Collection(STORES_COLLECTION)
+	cur, err := coll.Find(ctx, bson.M{"created_at": bson.M{"$exists": false}}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var st struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cur.Decode(&st); err != nil {
+			return err
+		}
+		if _, err := coll.UpdateByID(ctx, st.ID, bson.M{"$set": bson.M{"created_at": st.ID.Timestamp().UTC()}}); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+// backfillAddressHistory records the current address of stores added before address
+// history was kept, as changed when the store was added. Backfilled changes are marked
+// so the migration can be reverted.
+func backfillAddressHistory(ctx context.Context, db indom.DBStore) error {
+	cur, err := db.Store().Collection(STORES_COLLECTION).Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	history := db.Store().Collection(ADDRESS_HISTORY_COLLECTION)
+	for cur.Next(ctx) {
+		var st struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			AddressId string             `bson:"address_id"`
+		}
+		if err := cur.Decode(&st); err != nil {
+			return err
+		}
+
+		n, err := history.CountDocuments(ctx, bson.M{"store_id": st.ID.Hex()})
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			continue
+		}
+		if _, err := history.InsertOne(ctx, bson.M{
+			"store_id":   st.ID.Hex(),
+			"address_id": st.AddressId,
+			"changed_at": st.ID.Timestamp().UTC().Truncate(time.Millisecond),
+			"backfilled": true,
+		}); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+func ignoreMissingIndex(err error) error {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Name == "IndexNotFound" {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package stores
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/comfforts/logger"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+// FindServingStores returns the stores with service areas containing the point,
+// matched with $geoIntersects on the service area's 2dsphere index.
+func (sr *storesRepo) FindServingStores(ctx context.Context, params *stdom.ServingStoresQuery) ([]*stdom.Store, error) {
+	ctx, span := startSpan(ctx, "stores.repo.serving")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("finding serving stores")
+
+	if err := validateServingQuery(params); err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	filter := bson.M{"service_area": bson.M{"$geoIntersects": bson.M{
+		"$geometry": bson.M{"type": "Point", "coordinates": bson.A{params.Longitude, params.Latitude}},
+	}}}
+	if params.Org != "" {
+		filter["org"] = params.Org
+	}
+	if len(params.Capabilities) > 0 {
+		filter["capabilities"] = bson.M{"$all": params.Capabilities}
+	}
+	if !params.IncludeTemporarilyClosed {
+		filter["status"] = bson.M{"$ne": stdom.STORE_TEMPORARILY_CLOSED}
+	}
+	cursor, err := sr.Store().Collection(STORES_COLLECTION).Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		l.Error("FindServingStores error", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	stores := []*stdom.Store{}
+	if err := cursor.All(ctx, &stores); err != nil {
+		l.Error("FindServingStores error decoding stores", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	return stores, nil
+}
+
+func validateServingQuery(params *stdom.ServingStoresQuery) error {
+	if params == nil {
+		return ErrMissingRequired
+	}
+	if params.Latitude < -90 || params.Latitude > 90 || params.Longitude < -180 || params.Longitude > 180 {
+		return ErrInvalidServingQuery
+	}
+	return nil
+}
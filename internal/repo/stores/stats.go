@@ -0,0 +1,231 @@
+package stores
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/comfforts/logger"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+type statsCountDoc struct {
+	Key   string `bson:"_id"`
+	Count int    `bson:"count"`
+}
+
+type statsBucketDoc struct {
+	Start time.Time `bson:"_id"`
+	Count int       `bson:"count"`
+}
+
+// GetStoreStats counts current stores by org & region with one $facet aggregation over
+// stores, and buckets additions & deletions with another over stores & deletions, since
+// deleted stores were added too.
+func (sr *storesRepo) GetStoreStats(ctx context.Context, params *stdom.StoreStatsQuery) (*stdom.StoreStats, error) {
+	ctx, span := startSpan(ctx, "stores.repo.stats")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("getting store stats")
+
+	if err := validateStatsQuery(params); err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	match := bson.M{}
+	if params.Org != "" {
+		match["org"] = params.Org
+	}
+	createdIn := bson.M{"created_at": bson.M{"$gte": params.From, "$lt": params.To}}
+	deletedIn := bson.M{"deleted_at": bson.M{"$gte": params.From, "$lt": params.To}}
+
+	storeFacets := bson.M{
+		"total": bson.A{bson.M{"$count": "count"}},
+		"orgs": bson.A{
+			bson.M{"$group": bson.M{"_id": "$org", "count": bson.M{"$sum": 1}}},
+		},
+		"added": bson.A{
+			bson.M{"$match": createdIn},
+			bson.M{"$group": bson.M{"_id": dateTrunc("$created_at", params.Interval), "count": bson.M{"$sum": 1}}},
+		},
+	}
+	if params.RegionPrecision > 0 {
+		storeFacets["regions"] = bson.A{
+			bson.M{"$group": bson.M{
+				"_id":   bson.M{"$substrCP": bson.A{"$address_id", 0, params.RegionPrecision}},
+				"count": bson.M{"$sum": 1},
+			}},
+		}
+	}
+	var current struct {
+		Total []struct {
+			Count int `bson:"count"`
+		} `bson:"total"`
+		Orgs    []statsCountDoc  `bson:"orgs"`
+		Regions []statsCountDoc  `bson:"regions"`
+		Added   []statsBucketDoc `bson:"added"`
+	}
+	if err := sr.aggregateFacets(ctx, STORES_COLLECTION, match, storeFacets, &current); err != nil {
+		l.Error("GetStoreStats error aggregating stores", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	var deleted struct {
+		Added   []statsBucketDoc `bson:"added"`
+		Deleted []statsBucketDoc `bson:"deleted"`
+	}
+	if err := sr.aggregateFacets(ctx, STORE_DELETIONS_COLLECTION, match, bson.M{
+		"added": bson.A{
+			bson.M{"$match": createdIn},
+			bson.M{"$group": bson.M{"_id": dateTrunc("$created_at", params.Interval), "count": bson.M{"$sum": 1}}},
+		},
+		"deleted": bson.A{
+			bson.M{"$match": deletedIn},
+			bson.M{"$group": bson.M{"_id": dateTrunc("$deleted_at", params.Interval), "count": bson.M{"$sum": 1}}},
+		},
+	}, &deleted); err != nil {
+		l.Error("GetStoreStats error aggregating deletions", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	stats := &stdom.StoreStats{}
+	if len(current.Total) > 0 {
+		stats.Total = current.Total[0].Count
+	}
+	orgs := map[string]int{}
+	for _, d := range current.Orgs {
+		orgs[d.Key] = d.Count
+	}
+	stats.Orgs = statsCounts(orgs)
+	if params.RegionPrecision > 0 {
+		regions := map[string]int{}
+		for _, d := range current.Regions {
+			regions[d.Key] = d.Count
+		}
+		stats.Regions = regionCounts(regions)
+	}
+
+	series, buckets := newStatsSeries(params)
+	for _, docs := range [][]statsBucketDoc{current.Added, deleted.Added} {
+		for _, d := range docs {
+			if b, ok := buckets[d.Start.Unix()]; ok {
+				b.Added += d.Count
+			}
+		}
+	}
+	for _, d := range deleted.Deleted {
+		if b, ok := buckets[d.Start.Unix()]; ok {
+			b.Deleted += d.Count
+		}
+	}
+	stats.Series = series
+	return stats, nil
+}
+
+// aggregateFacets runs a $match then $facet aggregation, decoding its single result into out.
+func (sr *storesRepo) aggregateFacets(ctx context.Context, collection string, match, facets bson.M, out any) error {
+	cursor, err := sr.Store().Collection(collection).Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$facet", Value: facets}},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		return cursor.Err()
+	}
+	return cursor.Decode(out)
+}
+
+// appendDeletion records a deleted store, in the caller's transaction. Stores added
+// before creation times were kept are taken as created at their ID's timestamp.
+func (sr *storesRepo) appendDeletion(ctx context.Context, objID primitive.ObjectID, st *stdom.Store) error {
+	createdAt := st.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = objID.Timestamp().UTC()
+	}
+	_, err := sr.Store().Collection(STORE_DELETIONS_COLLECTION).InsertOne(ctx, &stdom.StoreDeletion{
+		StoreID:   objID.Hex(),
+		Org:       st.Org,
+		AddressId: st.AddressId,
+		CreatedAt: createdAt,
+		DeletedAt: time.Now().UTC(),
+	})
+	return err
+}
+
+// dateTrunc truncates a date field to its stats bucket start, matching StatsInterval.BucketStart.
+func dateTrunc(field string, interval stdom.StatsInterval) bson.M {
+	trunc := bson.M{"date": field, "unit": string(interval), "timezone": "UTC"}
+	if interval == stdom.STATS_WEEK {
+		trunc["startOfWeek"] = "monday"
+	}
+	return bson.M{"$dateTrunc": trunc}
+}
+
+func validateStatsQuery(params *stdom.StoreStatsQuery) error {
+	if params == nil || params.From.IsZero() || params.To.IsZero() {
+		return ErrMissingRequired
+	}
+	if !params.Interval.Valid() || !params.From.Before(params.To) || params.RegionPrecision < 0 {
+		return ErrInvalidStatsQuery
+	}
+	return nil
+}
+
+// newStatsSeries returns the empty series buckets from the query's From until its To,
+// with the buckets by start time.
+func newStatsSeries(params *stdom.StoreStatsQuery) ([]*stdom.StatsBucket, map[int64]*stdom.StatsBucket) {
+	series := []*stdom.StatsBucket{}
+	buckets := map[int64]*stdom.StatsBucket{}
+	for start := params.Interval.BucketStart(params.From); start.Before(params.To); start = params.Interval.Next(start) {
+		b := &stdom.StatsBucket{Start: start}
+		series = append(series, b)
+		buckets[start.Unix()] = b
+	}
+	return series, buckets
+}
+
+func statsCounts(counts map[string]int) []*stdom.StatsCount {
+	stats := make([]*stdom.StatsCount, 0, len(counts))
+	for k, n := range counts {
+		stats = append(stats, &stdom.StatsCount{Key: k, Count: n})
+	}
+	stdom.SortStatsCounts(stats)
+	return stats
+}
+
+// regionCounts orders region counts like facet buckets, centering each on its cell.
+// Regions that aren't address ID prefixes are left without a center.
+func regionCounts(counts map[string]int) []*stdom.RegionCount {
+	regions := make([]*stdom.RegionCount, 0, len(counts))
+	for region, n := range counts {
+		rc := &stdom.RegionCount{Region: region, Count: n}
+		if lat, lon, err := geodom.DecodeAddressId(region); err == nil {
+			rc.Latitude, rc.Longitude = lat, lon
+		}
+		regions = append(regions, rc)
+	}
+	sort.Slice(regions, func(i, j int) bool {
+		if regions[i].Count != regions[j].Count {
+			return regions[i].Count > regions[j].Count
+		}
+		return regions[i].Region < regions[j].Region
+	})
+	return regions
+}
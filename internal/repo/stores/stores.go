@@ -3,7 +3,10 @@ package stores
 import (
 	"context"
 	"errors"
+	"math"
 	"regexp"
+	"sort"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -16,55 +19,94 @@ import (
 
 	"github.com/comfforts/logger"
 
+	evdom "github.com/comfforts/comff-stores/internal/domain/events"
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
 	indom "github.com/comfforts/comff-stores/internal/domain/infra"
 	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
 	"github.com/comfforts/comff-stores/internal/infra/observability"
+	obrepo "github.com/comfforts/comff-stores/internal/repo/outbox"
 )
 
-const STORES_COLLECTION = "stores.stores"
+const (
+	STORES_COLLECTION          = "stores.stores"
+	ADDRESS_HISTORY_COLLECTION = "stores.address_history"
+	STORE_DELETIONS_COLLECTION = "stores.deletions"
+)
+
+// address index names, the global unique index keeps its original default name
+const (
+	ADDRESS_UNIQUE_INDEX     = "address_id_1"
+	ORG_ADDRESS_UNIQUE_INDEX = "org_1_address_id_1"
+	ADDRESS_SEARCH_INDEX     = "address_id_search"
+)
 
 const (
-	ERR_MISSING_REQUIRED = "missing required parameters"
-	ERR_DUPLICATE_STORE  = "duplicate store"
-	ERR_DECODING_REC_ID  = "error decoding record ID"
-	ERR_NO_STORE         = "no store found"
+	ERR_MISSING_REQUIRED      = "missing required parameters"
+	ERR_DUPLICATE_STORE       = stdom.ERR_DUPLICATE_STORE
+	ERR_STORE_CONFLICT        = stdom.ERR_STORE_CONFLICT
+	ERR_DECODING_REC_ID       = "error decoding record ID"
+	ERR_NO_STORE              = "no store found"
+	ERR_INVALID_UNIQUENESS    = "invalid address uniqueness rule"
+	ERR_UNIQUENESS_VIOLATIONS = "existing stores violate the address uniqueness rule"
+	ERR_INVALID_STATS_QUERY   = "invalid stats query"
+	ERR_INVALID_CLUSTER_QUERY = "invalid cluster query"
+	ERR_INVALID_SERVING_QUERY = "invalid serving stores query"
 )
 
 var (
-	ErrMissingRequired = errors.New(ERR_MISSING_REQUIRED)
-	ErrDuplicateStore  = errors.New(ERR_DUPLICATE_STORE)
-	ErrDecodeRecId     = errors.New(ERR_DECODING_REC_ID)
-	ErrNoStore         = errors.New(ERR_NO_STORE)
+	ErrMissingRequired      = errors.New(ERR_MISSING_REQUIRED)
+	ErrDuplicateStore       = stdom.ErrDuplicateStore
+	ErrStoreConflict        = stdom.ErrStoreConflict
+	ErrDecodeRecId          = errors.New(ERR_DECODING_REC_ID)
+	ErrNoStore              = errors.New(ERR_NO_STORE)
+	ErrInvalidUniqueness    = errors.New(ERR_INVALID_UNIQUENESS)
+	ErrUniquenessViolations = errors.New(ERR_UNIQUENESS_VIOLATIONS)
+	ErrInvalidStatsQuery    = errors.New(ERR_INVALID_STATS_QUERY)
+	ErrInvalidClusterQuery  = errors.New(ERR_INVALID_CLUSTER_QUERY)
+	ErrInvalidServingQuery  = errors.New(ERR_INVALID_SERVING_QUERY)
 )
 
 type storesRepo struct {
 	indom.DBStore
-	metrics observability.Metrics
+	metrics    observability.Metrics
+	uniqueness stdom.AddressUniqueness
 }
 
-func NewStoresRepo(ctx context.Context, rc indom.DBStore, mt observability.Metrics) (*storesRepo, error) {
+// NewStoresRepo returns the mongo stores repo enforcing the address uniqueness rule,
+// global when empty. Indexes are set up by Migrations, the address indexes are moved to
+// the rule here too when they don't match it, so the rule holds without migrations &
+// rule changes apply on restart.
+func NewStoresRepo(ctx context.Context, rc indom.DBStore, mt observability.Metrics, uniq stdom.AddressUniqueness) (*storesRepo, error) {
 	l, err := logger.LoggerFromContext(ctx)
 	if err != nil {
 		l = logger.GetSlogLogger()
 	}
 
-	// ensure stores indexes
-	if err = rc.EnsureIndexes(ctx, STORES_COLLECTION, []mongo.IndexModel{
-		{
-			Keys: bson.D{
-				{Key: "address_id", Value: 1},
-			},
-			Options: options.Index().SetUnique(true), // unique index on address_id
-		},
-	}); err != nil {
-		l.Error("error adding stores indexes", "error", err.Error())
+	if uniq == "" {
+		uniq = stdom.ADDRESS_UNIQUE_GLOBAL
+	}
+	if !uniq.Valid() {
+		l.Error("error initializing stores repo", "error", ERR_INVALID_UNIQUENESS, "uniqueness", uniq)
+		return nil, ErrInvalidUniqueness
+	}
+
+	// a mismatch means migrations haven't run or the rule changed since
+	if ok, err := hasAddressIndexes(ctx, rc, uniq); err != nil {
+		l.Error("error checking stores address indexes", "error", err.Error(), "uniqueness", uniq)
 		return nil, err
+	} else if !ok {
+		l.Info("moving stores address indexes to the uniqueness rule", "uniqueness", uniq)
+		if err := migrateAddressIndexes(ctx, rc, uniq); err != nil {
+			l.Error("error moving stores address indexes", "error", err.Error(), "uniqueness", uniq)
+			return nil, err
+		}
 	}
 
-	l.Info("initialized stores repo")
+	l.Info("initialized stores repo", "uniqueness", uniq)
 	return &storesRepo{
-		DBStore: rc,
-		metrics: mt,
+		DBStore:    rc,
+		metrics:    mt,
+		uniqueness: uniq,
 	}, nil
 }
 
@@ -85,23 +127,50 @@ func (sr *storesRepo) AddStore(ctx context.Context, st *stdom.Store) (string, er
 
 	coll := sr.Store().Collection(STORES_COLLECTION)
 
-	res, err := coll.InsertOne(ctx, st)
-	if err != nil {
-		if mongo.IsDuplicateKeyError(err) {
-			finishSpan(span, ErrDuplicateStore)
-			return "", ErrDuplicateStore
+	doc := *st
+	doc.NameTrigrams = stdom.StoreNameTrigrams(st)
+	doc.Score = 0
+	doc.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+	doc.Location = storeLocation(st.AddressId)
+	if doc.Status == "" {
+		doc.Status = stdom.STORE_ACTIVE
+	}
+
+	var idHex string
+	err = sr.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := sr.touchVersions(ctx, st.ParentChain); err != nil {
+			return err
 		}
+		res, err := coll.InsertOne(ctx, &doc)
+		if err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return ErrDuplicateStore
+			}
+			return err
+		}
+
+		id, ok := res.InsertedID.(primitive.ObjectID)
+		if !ok {
+			return ErrDecodeRecId
+		}
+		idHex = id.Hex()
 
+		added := doc
+		added.ID = idHex
+		if err := sr.appendAddressChange(ctx, idHex, st.AddressId, ""); err != nil {
+			return err
+		}
+		return obrepo.AppendEvent(ctx, sr.Store(), evdom.STORE_ADDED, &added, "")
+	})
+	if errors.Is(err, ErrDuplicateStore) {
+		err = sr.duplicateError(ctx, primitive.NilObjectID, st.AddressId, st.Org)
+	}
+	if err != nil {
+		l.Error("AddStore error", "error", err.Error())
 		finishSpan(span, err)
 		return "", err
 	}
-
-	id, ok := res.InsertedID.(primitive.ObjectID)
-	if !ok {
-		finishSpan(span, ErrDecodeRecId)
-		return "", ErrDecodeRecId
-	}
-	return id.Hex(), nil
+	return idHex, nil
 }
 
 func (sr *storesRepo) GetStore(ctx context.Context, idHex string) (*stdom.Store, error) {
@@ -142,7 +211,7 @@ func (sr *storesRepo) GetStore(ctx context.Context, idHex string) (*stdom.Store,
 	return &store, nil
 }
 
-func (sr *storesRepo) DeleteStore(ctx context.Context, idHex string) error {
+func (sr *storesRepo) DeleteStore(ctx context.Context, idHex string, ifVersion *int64) error {
 	ctx, span := startSpan(ctx, "stores.repo.delete")
 	defer span.End()
 
@@ -165,17 +234,36 @@ func (sr *storesRepo) DeleteStore(ctx context.Context, idHex string) error {
 		return ErrDecodeRecId
 	}
 	filter := bson.M{"_id": objID}
-
-	res, err := coll.DeleteOne(ctx, filter)
+	if ifVersion != nil {
+		filter = versionFilter(objID, *ifVersion)
+	}
+
+	err = sr.WithTransaction(ctx, func(ctx context.Context) error {
+		var deleted stdom.Store
+		if err := coll.FindOneAndDelete(ctx, filter).Decode(&deleted); err != nil {
+			if err != mongo.ErrNoDocuments {
+				return err
+			}
+			if ifVersion != nil {
+				// changed since, or deleted
+				if n, err := coll.CountDocuments(ctx, bson.M{"_id": objID}); err != nil {
+					return err
+				} else if n > 0 {
+					return ErrStoreConflict
+				}
+			}
+			return ErrNoStore
+		}
+		if err := sr.appendDeletion(ctx, objID, &deleted); err != nil {
+			return err
+		}
+		return obrepo.AppendEvent(ctx, sr.Store(), evdom.STORE_DELETED, &deleted, "")
+	})
 	if err != nil {
 		l.Error("DeleteStore error", "error", err.Error())
 		finishSpan(span, err)
 		return err
 	}
-	if res.DeletedCount == 0 {
-		finishSpan(span, ErrNoStore)
-		return ErrNoStore
-	}
 
 	return nil
 }
@@ -214,27 +302,143 @@ func (sr *storesRepo) UpdateStore(ctx context.Context, idHex string, params *std
 	if params.AddressId != "" {
 		updateParams["address_id"] = params.AddressId
 	}
-	if len(updateParams) == 0 {
+	if params.Description != "" {
+		updateParams["description"] = params.Description
+	}
+	if len(params.Tags) > 0 {
+		updateParams["tags"] = params.Tags
+	}
+	if params.Status != "" {
+		updateParams["status"] = params.Status
+	}
+	if params.ServiceArea != nil {
+		updateParams["service_area"] = params.ServiceArea
+	}
+	if params.ParentID != "" {
+		updateParams["parent_id"] = params.ParentID
+	}
+	if params.Region != "" {
+		updateParams["region"] = params.Region
+	}
+	if len(params.Capabilities) > 0 {
+		updateParams["capabilities"] = params.Capabilities
+	}
+	if params.Locale != "" {
+		updateParams["locale"] = params.Locale
+	}
+	if len(params.Translations) > 0 {
+		updateParams["translations"] = params.Translations
+	}
+	unsetParams := bson.M{}
+	if params.DetachParent {
+		unsetParams["parent_id"] = ""
+	}
+	if params.ClearTranslations && len(params.Translations) == 0 {
+		unsetParams["translations"] = ""
+	}
+	if len(params.Attachments) > 0 {
+		updateParams["attachments"] = params.Attachments
+	} else if params.ClearAttachments {
+		unsetParams["attachments"] = ""
+	}
+	if params.Schedule != nil {
+		if len(params.Schedule.Closures) > 0 {
+			updateParams["closures"] = params.Schedule.Closures
+		} else {
+			unsetParams["closures"] = ""
+		}
+		if params.Schedule.StatusChangeAt != nil {
+			updateParams["status_change_at"] = params.Schedule.StatusChangeAt
+		} else {
+			unsetParams["status_change_at"] = ""
+		}
+		// rescheduled stores can be claimed again
+		unsetParams["status_claimed_until"] = ""
+	}
+	if len(updateParams) == 0 && len(unsetParams) == 0 {
 		finishSpan(span, ErrMissingRequired)
 		return ErrMissingRequired
 	}
-	update := bson.M{"$set": updateParams}
+	if params.AddressId != "" {
+		if loc := storeLocation(params.AddressId); loc != nil {
+			updateParams["location"] = loc
+		} else {
+			unsetParams["location"] = ""
+		}
+	}
+	// name trigrams cover the translated names too, reindexed from the updated store
+	reindex := params.Name != "" || len(params.Translations) > 0 || params.ClearTranslations
+
+	// the current version, for the name trigrams & address history, updated only while
+	// it's current
+	var current stdom.Store
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = sr.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := coll.FindOne(ctx, filter).Decode(&current); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return ErrNoStore
+			}
+			return err
+		}
+		if params.IfVersion != nil && *params.IfVersion != current.Version {
+			return ErrStoreConflict
+		}
+		if err := sr.touchVersions(ctx, params.ParentChain); err != nil {
+			return err
+		}
+		if reindex {
+			updateParams["name_trigrams"] = stdom.ApplyUpdate(&current, params).NameTrigrams
+		}
+		update := bson.M{"$inc": bson.M{"version": 1}}
+		if len(updateParams) > 0 {
+			update["$set"] = updateParams
+		}
+		if len(unsetParams) > 0 {
+			update["$unset"] = unsetParams
+		}
 
-	res, err := coll.UpdateOne(ctx, filter, update)
+		var updated stdom.Store
+		if err := coll.FindOneAndUpdate(ctx, versionFilter(objID, current.Version), update, opts).Decode(&updated); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return ErrStoreConflict
+			}
+			if mongo.IsDuplicateKeyError(err) {
+				return ErrDuplicateStore
+			}
+			return err
+		}
+		if updated.AddressId != current.AddressId {
+			if err := sr.appendAddressChange(ctx, idHex, updated.AddressId, current.AddressId); err != nil {
+				return err
+			}
+		}
+		prevOrg := ""
+		if updated.Org != current.Org {
+			prevOrg = current.Org
+		}
+		return obrepo.AppendEvent(ctx, sr.Store(), evdom.STORE_UPDATED, &updated, prevOrg)
+	})
+	if errors.Is(err, ErrDuplicateStore) {
+		// the conflict is on the updated address & org, defaulting to the store's own
+		addressId, org := params.AddressId, params.Org
+		if addressId == "" {
+			addressId = current.AddressId
+		}
+		if org == "" {
+			org = current.Org
+		}
+		err = sr.duplicateError(ctx, objID, addressId, org)
+	}
 	if err != nil {
 		l.Error("UpdateStore error", "error", err.Error())
 		finishSpan(span, err)
 		return err
 	}
-	if res.MatchedCount == 0 {
-		finishSpan(span, ErrNoStore)
-		return ErrNoStore
-	}
 
 	return nil
 }
 
-func (sr *storesRepo) SearchStores(ctx context.Context, params *stdom.SearchStoreQuery) ([]*stdom.Store, error) {
+func (sr *storesRepo) SearchStores(ctx context.Context, params *stdom.SearchStoreQuery) (*stdom.SearchStoreResult, error) {
 	ctx, span := startSpan(ctx, "stores.repo.search")
 	defer span.End()
 
@@ -244,43 +448,434 @@ func (sr *storesRepo) SearchStores(ctx context.Context, params *stdom.SearchStor
 	}
 	l.Debug("searching stores")
 
-	coll := sr.Store().Collection(STORES_COLLECTION)
+	fuzzy := params.Fuzzy && params.Name != ""
 	filter := bson.M{}
 	if params.Org != "" {
 		filter["org"] = bson.M{"$regex": "^" + regexp.QuoteMeta(params.Org), "$options": "i"}
 	}
 	if params.Name != "" {
-		filter["name"] = bson.M{"$regex": "^" + regexp.QuoteMeta(params.Name), "$options": "i"}
+		if fuzzy {
+			// candidates share a name trigram, narrowed & scored below
+			filter["name_trigrams"] = bson.M{"$in": stdom.NameTrigrams(params.Name)}
+		} else {
+			// names match in any locale
+			name := bson.M{"$regex": "^" + regexp.QuoteMeta(params.Name), "$options": "i"}
+			filter["$or"] = bson.A{bson.M{"name": name}, bson.M{"translations.name": name}}
+		}
 	}
 	if params.AddressId != "" {
 		filter["address_id"] = bson.M{"$regex": "^" + regexp.QuoteMeta(params.AddressId), "$options": "i"}
 	}
+	// free text queries match the text index & rank by relevance
+	if params.Query != "" {
+		filter["$text"] = bson.M{"$search": params.Query}
+	}
+	if params.Region != "" {
+		filter["region"] = bson.M{"$regex": "^" + regexp.QuoteMeta(params.Region) + "(" + regexp.QuoteMeta(stdom.REGION_SEPARATOR) + "|$)"}
+	}
+	if len(params.Capabilities) > 0 {
+		filter["capabilities"] = bson.M{"$all": params.Capabilities}
+	}
+	if !params.IncludeTemporarilyClosed {
+		filter["status"] = bson.M{"$ne": stdom.STORE_TEMPORARILY_CLOSED}
+	}
+	if len(params.Within) > 0 {
+		filter["location"] = bson.M{"$geoWithin": bson.M{"$geometry": bson.M{
+			"type":        "MultiPolygon",
+			"coordinates": params.Within,
+		}}}
+	}
+
+	if params.Near != nil {
+		result, err := sr.nearestSearch(ctx, filter, params)
+		if err != nil {
+			l.Error("SearchStores error", "error", err.Error())
+			finishSpan(span, err)
+			return nil, err
+		}
+		return result, nil
+	}
 
-	cursor, err := coll.Find(ctx, filter)
+	// fuzzy matches are scored here, so they're paged & counted in process
+	if fuzzy {
+		matched, err := sr.fuzzyMatches(ctx, filter, params)
+		if err != nil {
+			l.Error("SearchStores error", "error", err.Error())
+			finishSpan(span, err)
+			return nil, err
+		}
+		return pageAndFacet(matched, params), nil
+	}
+
+	result, err := sr.facetedSearch(ctx, filter, params)
 	if err != nil {
 		l.Error("SearchStores error", "error", err.Error())
 		finishSpan(span, err)
 		return nil, err
 	}
+	return result, nil
+}
+
+type facetBucketDoc struct {
+	Value string `bson:"_id"`
+	Count int    `bson:"count"`
+}
+
+// facetedSearch pages the matching stores, streamed from a cursor, then counts their
+// total & facets in one aggregation, with a $facet stage for each. The page is kept out
+// of the $facet stage, its single result document being capped at 16MB.
+func (sr *storesRepo) facetedSearch(ctx context.Context, filter bson.M, params *stdom.SearchStoreQuery) (*stdom.SearchStoreResult, error) {
+	coll := sr.Store().Collection(STORES_COLLECTION)
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	if params.Query != "" {
+		pipeline = append(
+			pipeline,
+			bson.D{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}}}},
+		)
+	} else {
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}})
+	}
+	if params.Offset > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: params.Offset}})
+	}
+	if params.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: params.Limit}})
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
 	defer cursor.Close(ctx)
 
-	var storesList []*stdom.Store
+	result := &stdom.SearchStoreResult{
+		Stores: []*stdom.Store{},
+	}
+	if err := cursor.All(ctx, &result.Stores); err != nil {
+		return nil, err
+	}
+
+	facets := bson.M{
+		"total": bson.A{bson.M{"$count": "count"}},
+	}
+	for _, field := range params.Facets {
+		facets[string(field)] = facetPipeline(field)
+	}
+	counts, err := coll.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$facet", Value: facets}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer counts.Close(ctx)
+
+	if !counts.Next(ctx) {
+		return result, counts.Err()
+	}
+
+	var res struct {
+		Total []struct {
+			Count int `bson:"count"`
+		} `bson:"total"`
+	}
+	if err := counts.Decode(&res); err != nil {
+		return nil, err
+	}
+	if len(res.Total) > 0 {
+		result.Total = res.Total[0].Count
+	}
+	if len(params.Facets) > 0 {
+		result.Facets = map[stdom.FacetField][]*stdom.FacetBucket{}
+		for _, field := range params.Facets {
+			var docs []facetBucketDoc
+			if err := counts.Current.Lookup(string(field)).Unmarshal(&docs); err != nil {
+				return nil, err
+			}
+			buckets := make([]*stdom.FacetBucket, 0, len(docs))
+			for _, d := range docs {
+				buckets = append(buckets, &stdom.FacetBucket{Value: d.Value, Count: d.Count})
+			}
+			result.Facets[field] = buckets
+		}
+	}
+	return result, nil
+}
+
+// nearestSearch pages the matching stores nearest the point. $geoNear finds them on the
+// location index, text searches, which it can't run, are measured in the pipeline instead.
+// Distances are great circle distances, as measured elsewhere.
+func (sr *storesRepo) nearestSearch(ctx context.Context, filter bson.M, params *stdom.SearchStoreQuery) (*stdom.SearchStoreResult, error) {
+	near := params.Near
+	var pipeline mongo.Pipeline
+	if params.Query == "" {
+		geoNear := bson.M{
+			"near":          bson.M{"type": "Point", "coordinates": bson.A{near.Longitude, near.Latitude}},
+			"key":           "location",
+			"distanceField": "distance",
+			"spherical":     true,
+			"query":         filter,
+		}
+		if near.MaxDistance > 0 {
+			geoNear["maxDistance"] = near.MaxDistance
+		}
+		pipeline = mongo.Pipeline{{{Key: "$geoNear", Value: geoNear}}}
+	} else {
+		located := bson.M{"$exists": true}
+		if near.MaxDistance > 0 {
+			located = bson.M{"$geoWithin": bson.M{"$centerSphere": bson.A{
+				bson.A{near.Longitude, near.Latitude},
+				near.MaxDistance / geodom.EARTH_RADIUS_METERS,
+			}}}
+		}
+		if _, ok := filter["location"]; ok {
+			filter["$and"] = bson.A{bson.M{"location": located}}
+		} else {
+			filter["location"] = located
+		}
+		pipeline = mongo.Pipeline{
+			{{Key: "$match", Value: filter}},
+			{{Key: "$addFields", Value: bson.M{"distance": distanceExpr(near.Latitude, near.Longitude)}}},
+			{{Key: "$sort", Value: bson.D{{Key: "distance", Value: 1}, {Key: "_id", Value: 1}}}},
+		}
+	}
+	if params.Offset > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: params.Offset}})
+	}
+	if params.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: params.Limit}})
+	}
+
+	cursor, err := sr.Store().Collection(STORES_COLLECTION).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	stores := []*stdom.Store{}
+	if err := cursor.All(ctx, &stores); err != nil {
+		return nil, err
+	}
+	for _, st := range stores {
+		st.Distance = geodom.DistanceMeters(near.Latitude, near.Longitude, st.Location.Coordinates[1], st.Location.Coordinates[0])
+	}
+	sortByDistance(stores)
+	return &stdom.SearchStoreResult{Stores: stores, Total: len(stores)}, nil
+}
+
+// distanceExpr is the great circle distance in meters from the point to the store's
+// location, by the haversine formula, as geodom.DistanceMeters.
+func distanceExpr(lat, lon float64) bson.M {
+	rad := func(v any) bson.M { return bson.M{"$degreesToRadians": v} }
+	halfSinSq := func(v bson.M) bson.M {
+		return bson.M{"$pow": bson.A{bson.M{"$sin": bson.M{"$divide": bson.A{v, 2}}}, 2}}
+	}
+	stLat := rad(bson.M{"$arrayElemAt": bson.A{"$location.coordinates", 1}})
+	stLon := rad(bson.M{"$arrayElemAt": bson.A{"$location.coordinates", 0}})
+	dLat := bson.M{"$subtract": bson.A{stLat, lat * math.Pi / 180}}
+	dLon := bson.M{"$subtract": bson.A{stLon, lon * math.Pi / 180}}
+	h := bson.M{"$add": bson.A{
+		halfSinSq(dLat),
+		bson.M{"$multiply": bson.A{math.Cos(lat * math.Pi / 180), bson.M{"$cos": stLat}, halfSinSq(dLon)}},
+	}}
+	return bson.M{"$multiply": bson.A{2 * geodom.EARTH_RADIUS_METERS, bson.M{"$asin": bson.M{"$sqrt": bson.M{"$min": bson.A{1, h}}}}}}
+}
+
+// sortByDistance orders stores nearest first, keeping the order of equal distances.
+func sortByDistance(stores []*stdom.Store) {
+	sort.SliceStable(stores, func(i, j int) bool {
+		return stores[i].Distance < stores[j].Distance
+	})
+}
+
+// facetPipeline counts stores by a field's values, largest first.
+func facetPipeline(field stdom.FacetField) bson.A {
+	group := bson.A{}
+	switch field {
+	case stdom.FACET_STATUS:
+		group = append(group, bson.M{"$group": bson.M{
+			"_id":   bson.M{"$ifNull": bson.A{"$status", string(stdom.STORE_ACTIVE)}},
+			"count": bson.M{"$sum": 1},
+		}})
+	case stdom.FACET_TAGS, stdom.FACET_CAPABILITIES:
+		group = append(group, bson.M{"$unwind": "$" + string(field)}, bson.M{"$group": bson.M{
+			"_id":   "$" + string(field),
+			"count": bson.M{"$sum": 1},
+		}})
+	default:
+		group = append(group, bson.M{"$group": bson.M{
+			"_id":   "$" + string(field),
+			"count": bson.M{"$sum": 1},
+		}})
+	}
+	return append(group, bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}})
+}
+
+// fuzzyMatches returns the trigram candidates at least as similar as the threshold, most similar first.
+// Only candidates sharing enough of the query's trigrams are scored, at most FUZZY_MAX_CANDIDATES of them.
+func (sr *storesRepo) fuzzyMatches(ctx context.Context, filter bson.M, params *stdom.SearchStoreQuery) ([]*stdom.Store, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	trigrams := stdom.NameTrigrams(params.Name)
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$addFields", Value: bson.M{"trigram_overlap": bson.M{"$size": bson.M{"$setIntersection": bson.A{"$name_trigrams", trigrams}}}}}},
+		{{Key: "$match", Value: bson.M{"trigram_overlap": bson.M{"$gte": stdom.MinTrigramOverlap(trigrams)}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "trigram_overlap", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: stdom.FUZZY_MAX_CANDIDATES}},
+		{{Key: "$unset", Value: "trigram_overlap"}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+	cursor, err := sr.Store().Collection(STORES_COLLECTION).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var matched []*stdom.Store
 	for cursor.Next(ctx) {
 		var st stdom.Store
 		if err := cursor.Decode(&st); err != nil {
 			l.Error("SearchStores error decoding store", "error", err.Error())
 			continue
 		}
-		storesList = append(storesList, &st)
+		if st.Score = stdom.StoreNameSimilarity(params.Name, &st); st.Score < params.MinSimilarity {
+			continue
+		}
+		matched = append(matched, &st)
 	}
-
 	if err := cursor.Err(); err != nil {
-		l.Error("SearchStores cursor error", "error", err.Error())
+		return nil, err
+	}
+
+	sortByScore(matched)
+	return matched, nil
+}
+
+// sortByScore orders stores by descending score, keeping the order of equal scores.
+func sortByScore(stores []*stdom.Store) {
+	sort.SliceStable(stores, func(i, j int) bool {
+		return stores[i].Score > stores[j].Score
+	})
+}
+
+// duplicateError names the existing store an address conflicts with under the uniqueness rule,
+// falling back to ErrDuplicateStore when it can't be found.
+func (sr *storesRepo) duplicateError(ctx context.Context, exceptID primitive.ObjectID, addressId, org string) error {
+	filter := bson.M{"address_id": addressId}
+	if sr.uniqueness == stdom.ADDRESS_UNIQUE_ORG {
+		filter["org"] = org
+	} else {
+		org = ""
+	}
+	if !exceptID.IsZero() {
+		filter["_id"] = bson.M{"$ne": exceptID}
+	}
+
+	var existing stdom.Store
+	if err := sr.Store().Collection(STORES_COLLECTION).FindOne(ctx, filter).Decode(&existing); err != nil {
+		return ErrDuplicateStore
+	}
+	return &stdom.DuplicateStoreError{
+		ExistingID: existing.ID,
+		AddressId:  addressId,
+		Org:        org,
+	}
+}
+
+// GetAddressHistory returns a store's address changes, oldest first.
+// History is kept after a store is deleted.
+func (sr *storesRepo) GetAddressHistory(ctx context.Context, idHex string) ([]*stdom.AddressChange, error) {
+	ctx, span := startSpan(ctx, "stores.repo.address_history")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("getting store address history")
+
+	if idHex == "" {
+		finishSpan(span, ErrMissingRequired)
+		return nil, ErrMissingRequired
+	}
+	if _, err := primitive.ObjectIDFromHex(idHex); err != nil {
+		l.Error("GetAddressHistory error invalid idHex", "error", err.Error())
+		finishSpan(span, ErrDecodeRecId)
+		return nil, ErrDecodeRecId
+	}
+
+	coll := sr.Store().Collection(ADDRESS_HISTORY_COLLECTION)
+	opts := options.Find().SetSort(bson.D{{Key: "changed_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := coll.Find(ctx, bson.M{"store_id": idHex}, opts)
+	if err != nil {
+		l.Error("GetAddressHistory error", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	changes := []*stdom.AddressChange{}
+	if err := cursor.All(ctx, &changes); err != nil {
+		l.Error("GetAddressHistory error decoding changes", "error", err.Error())
 		finishSpan(span, err)
 		return nil, err
 	}
+	return changes, nil
+}
+
+// touchVersions counts an update of each of the stores still at their versions, in the
+// caller's transaction, so writes checked against them conflict with their concurrent
+// updates.
+func (sr *storesRepo) touchVersions(ctx context.Context, chain []*stdom.StoreVersion) error {
+	coll := sr.Store().Collection(STORES_COLLECTION)
+	for _, sv := range chain {
+		objID, err := primitive.ObjectIDFromHex(sv.ID)
+		if err != nil {
+			return ErrDecodeRecId
+		}
+		res, err := coll.UpdateOne(ctx, versionFilter(objID, sv.Version), bson.M{"$inc": bson.M{"version": 1}})
+		if err != nil {
+			return err
+		}
+		if res.MatchedCount == 0 {
+			return ErrStoreConflict
+		}
+	}
+	return nil
+}
+
+// versionFilter matches the store at the version, stores never updated have none.
+func versionFilter(id primitive.ObjectID, version int64) bson.M {
+	if version == 0 {
+		return bson.M{"_id": id, "version": bson.M{"$in": bson.A{0, nil}}}
+	}
+	return bson.M{"_id": id, "version": version}
+}
+
+// storeLocation returns the point of an address ID, nil when it isn't one.
+func storeLocation(addressId string) *geodom.GeoJSONPoint {
+	lat, lon, err := geodom.DecodeAddressId(addressId)
+	if err != nil {
+		return nil
+	}
+	return geodom.NewGeoJSONPoint(lat, lon)
+}
 
-	return storesList, nil
+// appendAddressChange records a store's new address ID, in the caller's transaction.
+func (sr *storesRepo) appendAddressChange(ctx context.Context, storeID, addressId, prevAddressId string) error {
+	_, err := sr.Store().Collection(ADDRESS_HISTORY_COLLECTION).InsertOne(ctx, &stdom.AddressChange{
+		StoreID:           storeID,
+		AddressId:         addressId,
+		PreviousAddressId: prevAddressId,
+		ChangedAt:         time.Now().UTC(),
+	})
+	return err
 }
 
 func (sr *storesRepo) Close(ctx context.Context) error {
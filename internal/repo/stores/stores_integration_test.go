@@ -9,8 +9,11 @@ import (
 
 	"github.com/comfforts/logger"
 
+	evdom "github.com/comfforts/comff-stores/internal/domain/events"
 	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
 	"github.com/comfforts/comff-stores/internal/infra/mongostore"
+	mgrepo "github.com/comfforts/comff-stores/internal/repo/migrations"
+	obrepo "github.com/comfforts/comff-stores/internal/repo/outbox"
 	strepo "github.com/comfforts/comff-stores/internal/repo/stores"
 	envutils "github.com/comfforts/comff-stores/pkg/utils/environ"
 )
@@ -28,7 +31,7 @@ func TestStoresRepo(t *testing.T) {
 	cl, err := mongostore.NewMongoStore(ctx, nmCfg)
 	require.NoError(t, err)
 
-	storesRepo, err := strepo.NewStoresRepo(ctx, cl, nil)
+	storesRepo, err := strepo.NewStoresRepo(ctx, cl, nil, stdom.ADDRESS_UNIQUE_GLOBAL)
 	require.NoError(t, err)
 
 	defer func() {
@@ -50,7 +53,7 @@ func TestStoresCRUD(t *testing.T) {
 	cl, err := mongostore.NewMongoStore(ctx, nmCfg)
 	require.NoError(t, err)
 
-	storesRepo, err := strepo.NewStoresRepo(ctx, cl, nil)
+	storesRepo, err := strepo.NewStoresRepo(ctx, cl, nil, stdom.ADDRESS_UNIQUE_GLOBAL)
 	require.NoError(t, err)
 
 	defer func() {
@@ -88,9 +91,132 @@ func TestStoresCRUD(t *testing.T) {
 	})
 	require.ErrorIs(t, err, strepo.ErrDuplicateStore)
 
-	err = storesRepo.DeleteStore(ctx, id)
+	err = storesRepo.DeleteStore(ctx, id, nil)
 	require.NoError(t, err)
 
 	_, err = storesRepo.GetStore(ctx, id)
 	require.ErrorIs(t, err, strepo.ErrNoStore)
 }
+
+func TestStoresOutbox(t *testing.T) {
+	// Initialize logger
+	l := logger.GetSlogLogger()
+	l.Debug("TestStoresOutbox Logger initialized")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	nmCfg := envutils.BuildMongoStoreConfig(true)
+	cl, err := mongostore.NewMongoStore(ctx, nmCfg)
+	require.NoError(t, err)
+
+	storesRepo, err := strepo.NewStoresRepo(ctx, cl, nil, stdom.ADDRESS_UNIQUE_GLOBAL)
+	require.NoError(t, err)
+
+	defer func() {
+		err := storesRepo.Close(ctx)
+		require.NoError(t, err)
+	}()
+
+	outboxRepo, err := obrepo.NewOutboxRepo(ctx, cl)
+	require.NoError(t, err)
+
+	id, err := storesRepo.AddStore(ctx, &stdom.Store{
+		Name:      "Outbox Store",
+		Org:       "Test Org",
+		AddressId: "Outbox Address ID",
+	})
+	require.NoError(t, err)
+
+	err = storesRepo.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{
+		Name: "Updated Outbox Store",
+	})
+	require.NoError(t, err)
+
+	err = storesRepo.DeleteStore(ctx, id, nil)
+	require.NoError(t, err)
+
+	entries, err := outboxRepo.ClaimPending(ctx, 100, time.Minute)
+	require.NoError(t, err)
+
+	evTypes := []evdom.EventType{}
+	for _, entry := range entries {
+		if entry.Event.StoreID != id {
+			continue
+		}
+		evTypes = append(evTypes, entry.Event.Type)
+		require.Equal(t, "Test Org", entry.Event.Org)
+		require.NoError(t, outboxRepo.MarkDelivered(ctx, entry.ID))
+	}
+	require.ElementsMatch(t, []evdom.EventType{evdom.STORE_ADDED, evdom.STORE_UPDATED, evdom.STORE_DELETED}, evTypes)
+}
+
+func TestStoresRepoConformance(t *testing.T) {
+	// Initialize logger
+	l := logger.GetSlogLogger()
+	l.Debug("TestStoresRepoConformance Logger initialized")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	nmCfg := envutils.BuildMongoStoreConfig(true)
+	cl, err := mongostore.NewMongoStore(ctx, nmCfg)
+	require.NoError(t, err)
+
+	// text search needs the migrated text index
+	mg, err := mgrepo.NewMigrator(ctx, cl, strepo.Migrations(stdom.ADDRESS_UNIQUE_GLOBAL), mgrepo.MigratorOptions{})
+	require.NoError(t, err)
+	_, err = mg.Up(ctx, 0)
+	require.NoError(t, err)
+
+	storesRepo, err := strepo.NewStoresRepo(ctx, cl, nil, stdom.ADDRESS_UNIQUE_GLOBAL)
+	require.NoError(t, err)
+
+	defer func() {
+		err := storesRepo.Close(ctx)
+		require.NoError(t, err)
+	}()
+
+	runStoresRepoConformance(t, ctx, storesRepo)
+}
+
+func TestStoresRepoAddressUniqueness(t *testing.T) {
+	// Initialize logger
+	l := logger.GetSlogLogger()
+	l.Debug("TestStoresRepoAddressUniqueness Logger initialized")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	nmCfg := envutils.BuildMongoStoreConfig(true)
+	cl, err := mongostore.NewMongoStore(ctx, nmCfg)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, cl.Close(ctx))
+	}()
+
+	// the address indexes migration is re-applied for each rule, ending back on the default global rule
+	for _, uniq := range []stdom.AddressUniqueness{stdom.ADDRESS_UNIQUE_ORG, stdom.ADDRESS_UNIQUE_NONE, stdom.ADDRESS_UNIQUE_GLOBAL} {
+		t.Run(string(uniq), func(t *testing.T) {
+			mg, err := mgrepo.NewMigrator(ctx, cl, strepo.Migrations(uniq), mgrepo.MigratorOptions{})
+			require.NoError(t, err)
+			_, err = mg.Up(ctx, 0)
+			require.NoError(t, err)
+			_, err = mg.Down(ctx, 1)
+			require.NoError(t, err)
+			_, err = mg.Up(ctx, 0)
+			require.NoError(t, err)
+
+			storesRepo, err := strepo.NewStoresRepo(ctx, cl, nil, uniq)
+			require.NoError(t, err)
+			runAddressUniquenessConformance(t, ctx, storesRepo, uniq)
+		})
+	}
+
+	_, err = strepo.NewStoresRepo(ctx, cl, nil, "regional")
+	require.ErrorIs(t, err, strepo.ErrInvalidUniqueness)
+}
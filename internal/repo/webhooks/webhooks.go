@@ -0,0 +1,436 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/comfforts/logger"
+
+	indom "github.com/comfforts/comff-stores/internal/domain/infra"
+	whdom "github.com/comfforts/comff-stores/internal/domain/webhooks"
+)
+
+const (
+	WEBHOOKS_COLLECTION   = "stores.webhooks"
+	DELIVERIES_COLLECTION = "stores.webhook_deliveries"
+)
+
+const DEFAULT_DELIVERIES_LIMIT = 100
+
+const (
+	ERR_MISSING_REQUIRED = "missing required parameters"
+	ERR_DECODING_REC_ID  = "error decoding record ID"
+	ERR_NO_WEBHOOK       = whdom.ERR_NO_WEBHOOK
+	ERR_NO_DELIVERY      = "no webhook delivery found"
+	ERR_CLAIM_LOST       = whdom.ERR_CLAIM_LOST
+)
+
+var (
+	ErrMissingRequired = errors.New(ERR_MISSING_REQUIRED)
+	ErrDecodeRecId     = errors.New(ERR_DECODING_REC_ID)
+	ErrNoWebhook       = whdom.ErrNoWebhook
+	ErrNoDelivery      = errors.New(ERR_NO_DELIVERY)
+	ErrClaimLost       = whdom.ErrClaimLost
+)
+
+type webhooksRepo struct {
+	indom.DBStore
+}
+
+func NewWebhooksRepo(ctx context.Context, rc indom.DBStore) (*webhooksRepo, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	// ensure webhooks indexes
+	if err = rc.EnsureIndexes(ctx, WEBHOOKS_COLLECTION, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "org", Value: 1},
+			},
+		},
+	}); err != nil {
+		l.Error("error adding webhooks indexes", "error", err.Error())
+		return nil, err
+	}
+
+	// ensure webhook deliveries indexes
+	if err = rc.EnsureIndexes(ctx, DELIVERIES_COLLECTION, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "webhook_id", Value: 1},
+				{Key: "event_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true), // one delivery per webhook & event
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "next_attempt_at", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "webhook_id", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+	}); err != nil {
+		l.Error("error adding webhook deliveries indexes", "error", err.Error())
+		return nil, err
+	}
+
+	l.Info("initialized webhooks repo")
+	return &webhooksRepo{
+		DBStore: rc,
+	}, nil
+}
+
+func (wr *webhooksRepo) AddSubscription(ctx context.Context, sub *whdom.Subscription) (string, error) {
+	ctx, span := startSpan(ctx, "stores.webhooks.add")
+	defer span.End()
+
+	if sub == nil || sub.URL == "" || sub.Secret == "" {
+		finishSpan(span, ErrMissingRequired)
+		return "", ErrMissingRequired
+	}
+
+	if sub.CreatedAt.IsZero() {
+		sub.CreatedAt = time.Now().UTC()
+	}
+	res, err := wr.Store().Collection(WEBHOOKS_COLLECTION).InsertOne(ctx, sub)
+	if err != nil {
+		finishSpan(span, err)
+		return "", err
+	}
+
+	id, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		finishSpan(span, ErrDecodeRecId)
+		return "", ErrDecodeRecId
+	}
+	return id.Hex(), nil
+}
+
+func (wr *webhooksRepo) GetSubscription(ctx context.Context, id string) (*whdom.Subscription, error) {
+	ctx, span := startSpan(ctx, "stores.webhooks.get")
+	defer span.End()
+
+	objID, err := objectID(id)
+	if err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	var sub whdom.Subscription
+	if err := wr.Store().Collection(WEBHOOKS_COLLECTION).FindOne(ctx, bson.M{"_id": objID}).Decode(&sub); err != nil {
+		if err == mongo.ErrNoDocuments {
+			finishSpan(span, ErrNoWebhook)
+			return nil, ErrNoWebhook
+		}
+		finishSpan(span, err)
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (wr *webhooksRepo) DeleteSubscription(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "stores.webhooks.delete")
+	defer span.End()
+
+	objID, err := objectID(id)
+	if err != nil {
+		finishSpan(span, err)
+		return err
+	}
+
+	res, err := wr.Store().Collection(WEBHOOKS_COLLECTION).DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		finishSpan(span, err)
+		return err
+	}
+	if res.DeletedCount == 0 {
+		finishSpan(span, ErrNoWebhook)
+		return ErrNoWebhook
+	}
+	return nil
+}
+
+func (wr *webhooksRepo) ListSubscriptions(ctx context.Context, org string) ([]*whdom.Subscription, error) {
+	ctx, span := startSpan(ctx, "stores.webhooks.list")
+	defer span.End()
+
+	filter := bson.M{}
+	if org != "" {
+		filter["org"] = org
+	}
+
+	subs, err := wr.findSubscriptions(ctx, filter)
+	finishSpan(span, err)
+	return subs, err
+}
+
+func (wr *webhooksRepo) MatchSubscriptions(ctx context.Context, eventType string, orgs []string) ([]*whdom.Subscription, error) {
+	ctx, span := startSpan(ctx, "stores.webhooks.match")
+	defer span.End()
+
+	filter := bson.M{
+		"$and": bson.A{
+			bson.M{"org": bson.M{"$in": append([]string{""}, orgs...)}},
+			bson.M{"$or": bson.A{
+				bson.M{"event_types": bson.M{"$size": 0}},
+				bson.M{"event_types": eventType},
+			}},
+		},
+	}
+
+	subs, err := wr.findSubscriptions(ctx, filter)
+	finishSpan(span, err)
+	return subs, err
+}
+
+func (wr *webhooksRepo) EnqueueDeliveries(ctx context.Context, deliveries []*whdom.Delivery) error {
+	ctx, span := startSpan(ctx, "stores.webhooks.enqueue")
+	defer span.End()
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	docs := make([]any, 0, len(deliveries))
+	for _, d := range deliveries {
+		docs = append(docs, d)
+	}
+
+	_, err := wr.Store().Collection(DELIVERIES_COLLECTION).InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		// deliveries already enqueued by a previous publish of the same event are skipped
+		var bwe mongo.BulkWriteException
+		if errors.As(err, &bwe) && bwe.WriteConcernError == nil && allDuplicateKeyErrors(bwe.WriteErrors) {
+			return nil
+		}
+		finishSpan(span, err)
+		return err
+	}
+	return nil
+}
+
+func (wr *webhooksRepo) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*whdom.Delivery, error) {
+	ctx, span := startSpan(ctx, "stores.webhooks.claim")
+	defer span.End()
+
+	coll := wr.Store().Collection(DELIVERIES_COLLECTION)
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	deliveries := []*whdom.Delivery{}
+	for len(deliveries) < limit {
+		now := time.Now().UTC()
+		filter := bson.M{
+			"status":          whdom.DELIVERY_PENDING,
+			"next_attempt_at": bson.M{"$lte": now},
+			"locked_until":    bson.M{"$lte": now},
+		}
+		update := bson.M{"$set": bson.M{
+			"locked_until": now.Add(lease),
+			"claim":        primitive.NewObjectID().Hex(),
+		}}
+
+		var d whdom.Delivery
+		if err := coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&d); err != nil {
+			if err == mongo.ErrNoDocuments {
+				break
+			}
+			finishSpan(span, err)
+			return nil, err
+		}
+		deliveries = append(deliveries, &d)
+	}
+	return deliveries, nil
+}
+
+func (wr *webhooksRepo) ExtendClaim(ctx context.Context, id, claim string, lease time.Duration) error {
+	ctx, span := startSpan(ctx, "stores.webhooks.extend_claim")
+	defer span.End()
+
+	err := wr.settle(ctx, id, claim, bson.M{
+		"$set": bson.M{"locked_until": time.Now().UTC().Add(lease)},
+	})
+	finishSpan(span, err)
+	return err
+}
+
+func (wr *webhooksRepo) MarkDelivered(ctx context.Context, id, claim string, statusCode int) error {
+	ctx, span := startSpan(ctx, "stores.webhooks.delivered")
+	defer span.End()
+
+	err := wr.settle(ctx, id, claim, bson.M{
+		"$set": bson.M{
+			"status":           whdom.DELIVERY_DELIVERED,
+			"delivered_at":     time.Now().UTC(),
+			"last_status_code": statusCode,
+			"locked_until":     time.Time{},
+		},
+		"$unset": bson.M{"claim": ""},
+		"$inc":   bson.M{"attempts": 1},
+	})
+	finishSpan(span, err)
+	return err
+}
+
+func (wr *webhooksRepo) MarkFailed(ctx context.Context, id, claim string, statusCode int, lastErr string, nextAttemptAt *time.Time) error {
+	ctx, span := startSpan(ctx, "stores.webhooks.failed")
+	defer span.End()
+
+	set := bson.M{
+		"last_status_code": statusCode,
+		"last_error":       lastErr,
+		"locked_until":     time.Time{},
+	}
+	if nextAttemptAt != nil {
+		set["next_attempt_at"] = nextAttemptAt.UTC()
+	} else {
+		set["status"] = whdom.DELIVERY_DEAD
+	}
+
+	err := wr.settle(ctx, id, claim, bson.M{
+		"$set":   set,
+		"$unset": bson.M{"claim": ""},
+		"$inc":   bson.M{"attempts": 1},
+	})
+	finishSpan(span, err)
+	return err
+}
+
+func (wr *webhooksRepo) ListDeliveries(ctx context.Context, params *whdom.DeliveriesQuery) ([]*whdom.Delivery, error) {
+	ctx, span := startSpan(ctx, "stores.webhooks.deliveries")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if params == nil || params.SubscriptionID == "" {
+		finishSpan(span, ErrMissingRequired)
+		return nil, ErrMissingRequired
+	}
+
+	filter := bson.M{"webhook_id": params.SubscriptionID}
+	if params.Status != "" {
+		filter["status"] = params.Status
+	}
+	limit := params.Limit
+	if limit <= 0 || limit > DEFAULT_DELIVERIES_LIMIT {
+		limit = DEFAULT_DELIVERIES_LIMIT
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := wr.Store().Collection(DELIVERIES_COLLECTION).Find(ctx, filter, opts)
+	if err != nil {
+		l.Error("ListDeliveries error", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []*whdom.Delivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		l.Error("ListDeliveries error decoding deliveries", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (wr *webhooksRepo) findSubscriptions(ctx context.Context, filter bson.M) ([]*whdom.Subscription, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	cursor, err := wr.Store().Collection(WEBHOOKS_COLLECTION).Find(ctx, filter)
+	if err != nil {
+		l.Error("error finding webhooks", "error", err.Error())
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	subs := []*whdom.Subscription{}
+	if err := cursor.All(ctx, &subs); err != nil {
+		l.Error("error decoding webhooks", "error", err.Error())
+		return nil, err
+	}
+	return subs, nil
+}
+
+// settle updates the delivery while it's pending under the claim, so a dispatcher whose
+// lease expired & was claimed by another can't settle the other's attempt.
+func (wr *webhooksRepo) settle(ctx context.Context, id, claim string, update bson.M) error {
+	objID, err := objectID(id)
+	if err != nil {
+		return err
+	}
+	if claim == "" {
+		return ErrMissingRequired
+	}
+
+	res, err := wr.Store().Collection(DELIVERIES_COLLECTION).UpdateOne(ctx, bson.M{
+		"_id":    objID,
+		"claim":  claim,
+		"status": whdom.DELIVERY_PENDING,
+	}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrClaimLost
+	}
+	return nil
+}
+
+func objectID(id string) (primitive.ObjectID, error) {
+	if id == "" {
+		return primitive.NilObjectID, ErrMissingRequired
+	}
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, ErrDecodeRecId
+	}
+	return objID, nil
+}
+
+func allDuplicateKeyErrors(errs []mongo.BulkWriteError) bool {
+	for _, we := range errs {
+		if we.Code != 11000 {
+			return false
+		}
+	}
+	return len(errs) > 0
+}
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer("stores-webhooks").Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func finishSpan(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(otelcodes.Error, err.Error())
+}
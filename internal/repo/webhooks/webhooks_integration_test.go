@@ -0,0 +1,130 @@
+package webhooks_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/comfforts/logger"
+
+	whdom "github.com/comfforts/comff-stores/internal/domain/webhooks"
+	"github.com/comfforts/comff-stores/internal/infra/mongostore"
+	whrepo "github.com/comfforts/comff-stores/internal/repo/webhooks"
+	envutils "github.com/comfforts/comff-stores/pkg/utils/environ"
+)
+
+func TestWebhooksRepo(t *testing.T) {
+	// Initialize logger
+	l := logger.GetSlogLogger()
+	l.Debug("TestWebhooksRepo Logger initialized")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	nmCfg := envutils.BuildMongoStoreConfig(true)
+	cl, err := mongostore.NewMongoStore(ctx, nmCfg)
+	require.NoError(t, err)
+
+	wr, err := whrepo.NewWebhooksRepo(ctx, cl)
+	require.NoError(t, err)
+
+	defer func() {
+		err := wr.Close(ctx)
+		require.NoError(t, err)
+	}()
+
+	orgHookID, err := wr.AddSubscription(ctx, &whdom.Subscription{
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: []string{"store.added"},
+		Org:        "Webhook Test Org",
+		Secret:     "test-secret",
+	})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, wr.DeleteSubscription(ctx, orgHookID))
+	}()
+
+	allHookID, err := wr.AddSubscription(ctx, &whdom.Subscription{
+		URL:        "https://other.example.com/hooks",
+		EventTypes: []string{},
+		Secret:     "test-secret",
+	})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, wr.DeleteSubscription(ctx, allHookID))
+	}()
+
+	subs, err := wr.ListSubscriptions(ctx, "Webhook Test Org")
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+	require.Equal(t, orgHookID, subs[0].ID)
+
+	matched, err := wr.MatchSubscriptions(ctx, "store.added", []string{"Webhook Test Org"})
+	require.NoError(t, err)
+	ids := []string{}
+	for _, s := range matched {
+		ids = append(ids, s.ID)
+	}
+	require.Contains(t, ids, orgHookID)
+	require.Contains(t, ids, allHookID)
+
+	// a store moved from the org matches its subscriptions too
+	matched, err = wr.MatchSubscriptions(ctx, "store.added", []string{"Other Webhook Test Org", "Webhook Test Org"})
+	require.NoError(t, err)
+	ids = []string{}
+	for _, s := range matched {
+		ids = append(ids, s.ID)
+	}
+	require.Contains(t, ids, orgHookID)
+
+	matched, err = wr.MatchSubscriptions(ctx, "store.deleted", []string{"Webhook Test Org"})
+	require.NoError(t, err)
+	for _, s := range matched {
+		require.NotEqual(t, orgHookID, s.ID)
+	}
+
+	now := time.Now().UTC()
+	dl := &whdom.Delivery{
+		SubscriptionID: orgHookID,
+		EventID:        "webhook-test-event",
+		EventType:      "store.added",
+		Payload:        []byte(`{}`),
+		Status:         whdom.DELIVERY_PENDING,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+	require.NoError(t, wr.EnqueueDeliveries(ctx, []*whdom.Delivery{dl}))
+	// re-enqueueing the same event is a no-op
+	require.NoError(t, wr.EnqueueDeliveries(ctx, []*whdom.Delivery{dl}))
+
+	deliveries, err := wr.ListDeliveries(ctx, &whdom.DeliveriesQuery{SubscriptionID: orgHookID})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	dlID := deliveries[0].ID
+
+	// deliveries are settled under their claim only
+	require.ErrorIs(t, wr.MarkFailed(ctx, dlID, "other-claim", 500, "server error", nil), whdom.ErrClaimLost)
+	claimed, err := wr.ClaimDeliveries(ctx, 1000, time.Minute)
+	require.NoError(t, err)
+	claim := ""
+	for _, d := range claimed {
+		if d.ID == dlID {
+			claim = d.Claim
+		}
+	}
+	require.NotEmpty(t, claim)
+	require.NoError(t, wr.ExtendClaim(ctx, dlID, claim, time.Minute))
+	require.NoError(t, wr.MarkFailed(ctx, dlID, claim, 500, "server error", nil))
+	require.ErrorIs(t, wr.MarkFailed(ctx, dlID, claim, 500, "server error", nil), whdom.ErrClaimLost)
+	deliveries, err = wr.ListDeliveries(ctx, &whdom.DeliveriesQuery{
+		SubscriptionID: orgHookID,
+		Status:         whdom.DELIVERY_DEAD,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, 1, deliveries[0].Attempts)
+	require.Equal(t, 500, deliveries[0].LastStatusCode)
+}
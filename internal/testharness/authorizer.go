@@ -0,0 +1,37 @@
+package testharness
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ALL_ACTIONS in a policy allows every action.
+const ALL_ACTIONS = "*"
+
+// Policy maps a certificate subject to the actions it's allowed.
+type Policy map[string][]string
+
+// DefaultPolicy allows root everything & nobody nothing,
+// matching the policy the stores server is deployed with.
+func DefaultPolicy() Policy {
+	return Policy{
+		ROOT_SUBJECT: {ALL_ACTIONS},
+	}
+}
+
+// policyAuthorizer is a grpc handler Authorizer over a static Policy.
+type policyAuthorizer struct {
+	policy Policy
+}
+
+func NewPolicyAuthorizer(policy Policy) *policyAuthorizer {
+	return &policyAuthorizer{policy: policy}
+}
+
+func (pa *policyAuthorizer) Authorize(subject, object, action string) error {
+	actions := pa.policy[subject]
+	if slices.Contains(actions, ALL_ACTIONS) || slices.Contains(actions, action) {
+		return nil
+	}
+	return fmt.Errorf("%s not permitted to %s to %s", subject, action, object)
+}
@@ -0,0 +1,139 @@
+package testharness
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"time"
+)
+
+const (
+	// ROOT_SUBJECT is the common name of the client certificate allowed all actions by the default policy.
+	ROOT_SUBJECT = "root"
+	// NOBODY_SUBJECT is the common name of the client certificate allowed nothing by the default policy.
+	NOBODY_SUBJECT = "nobody"
+	// SERVER_NAME is the name the in-process servers are dialed & verified with.
+	SERVER_NAME = "bufnet"
+)
+
+// Certs are a throwaway CA & the server and client certificates it signed,
+// generated in memory for the in-process servers.
+type Certs struct {
+	CA     *x509.Certificate
+	Pool   *x509.CertPool
+	Server tls.Certificate
+	Root   tls.Certificate
+	Nobody tls.Certificate
+}
+
+// GenerateCerts generates a CA, a server certificate for SERVER_NAME, localhost & 127.0.0.1,
+// and the root & nobody client certificates.
+func GenerateCerts() (*Certs, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	caTmpl, err := certTemplate("stores-test-ca")
+	if err != nil {
+		return nil, err
+	}
+	caTmpl.IsCA = true
+	caTmpl.BasicConstraintsValid = true
+	caTmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	certs := &Certs{
+		CA:   ca,
+		Pool: pool,
+	}
+
+	if certs.Server, err = leafCert(ca, caKey, "stores-test-server", x509.ExtKeyUsageServerAuth); err != nil {
+		return nil, err
+	}
+	if certs.Root, err = leafCert(ca, caKey, ROOT_SUBJECT, x509.ExtKeyUsageClientAuth); err != nil {
+		return nil, err
+	}
+	if certs.Nobody, err = leafCert(ca, caKey, NOBODY_SUBJECT, x509.ExtKeyUsageClientAuth); err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
+
+// ServerTLSConfig requires & verifies client certificates, like the stores server in production.
+func (c *Certs) ServerTLSConfig() *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{c.Server},
+		ClientCAs:    c.Pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}
+}
+
+// ClientTLSConfig presents the given client certificate & verifies the server against the CA.
+func (c *Certs) ClientTLSConfig(cert tls.Certificate) *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      c.Pool,
+		ServerName:   SERVER_NAME,
+		MinVersion:   tls.VersionTLS12,
+	}
+}
+
+func leafCert(ca *x509.Certificate, caKey *ecdsa.PrivateKey, cn string, usage x509.ExtKeyUsage) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	tmpl, err := certTemplate(cn)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+	tmpl.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+	if usage == x509.ExtKeyUsageServerAuth {
+		tmpl.DNSNames = []string{SERVER_NAME, "localhost"}
+		tmpl.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, nil
+}
+
+func certTemplate(cn string) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn, Organization: []string{"comfforts"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(24 * time.Hour),
+	}, nil
+}
@@ -0,0 +1,194 @@
+package testharness
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	geo_v1 "github.com/comfforts/comff-geo/api/geo/v1"
+	geocl "github.com/comfforts/comff-geo/clients/go"
+	"github.com/comfforts/logger"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	geoinfra "github.com/comfforts/comff-stores/internal/infra/geo"
+	testutils "github.com/comfforts/comff-stores/pkg/utils/test"
+)
+
+const BUFCONN_SIZE = 1024 * 1024
+
+var _ geo_v1.GeoServer = (*GeoServer)(nil)
+
+// GeoServer is an in-process fake comff-geo gRPC server over bufconn,
+// resolving addresses with the offline geocoder. Failures can be injected
+// to exercise the stores service's handling of an unavailable geo service.
+type GeoServer struct {
+	geo_v1.UnimplementedGeoServer
+	geocoder geodom.Geocoder
+	lis      *bufconn.Listener
+	srv      *grpc.Server
+
+	mu      sync.Mutex
+	failure error
+	calls   int
+}
+
+// DatasetFixtures are the pkg/utils/test data set addresses as geocoder fixtures.
+func DatasetFixtures() []*geoinfra.FixtureAddress {
+	fixtures := []*geoinfra.FixtureAddress{}
+	for _, dest := range append(testutils.BuildPetalumaSet1(), testutils.BuildPetalumaSet2()...) {
+		fixtures = append(fixtures, &geoinfra.FixtureAddress{
+			Street:     dest.GetStreet(),
+			City:       dest.GetCity(),
+			State:      dest.GetState(),
+			PostalCode: dest.GetPostalCode(),
+			Country:    dest.GetCountry(),
+			Latitude:   dest.GetLatitude(),
+			Longitude:  dest.GetLongitude(),
+		})
+	}
+	return fixtures
+}
+
+// NewGeoServer starts a fake geo server resolving the given addresses,
+// the pkg/utils/test data sets when none are given.
+func NewGeoServer(ctx context.Context, fixtures []*geoinfra.FixtureAddress) (*GeoServer, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if len(fixtures) == 0 {
+		fixtures = DatasetFixtures()
+	}
+	geocoder, err := geoinfra.NewOfflineGeocoderFromFixtures(ctx, fixtures)
+	if err != nil {
+		return nil, err
+	}
+
+	gs := &GeoServer{
+		geocoder: geocoder,
+		lis:      bufconn.Listen(BUFCONN_SIZE),
+		srv:      grpc.NewServer(),
+	}
+	geo_v1.RegisterGeoServer(gs.srv, gs)
+	go func() {
+		if err := gs.srv.Serve(gs.lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			l.Error("fake geo server stopped serving", "error", err.Error())
+		}
+	}()
+
+	l.Info("fake geo server started", "addresses", len(fixtures))
+	return gs, nil
+}
+
+// Client returns a geo client connected to the fake server.
+func (gs *GeoServer) Client(ctx context.Context) (geocl.Client, error) {
+	conn, err := grpc.NewClient(
+		"passthrough:///"+SERVER_NAME,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return gs.lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &geoClient{
+		GeoClient: geo_v1.NewGeoClient(conn),
+		conn:      conn,
+	}, nil
+}
+
+// SetFailure makes every call fail with err until cleared with nil,
+// e.g. status.Error(codes.Unavailable, ...) for a geo outage.
+func (gs *GeoServer) SetFailure(err error) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.failure = err
+}
+
+// Calls returns the number of calls served, failed ones included.
+func (gs *GeoServer) Calls() int {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	return gs.calls
+}
+
+func (gs *GeoServer) Stop() {
+	gs.srv.Stop()
+}
+
+func (gs *GeoServer) GeoLocate(ctx context.Context, req *geo_v1.GeoRequest) (*geo_v1.GeoResponse, error) {
+	if err := gs.served(); err != nil {
+		return nil, err
+	}
+
+	var (
+		loc *geodom.Location
+		err error
+	)
+	switch {
+	case req.GetLatitude() != 0 || req.GetLongitude() != 0:
+		loc, err = gs.geocoder.GeocodeLatLon(ctx, req.GetLatitude(), req.GetLongitude())
+	case req.GetAddressStr() != "":
+		loc, err = gs.geocoder.GeocodeAddress(ctx, req.GetAddressStr())
+	default:
+		parts := []string{}
+		for _, p := range []string{req.GetStreet(), req.GetCity(), req.GetState(), req.GetPostalCode(), req.GetCountry()} {
+			if p != "" {
+				parts = append(parts, p)
+			}
+		}
+		if len(parts) == 0 {
+			return nil, status.Error(codes.InvalidArgument, "address or lat/lon required")
+		}
+		loc, err = gs.geocoder.GeocodeAddress(ctx, strings.Join(parts, ", "))
+	}
+	return geoResponse(loc, err)
+}
+
+func (gs *GeoServer) GetGeoLocation(ctx context.Context, req *geo_v1.GeoLocationRequest) (*geo_v1.GeoResponse, error) {
+	if err := gs.served(); err != nil {
+		return nil, err
+	}
+	return geoResponse(gs.geocoder.LocateAddressId(ctx, req.GetAddressId()))
+}
+
+func (gs *GeoServer) served() error {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.calls++
+	return gs.failure
+}
+
+func geoResponse(loc *geodom.Location, err error) (*geo_v1.GeoResponse, error) {
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &geo_v1.GeoResponse{
+		Point: &geo_v1.Point{
+			Hash:             loc.AddressId,
+			Latitude:         loc.Latitude,
+			Longitude:        loc.Longitude,
+			FormattedAddress: loc.FormattedAddress,
+		},
+	}, nil
+}
+
+// geoClient is a geo client over a connection to the fake geo server.
+type geoClient struct {
+	geo_v1.GeoClient
+	conn *grpc.ClientConn
+}
+
+func (gc *geoClient) Close(ctx context.Context) error {
+	return gc.conn.Close()
+}
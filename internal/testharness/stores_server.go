@@ -0,0 +1,215 @@
+package testharness
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/comfforts/logger"
+
+	api "github.com/comfforts/comff-stores/api/stores/v1"
+	grpchandler "github.com/comfforts/comff-stores/internal/delivery/stores/grpc_handler"
+	blobdom "github.com/comfforts/comff-stores/internal/domain/blobs"
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	iddom "github.com/comfforts/comff-stores/internal/domain/idempotency"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+	whdom "github.com/comfforts/comff-stores/internal/domain/webhooks"
+	"github.com/comfforts/comff-stores/internal/infra/capabilities"
+	geoinfra "github.com/comfforts/comff-stores/internal/infra/geo"
+	"github.com/comfforts/comff-stores/internal/infra/observability"
+	"github.com/comfforts/comff-stores/internal/infra/routing"
+	strepo "github.com/comfforts/comff-stores/internal/repo/stores"
+	"github.com/comfforts/comff-stores/internal/usecase/services/stores"
+)
+
+// StoresServerOptions configure the in-process stores server, zero values give
+// an in-memory stores repo, the data set geo fixtures & the default policy.
+type StoresServerOptions struct {
+	StoresRepo stdom.StoresRepo
+	// AddressUniqueness is the in-memory stores repo's address uniqueness rule,
+	// ignored with StoresRepo.
+	AddressUniqueness stdom.AddressUniqueness
+	// WebhooksService & IdempotencyRepo are optional, webhook RPCs return
+	// Unimplemented & idempotency keys are ignored without them.
+	WebhooksService whdom.WebhooksService
+	IdempotencyRepo iddom.IdempotencyRepo
+	IdempotencyTTL  time.Duration
+	Policy          Policy
+	GeoFixtures     []*geoinfra.FixtureAddress
+	// GeoResilience configures the geo client decorator, retries are off by default.
+	GeoResilience geodom.ResilienceOptions
+	// Routing ranks routed searches, a default haversine router when nil.
+	Routing geodom.RoutingProvider
+	// Capabilities is the capability catalog, the built-in one when nil.
+	Capabilities *stdom.CapabilityCatalog
+	// Blobs stores attachment uploads, uploads fail without it.
+	Blobs blobdom.BlobStore
+	// ValidationRules are the compiled store validation rules, none when nil.
+	ValidationRules *stdom.ValidationRules
+}
+
+// StoresServer is the stores gRPC server, with the production handler, interceptors,
+// TLS client authentication & authorization, served in-process over bufconn with
+// generated certificates, backed by a fake geo server.
+type StoresServer struct {
+	Certs *Certs
+	Geo   *GeoServer
+	Repo  stdom.StoresRepo
+	// Scheduler applies scheduled status changes, in place of the server's status scheduler.
+	Scheduler stdom.StatusScheduler
+	// Client authenticates as root, NobodyClient as nobody.
+	Client       api.StoresClient
+	NobodyClient api.StoresClient
+
+	lis      *bufconn.Listener
+	srv      *grpc.Server
+	geocoder geodom.Geocoder
+	conns    []*grpc.ClientConn
+}
+
+func NewStoresServer(ctx context.Context, opts StoresServerOptions) (*StoresServer, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	certs, err := GenerateCerts()
+	if err != nil {
+		l.Error("error generating test certificates", "error", err.Error())
+		return nil, err
+	}
+
+	metrics, err := observability.NewMetrics()
+	if err != nil {
+		return nil, err
+	}
+
+	sr := opts.StoresRepo
+	if sr == nil {
+		if sr, err = strepo.NewMemoryStoresRepo(ctx, metrics, opts.AddressUniqueness); err != nil {
+			return nil, err
+		}
+	}
+
+	gs, err := NewGeoServer(ctx, opts.GeoFixtures)
+	if err != nil {
+		return nil, err
+	}
+	ss := &StoresServer{
+		Certs: certs,
+		Geo:   gs,
+		Repo:  sr,
+		lis:   bufconn.Listen(BUFCONN_SIZE),
+	}
+
+	gc, err := gs.Client(ctx)
+	if err != nil {
+		gs.Stop()
+		return nil, err
+	}
+	rgc, err := geoinfra.NewResilientClient(ctx, gc, opts.GeoResilience)
+	if err != nil {
+		gs.Stop()
+		return nil, err
+	}
+	if ss.geocoder, err = geoinfra.NewComffGeocoder(rgc); err != nil {
+		gs.Stop()
+		return nil, err
+	}
+
+	router := opts.Routing
+	if router == nil {
+		router = routing.NewHaversineRouter(routing.HaversineOptions{})
+	}
+	catalog := opts.Capabilities
+	if catalog == nil {
+		if catalog, err = capabilities.LoadCatalog(ctx, ""); err != nil {
+			gs.Stop()
+			return nil, err
+		}
+	}
+	svc, err := stores.NewStoresService(ctx, sr, ss.geocoder, metrics, stores.StoresServiceOptions{
+		Routing:         router,
+		Capabilities:    catalog,
+		Blobs:           opts.Blobs,
+		ValidationRules: opts.ValidationRules,
+	})
+	if err != nil {
+		gs.Stop()
+		return nil, err
+	}
+	ss.Scheduler = svc
+
+	policy := opts.Policy
+	if policy == nil {
+		policy = DefaultPolicy()
+	}
+	cfg := &grpchandler.Config{
+		Authorizer:      NewPolicyAuthorizer(policy),
+		StoresService:   svc,
+		WebhooksService: opts.WebhooksService,
+		IdempotencyRepo: opts.IdempotencyRepo,
+		IdempotencyTTL:  opts.IdempotencyTTL,
+	}
+	if ss.srv, err = grpchandler.NewGRPCServer(cfg, grpc.Creds(credentials.NewTLS(certs.ServerTLSConfig()))); err != nil {
+		gs.Stop()
+		return nil, err
+	}
+	go func() {
+		if err := ss.srv.Serve(ss.lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			l.Error("in-process stores server stopped serving", "error", err.Error())
+		}
+	}()
+
+	rootConn, err := ss.Dial(certs.Root)
+	if err != nil {
+		ss.Close(ctx)
+		return nil, err
+	}
+	ss.Client = api.NewStoresClient(rootConn)
+
+	nobodyConn, err := ss.Dial(certs.Nobody)
+	if err != nil {
+		ss.Close(ctx)
+		return nil, err
+	}
+	ss.NobodyClient = api.NewStoresClient(nobodyConn)
+
+	l.Info("in-process stores server started")
+	return ss, nil
+}
+
+// Dial connects to the stores server presenting the given client certificate,
+// the connection is closed with the server.
+func (ss *StoresServer) Dial(cert tls.Certificate, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ss.lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(credentials.NewTLS(ss.Certs.ClientTLSConfig(cert))),
+	}, opts...)
+	conn, err := grpc.NewClient("passthrough:///"+SERVER_NAME, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ss.conns = append(ss.conns, conn)
+	return conn, nil
+}
+
+// Close closes client connections, the geocoder & stores repo, and stops the stores & geo servers.
+func (ss *StoresServer) Close(ctx context.Context) error {
+	var err error
+	for _, conn := range ss.conns {
+		err = errors.Join(err, conn.Close())
+	}
+	ss.srv.Stop()
+	err = errors.Join(err, ss.geocoder.Close(ctx), ss.Repo.Close(ctx))
+	ss.Geo.Stop()
+	return err
+}
@@ -0,0 +1,81 @@
+package relay
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// pollLoop runs a poll function in a goroutine every interval, until stopped.
+type pollLoop struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// start runs poll right away & then every interval, until stop is called or ctx is done.
+// It returns errRunning if the loop is already running.
+func (pl *pollLoop) start(ctx context.Context, interval time.Duration, poll func(ctx context.Context), errRunning error) error {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	if pl.cancel != nil {
+		return errRunning
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	pl.cancel = cancel
+	pl.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			poll(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}(pl.done)
+	return nil
+}
+
+// stop signals the loop to exit & waits for the in-flight poll to settle.
+func (pl *pollLoop) stop(ctx context.Context) error {
+	pl.mu.Lock()
+	cancel, done := pl.cancel, pl.done
+	pl.cancel, pl.done = nil, nil
+	pl.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// backoff returns an exponential backoff, jittered between half and full value,
+// for the given number of prior attempts.
+func backoff(attempts int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 0; i < attempts && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d/2 + rand.N(d/2+1)
+}
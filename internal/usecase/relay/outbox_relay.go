@@ -0,0 +1,170 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/comfforts/logger"
+
+	evdom "github.com/comfforts/comff-stores/internal/domain/events"
+)
+
+const (
+	DEFAULT_BATCH_SIZE    = 50
+	DEFAULT_POLL_INTERVAL = 2 * time.Second
+	DEFAULT_LEASE         = 30 * time.Second
+	DEFAULT_BASE_BACKOFF  = time.Second
+	DEFAULT_MAX_BACKOFF   = 5 * time.Minute
+)
+
+const (
+	ERR_MISSING_OUTBOX_REPO = "missing outbox repo"
+	ERR_MISSING_PUBLISHER   = "missing event publisher"
+	ERR_RELAY_RUNNING       = "outbox relay already running"
+)
+
+var (
+	ErrMissingOutboxRepo = errors.New(ERR_MISSING_OUTBOX_REPO)
+	ErrMissingPublisher  = errors.New(ERR_MISSING_PUBLISHER)
+	ErrRelayRunning      = errors.New(ERR_RELAY_RUNNING)
+)
+
+type RelayOptions struct {
+	BatchSize    int
+	PollInterval time.Duration
+	Lease        time.Duration
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+func DefaultRelayOptions() RelayOptions {
+	return RelayOptions{
+		BatchSize:    DEFAULT_BATCH_SIZE,
+		PollInterval: DEFAULT_POLL_INTERVAL,
+		Lease:        DEFAULT_LEASE,
+		BaseBackoff:  DEFAULT_BASE_BACKOFF,
+		MaxBackoff:   DEFAULT_MAX_BACKOFF,
+	}
+}
+
+// outboxRelay polls the outbox for pending events & hands them to the publisher.
+// Entries are only marked delivered after a successful publish, so an event
+// may be published more than once (at-least-once), but never dropped.
+type outboxRelay struct {
+	repo evdom.OutboxRepo
+	pub  evdom.EventPublisher
+	opts RelayOptions
+
+	loop pollLoop
+}
+
+func NewOutboxRelay(ctx context.Context, repo evdom.OutboxRepo, pub evdom.EventPublisher, opts RelayOptions) (*outboxRelay, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if repo == nil {
+		return nil, ErrMissingOutboxRepo
+	}
+	if pub == nil {
+		return nil, ErrMissingPublisher
+	}
+
+	defaults := DefaultRelayOptions()
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaults.BatchSize
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaults.PollInterval
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = defaults.Lease
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = defaults.BaseBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaults.MaxBackoff
+	}
+
+	l.Info("initialized outbox relay", "batch_size", opts.BatchSize, "poll_interval", opts.PollInterval.String())
+	return &outboxRelay{
+		repo: repo,
+		pub:  pub,
+		opts: opts,
+	}, nil
+}
+
+// Start runs the relay loop in a goroutine until Stop is called or ctx is done.
+func (r *outboxRelay) Start(ctx context.Context) error {
+	return r.loop.start(ctx, r.opts.PollInterval, func(ctx context.Context) { r.RelayBatch(ctx) }, ErrRelayRunning)
+}
+
+// Stop signals the relay loop to exit & waits for the in-flight batch to settle.
+func (r *outboxRelay) Stop(ctx context.Context) error {
+	return r.loop.stop(ctx)
+}
+
+// RelayBatch claims a batch of due outbox entries & publishes them.
+// It returns the number of entries delivered.
+func (r *outboxRelay) RelayBatch(ctx context.Context) int {
+	ctx, span := startSpan(ctx, "stores.relay.batch")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	entries, err := r.repo.ClaimPending(ctx, r.opts.BatchSize, r.opts.Lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			l.Error("error claiming outbox entries", "error", err.Error())
+			finishSpan(span, err)
+		}
+		return 0
+	}
+
+	delivered := 0
+	for _, entry := range entries {
+		if err := r.pub.Publish(ctx, entry.Event); err != nil {
+			next := time.Now().Add(backoff(entry.Attempts, r.opts.BaseBackoff, r.opts.MaxBackoff))
+			l.Warn("error publishing outbox event, will retry", "error", err.Error(), "entry_id", entry.ID, "attempts", entry.Attempts+1, "next_attempt_at", next)
+			if mErr := r.repo.MarkFailed(ctx, entry.ID, next, err.Error()); mErr != nil {
+				l.Error("error marking outbox entry failed", "error", mErr.Error(), "entry_id", entry.ID)
+			}
+			continue
+		}
+
+		// if this fails the entry is re-delivered after the lease expires
+		if err := r.repo.MarkDelivered(ctx, entry.ID); err != nil {
+			l.Error("error marking outbox entry delivered", "error", err.Error(), "entry_id", entry.ID)
+			continue
+		}
+		delivered++
+	}
+
+	if len(entries) > 0 {
+		l.Debug("relayed outbox entries", "claimed", len(entries), "delivered", delivered)
+	}
+	return delivered
+}
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer("stores-relay").Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func finishSpan(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(otelcodes.Error, err.Error())
+}
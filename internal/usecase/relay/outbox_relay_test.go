@@ -0,0 +1,175 @@
+package relay_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/comfforts/logger"
+
+	evdom "github.com/comfforts/comff-stores/internal/domain/events"
+	"github.com/comfforts/comff-stores/internal/usecase/relay"
+)
+
+func TestOutboxRelayRetries(t *testing.T) {
+	l := logger.GetSlogLogger()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	repo := newMemOutbox(
+		&evdom.Event{ID: "ev-1", Type: evdom.STORE_ADDED, StoreID: "st-1"},
+		&evdom.Event{ID: "ev-2", Type: evdom.STORE_UPDATED, StoreID: "st-1"},
+	)
+	// fail the first publish of every event
+	pub := &flakyPublisher{failures: map[string]int{"ev-1": 1, "ev-2": 1}}
+
+	rl, err := relay.NewOutboxRelay(ctx, repo, pub, relay.RelayOptions{
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 0, rl.RelayBatch(ctx))
+	require.Equal(t, 2, repo.pendingCount())
+
+	require.Eventually(t, func() bool {
+		rl.RelayBatch(ctx)
+		return repo.pendingCount() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.ElementsMatch(t, []string{"ev-1", "ev-2"}, pub.published())
+	for _, e := range repo.entries {
+		require.Equal(t, 2, e.Attempts)
+		require.NotNil(t, e.DeliveredAt)
+	}
+}
+
+func TestOutboxRelayStartStop(t *testing.T) {
+	l := logger.GetSlogLogger()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	repo := newMemOutbox(&evdom.Event{ID: "ev-1", Type: evdom.STORE_DELETED, StoreID: "st-1"})
+	pub := &flakyPublisher{}
+
+	rl, err := relay.NewOutboxRelay(ctx, repo, pub, relay.RelayOptions{
+		PollInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, rl.Start(ctx))
+	require.ErrorIs(t, rl.Start(ctx), relay.ErrRelayRunning)
+
+	require.Eventually(t, func() bool {
+		return repo.pendingCount() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, rl.Stop(ctx))
+	require.Equal(t, []string{"ev-1"}, pub.published())
+}
+
+type memOutbox struct {
+	mu      sync.Mutex
+	entries map[string]*evdom.OutboxEntry
+}
+
+func newMemOutbox(evs ...*evdom.Event) *memOutbox {
+	mo := &memOutbox{entries: map[string]*evdom.OutboxEntry{}}
+	for _, ev := range evs {
+		mo.entries[ev.ID] = &evdom.OutboxEntry{
+			ID:     ev.ID,
+			Event:  ev,
+			Status: evdom.OUTBOX_PENDING,
+		}
+	}
+	return mo
+}
+
+func (mo *memOutbox) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*evdom.OutboxEntry, error) {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+
+	now := time.Now()
+	claimed := []*evdom.OutboxEntry{}
+	for _, e := range mo.entries {
+		if len(claimed) == limit {
+			break
+		}
+		if e.Status != evdom.OUTBOX_PENDING || e.NextAttemptAt.After(now) || e.LockedUntil.After(now) {
+			continue
+		}
+		e.LockedUntil = now.Add(lease)
+		cp := *e
+		claimed = append(claimed, &cp)
+	}
+	return claimed, nil
+}
+
+func (mo *memOutbox) MarkDelivered(ctx context.Context, id string) error {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+
+	now := time.Now()
+	e := mo.entries[id]
+	e.Status = evdom.OUTBOX_DELIVERED
+	e.DeliveredAt = &now
+	e.Attempts++
+	e.LockedUntil = time.Time{}
+	return nil
+}
+
+func (mo *memOutbox) MarkFailed(ctx context.Context, id string, nextAttemptAt time.Time, lastErr string) error {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+
+	e := mo.entries[id]
+	e.NextAttemptAt = nextAttemptAt
+	e.LastError = lastErr
+	e.Attempts++
+	e.LockedUntil = time.Time{}
+	return nil
+}
+
+func (mo *memOutbox) pendingCount() int {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+
+	n := 0
+	for _, e := range mo.entries {
+		if e.Status == evdom.OUTBOX_PENDING {
+			n++
+		}
+	}
+	return n
+}
+
+type flakyPublisher struct {
+	mu       sync.Mutex
+	failures map[string]int
+	events   []string
+}
+
+func (fp *flakyPublisher) Publish(ctx context.Context, ev *evdom.Event) error {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	if fp.failures[ev.ID] > 0 {
+		fp.failures[ev.ID]--
+		return errors.New("publish failed")
+	}
+	fp.events = append(fp.events, ev.ID)
+	return nil
+}
+
+func (fp *flakyPublisher) Close(ctx context.Context) error { return nil }
+
+func (fp *flakyPublisher) published() []string {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+	return append([]string{}, fp.events...)
+}
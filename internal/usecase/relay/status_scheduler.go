@@ -0,0 +1,121 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/comfforts/logger"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+const (
+	DEFAULT_STATUS_BATCH_SIZE    = 100
+	DEFAULT_STATUS_POLL_INTERVAL = 30 * time.Second
+	DEFAULT_STATUS_LEASE         = time.Minute
+)
+
+const (
+	ERR_MISSING_STATUS_SCHEDULER = "missing status scheduler"
+	ERR_SCHEDULER_RUNNING        = "status scheduler already running"
+)
+
+var (
+	ErrMissingStatusScheduler = errors.New(ERR_MISSING_STATUS_SCHEDULER)
+	ErrSchedulerRunning       = errors.New(ERR_SCHEDULER_RUNNING)
+)
+
+type SchedulerOptions struct {
+	BatchSize    int
+	PollInterval time.Duration
+	Lease        time.Duration
+}
+
+func DefaultSchedulerOptions() SchedulerOptions {
+	return SchedulerOptions{
+		BatchSize:    DEFAULT_STATUS_BATCH_SIZE,
+		PollInterval: DEFAULT_STATUS_POLL_INTERVAL,
+		Lease:        DEFAULT_STATUS_LEASE,
+	}
+}
+
+// statusScheduler polls for stores' due status changes & applies them, closing stores
+// as their closures start & reopening them as they end, within a poll interval. Each
+// batch is claimed for a lease, so schedulers on several instances share the changes.
+type statusScheduler struct {
+	ss   stdom.StatusScheduler
+	opts SchedulerOptions
+
+	loop pollLoop
+}
+
+func NewStatusScheduler(ctx context.Context, ss stdom.StatusScheduler, opts SchedulerOptions) (*statusScheduler, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if ss == nil {
+		return nil, ErrMissingStatusScheduler
+	}
+
+	defaults := DefaultSchedulerOptions()
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaults.BatchSize
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaults.PollInterval
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = defaults.Lease
+	}
+
+	l.Info("initialized status scheduler", "batch_size", opts.BatchSize, "poll_interval", opts.PollInterval.String(), "lease", opts.Lease.String())
+	return &statusScheduler{
+		ss:   ss,
+		opts: opts,
+	}, nil
+}
+
+// Start runs the scheduler loop in a goroutine until Stop is called or ctx is done.
+func (s *statusScheduler) Start(ctx context.Context) error {
+	return s.loop.start(ctx, s.opts.PollInterval, func(ctx context.Context) {
+		// full batches may leave more changes due
+		for ctx.Err() == nil {
+			if s.ApplyDue(ctx, time.Now()) < s.opts.BatchSize {
+				break
+			}
+		}
+	}, ErrSchedulerRunning)
+}
+
+// Stop signals the scheduler loop to exit & waits for the in-flight batch to settle.
+func (s *statusScheduler) Stop(ctx context.Context) error {
+	return s.loop.stop(ctx)
+}
+
+// ApplyDue applies a batch of the status changes due at the time.
+// It returns the number of stores changed.
+func (s *statusScheduler) ApplyDue(ctx context.Context, at time.Time) int {
+	ctx, span := startSpan(ctx, "stores.scheduler.batch")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	changed, err := s.ss.ApplyScheduledStatusChanges(ctx, at, s.opts.BatchSize, s.opts.Lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			l.Error("error applying scheduled status changes", "error", err.Error())
+			finishSpan(span, err)
+		}
+		return 0
+	}
+	if changed > 0 {
+		l.Debug("applied scheduled status changes", "changed", changed)
+	}
+	return changed
+}
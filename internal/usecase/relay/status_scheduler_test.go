@@ -0,0 +1,110 @@
+package relay_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/comfforts/logger"
+
+	"github.com/comfforts/comff-stores/internal/usecase/relay"
+)
+
+func TestStatusSchedulerApplyDue(t *testing.T) {
+	l := logger.GetSlogLogger()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	fs := &fakeStatusScheduler{due: 3}
+	sc, err := relay.NewStatusScheduler(ctx, fs, relay.SchedulerOptions{BatchSize: 2})
+	require.NoError(t, err)
+
+	at := time.Now()
+	require.Equal(t, 2, sc.ApplyDue(ctx, at))
+	require.Equal(t, 1, sc.ApplyDue(ctx, at))
+	require.Equal(t, 0, sc.ApplyDue(ctx, at))
+	require.Equal(t, []int{2, 2, 2}, fs.limits())
+	// batches are claimed for the default lease
+	require.Equal(t, relay.DEFAULT_STATUS_LEASE, fs.claimLease())
+
+	fs.setErr(errors.New("repo down"))
+	require.Equal(t, 0, sc.ApplyDue(ctx, at))
+
+	_, err = relay.NewStatusScheduler(ctx, nil, relay.SchedulerOptions{})
+	require.ErrorIs(t, err, relay.ErrMissingStatusScheduler)
+}
+
+func TestStatusSchedulerStartStop(t *testing.T) {
+	l := logger.GetSlogLogger()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	// more changes due than a batch, drained without waiting on the poll interval
+	fs := &fakeStatusScheduler{due: 5}
+	sc, err := relay.NewStatusScheduler(ctx, fs, relay.SchedulerOptions{
+		BatchSize:    2,
+		PollInterval: time.Hour,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, sc.Start(ctx))
+	require.ErrorIs(t, sc.Start(ctx), relay.ErrSchedulerRunning)
+
+	require.Eventually(t, func() bool {
+		return fs.remaining() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, sc.Stop(ctx))
+	require.NoError(t, sc.Stop(ctx))
+}
+
+type fakeStatusScheduler struct {
+	mu    sync.Mutex
+	due   int
+	err   error
+	calls []int
+	lease time.Duration
+}
+
+func (fs *fakeStatusScheduler) ApplyScheduledStatusChanges(ctx context.Context, at time.Time, limit int, lease time.Duration) (int, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.calls = append(fs.calls, limit)
+	fs.lease = lease
+	if fs.err != nil {
+		return 0, fs.err
+	}
+	changed := min(fs.due, limit)
+	fs.due -= changed
+	return changed, nil
+}
+
+func (fs *fakeStatusScheduler) setErr(err error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.err = err
+}
+
+func (fs *fakeStatusScheduler) limits() []int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return append([]int{}, fs.calls...)
+}
+
+func (fs *fakeStatusScheduler) claimLease() time.Duration {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.lease
+}
+
+func (fs *fakeStatusScheduler) remaining() int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.due
+}
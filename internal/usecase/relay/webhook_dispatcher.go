@@ -0,0 +1,254 @@
+package relay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/comfforts/logger"
+
+	whdom "github.com/comfforts/comff-stores/internal/domain/webhooks"
+)
+
+const (
+	DEFAULT_MAX_ATTEMPTS    = 8
+	DEFAULT_REQUEST_TIMEOUT = 10 * time.Second
+)
+
+const (
+	ERR_MISSING_WEBHOOKS_REPO = "missing webhooks repo"
+	ERR_DISPATCHER_RUNNING    = "webhook dispatcher already running"
+)
+
+var (
+	ErrMissingWebhooksRepo = errors.New(ERR_MISSING_WEBHOOKS_REPO)
+	ErrDispatcherRunning   = errors.New(ERR_DISPATCHER_RUNNING)
+)
+
+type DispatcherOptions struct {
+	RelayOptions
+	// MaxAttempts is the number of attempts after which a delivery is dead-lettered.
+	MaxAttempts    int
+	RequestTimeout time.Duration
+	// Client sends the deliveries, when nil one that only dials public addresses &
+	// doesn't follow redirects.
+	Client *http.Client
+}
+
+func DefaultDispatcherOptions() DispatcherOptions {
+	return DispatcherOptions{
+		RelayOptions:   DefaultRelayOptions(),
+		MaxAttempts:    DEFAULT_MAX_ATTEMPTS,
+		RequestTimeout: DEFAULT_REQUEST_TIMEOUT,
+	}
+}
+
+// webhookDispatcher polls for due webhook deliveries & POSTs them, signed with
+// the subscription secret, to the subscriber URL. Failed deliveries are retried
+// with exponential backoff until MaxAttempts, after which they are dead-lettered.
+type webhookDispatcher struct {
+	repo whdom.WebhooksRepo
+	opts DispatcherOptions
+
+	loop pollLoop
+}
+
+func NewWebhookDispatcher(ctx context.Context, repo whdom.WebhooksRepo, opts DispatcherOptions) (*webhookDispatcher, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if repo == nil {
+		return nil, ErrMissingWebhooksRepo
+	}
+
+	defaults := DefaultDispatcherOptions()
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaults.BatchSize
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaults.PollInterval
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = defaults.Lease
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = defaults.BaseBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaults.MaxBackoff
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaults.MaxAttempts
+	}
+	if opts.RequestTimeout <= 0 {
+		opts.RequestTimeout = defaults.RequestTimeout
+	}
+	if opts.Client == nil {
+		opts.Client = publicClient(opts.RequestTimeout)
+	}
+
+	l.Info("initialized webhook dispatcher", "batch_size", opts.BatchSize, "max_attempts", opts.MaxAttempts)
+	return &webhookDispatcher{
+		repo: repo,
+		opts: opts,
+	}, nil
+}
+
+// Start runs the dispatch loop in a goroutine until Stop is called or ctx is done.
+func (d *webhookDispatcher) Start(ctx context.Context) error {
+	return d.loop.start(ctx, d.opts.PollInterval, func(ctx context.Context) { d.DispatchBatch(ctx) }, ErrDispatcherRunning)
+}
+
+// Stop signals the dispatch loop to exit & waits for the in-flight batch to settle.
+func (d *webhookDispatcher) Stop(ctx context.Context) error {
+	return d.loop.stop(ctx)
+}
+
+// DispatchBatch claims a batch of due deliveries & sends them. Each delivery's claim is
+// extended before it's sent, to outlast the request, so deliveries late in a slow batch
+// aren't claimed & sent again by other dispatchers. Deliveries claimed by another once
+// their lease expired are skipped. It returns the number of deliveries that succeeded.
+func (d *webhookDispatcher) DispatchBatch(ctx context.Context) int {
+	ctx, span := startSpan(ctx, "stores.webhooks.dispatch")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	deliveries, err := d.repo.ClaimDeliveries(ctx, d.opts.BatchSize, d.opts.Lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			l.Error("error claiming webhook deliveries", "error", err.Error())
+			finishSpan(span, err)
+		}
+		return 0
+	}
+
+	delivered := 0
+	for _, dl := range deliveries {
+		sub, err := d.repo.GetSubscription(ctx, dl.SubscriptionID)
+		if err != nil {
+			if !errors.Is(err, whdom.ErrNoWebhook) {
+				// left claimed, the delivery is retried after the lease expires
+				l.Error("error fetching webhook for delivery, will retry", "error", err.Error(), "delivery_id", dl.ID, "webhook_id", dl.SubscriptionID)
+				continue
+			}
+			// subscription removed since the delivery was enqueued, nothing to retry
+			l.Warn("webhook for delivery removed, dead-lettering", "delivery_id", dl.ID, "webhook_id", dl.SubscriptionID)
+			if mErr := d.repo.MarkFailed(ctx, dl.ID, dl.Claim, 0, err.Error(), nil); mErr != nil {
+				l.Error("error marking webhook delivery failed", "error", mErr.Error(), "delivery_id", dl.ID)
+			}
+			continue
+		}
+
+		// the claim covers the request & settling it
+		if err := d.repo.ExtendClaim(ctx, dl.ID, dl.Claim, d.opts.RequestTimeout+d.opts.Lease); err != nil {
+			if errors.Is(err, whdom.ErrClaimLost) {
+				l.Warn("webhook delivery claimed by another dispatcher, skipping", "delivery_id", dl.ID)
+			} else {
+				l.Error("error extending webhook delivery claim, will retry", "error", err.Error(), "delivery_id", dl.ID)
+			}
+			continue
+		}
+
+		statusCode, err := d.send(ctx, sub, dl)
+		if err != nil {
+			var next *time.Time
+			if dl.Attempts+1 < d.opts.MaxAttempts {
+				n := time.Now().Add(backoff(dl.Attempts, d.opts.BaseBackoff, d.opts.MaxBackoff))
+				next = &n
+				l.Warn("error sending webhook delivery, will retry", "error", err.Error(), "delivery_id", dl.ID, "attempts", dl.Attempts+1, "next_attempt_at", n)
+			} else {
+				l.Error("error sending webhook delivery, dead-lettering", "error", err.Error(), "delivery_id", dl.ID, "attempts", dl.Attempts+1)
+			}
+			if mErr := d.repo.MarkFailed(ctx, dl.ID, dl.Claim, statusCode, err.Error(), next); mErr != nil {
+				l.Error("error marking webhook delivery failed", "error", mErr.Error(), "delivery_id", dl.ID)
+			}
+			continue
+		}
+
+		// if this fails the delivery is re-sent after the lease expires
+		if err := d.repo.MarkDelivered(ctx, dl.ID, dl.Claim, statusCode); err != nil {
+			l.Error("error marking webhook delivery delivered", "error", err.Error(), "delivery_id", dl.ID)
+			continue
+		}
+		delivered++
+	}
+
+	if len(deliveries) > 0 {
+		l.Debug("dispatched webhook deliveries", "claimed", len(deliveries), "delivered", delivered)
+	}
+	return delivered
+}
+
+// send POSTs the delivery payload, returning the response status code.
+// Any non 2xx response is treated as a failure.
+func (d *webhookDispatcher) send(ctx context.Context, sub *whdom.Subscription, dl *whdom.Delivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.opts.RequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(dl.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(whdom.TIMESTAMP_HEADER, strconv.FormatInt(ts, 10))
+	req.Header.Set(whdom.SIGNATURE_HEADER, whdom.SignPayload(sub.Secret, ts, dl.Payload))
+	req.Header.Set(whdom.EVENT_TYPE_HEADER, dl.EventType)
+	req.Header.Set(whdom.DELIVERY_ID_HEADER, dl.ID)
+
+	resp, err := d.opts.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// publicClient returns an HTTP client that only dials public addresses, checked on the
+// resolved address so hosts can't resolve to internal ones after registration, & doesn't
+// follow redirects, their responses failing the delivery.
+func publicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			ap, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !whdom.PublicAddr(ap.Addr()) {
+				return fmt.Errorf("webhook address %s is not public", ap.Addr())
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
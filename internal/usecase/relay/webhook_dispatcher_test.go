@@ -0,0 +1,371 @@
+package relay_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/comfforts/logger"
+
+	whdom "github.com/comfforts/comff-stores/internal/domain/webhooks"
+	"github.com/comfforts/comff-stores/internal/usecase/relay"
+)
+
+func TestWebhookDispatcherSignsAndRetries(t *testing.T) {
+	l := logger.GetSlogLogger()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	const secret = "test-secret"
+	payload := []byte(`{"id":"ev-1","type":"store.added"}`)
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		ts, err := strconv.ParseInt(r.Header.Get(whdom.TIMESTAMP_HEADER), 10, 64)
+		require.NoError(t, err)
+		require.True(t, whdom.VerifySignature(secret, ts, body, r.Header.Get(whdom.SIGNATURE_HEADER)))
+		require.Equal(t, "store.added", r.Header.Get(whdom.EVENT_TYPE_HEADER))
+		require.Equal(t, "dl-1", r.Header.Get(whdom.DELIVERY_ID_HEADER))
+
+		// fail the first attempt
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	repo := newMemWebhooks(&whdom.Subscription{ID: "wh-1", URL: srv.URL, Secret: secret})
+	repo.add(&whdom.Delivery{ID: "dl-1", SubscriptionID: "wh-1", EventID: "ev-1", EventType: "store.added", Payload: payload})
+
+	dp, err := relay.NewWebhookDispatcher(ctx, repo, relay.DispatcherOptions{
+		Client: srv.Client(),
+		RelayOptions: relay.RelayOptions{
+			BaseBackoff: time.Millisecond,
+			MaxBackoff:  time.Millisecond,
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 0, dp.DispatchBatch(ctx))
+	dl := repo.get("dl-1")
+	require.Equal(t, whdom.DELIVERY_PENDING, dl.Status)
+	require.Equal(t, http.StatusServiceUnavailable, dl.LastStatusCode)
+
+	require.Eventually(t, func() bool {
+		dp.DispatchBatch(ctx)
+		return repo.get("dl-1").Status == whdom.DELIVERY_DELIVERED
+	}, 5*time.Second, 10*time.Millisecond)
+
+	dl = repo.get("dl-1")
+	require.Equal(t, 2, dl.Attempts)
+	require.Equal(t, http.StatusNoContent, dl.LastStatusCode)
+	require.NotNil(t, dl.DeliveredAt)
+}
+
+func TestWebhookDispatcherDeadLetters(t *testing.T) {
+	l := logger.GetSlogLogger()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	repo := newMemWebhooks(&whdom.Subscription{ID: "wh-1", URL: srv.URL, Secret: "test-secret"})
+	repo.add(&whdom.Delivery{ID: "dl-1", SubscriptionID: "wh-1", EventID: "ev-1", EventType: "store.updated", Payload: []byte(`{}`)})
+	// subscription since removed
+	repo.add(&whdom.Delivery{ID: "dl-2", SubscriptionID: "wh-2", EventID: "ev-1", EventType: "store.updated", Payload: []byte(`{}`)})
+
+	dp, err := relay.NewWebhookDispatcher(ctx, repo, relay.DispatcherOptions{
+		Client: srv.Client(),
+		RelayOptions: relay.RelayOptions{
+			BaseBackoff: time.Millisecond,
+			MaxBackoff:  time.Millisecond,
+		},
+		MaxAttempts: 3,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		dp.DispatchBatch(ctx)
+		return repo.get("dl-1").Status == whdom.DELIVERY_DEAD
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Equal(t, 3, repo.get("dl-1").Attempts)
+	require.Equal(t, http.StatusInternalServerError, repo.get("dl-1").LastStatusCode)
+	require.Equal(t, whdom.DELIVERY_DEAD, repo.get("dl-2").Status)
+	require.Equal(t, 1, repo.get("dl-2").Attempts)
+}
+
+func TestWebhookDispatcherRetriesLookupErrors(t *testing.T) {
+	l := logger.GetSlogLogger()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	repo := newMemWebhooks(&whdom.Subscription{ID: "wh-1", URL: srv.URL, Secret: "test-secret"})
+	repo.add(&whdom.Delivery{ID: "dl-1", SubscriptionID: "wh-1", EventID: "ev-1", EventType: "store.updated", Payload: []byte(`{}`)})
+	repo.subErr = errors.New("connection refused")
+
+	dp, err := relay.NewWebhookDispatcher(ctx, repo, relay.DispatcherOptions{
+		Client: srv.Client(),
+		RelayOptions: relay.RelayOptions{
+			Lease: 10 * time.Millisecond,
+		},
+	})
+	require.NoError(t, err)
+
+	// a failed subscription lookup leaves the delivery for retry
+	require.Equal(t, 0, dp.DispatchBatch(ctx))
+	dl := repo.get("dl-1")
+	require.Equal(t, whdom.DELIVERY_PENDING, dl.Status)
+	require.Equal(t, 0, dl.Attempts)
+
+	repo.mu.Lock()
+	repo.subErr = nil
+	repo.mu.Unlock()
+
+	require.Eventually(t, func() bool {
+		dp.DispatchBatch(ctx)
+		return repo.get("dl-1").Status == whdom.DELIVERY_DELIVERED
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestWebhookDispatcherSkipsLostClaims(t *testing.T) {
+	l := logger.GetSlogLogger()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	var calls atomic.Int32
+	var repo *memWebhooks
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// another dispatcher claims the delivery once its lease expired mid-send
+		calls.Add(1)
+		if r.Header.Get(whdom.DELIVERY_ID_HEADER) == "dl-1" {
+			repo.mu.Lock()
+			repo.deliveries["dl-1"].Claim = "other-claim"
+			repo.mu.Unlock()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	repo = newMemWebhooks(&whdom.Subscription{ID: "wh-1", URL: srv.URL, Secret: "test-secret"})
+	repo.add(&whdom.Delivery{ID: "dl-1", SubscriptionID: "wh-1", EventID: "ev-1", EventType: "store.updated", Payload: []byte(`{}`)})
+	repo.add(&whdom.Delivery{ID: "dl-2", SubscriptionID: "wh-1", EventID: "ev-2", EventType: "store.updated", Payload: []byte(`{}`)})
+
+	dp, err := relay.NewWebhookDispatcher(ctx, repo, relay.DispatcherOptions{
+		Client: srv.Client(),
+		RelayOptions: relay.RelayOptions{
+			Lease: time.Minute,
+		},
+	})
+	require.NoError(t, err)
+
+	// the stale claim settles nothing, the other dispatcher's attempt stands
+	require.Equal(t, 1, dp.DispatchBatch(ctx))
+	dl := repo.get("dl-1")
+	require.Equal(t, whdom.DELIVERY_PENDING, dl.Status)
+	require.Equal(t, 0, dl.Attempts)
+
+	// claims are extended to outlast the request
+	dl = repo.get("dl-2")
+	require.Equal(t, whdom.DELIVERY_DELIVERED, dl.Status)
+	require.Greater(t, repo.extended["dl-2"], time.Minute)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestWebhookDispatcherRefusesPrivateHosts(t *testing.T) {
+	l := logger.GetSlogLogger()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ctx = logger.WithLogger(ctx, l)
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	repo := newMemWebhooks(&whdom.Subscription{ID: "wh-1", URL: srv.URL, Secret: "test-secret"})
+	repo.add(&whdom.Delivery{ID: "dl-1", SubscriptionID: "wh-1", EventID: "ev-1", EventType: "store.updated", Payload: []byte(`{}`)})
+
+	// the default client doesn't dial loopback addresses
+	dp, err := relay.NewWebhookDispatcher(ctx, repo, relay.DispatcherOptions{})
+	require.NoError(t, err)
+
+	require.Equal(t, 0, dp.DispatchBatch(ctx))
+	dl := repo.get("dl-1")
+	require.Equal(t, 1, dl.Attempts)
+	require.Contains(t, dl.LastError, "not public")
+	require.Equal(t, int32(0), calls.Load())
+}
+
+type memWebhooks struct {
+	mu         sync.Mutex
+	subs       map[string]*whdom.Subscription
+	deliveries map[string]*whdom.Delivery
+	subErr     error
+	claims     int
+	// extended records each delivery's last claim extension
+	extended map[string]time.Duration
+}
+
+func newMemWebhooks(subs ...*whdom.Subscription) *memWebhooks {
+	mw := &memWebhooks{
+		subs:       map[string]*whdom.Subscription{},
+		deliveries: map[string]*whdom.Delivery{},
+		extended:   map[string]time.Duration{},
+	}
+	for _, s := range subs {
+		mw.subs[s.ID] = s
+	}
+	return mw
+}
+
+func (mw *memWebhooks) add(d *whdom.Delivery) {
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+	d.Status = whdom.DELIVERY_PENDING
+	mw.deliveries[d.ID] = d
+}
+
+func (mw *memWebhooks) get(id string) whdom.Delivery {
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+	return *mw.deliveries[id]
+}
+
+func (mw *memWebhooks) AddSubscription(ctx context.Context, sub *whdom.Subscription) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (mw *memWebhooks) GetSubscription(ctx context.Context, id string) (*whdom.Subscription, error) {
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+	if mw.subErr != nil {
+		return nil, mw.subErr
+	}
+	if s, ok := mw.subs[id]; ok {
+		return s, nil
+	}
+	return nil, whdom.ErrNoWebhook
+}
+
+func (mw *memWebhooks) DeleteSubscription(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+
+func (mw *memWebhooks) ListSubscriptions(ctx context.Context, org string) ([]*whdom.Subscription, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (mw *memWebhooks) MatchSubscriptions(ctx context.Context, eventType string, orgs []string) ([]*whdom.Subscription, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (mw *memWebhooks) EnqueueDeliveries(ctx context.Context, deliveries []*whdom.Delivery) error {
+	return errors.New("not implemented")
+}
+
+func (mw *memWebhooks) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*whdom.Delivery, error) {
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+
+	now := time.Now()
+	claimed := []*whdom.Delivery{}
+	for _, d := range mw.deliveries {
+		if len(claimed) == limit {
+			break
+		}
+		if d.Status != whdom.DELIVERY_PENDING || d.NextAttemptAt.After(now) || d.LockedUntil.After(now) {
+			continue
+		}
+		mw.claims++
+		d.LockedUntil = now.Add(lease)
+		d.Claim = "claim-" + strconv.Itoa(mw.claims)
+		cp := *d
+		claimed = append(claimed, &cp)
+	}
+	return claimed, nil
+}
+
+func (mw *memWebhooks) ExtendClaim(ctx context.Context, id, claim string, lease time.Duration) error {
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+
+	d := mw.deliveries[id]
+	if d.Claim != claim || d.Status != whdom.DELIVERY_PENDING {
+		return whdom.ErrClaimLost
+	}
+	d.LockedUntil = time.Now().Add(lease)
+	mw.extended[id] = lease
+	return nil
+}
+
+func (mw *memWebhooks) MarkDelivered(ctx context.Context, id, claim string, statusCode int) error {
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+
+	now := time.Now()
+	d := mw.deliveries[id]
+	if d.Claim != claim || d.Status != whdom.DELIVERY_PENDING {
+		return whdom.ErrClaimLost
+	}
+	d.Status = whdom.DELIVERY_DELIVERED
+	d.DeliveredAt = &now
+	d.LastStatusCode = statusCode
+	d.Attempts++
+	d.LockedUntil = time.Time{}
+	return nil
+}
+
+func (mw *memWebhooks) MarkFailed(ctx context.Context, id, claim string, statusCode int, lastErr string, nextAttemptAt *time.Time) error {
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+
+	d := mw.deliveries[id]
+	if d.Claim != claim || d.Status != whdom.DELIVERY_PENDING {
+		return whdom.ErrClaimLost
+	}
+	d.LastStatusCode = statusCode
+	d.LastError = lastErr
+	d.Attempts++
+	d.LockedUntil = time.Time{}
+	if nextAttemptAt != nil {
+		d.NextAttemptAt = *nextAttemptAt
+	} else {
+		d.Status = whdom.DELIVERY_DEAD
+	}
+	return nil
+}
+
+func (mw *memWebhooks) ListDeliveries(ctx context.Context, params *whdom.DeliveriesQuery) ([]*whdom.Delivery, error) {
+	return nil, errors.New("not implemented")
+}
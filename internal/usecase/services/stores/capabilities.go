@@ -0,0 +1,54 @@
+package stores
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+const INVALID_CAPABILITY = "invalid capability"
+
+var ErrInvalidCapability = errors.New(INVALID_CAPABILITY)
+
+// assignableCapabilities normalizes the capabilities assigned to a store, removing
+// duplicates. Capabilities must be in the catalog & not deprecated.
+func (ss *storesService) assignableCapabilities(codes []string) ([]string, error) {
+	normalized, err := ss.catalogCapabilities(codes)
+	if err != nil {
+		return nil, err
+	}
+	for _, code := range normalized {
+		if c, _ := ss.catalog.Lookup(code); c.Deprecated {
+			return nil, fmt.Errorf("%w: %s is deprecated", ErrInvalidCapability, code)
+		}
+	}
+	return normalized, nil
+}
+
+// catalogCapabilities normalizes capabilities, removing duplicates, all of which
+// must be in the catalog.
+func (ss *storesService) catalogCapabilities(codes []string) ([]string, error) {
+	normalized := []string{}
+	for _, code := range codes {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if _, ok := ss.catalog.Lookup(code); !ok {
+			return nil, fmt.Errorf("%w: %q isn't in the catalog", ErrInvalidCapability, code)
+		}
+		if !slices.Contains(normalized, code) {
+			normalized = append(normalized, code)
+		}
+	}
+	return normalized, nil
+}
+
+// GetCapabilityCatalog returns the capability catalog, an empty one when the service has none.
+func (ss *storesService) GetCapabilityCatalog(ctx context.Context) (*stdom.CapabilityCatalog, error) {
+	if ss.catalog == nil {
+		return &stdom.CapabilityCatalog{}, nil
+	}
+	return ss.catalog, nil
+}
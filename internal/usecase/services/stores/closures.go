@@ -0,0 +1,197 @@
+package stores
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/comfforts/logger"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+// stores have at most MAX_STORE_CLOSURES scheduled & current closures, with reasons
+// of up to MAX_CLOSURE_REASON_LENGTH characters
+const (
+	MAX_STORE_CLOSURES        = 20
+	MAX_CLOSURE_REASON_LENGTH = 200
+)
+
+const (
+	INVALID_CLOSURE   = "invalid store closure"
+	CLOSURE_NOT_FOUND = "store closure not found"
+	SCHEDULED_STATUS  = "temporarily closed status is set by closures"
+)
+
+var (
+	ErrInvalidClosure  = errors.New(INVALID_CLOSURE)
+	ErrClosureNotFound = errors.New(CLOSURE_NOT_FOUND)
+	ErrScheduledStatus = errors.New(SCHEDULED_STATUS)
+)
+
+// ScheduleClosure adds a closure to the store, closing it right away when it's already
+// in effect.
+func (ss *storesService) ScheduleClosure(ctx context.Context, id string, params *stdom.ScheduleClosureParams) (*stdom.StoreClosure, error) {
+	ctx, span := startSpan(ctx, "stores.service.schedule_closure")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("scheduling store closure")
+
+	if id == "" || params == nil {
+		finishSpan(span, ErrMissingRequiredField)
+		return nil, ErrMissingRequiredField
+	}
+
+	// times are kept to the millisecond, as stored
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	closure := &stdom.StoreClosure{
+		ID:     primitive.NewObjectID().Hex(),
+		From:   params.From.UTC().Truncate(time.Millisecond),
+		Reason: params.Reason,
+	}
+	if params.From.IsZero() {
+		closure.From = now
+	}
+	if params.Until != nil {
+		until := params.Until.UTC().Truncate(time.Millisecond)
+		if !until.After(closure.From) || !until.After(now) {
+			err := fmt.Errorf("%w: reopens before it closes or has ended", ErrInvalidClosure)
+			finishSpan(span, err)
+			return nil, err
+		}
+		closure.Until = &until
+	}
+	if len(closure.Reason) > MAX_CLOSURE_REASON_LENGTH {
+		err := fmt.Errorf("%w: reason longer than %d characters", ErrInvalidClosure, MAX_CLOSURE_REASON_LENGTH)
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	err = ss.updateStore(ctx, id, func(st *stdom.Store) (*stdom.UpdateStoreQuery, error) {
+		closures := append(slices.Clone(st.Closures), closure)
+		if len(closures) > MAX_STORE_CLOSURES {
+			return nil, fmt.Errorf("%w: store has %d closures", ErrInvalidClosure, MAX_STORE_CLOSURES)
+		}
+		query, _ := rescheduleQuery(st, closures, now)
+		return query, nil
+	})
+	if err != nil {
+		l.Error("error scheduling store closure in repository", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	return closure, nil
+}
+
+// CancelClosure removes a closure from the store, reopening it when nothing else keeps
+// it closed.
+func (ss *storesService) CancelClosure(ctx context.Context, id, closureID string) error {
+	ctx, span := startSpan(ctx, "stores.service.cancel_closure")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("canceling store closure")
+
+	if id == "" || closureID == "" {
+		finishSpan(span, ErrMissingRequiredField)
+		return ErrMissingRequiredField
+	}
+
+	err = ss.updateStore(ctx, id, func(st *stdom.Store) (*stdom.UpdateStoreQuery, error) {
+		closures := slices.DeleteFunc(slices.Clone(st.Closures), func(c *stdom.StoreClosure) bool {
+			return c.ID == closureID
+		})
+		if len(closures) == len(st.Closures) {
+			return nil, ErrClosureNotFound
+		}
+		query, _ := rescheduleQuery(st, closures, time.Now())
+		return query, nil
+	})
+	if err != nil {
+		l.Error("error canceling store closure in repository", "error", err.Error())
+		finishSpan(span, err)
+		return err
+	}
+	return nil
+}
+
+// ApplyScheduledStatusChanges closes & reopens the stores with status changes due at the
+// time, claimed for the lease so concurrent schedulers apply each once. Each change is a
+// store update, emitting its store.updated event.
+func (ss *storesService) ApplyScheduledStatusChanges(ctx context.Context, at time.Time, limit int, lease time.Duration) (int, error) {
+	ctx, span := startSpan(ctx, "stores.service.apply_status_changes")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	due, err := ss.storesRepo.ClaimStatusChangesDue(ctx, at, limit, lease)
+	if err != nil {
+		l.Error("error claiming due store status changes from repository", "error", err.Error())
+		finishSpan(span, err)
+		return 0, err
+	}
+
+	changed := 0
+	for _, st := range due {
+		ok := false
+		err := ss.updateStore(ctx, st.ID, func(st *stdom.Store) (*stdom.UpdateStoreQuery, error) {
+			// another update may have rescheduled it
+			if st.StatusChangeAt == nil || st.StatusChangeAt.After(at) {
+				ok = false
+				return nil, nil
+			}
+			var query *stdom.UpdateStoreQuery
+			query, ok = rescheduleQuery(st, st.Closures, at)
+			return query, nil
+		})
+		if err != nil {
+			// retried once the claim expires, the change is still due
+			l.Error("error applying store status change", "error", err.Error(), "store_id", st.ID)
+			continue
+		}
+		if ok {
+			l.Info("applied scheduled store status change", "store_id", st.ID, "status_change_at", st.StatusChangeAt)
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// scheduleStatus sets the status the store's closures give the updated status, along
+// with its schedule.
+func scheduleStatus(st *stdom.Store, query *stdom.UpdateStoreQuery) {
+	if len(st.Closures) == 0 {
+		return
+	}
+	query.Status, query.Schedule = stdom.ScheduledStatus(query.Status, st.Closures, time.Now())
+}
+
+// rescheduleQuery returns the update setting the store's closures & the status they give
+// it at the time, reporting whether the status changes. It's nil when neither changes.
+func rescheduleQuery(st *stdom.Store, closures []*stdom.StoreClosure, at time.Time) (*stdom.UpdateStoreQuery, bool) {
+	status, schedule := stdom.ScheduledStatus(st.Status, closures, at)
+	// stores without a status are active
+	changed := status != st.Status && (st.Status != "" || status != stdom.STORE_ACTIVE)
+	if !changed && stdom.SameSchedule(st, schedule) {
+		return nil, false
+	}
+	query := &stdom.UpdateStoreQuery{Schedule: schedule}
+	if changed {
+		query.Status = status
+	}
+	return query, changed
+}
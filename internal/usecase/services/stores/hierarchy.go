@@ -0,0 +1,240 @@
+package stores
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/comfforts/logger"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+// parent chains are at most MAX_HIERARCHY_DEPTH stores long, ancestors are walked no further
+const MAX_HIERARCHY_DEPTH = 8
+
+const (
+	INVALID_PARENT     = "invalid parent store"
+	INVALID_REGION     = "invalid region"
+	STORE_HAS_CHILDREN = "store has child stores"
+)
+
+var (
+	ErrInvalidParent    = errors.New(INVALID_PARENT)
+	ErrInvalidRegion    = errors.New(INVALID_REGION)
+	ErrStoreHasChildren = errors.New(STORE_HAS_CHILDREN)
+)
+
+// storeRegion normalizes a region path, empty when unset.
+func storeRegion(region string) (string, error) {
+	if region == "" {
+		return "", nil
+	}
+	normalized, ok := stdom.NormalizeRegion(region)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrInvalidRegion, region)
+	}
+	return normalized, nil
+}
+
+// checkParent returns the parent of a store in the org & its ancestors, parent first,
+// once they're known not to include the store, and to leave room for the store & the
+// levels of stores below it within MAX_HIERARCHY_DEPTH. The store ID is empty for new
+// stores.
+func (ss *storesService) checkParent(ctx context.Context, id, parentID, org string, levels int) ([]*stdom.Store, error) {
+	if parentID == id {
+		return nil, fmt.Errorf("%w: a store can't be its own parent", ErrInvalidParent)
+	}
+	parent, err := ss.storesRepo.GetStore(ctx, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidParent, err)
+	}
+	if parent.Org != org {
+		return nil, fmt.Errorf("%w: parent %s is in another org", ErrInvalidParent, parentID)
+	}
+
+	ancestors, err := ss.ancestors(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+	if len(ancestors)+2+levels > MAX_HIERARCHY_DEPTH {
+		return nil, fmt.Errorf("%w: hierarchy deeper than %d stores", ErrInvalidParent, MAX_HIERARCHY_DEPTH)
+	}
+	for _, a := range ancestors {
+		if a.ID == id {
+			return nil, fmt.Errorf("%w: store %s is an ancestor of parent %s", ErrInvalidParent, id, parentID)
+		}
+	}
+	return append([]*stdom.Store{parent}, ancestors...), nil
+}
+
+// checkNewParent checks a new store's parent, adding it conditional on the parent chain
+// it checked, in the parent's region unless the store's region is set.
+func (ss *storesService) checkNewParent(ctx context.Context, store *stdom.Store, inheritRegion bool) error {
+	chain, err := ss.checkParent(ctx, "", store.ParentID, store.Org, 0)
+	if err != nil {
+		return err
+	}
+	store.ParentChain = storeVersions(chain)
+	if inheritRegion {
+		store.Region = chain[0].Region
+	}
+	return nil
+}
+
+// checkHierarchyUpdate keeps a store's parent & satellites in its org, its satellites
+// in its region, and its parent out of the stores below it. The update is conditional
+// on the parent chain it checked, so concurrent reparenting can't form a cycle.
+func (ss *storesService) checkHierarchyUpdate(ctx context.Context, current *stdom.Store, params *stdom.UpdateStoreParams, query *stdom.UpdateStoreQuery) error {
+	if params.ParentID != "" && params.DetachParent {
+		return fmt.Errorf("%w: can't both set & detach the parent", ErrInvalidParent)
+	}
+
+	orgChange := params.Org != "" && params.Org != current.Org
+	regionChange := query.Region != "" && query.Region != current.Region
+	if orgChange || regionChange {
+		// adding a satellite changes its parent's version, failing this update if it
+		// races with the add
+		children, err := ss.storesRepo.ListChildStores(ctx, current.ID)
+		if err != nil {
+			return err
+		}
+		switch {
+		case len(children) > 0 && orgChange:
+			return fmt.Errorf("%w: detach them before changing org", ErrStoreHasChildren)
+		case len(children) > 0:
+			return fmt.Errorf("%w: detach them before changing region", ErrStoreHasChildren)
+		}
+	}
+
+	org := current.Org
+	if orgChange {
+		org = params.Org
+	}
+	switch {
+	case params.ParentID != "":
+		levels, err := ss.descendantLevels(ctx, current.ID)
+		if err != nil {
+			return err
+		}
+		chain, err := ss.checkParent(ctx, current.ID, params.ParentID, org, levels)
+		if err != nil {
+			return err
+		}
+		query.ParentChain = storeVersions(chain)
+	case current.ParentID != "" && !params.DetachParent && org != current.Org:
+		return fmt.Errorf("%w: parent %s is in another org", ErrInvalidParent, current.ParentID)
+	}
+	return nil
+}
+
+// storeVersions returns the stores' versions.
+func storeVersions(stores []*stdom.Store) []*stdom.StoreVersion {
+	versions := []*stdom.StoreVersion{}
+	for _, st := range stores {
+		versions = append(versions, &stdom.StoreVersion{ID: st.ID, Version: st.Version})
+	}
+	return versions
+}
+
+// ancestors returns the store's parent first, up to the root store, at most
+// MAX_HIERARCHY_DEPTH of them.
+func (ss *storesService) ancestors(ctx context.Context, st *stdom.Store) ([]*stdom.Store, error) {
+	ancestors := []*stdom.Store{}
+	for st.ParentID != "" && len(ancestors) < MAX_HIERARCHY_DEPTH {
+		parent, err := ss.storesRepo.GetStore(ctx, st.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		ancestors = append(ancestors, parent)
+		st = parent
+	}
+	return ancestors, nil
+}
+
+// descendantLevels counts the levels of stores below the store, up to MAX_HIERARCHY_DEPTH.
+func (ss *storesService) descendantLevels(ctx context.Context, id string) (int, error) {
+	levels, ids := 0, []string{id}
+	for len(ids) > 0 && levels < MAX_HIERARCHY_DEPTH {
+		next := []string{}
+		for _, parentID := range ids {
+			children, err := ss.storesRepo.ListChildStores(ctx, parentID)
+			if err != nil {
+				return 0, err
+			}
+			for _, c := range children {
+				next = append(next, c.ID)
+			}
+		}
+		if len(next) > 0 {
+			levels++
+		}
+		ids = next
+	}
+	return levels, nil
+}
+
+// ListChildStores returns the store's satellite stores, in the order they were added.
+func (ss *storesService) ListChildStores(ctx context.Context, id string, opts *stdom.GetStoreOptions) ([]*stdom.Store, error) {
+	ctx, span := startSpan(ctx, "stores.service.children")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("listing child stores")
+
+	if id == "" {
+		finishSpan(span, ErrMissingRequiredField)
+		return nil, ErrMissingRequiredField
+	}
+
+	children, err := ss.storesRepo.ListChildStores(ctx, id)
+	if err != nil {
+		l.Error("error listing child stores in repository", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	if opts != nil && opts.IncludeAddress {
+		if err := ss.hydrateAddresses(ctx, children); err != nil {
+			finishSpan(span, err)
+			return nil, err
+		}
+	}
+	if opts != nil {
+		localize(children, opts.Locales)
+	}
+	return children, nil
+}
+
+// GetStoreAncestors returns the store's parent first, up to the root store.
+func (ss *storesService) GetStoreAncestors(ctx context.Context, id string) ([]*stdom.Store, error) {
+	ctx, span := startSpan(ctx, "stores.service.ancestors")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("getting store ancestors")
+
+	if id == "" {
+		finishSpan(span, ErrMissingRequiredField)
+		return nil, ErrMissingRequiredField
+	}
+
+	st, err := ss.storesRepo.GetStore(ctx, id)
+	if err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+	ancestors, err := ss.ancestors(ctx, st)
+	if err != nil {
+		l.Error("error getting store ancestors from repository", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	return ancestors, nil
+}
@@ -0,0 +1,89 @@
+package stores
+
+import (
+	"errors"
+	"fmt"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+// stores have at most MAX_STORE_TRANSLATIONS translations
+const MAX_STORE_TRANSLATIONS = 20
+
+const (
+	INVALID_LOCALE      = "invalid locale"
+	INVALID_TRANSLATION = "invalid store translation"
+)
+
+var (
+	ErrInvalidLocale      = errors.New(INVALID_LOCALE)
+	ErrInvalidTranslation = errors.New(INVALID_TRANSLATION)
+)
+
+// storeLocale returns the normalized locale, empty when unset.
+func storeLocale(locale string) (string, error) {
+	if locale == "" {
+		return "", nil
+	}
+	normalized, ok := stdom.NormalizeLocale(locale)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidLocale, locale)
+	}
+	return normalized, nil
+}
+
+// storeTranslations returns the translations with normalized locales, each named, in
+// a distinct locale other than the store's.
+func storeTranslations(locale string, translations []*stdom.StoreTranslation) ([]*stdom.StoreTranslation, error) {
+	if len(translations) > MAX_STORE_TRANSLATIONS {
+		return nil, fmt.Errorf("%w: more than %d translations", ErrInvalidTranslation, MAX_STORE_TRANSLATIONS)
+	}
+	seen := map[string]bool{}
+	normalized := make([]*stdom.StoreTranslation, 0, len(translations))
+	for _, tr := range translations {
+		if tr == nil || tr.Locale == "" || tr.Name == "" {
+			return nil, fmt.Errorf("%w: locale & name are required", ErrInvalidTranslation)
+		}
+		trLocale, err := storeLocale(tr.Locale)
+		if err != nil {
+			return nil, err
+		}
+		if trLocale == locale || seen[trLocale] {
+			return nil, fmt.Errorf("%w: duplicate locale %s", ErrInvalidTranslation, trLocale)
+		}
+		seen[trLocale] = true
+		normalized = append(normalized, &stdom.StoreTranslation{
+			Locale:      trLocale,
+			Name:        tr.Name,
+			Description: tr.Description,
+		})
+	}
+	return normalized, nil
+}
+
+// checkUpdatedLocales keeps the store's translations, as the query updates them, in
+// locales other than the store's, the current locale & translations where the query
+// leaves them.
+func checkUpdatedLocales(st *stdom.Store, query *stdom.UpdateStoreQuery) error {
+	if query.Locale == "" && len(query.Translations) == 0 {
+		return nil
+	}
+	updated := stdom.ApplyUpdate(st, query)
+	for _, tr := range updated.Translations {
+		if tr.Locale == updated.Locale {
+			return fmt.Errorf("%w: duplicate locale %s", ErrInvalidTranslation, tr.Locale)
+		}
+	}
+	return nil
+}
+
+// localize sets the stores' names & descriptions to their translations best matching
+// the preferred locales.
+func localize(stores []*stdom.Store, locales []string) {
+	if len(locales) == 0 {
+		return
+	}
+	for _, st := range stores {
+		stdom.LocalizeStore(st, locales)
+	}
+}
@@ -0,0 +1,307 @@
+package stores
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/comfforts/logger"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+// stores have at most MAX_STORE_ATTACHMENTS attachments, referenced by URIs of up to
+// MAX_ATTACHMENT_URI_LENGTH characters. Uploads are at most MAX_ATTACHMENT_BYTES.
+const (
+	MAX_STORE_ATTACHMENTS     = 20
+	MAX_ATTACHMENT_URI_LENGTH = 2048
+	MAX_ATTACHMENT_BYTES      = 10 << 20
+)
+
+const (
+	INVALID_ATTACHMENT   = "invalid store attachment"
+	ATTACHMENT_NOT_FOUND = "store attachment not found"
+	BLOBS_UNAVAILABLE    = "attachment uploads aren't configured"
+)
+
+var (
+	ErrInvalidAttachment  = errors.New(INVALID_ATTACHMENT)
+	ErrAttachmentNotFound = errors.New(ATTACHMENT_NOT_FOUND)
+	ErrBlobsUnavailable   = errors.New(BLOBS_UNAVAILABLE)
+)
+
+// uploadable image content types & their file extensions
+var uploadExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+}
+
+// AddAttachment attaches an asset hosted elsewhere to the store, last in display order.
+func (ss *storesService) AddAttachment(ctx context.Context, id string, params *stdom.AddAttachmentParams) (*stdom.StoreAttachment, error) {
+	ctx, span := startSpan(ctx, "stores.service.add_attachment")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("adding store attachment")
+
+	if id == "" || params == nil || params.URI == "" {
+		finishSpan(span, ErrMissingRequiredField)
+		return nil, ErrMissingRequiredField
+	}
+	if !params.Kind.Valid() {
+		err := fmt.Errorf("%w: unknown kind %q", ErrInvalidAttachment, params.Kind)
+		finishSpan(span, err)
+		return nil, err
+	}
+	if u, err := url.Parse(params.URI); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(params.URI) > MAX_ATTACHMENT_URI_LENGTH {
+		err := fmt.Errorf("%w: uri must be an http or https URI of up to %d characters", ErrInvalidAttachment, MAX_ATTACHMENT_URI_LENGTH)
+		finishSpan(span, err)
+		return nil, err
+	}
+	checksum := strings.ToLower(params.Checksum)
+	if _, err := hex.DecodeString(checksum); err != nil || (checksum != "" && len(checksum) != 2*sha256.Size) {
+		err := fmt.Errorf("%w: checksum must be a hex encoded SHA-256", ErrInvalidAttachment)
+		finishSpan(span, err)
+		return nil, err
+	}
+	if params.Width < 0 || params.Height < 0 {
+		err := fmt.Errorf("%w: negative dimensions", ErrInvalidAttachment)
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	attachment := &stdom.StoreAttachment{
+		ID:          primitive.NewObjectID().Hex(),
+		Kind:        params.Kind,
+		URI:         params.URI,
+		Checksum:    checksum,
+		Width:       params.Width,
+		Height:      params.Height,
+		ContentType: params.ContentType,
+		CreatedAt:   time.Now().UTC().Truncate(time.Millisecond),
+	}
+	if err := ss.attach(ctx, id, attachment); err != nil {
+		l.Error("error adding store attachment", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// UploadAttachment stores the uploaded image in blob storage & attaches it to the store,
+// last in display order. PNG, JPEG & GIF images are accepted, their checksum, dimensions
+// & content type are taken from the content.
+func (ss *storesService) UploadAttachment(ctx context.Context, id string, params *stdom.UploadAttachmentParams, r io.Reader) (*stdom.StoreAttachment, error) {
+	ctx, span := startSpan(ctx, "stores.service.upload_attachment")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("uploading store attachment")
+
+	if ss.blobs == nil {
+		finishSpan(span, ErrBlobsUnavailable)
+		return nil, ErrBlobsUnavailable
+	}
+	if id == "" || params == nil || r == nil {
+		finishSpan(span, ErrMissingRequiredField)
+		return nil, ErrMissingRequiredField
+	}
+	if !params.Kind.Valid() {
+		err := fmt.Errorf("%w: unknown kind %q", ErrInvalidAttachment, params.Kind)
+		finishSpan(span, err)
+		return nil, err
+	}
+	// fail before reading the upload when it can't be attached
+	st, err := ss.storesRepo.GetStore(ctx, id)
+	if err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+	if len(st.Attachments) >= MAX_STORE_ATTACHMENTS {
+		err := fmt.Errorf("%w: store has %d attachments", ErrInvalidAttachment, MAX_STORE_ATTACHMENTS)
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, MAX_ATTACHMENT_BYTES+1))
+	if err != nil {
+		l.Error("error reading store attachment upload", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	if len(data) == 0 || len(data) > MAX_ATTACHMENT_BYTES {
+		err := fmt.Errorf("%w: uploads are 1 to %d bytes", ErrInvalidAttachment, MAX_ATTACHMENT_BYTES)
+		finishSpan(span, err)
+		return nil, err
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := uploadExtensions[contentType]
+	if !ok {
+		err := fmt.Errorf("%w: unsupported content type %s", ErrInvalidAttachment, contentType)
+		finishSpan(span, err)
+		return nil, err
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		err := fmt.Errorf("%w: undecodable image", ErrInvalidAttachment)
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	attachment := &stdom.StoreAttachment{
+		ID:          primitive.NewObjectID().Hex(),
+		Kind:        params.Kind,
+		Checksum:    hex.EncodeToString(sum[:]),
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+		ContentType: contentType,
+		CreatedAt:   time.Now().UTC().Truncate(time.Millisecond),
+	}
+	attachment.BlobKey = path.Join("stores", st.ID, attachment.ID+ext)
+	if attachment.URI, err = ss.blobs.Put(ctx, attachment.BlobKey, bytes.NewReader(data)); err != nil {
+		l.Error("error storing store attachment blob", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	if err := ss.attach(ctx, id, attachment); err != nil {
+		l.Error("error adding uploaded store attachment", "error", err.Error())
+		ss.deleteBlob(ctx, attachment.BlobKey)
+		finishSpan(span, err)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// RemoveAttachment detaches the attachment from the store, deleting uploaded content
+// from blob storage.
+func (ss *storesService) RemoveAttachment(ctx context.Context, id, attachmentID string) error {
+	ctx, span := startSpan(ctx, "stores.service.remove_attachment")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("removing store attachment")
+
+	if id == "" || attachmentID == "" {
+		finishSpan(span, ErrMissingRequiredField)
+		return ErrMissingRequiredField
+	}
+
+	var removed *stdom.StoreAttachment
+	err = ss.updateStore(ctx, id, func(st *stdom.Store) (*stdom.UpdateStoreQuery, error) {
+		i := slices.IndexFunc(st.Attachments, func(sa *stdom.StoreAttachment) bool {
+			return sa.ID == attachmentID
+		})
+		if i < 0 {
+			return nil, ErrAttachmentNotFound
+		}
+		removed = st.Attachments[i]
+		attachments := slices.Delete(slices.Clone(st.Attachments), i, i+1)
+		return &stdom.UpdateStoreQuery{Attachments: attachments, ClearAttachments: len(attachments) == 0}, nil
+	})
+	if err != nil {
+		l.Error("error removing store attachment in repository", "error", err.Error())
+		finishSpan(span, err)
+		return err
+	}
+	if removed.BlobKey != "" {
+		ss.deleteBlob(ctx, removed.BlobKey)
+	}
+	return nil
+}
+
+// ReorderAttachments puts the store's attachments in the order of the IDs, which must
+// list each of them once.
+func (ss *storesService) ReorderAttachments(ctx context.Context, id string, attachmentIDs []string) ([]*stdom.StoreAttachment, error) {
+	ctx, span := startSpan(ctx, "stores.service.reorder_attachments")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("reordering store attachments")
+
+	if id == "" || len(attachmentIDs) == 0 {
+		finishSpan(span, ErrMissingRequiredField)
+		return nil, ErrMissingRequiredField
+	}
+
+	var attachments []*stdom.StoreAttachment
+	err = ss.updateStore(ctx, id, func(st *stdom.Store) (*stdom.UpdateStoreQuery, error) {
+		byID := map[string]*stdom.StoreAttachment{}
+		for _, sa := range st.Attachments {
+			byID[sa.ID] = sa
+		}
+		attachments = make([]*stdom.StoreAttachment, 0, len(attachmentIDs))
+		for _, aID := range attachmentIDs {
+			sa, ok := byID[aID]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown or repeated attachment %q", ErrInvalidAttachment, aID)
+			}
+			delete(byID, aID)
+			attachments = append(attachments, sa)
+		}
+		if len(byID) > 0 {
+			return nil, fmt.Errorf("%w: all of the store's attachments must be ordered", ErrInvalidAttachment)
+		}
+		return &stdom.UpdateStoreQuery{Attachments: attachments}, nil
+	})
+	if err != nil {
+		l.Error("error reordering store attachments in repository", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// attach appends the attachment to the store's.
+func (ss *storesService) attach(ctx context.Context, id string, attachment *stdom.StoreAttachment) error {
+	return ss.updateStore(ctx, id, func(st *stdom.Store) (*stdom.UpdateStoreQuery, error) {
+		if len(st.Attachments) >= MAX_STORE_ATTACHMENTS {
+			return nil, fmt.Errorf("%w: store has %d attachments", ErrInvalidAttachment, MAX_STORE_ATTACHMENTS)
+		}
+		return &stdom.UpdateStoreQuery{Attachments: append(slices.Clone(st.Attachments), attachment)}, nil
+	})
+}
+
+// deleteBlob deletes the blob, logging failures, orphaned blobs don't fail requests.
+func (ss *storesService) deleteBlob(ctx context.Context, key string) {
+	if ss.blobs == nil {
+		return
+	}
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	if err := ss.blobs.Delete(ctx, key); err != nil {
+		l.Error("error deleting store attachment blob", "error", err.Error(), "blob_key", key)
+	}
+}
@@ -0,0 +1,95 @@
+package stores
+
+import (
+	"context"
+
+	"github.com/comfforts/logger"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+// MAX_K_NEAREST is the most nearest stores a search returns.
+const MAX_K_NEAREST = 100
+
+// searchNearest returns the k stores nearest the searched address or point, matching
+// the other filters, ordered by distance. The repository finds them on the location index.
+func (ss *storesService) searchNearest(ctx context.Context, params *stdom.SearchStoreParams) (*stdom.SearchStoreResult, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if params.KNearest < 0 ||
+		params.KNearest > MAX_K_NEAREST ||
+		params.Within != nil ||
+		params.Fuzzy ||
+		len(params.Facets) > 0 ||
+		params.Limit != 0 ||
+		params.Offset != 0 ||
+		(params.RankBy != "" && !params.RankBy.Valid()) {
+		return nil, ErrInvalidSearch
+	}
+
+	origin, err := ss.locateOrigin(ctx, params.AddressId, params.AddressStr, params.Latitude, params.Longitude)
+	if err != nil {
+		return nil, err
+	}
+	// there's no point to search around
+	if origin == nil {
+		return nil, ErrInvalidSearch
+	}
+	lat, lon := origin.Latitude, origin.Longitude
+
+	// routed rankings re-rank more of the nearest stores
+	want := params.KNearest
+	if params.RankBy.Routed() {
+		want = routeCandidates(params.KNearest)
+	}
+
+	near := &stdom.NearPoint{Latitude: lat, Longitude: lon}
+	if params.Distance > 0 {
+		near.MaxDistance = float64(params.Distance)
+	}
+	l.Debug("searching nearest stores", "latitude", lat, "longitude", lon, "max-distance", near.MaxDistance)
+	result, err := ss.storesRepo.SearchStores(ctx, &stdom.SearchStoreQuery{
+		Org:                      params.Org,
+		Name:                     params.Name,
+		Query:                    params.Query,
+		Near:                     near,
+		Region:                   params.Region,
+		Capabilities:             params.Capabilities,
+		IncludeTemporarilyClosed: params.IncludeTemporarilyClosed,
+		Limit:                    want,
+	})
+	if err != nil {
+		l.Error("error searching nearest stores in repository", "error", err.Error())
+		return nil, err
+	}
+
+	nearest := result.Stores
+	if params.RankBy.Routed() {
+		nearest = ss.rankByRoute(ctx, origin, nearest, params.RankBy)
+	}
+	nearest = nearest[:min(params.KNearest, len(nearest))]
+	if params.IncludeAddress {
+		if err := ss.hydrateAddresses(ctx, nearest); err != nil {
+			return nil, err
+		}
+	}
+	localize(nearest, params.Locales)
+	return &stdom.SearchStoreResult{Stores: nearest, Total: len(nearest)}, nil
+}
+
+// locateOrigin returns the location of the address ID, or else of the geocoded address
+// string or point, nil when none is set.
+func (ss *storesService) locateOrigin(ctx context.Context, addressId, addressStr string, lat, lon float64) (*geodom.Location, error) {
+	if addressId == "" {
+		return ss.locateSearchPoint(ctx, addressStr, lat, lon)
+	}
+	lat, lon, err := geodom.DecodeAddressId(addressId)
+	if err != nil {
+		return nil, ErrInvalidAddressId
+	}
+	return &geodom.Location{AddressId: addressId, Latitude: lat, Longitude: lon}, nil
+}
@@ -0,0 +1,67 @@
+package stores
+
+import (
+	"context"
+	"sort"
+
+	"github.com/comfforts/logger"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+// routed rankings route ROUTE_CANDIDATE_FACTOR times the stores asked for, the nearest
+// by straight line, up to MAX_ROUTE_CANDIDATES
+const (
+	ROUTE_CANDIDATE_FACTOR = 3
+	MAX_ROUTE_CANDIDATES   = 100
+)
+
+// routeCandidates is how many of the nearest stores are routed to rank n of them.
+func routeCandidates(n int) int {
+	return max(n, min(n*ROUTE_CANDIDATE_FACTOR, MAX_ROUTE_CANDIDATES))
+}
+
+// rankByRoute routes the stores, in straight line order, from the origin & orders them
+// by travel time or road distance, the stores that can't be reached last. The stores
+// are left in straight line order when routing fails.
+func (ss *storesService) rankByRoute(ctx context.Context, origin *geodom.Location, stores []*stdom.Store, rankBy stdom.RankBy) []*stdom.Store {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if ss.routing == nil || len(stores) == 0 {
+		return stores
+	}
+
+	routed, dests := []*stdom.Store{}, []*geodom.Location{}
+	for _, st := range stores {
+		if lat, lon, err := geodom.DecodeAddressId(st.AddressId); err == nil {
+			routed = append(routed, st)
+			dests = append(dests, &geodom.Location{AddressId: st.AddressId, Latitude: lat, Longitude: lon})
+		}
+	}
+	routes, err := ss.routing.Routes(ctx, origin, dests)
+	if err != nil || len(routes) != len(dests) {
+		if err != nil {
+			l.Warn("error routing stores, ranking by distance", "error", err.Error())
+		}
+		return stores
+	}
+	for i, st := range routed {
+		st.Route = routes[i]
+	}
+
+	sort.SliceStable(stores, func(i, j int) bool {
+		ri, rj := stores[i].Route, stores[j].Route
+		switch {
+		case ri == nil || rj == nil:
+			return ri != nil && rj == nil
+		case rankBy == stdom.RANK_TRAVEL_TIME:
+			return ri.Duration < rj.Duration
+		}
+		return ri.DistanceMeters < rj.DistanceMeters
+	})
+	return stores
+}
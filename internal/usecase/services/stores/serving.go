@@ -0,0 +1,119 @@
+package stores
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/comfforts/logger"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+// serving stores are returned nearest first, DEFAULT_SERVING_LIMIT unless set, up to MAX_SERVING_LIMIT
+const (
+	DEFAULT_SERVING_LIMIT = 20
+	MAX_SERVING_LIMIT     = 100
+)
+
+const INVALID_SERVICE_AREA = "invalid service area"
+
+var ErrInvalidServiceArea = errors.New(INVALID_SERVICE_AREA)
+
+// serviceArea parses a store's GeoJSON service area, nil when unset.
+func serviceArea(geoJSON string) (*geodom.GeoJSONMultiPolygon, error) {
+	if geoJSON == "" {
+		return nil, nil
+	}
+	area, err := parseArea(geoJSON)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidServiceArea, err)
+	}
+	return geodom.NewGeoJSONMultiPolygon(area), nil
+}
+
+// FindServingStores returns the stores with service areas containing the customer's
+// address or point, nearest first, by route when ranked by one. Stores with address IDs
+// that don't decode rank last.
+func (ss *storesService) FindServingStores(ctx context.Context, params *stdom.FindServingStoresParams) ([]*stdom.Store, error) {
+	ctx, span := startSpan(ctx, "stores.service.serving")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("finding serving stores")
+
+	if params == nil {
+		finishSpan(span, ErrMissingRequiredField)
+		return nil, ErrMissingRequiredField
+	}
+	if params.Limit < 0 || (params.RankBy != "" && !params.RankBy.Valid()) {
+		finishSpan(span, ErrInvalidSearch)
+		return nil, ErrInvalidSearch
+	}
+	capabilities, err := ss.catalogCapabilities(params.Capabilities)
+	if err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+	limit := params.Limit
+	if limit == 0 {
+		limit = DEFAULT_SERVING_LIMIT
+	}
+	limit = min(limit, MAX_SERVING_LIMIT)
+
+	origin, err := ss.locateOrigin(ctx, params.AddressId, params.AddressStr, params.Latitude, params.Longitude)
+	if err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+	if origin == nil {
+		finishSpan(span, ErrMissingRequiredField)
+		return nil, ErrMissingRequiredField
+	}
+	lat, lon := origin.Latitude, origin.Longitude
+
+	stores, err := ss.storesRepo.FindServingStores(ctx, &stdom.ServingStoresQuery{
+		Org:                      params.Org,
+		Latitude:                 lat,
+		Longitude:                lon,
+		Capabilities:             capabilities,
+		IncludeTemporarilyClosed: params.IncludeTemporarilyClosed,
+	})
+	if err != nil {
+		l.Error("error finding serving stores in repository", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	located := map[string]bool{}
+	for _, st := range stores {
+		if stLat, stLon, err := geodom.DecodeAddressId(st.AddressId); err == nil {
+			st.Distance = geodom.DistanceMeters(lat, lon, stLat, stLon)
+			located[st.ID] = true
+		}
+	}
+	sort.SliceStable(stores, func(i, j int) bool {
+		if located[stores[i].ID] != located[stores[j].ID] {
+			return located[stores[i].ID]
+		}
+		return stores[i].Distance < stores[j].Distance
+	})
+	if params.RankBy.Routed() {
+		stores = ss.rankByRoute(ctx, origin, stores[:min(routeCandidates(limit), len(stores))], params.RankBy)
+	}
+	stores = stores[:min(limit, len(stores))]
+
+	if params.IncludeAddress {
+		if err := ss.hydrateAddresses(ctx, stores); err != nil {
+			finishSpan(span, err)
+			return nil, err
+		}
+	}
+	localize(stores, params.Locales)
+	return stores, nil
+}
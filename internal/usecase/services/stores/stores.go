@@ -3,28 +3,58 @@ package stores
 import (
 	"context"
 	"errors"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	otelcodes "go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
-	geo_v1 "github.com/comfforts/comff-geo/api/geo/v1"
-	geocl "github.com/comfforts/comff-geo/clients/go"
 	"github.com/comfforts/logger"
 
-	indom "github.com/comfforts/comff-stores/internal/domain/infra"
+	blobdom "github.com/comfforts/comff-stores/internal/domain/blobs"
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
 	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
 	"github.com/comfforts/comff-stores/internal/infra/observability"
 )
 
 const DEFAULT_SEARCH_RADIUS_METERS = 5000
 
+// search results are paged when a limit is set, up to MAX_SEARCH_LIMIT stores a page,
+// searches without one return every match
+const MAX_SEARCH_LIMIT = 1000
+
+// clusters are address ID prefixes CLUSTER_PRECISION_OFFSET characters longer than the
+// map zoom level, up to 8x8 clusters a map tile. From UNCLUSTERED_ZOOM up, stores are
+// returned individually, up to MAX_UNCLUSTERED_STORES.
+const (
+	MAX_CLUSTER_ZOOM         = 22
+	CLUSTER_PRECISION_OFFSET = 3
+	UNCLUSTERED_ZOOM         = 16
+	MAX_UNCLUSTERED_STORES   = 1000
+)
+
+// conditional store updates are rebuilt & retried up to MAX_UPDATE_ATTEMPTS times when
+// another update changes the store first
+const MAX_UPDATE_ATTEMPTS = 5
+
+// stats series default to the last DEFAULT_STATS_BUCKETS intervals, up to MAX_STATS_BUCKETS
+const (
+	DEFAULT_STATS_BUCKETS = 30
+	MAX_STATS_BUCKETS     = 366
+)
+
 const (
 	MISSING_REQUIRED_FIELD = "missing required field"
 	INVALID_ADDRESS_ID     = "invalid address ID"
 	INVALID_LAT_LON        = "invalid latitude/longitude"
 	INVALID_ADDRESS_STR    = "invalid address string"
+	GEO_UNAVAILABLE        = "geo service unavailable"
+	INVALID_SEARCH         = "invalid search parameters"
+	INVALID_STATUS         = "invalid store status"
+	INVALID_STATS          = "invalid stats parameters"
+	INVALID_BOUNDS         = "invalid bounding box"
+	INVALID_ZOOM           = "invalid zoom level"
 )
 
 var (
@@ -32,27 +62,41 @@ var (
 	ErrInvalidAddressId     = errors.New(INVALID_ADDRESS_ID)
 	ErrInvalidLatLon        = errors.New(INVALID_LAT_LON)
 	ErrInvalidAddressStr    = errors.New(INVALID_ADDRESS_STR)
+	ErrGeoUnavailable       = errors.New(GEO_UNAVAILABLE)
+	ErrInvalidSearch        = errors.New(INVALID_SEARCH)
+	ErrInvalidStatus        = errors.New(INVALID_STATUS)
+	ErrInvalidStats         = errors.New(INVALID_STATS)
+	ErrInvalidBounds        = errors.New(INVALID_BOUNDS)
+	ErrInvalidZoom          = errors.New(INVALID_ZOOM)
 )
 
-type StoresServiceConfig struct {
-	MongoConfig indom.StoreConfig
-}
-
-func NewStoresServiceConfig(
-	mongoCfg indom.StoreConfig,
-) (*StoresServiceConfig, error) {
-	return &StoresServiceConfig{
-		MongoConfig: mongoCfg,
-	}, nil
+// StoresServiceOptions are the stores service's optional dependencies, zero values
+// disable what needs them.
+type StoresServiceOptions struct {
+	// Routing ranks stores by route, straight line distance without it.
+	Routing geodom.RoutingProvider
+	// Capabilities are the capabilities stores can be assigned, none without a catalog.
+	Capabilities *stdom.CapabilityCatalog
+	// Blobs stores attachment uploads, uploads fail without it.
+	Blobs blobdom.BlobStore
+	// ValidationRules are the rules stores are validated against, without them stores
+	// only need a name, org & address ID.
+	ValidationRules *stdom.ValidationRules
 }
 
 type storesService struct {
 	metrics    observability.Metrics
 	storesRepo stdom.StoresRepo
-	geoClient  geocl.Client
+	geocoder   geodom.Geocoder
+	routing    geodom.RoutingProvider
+	catalog    *stdom.CapabilityCatalog
+	blobs      blobdom.BlobStore
+	rules      *stdom.ValidationRules
 }
 
-func NewStoresService(ctx context.Context, sr stdom.StoresRepo, gc geocl.Client, mt observability.Metrics) (*storesService, error) {
+// NewStoresService returns the stores service over the stores repository & geocoder,
+// with the optional dependencies in opts.
+func NewStoresService(ctx context.Context, sr stdom.StoresRepo, gc geodom.Geocoder, mt observability.Metrics, opts StoresServiceOptions) (*storesService, error) {
 	l, err := logger.LoggerFromContext(ctx)
 	if err != nil {
 		l = logger.GetSlogLogger()
@@ -62,7 +106,11 @@ func NewStoresService(ctx context.Context, sr stdom.StoresRepo, gc geocl.Client,
 	return &storesService{
 		metrics:    mt,
 		storesRepo: sr, // Initialize with actual storesRepo when available
-		geoClient:  gc,
+		geocoder:   gc,
+		routing:    opts.Routing,
+		catalog:    opts.Capabilities,
+		blobs:      opts.Blobs,
+		rules:      opts.ValidationRules,
 	}, nil
 }
 
@@ -76,24 +124,91 @@ func (ss *storesService) AddStore(ctx context.Context, st *stdom.AddStoreParams)
 	}
 	l.Debug("adding store")
 
-	if st == nil || st.AddressId == "" || st.Name == "" || st.Org == "" {
+	if st == nil {
 		finishSpan(span, ErrMissingRequiredField)
 		return "", ErrMissingRequiredField
 	}
+	if st.Status != "" && !st.Status.Valid() {
+		finishSpan(span, ErrInvalidStatus)
+		return "", ErrInvalidStatus
+	}
+	if st.Status == stdom.STORE_TEMPORARILY_CLOSED {
+		finishSpan(span, ErrScheduledStatus)
+		return "", ErrScheduledStatus
+	}
+	area, err := serviceArea(st.ServiceArea)
+	if err != nil {
+		l.Error("invalid service area", "error", err.Error())
+		finishSpan(span, err)
+		return "", err
+	}
+	region, err := storeRegion(st.Region)
+	if err != nil {
+		finishSpan(span, err)
+		return "", err
+	}
+	capabilities, err := ss.assignableCapabilities(st.Capabilities)
+	if err != nil {
+		finishSpan(span, err)
+		return "", err
+	}
+	locale, err := storeLocale(st.Locale)
+	if err != nil {
+		finishSpan(span, err)
+		return "", err
+	}
+	translations, err := storeTranslations(locale, st.Translations)
+	if err != nil {
+		finishSpan(span, err)
+		return "", err
+	}
+	store := &stdom.Store{
+		Name:         st.Name,
+		Org:          st.Org,
+		AddressId:    st.AddressId,
+		Description:  st.Description,
+		Tags:         st.Tags,
+		Status:       st.Status,
+		ServiceArea:  area,
+		ParentID:     st.ParentID,
+		Region:       region,
+		Capabilities: capabilities,
+		Locale:       locale,
+		Translations: translations,
+	}
+	// satellites are in their parent's region unless set, stores without an org fail
+	// validation below
+	inheritRegion := region == ""
+	if st.ParentID != "" && st.Org != "" {
+		if err := ss.checkNewParent(ctx, store, inheritRegion); err != nil {
+			l.Error("invalid parent store", "error", err.Error())
+			finishSpan(span, err)
+			return "", err
+		}
+	}
+	if err := ss.validateStore(store); err != nil {
+		l.Error("invalid store", "error", err.Error())
+		finishSpan(span, err)
+		return "", err
+	}
 
-	if _, err := ss.geoClient.GetGeoLocation(ctx, &geo_v1.GeoLocationRequest{
-		AddressId: st.AddressId,
-	}); err != nil {
+	if _, err := ss.geocoder.LocateAddressId(ctx, st.AddressId); err != nil {
 		l.Error("error validating address ID with geo service", "address_id", st.AddressId, "error", err.Error())
+		if errors.Is(err, geodom.ErrGeoUnavailable) {
+			finishSpan(span, ErrGeoUnavailable)
+			return "", ErrGeoUnavailable
+		}
 		finishSpan(span, ErrInvalidAddressId)
 		return "", ErrInvalidAddressId
 	}
 
-	id, err := ss.storesRepo.AddStore(ctx, &stdom.Store{
-		Name:      st.Name,
-		Org:       st.Org,
-		AddressId: st.AddressId,
-	})
+	id, err := ss.storesRepo.AddStore(ctx, store)
+	// the parent chain changed since it was checked
+	for attempt := 1; errors.Is(err, stdom.ErrStoreConflict) && attempt < MAX_UPDATE_ATTEMPTS; attempt++ {
+		if err = ss.checkNewParent(ctx, store, inheritRegion); err == nil {
+			id, err = ss.storesRepo.AddStore(ctx, store)
+		}
+	}
 	if err != nil {
 		l.Error("error adding store to repository", "error", err.Error())
 		finishSpan(span, err)
@@ -103,7 +218,7 @@ func (ss *storesService) AddStore(ctx context.Context, st *stdom.AddStoreParams)
 	return id, nil
 }
 
-func (ss *storesService) GetStore(ctx context.Context, id string) (*stdom.Store, error) {
+func (ss *storesService) GetStore(ctx context.Context, id string, opts *stdom.GetStoreOptions) (*stdom.Store, error) {
 	ctx, span := startSpan(ctx, "stores.service.get")
 	defer span.End()
 
@@ -123,6 +238,16 @@ func (ss *storesService) GetStore(ctx context.Context, id string) (*stdom.Store,
 		finishSpan(span, err)
 		return nil, err
 	}
+
+	if opts != nil && opts.IncludeAddress {
+		if err := ss.hydrateAddresses(ctx, []*stdom.Store{store}); err != nil {
+			finishSpan(span, err)
+			return nil, err
+		}
+	}
+	if opts != nil {
+		localize([]*stdom.Store{store}, opts.Locales)
+	}
 	return store, nil
 }
 
@@ -141,16 +266,80 @@ func (ss *storesService) UpdateStore(ctx context.Context, id string, params *std
 		return ErrMissingRequiredField
 	}
 
-	if params == nil || (params.Name == "" && params.Org == "" && params.AddressId == "") {
+	if params == nil || (params.Name == "" && params.Org == "" && params.AddressId == "" && params.Description == "" && len(params.Tags) == 0 && params.Status == "" && params.ServiceArea == "" && params.ParentID == "" && params.Region == "" && !params.DetachParent && len(params.Capabilities) == 0 && params.Locale == "" && len(params.Translations) == 0 && !params.ClearTranslations) {
 		finishSpan(span, ErrMissingRequiredField)
 		return ErrMissingRequiredField
 	}
-
-	if err := ss.storesRepo.UpdateStore(ctx, id, &stdom.UpdateStoreQuery{
-		Name:      params.Name,
-		Org:       params.Org,
-		AddressId: params.AddressId,
-	}); err != nil {
+	if params.Status != "" && !params.Status.Valid() {
+		finishSpan(span, ErrInvalidStatus)
+		return ErrInvalidStatus
+	}
+	if params.Status == stdom.STORE_TEMPORARILY_CLOSED {
+		finishSpan(span, ErrScheduledStatus)
+		return ErrScheduledStatus
+	}
+	area, err := serviceArea(params.ServiceArea)
+	if err != nil {
+		l.Error("invalid service area", "error", err.Error())
+		finishSpan(span, err)
+		return err
+	}
+	region, err := storeRegion(params.Region)
+	if err != nil {
+		finishSpan(span, err)
+		return err
+	}
+	capabilities, err := ss.assignableCapabilities(params.Capabilities)
+	if err != nil {
+		finishSpan(span, err)
+		return err
+	}
+	locale, err := storeLocale(params.Locale)
+	if err != nil {
+		finishSpan(span, err)
+		return err
+	}
+	translations, err := storeTranslations(locale, params.Translations)
+	if err != nil {
+		finishSpan(span, err)
+		return err
+	}
+	base := stdom.UpdateStoreQuery{
+		Name:              params.Name,
+		Org:               params.Org,
+		AddressId:         params.AddressId,
+		Description:       params.Description,
+		Tags:              params.Tags,
+		Status:            params.Status,
+		ServiceArea:       area,
+		ParentID:          params.ParentID,
+		Region:            region,
+		DetachParent:      params.DetachParent,
+		Capabilities:      capabilities,
+		Locale:            locale,
+		Translations:      translations,
+		ClearTranslations: params.ClearTranslations,
+	}
+	err = ss.updateStore(ctx, id, func(st *stdom.Store) (*stdom.UpdateStoreQuery, error) {
+		query := base
+		if err := ss.validateUpdate(st, &query); err != nil {
+			l.Error("invalid store update", "error", err.Error())
+			return nil, err
+		}
+		if err := ss.checkHierarchyUpdate(ctx, st, params, &query); err != nil {
+			l.Error("invalid parent store", "error", err.Error())
+			return nil, err
+		}
+		if err := checkUpdatedLocales(st, &query); err != nil {
+			return nil, err
+		}
+		if params.Status != "" {
+			// reactivated stores follow their closures again
+			scheduleStatus(st, &query)
+		}
+		return &query, nil
+	})
+	if err != nil {
 		l.Error("error updating store in repository", "error", err.Error())
 		finishSpan(span, err)
 		return err
@@ -158,6 +347,28 @@ func (ss *storesService) UpdateStore(ctx context.Context, id string, params *std
 	return nil
 }
 
+// updateStore updates the store with the query build returns for it, conditional on the
+// store's version. When another update changes the store first, the query is rebuilt
+// for the store as it is then & retried, up to MAX_UPDATE_ATTEMPTS times. A nil query
+// leaves the store as it is.
+func (ss *storesService) updateStore(ctx context.Context, id string, build func(st *stdom.Store) (*stdom.UpdateStoreQuery, error)) error {
+	for range MAX_UPDATE_ATTEMPTS {
+		st, err := ss.storesRepo.GetStore(ctx, id)
+		if err != nil {
+			return err
+		}
+		query, err := build(st)
+		if err != nil || query == nil {
+			return err
+		}
+		query.IfVersion = &st.Version
+		if err := ss.storesRepo.UpdateStore(ctx, id, query); !errors.Is(err, stdom.ErrStoreConflict) {
+			return err
+		}
+	}
+	return stdom.ErrStoreConflict
+}
+
 func (ss *storesService) DeleteStore(ctx context.Context, id string) error {
 	ctx, span := startSpan(ctx, "stores.service.delete")
 	defer span.End()
@@ -173,15 +384,47 @@ func (ss *storesService) DeleteStore(ctx context.Context, id string) error {
 		return ErrMissingRequiredField
 	}
 
-	err = ss.storesRepo.DeleteStore(ctx, id)
+	st, err := ss.deleteStore(ctx, id)
 	if err != nil {
 		finishSpan(span, err)
 		return err
 	}
+
+	// uploaded attachment content goes with the store
+	for _, a := range st.Attachments {
+		if a.BlobKey != "" {
+			ss.deleteBlob(ctx, a.BlobKey)
+		}
+	}
 	return nil
 }
 
-func (ss *storesService) SearchStores(ctx context.Context, params *stdom.SearchStoreParams) ([]*stdom.Store, error) {
+// deleteStore deletes the store without satellites, conditional on the version it was
+// checked at, so satellites added & attachments uploaded in between aren't left behind.
+// When another update changes the store first, it's checked again & the delete retried,
+// up to MAX_UPDATE_ATTEMPTS times. It returns the deleted store.
+func (ss *storesService) deleteStore(ctx context.Context, id string) (*stdom.Store, error) {
+	for range MAX_UPDATE_ATTEMPTS {
+		st, err := ss.storesRepo.GetStore(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		// satellites are detached or deleted first
+		children, err := ss.storesRepo.ListChildStores(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if len(children) > 0 {
+			return nil, ErrStoreHasChildren
+		}
+		if err := ss.storesRepo.DeleteStore(ctx, id, &st.Version); !errors.Is(err, stdom.ErrStoreConflict) {
+			return st, err
+		}
+	}
+	return nil, stdom.ErrStoreConflict
+}
+
+func (ss *storesService) SearchStores(ctx context.Context, params *stdom.SearchStoreParams) (*stdom.SearchStoreResult, error) {
 	ctx, span := startSpan(ctx, "stores.service.search")
 	defer span.End()
 
@@ -191,33 +434,73 @@ func (ss *storesService) SearchStores(ctx context.Context, params *stdom.SearchS
 	}
 	l.Debug("searching stores")
 
-	if params == nil || (params.Org == "" && params.Name == "" && params.AddressId == "" && params.AddressStr == "" && (params.Latitude == 0 || params.Longitude == 0)) {
+	if params == nil || (params.Org == "" && params.Name == "" && params.Query == "" && params.AddressId == "" && params.AddressStr == "" && (params.Latitude == 0 || params.Longitude == 0) && params.Within == nil && params.Region == "" && len(params.Capabilities) == 0) {
 		finishSpan(span, ErrMissingRequiredField)
 		return nil, ErrMissingRequiredField
 	}
+	if params.Region, err = storeRegion(params.Region); err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+	if params.Capabilities, err = ss.catalogCapabilities(params.Capabilities); err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+
+	var within []geodom.Polygon
+	if params.Within != nil {
+		if within, err = withinArea(params.Within); err != nil {
+			l.Error("invalid search area", "error", err.Error())
+			finishSpan(span, err)
+			return nil, err
+		}
+	}
+
+	for _, field := range params.Facets {
+		if !field.Valid() {
+			finishSpan(span, ErrInvalidSearch)
+			return nil, ErrInvalidSearch
+		}
+	}
+	if params.Limit < 0 || params.Offset < 0 || (params.RankBy != "" && params.KNearest == 0) {
+		finishSpan(span, ErrInvalidSearch)
+		return nil, ErrInvalidSearch
+	}
+	// nearest searches return k stores, they aren't paged
+	if params.KNearest != 0 {
+		result, err := ss.searchNearest(ctx, params)
+		if err != nil {
+			finishSpan(span, err)
+			return nil, err
+		}
+		return result, nil
+	}
+	if params.Limit > 0 {
+		params.Limit = min(params.Limit, MAX_SEARCH_LIMIT)
+	}
+
+	// fuzzy matching is on the name & scores by similarity, it doesn't mix with text queries
+	if params.Fuzzy {
+		switch {
+		case params.Name == "":
+			finishSpan(span, ErrMissingRequiredField)
+			return nil, ErrMissingRequiredField
+		case params.Query != "", params.MinSimilarity < 0, params.MinSimilarity > 1:
+			finishSpan(span, ErrInvalidSearch)
+			return nil, ErrInvalidSearch
+		case params.MinSimilarity == 0:
+			params.MinSimilarity = stdom.DEFAULT_FUZZY_MIN_SIMILARITY
+		}
+	}
 
 	if params.AddressId == "" {
-		if params.AddressStr != "" {
-			geoResp, err := ss.geoClient.GeoLocate(ctx, &geo_v1.GeoRequest{
-				AddressStr: params.AddressStr,
-			})
-			if err != nil {
-				l.Error("error validating address string with geo service", "address_str", params.AddressStr, "error", err.Error())
-				finishSpan(span, ErrInvalidAddressStr)
-				return nil, ErrInvalidAddressStr
-			}
-			params.AddressId = geoResp.GetPoint().GetHash()
-		} else if params.Latitude != 0 && params.Longitude != 0 {
-			geoResp, err := ss.geoClient.GeoLocate(ctx, &geo_v1.GeoRequest{
-				Latitude:  params.Latitude,
-				Longitude: params.Longitude,
-			})
-			if err != nil {
-				l.Error("error validating latitude/longitude with geo service", "latitude", params.Latitude, "longitude", params.Longitude, "error", err.Error())
-				finishSpan(span, ErrInvalidLatLon)
-				return nil, ErrInvalidLatLon
-			}
-			params.AddressId = geoResp.GetPoint().GetHash()
+		loc, err := ss.locateSearchPoint(ctx, params.AddressStr, params.Latitude, params.Longitude)
+		if err != nil {
+			finishSpan(span, err)
+			return nil, err
+		}
+		if loc != nil {
+			params.AddressId = loc.AddressId
 		}
 
 		if params.AddressId != "" {
@@ -251,18 +534,229 @@ func (ss *storesService) SearchStores(ctx context.Context, params *stdom.SearchS
 	}
 
 	searchQry := &stdom.SearchStoreQuery{
-		Org:       params.Org,
-		Name:      params.Name,
-		AddressId: params.AddressId,
+		Org:                      params.Org,
+		Name:                     params.Name,
+		AddressId:                params.AddressId,
+		Query:                    params.Query,
+		Fuzzy:                    params.Fuzzy,
+		MinSimilarity:            params.MinSimilarity,
+		Facets:                   params.Facets,
+		Limit:                    params.Limit,
+		Offset:                   params.Offset,
+		Within:                   within,
+		Region:                   params.Region,
+		Capabilities:             params.Capabilities,
+		IncludeTemporarilyClosed: params.IncludeTemporarilyClosed,
 	}
 
-	stores, err := ss.storesRepo.SearchStores(ctx, searchQry)
+	result, err := ss.storesRepo.SearchStores(ctx, searchQry)
 	if err != nil {
 		l.Error("error searching stores in repository", "error", err.Error())
 		finishSpan(span, err)
 		return nil, err
 	}
-	return stores, nil
+
+	if params.IncludeAddress {
+		if err := ss.hydrateAddresses(ctx, result.Stores); err != nil {
+			finishSpan(span, err)
+			return nil, err
+		}
+	}
+	localize(result.Stores, params.Locales)
+	return result, nil
+}
+
+// locateSearchPoint geocodes the address string or else the point, nil when neither is set.
+func (ss *storesService) locateSearchPoint(ctx context.Context, addressStr string, lat, lon float64) (*geodom.Location, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if addressStr != "" {
+		loc, err := ss.geocoder.GeocodeAddress(ctx, addressStr)
+		if err != nil {
+			l.Error("error validating address string with geo service", "address_str", addressStr, "error", err.Error())
+			if errors.Is(err, geodom.ErrGeoUnavailable) {
+				return nil, ErrGeoUnavailable
+			}
+			return nil, ErrInvalidAddressStr
+		}
+		return loc, nil
+	}
+	if lat != 0 && lon != 0 {
+		loc, err := ss.geocoder.GeocodeLatLon(ctx, lat, lon)
+		if err != nil {
+			l.Error("error validating latitude/longitude with geo service", "latitude", lat, "longitude", lon, "error", err.Error())
+			if errors.Is(err, geodom.ErrGeoUnavailable) {
+				return nil, ErrGeoUnavailable
+			}
+			return nil, ErrInvalidLatLon
+		}
+		return loc, nil
+	}
+	return nil, nil
+}
+
+func (ss *storesService) GetAddressHistory(ctx context.Context, id string) ([]*stdom.AddressChange, error) {
+	ctx, span := startSpan(ctx, "stores.service.address_history")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("getting store address history")
+
+	if id == "" {
+		finishSpan(span, ErrMissingRequiredField)
+		return nil, ErrMissingRequiredField
+	}
+
+	changes, err := ss.storesRepo.GetAddressHistory(ctx, id)
+	if err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+	return changes, nil
+}
+
+// GetStoreStats counts the stores & buckets their additions & deletions by day, unless
+// another interval is set. The series runs from the start of the bucket From falls in,
+// the last DEFAULT_STATS_BUCKETS buckets when unset, until To, now when unset.
+func (ss *storesService) GetStoreStats(ctx context.Context, params *stdom.StoreStatsParams) (*stdom.StoreStats, error) {
+	ctx, span := startSpan(ctx, "stores.service.stats")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("getting store stats")
+
+	if params == nil {
+		finishSpan(span, ErrMissingRequiredField)
+		return nil, ErrMissingRequiredField
+	}
+
+	interval := params.Interval
+	if interval == "" {
+		interval = stdom.STATS_DAY
+	}
+	to := params.To
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	from := params.From
+	if from.IsZero() {
+		from = interval.BucketStart(to)
+		for range DEFAULT_STATS_BUCKETS - 1 {
+			from = interval.BucketStart(from.Add(-time.Nanosecond))
+		}
+	}
+	if !interval.Valid() ||
+		!from.Before(to) ||
+		params.RegionPrecision < 0 ||
+		params.RegionPrecision > geodom.DEFAULT_ADDRESS_ID_PRECISION {
+		finishSpan(span, ErrInvalidStats)
+		return nil, ErrInvalidStats
+	}
+	from = interval.BucketStart(from)
+
+	buckets := 0
+	for start := from; start.Before(to); start = interval.Next(start) {
+		if buckets++; buckets > MAX_STATS_BUCKETS {
+			finishSpan(span, ErrInvalidStats)
+			return nil, ErrInvalidStats
+		}
+	}
+
+	stats, err := ss.storesRepo.GetStoreStats(ctx, &stdom.StoreStatsQuery{
+		Org:             params.Org,
+		From:            from,
+		To:              to,
+		Interval:        interval,
+		RegionPrecision: params.RegionPrecision,
+	})
+	if err != nil {
+		l.Error("error getting store stats from repository", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ClusterStores groups the stores in the bounds for a map at the zoom level, returning
+// them individually when zoomed in from UNCLUSTERED_ZOOM.
+func (ss *storesService) ClusterStores(ctx context.Context, params *stdom.ClusterStoresParams) ([]*stdom.StoreCluster, error) {
+	ctx, span := startSpan(ctx, "stores.service.cluster")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+	l.Debug("clustering stores")
+
+	if params == nil || params.Bounds == nil {
+		finishSpan(span, ErrMissingRequiredField)
+		return nil, ErrMissingRequiredField
+	}
+	if !params.Bounds.Valid() {
+		finishSpan(span, ErrInvalidBounds)
+		return nil, ErrInvalidBounds
+	}
+	if params.Zoom < 0 || params.Zoom > MAX_CLUSTER_ZOOM {
+		finishSpan(span, ErrInvalidZoom)
+		return nil, ErrInvalidZoom
+	}
+
+	clusters, err := ss.storesRepo.ClusterStores(ctx, &stdom.ClusterStoresQuery{
+		Org:        params.Org,
+		Bounds:     params.Bounds,
+		Precision:  min(params.Zoom+CLUSTER_PRECISION_OFFSET, geodom.DEFAULT_ADDRESS_ID_PRECISION),
+		Individual: params.Zoom >= UNCLUSTERED_ZOOM,
+		Limit:      MAX_UNCLUSTERED_STORES,
+	})
+	if err != nil {
+		l.Error("error clustering stores in repository", "error", err.Error())
+		finishSpan(span, err)
+		return nil, err
+	}
+	return clusters, nil
+}
+
+// hydrateAddresses resolves the stores' addresses with geo, once per address ID.
+// Stores whose address ID geo no longer resolves are left without an address.
+func (ss *storesService) hydrateAddresses(ctx context.Context, stores []*stdom.Store) error {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	resolved := map[string]*stdom.Address{}
+	for _, st := range stores {
+		addr, ok := resolved[st.AddressId]
+		if !ok {
+			loc, err := ss.geocoder.LocateAddressId(ctx, st.AddressId)
+			if err != nil {
+				if errors.Is(err, geodom.ErrGeoUnavailable) {
+					l.Error("geo unavailable resolving store address", "address_id", st.AddressId, "error", err.Error())
+					return ErrGeoUnavailable
+				}
+				l.Warn("error resolving store address", "store_id", st.ID, "address_id", st.AddressId, "error", err.Error())
+			} else {
+				addr = &stdom.Address{
+					FormattedAddress: loc.FormattedAddress,
+					Latitude:         loc.Latitude,
+					Longitude:        loc.Longitude,
+				}
+			}
+			resolved[st.AddressId] = addr
+		}
+		st.Address = addr
+	}
+	return nil
 }
 
 func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
@@ -13,6 +13,7 @@ import (
 	"github.com/comfforts/logger"
 
 	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+	geoinfra "github.com/comfforts/comff-stores/internal/infra/geo"
 	"github.com/comfforts/comff-stores/internal/infra/mongostore"
 	"github.com/comfforts/comff-stores/internal/infra/observability"
 	strepo "github.com/comfforts/comff-stores/internal/repo/stores"
@@ -39,7 +40,7 @@ func TestStoresService(t *testing.T) {
 	require.NoError(t, err)
 
 	// Initialize stores repository
-	sr, err := strepo.NewStoresRepo(ctx, ms, metrics)
+	sr, err := strepo.NewStoresRepo(ctx, ms, metrics, stdom.ADDRESS_UNIQUE_GLOBAL)
 	require.NoError(t, err)
 	defer func() {
 		err := sr.Close(ctx)
@@ -58,8 +59,12 @@ func TestStoresService(t *testing.T) {
 		require.NoError(t, err)
 	}()
 
+	// Initialize geocoder
+	geocoder, err := geoinfra.NewComffGeocoder(gc)
+	require.NoError(t, err)
+
 	// Initialize stores service
-	_, err = stores.NewStoresService(ctx, sr, gc, metrics)
+	_, err = stores.NewStoresService(ctx, sr, geocoder, metrics, stores.StoresServiceOptions{})
 	require.NoError(t, err)
 	l.Debug("TestStoresRepo done")
 }
@@ -82,7 +87,7 @@ func TestStoresServiceCRUD(t *testing.T) {
 	require.NoError(t, err)
 
 	// Initialize stores repository
-	sr, err := strepo.NewStoresRepo(ctx, ms, metrics)
+	sr, err := strepo.NewStoresRepo(ctx, ms, metrics, stdom.ADDRESS_UNIQUE_GLOBAL)
 	require.NoError(t, err)
 	defer func() {
 		err := sr.Close(ctx)
@@ -101,8 +106,12 @@ func TestStoresServiceCRUD(t *testing.T) {
 		require.NoError(t, err)
 	}()
 
+	// Initialize geocoder
+	geocoder, err := geoinfra.NewComffGeocoder(gc)
+	require.NoError(t, err)
+
 	// Initialize stores service
-	ss, err := stores.NewStoresService(ctx, sr, gc, metrics)
+	ss, err := stores.NewStoresService(ctx, sr, geocoder, metrics, stores.StoresServiceOptions{})
 	require.NoError(t, err)
 
 	// Test AddStore with valid data
@@ -115,7 +124,7 @@ func TestStoresServiceCRUD(t *testing.T) {
 	require.NotEmpty(t, storeId)
 
 	// Test GetStore
-	store, err := ss.GetStore(ctx, storeId)
+	store, err := ss.GetStore(ctx, storeId, nil)
 	require.NoError(t, err)
 	require.NotNil(t, store)
 	require.Equal(t, "Test Store", store.Name)
@@ -127,7 +136,7 @@ func TestStoresServiceCRUD(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	store, err = ss.GetStore(ctx, storeId)
+	store, err = ss.GetStore(ctx, storeId, nil)
 	require.NoError(t, err)
 	require.NotNil(t, store)
 	require.Equal(t, "Updated Test Store", store.Name)
@@ -137,7 +146,7 @@ func TestStoresServiceCRUD(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify store is deleted
-	deletedStore, err := ss.GetStore(ctx, storeId)
+	deletedStore, err := ss.GetStore(ctx, storeId, nil)
 	require.Error(t, err)
 	require.Equal(t, err, strepo.ErrNoStore)
 	require.Nil(t, deletedStore)
@@ -162,7 +171,7 @@ func TestStoresServiceSearchStores(t *testing.T) {
 	require.NoError(t, err)
 
 	// Initialize stores repository
-	sr, err := strepo.NewStoresRepo(ctx, ms, metrics)
+	sr, err := strepo.NewStoresRepo(ctx, ms, metrics, stdom.ADDRESS_UNIQUE_GLOBAL)
 	require.NoError(t, err)
 	defer func() {
 		err := sr.Close(ctx)
@@ -181,8 +190,12 @@ func TestStoresServiceSearchStores(t *testing.T) {
 		require.NoError(t, err)
 	}()
 
+	// Initialize geocoder
+	geocoder, err := geoinfra.NewComffGeocoder(gc)
+	require.NoError(t, err)
+
 	// Initialize stores service
-	ss, err := stores.NewStoresService(ctx, sr, gc, metrics)
+	ss, err := stores.NewStoresService(ctx, sr, geocoder, metrics, stores.StoresServiceOptions{})
 	require.NoError(t, err)
 
 	addrIdMap := map[string]*geo_v1.Point{}
@@ -234,8 +247,8 @@ func TestStoresServiceSearchStores(t *testing.T) {
 	})
 	require.NoError(t, err)
 	require.NotNil(t, sts)
-	require.GreaterOrEqual(t, len(sts), 1)
-	l.Debug("SearchStores returned stores", "count", len(sts))
+	require.GreaterOrEqual(t, len(sts.Stores), 1)
+	l.Debug("SearchStores returned stores", "count", len(sts.Stores))
 
 	// name search
 	sts, err = ss.SearchStores(ctx, &stdom.SearchStoreParams{
@@ -243,8 +256,8 @@ func TestStoresServiceSearchStores(t *testing.T) {
 	})
 	require.NoError(t, err)
 	require.NotNil(t, sts)
-	require.GreaterOrEqual(t, len(sts), 1)
-	l.Debug("SearchStores returned stores", "count", len(sts))
+	require.GreaterOrEqual(t, len(sts.Stores), 1)
+	l.Debug("SearchStores returned stores", "count", len(sts.Stores))
 
 	// address id search
 	sts, err = ss.SearchStores(ctx, &stdom.SearchStoreParams{
@@ -252,8 +265,8 @@ func TestStoresServiceSearchStores(t *testing.T) {
 	})
 	require.NoError(t, err)
 	require.NotNil(t, sts)
-	require.Equal(t, 1, len(sts))
-	l.Debug("SearchStores returned stores", "count", len(sts))
+	require.Equal(t, 1, len(sts.Stores))
+	l.Debug("SearchStores returned stores", "count", len(sts.Stores))
 
 	// lat/long search
 	sts, err = ss.SearchStores(ctx, &stdom.SearchStoreParams{
@@ -263,8 +276,8 @@ func TestStoresServiceSearchStores(t *testing.T) {
 	})
 	require.NoError(t, err)
 	require.NotNil(t, sts)
-	require.GreaterOrEqual(t, len(sts), 1)
-	l.Debug("SearchStores returned stores", "count", len(sts))
+	require.GreaterOrEqual(t, len(sts.Stores), 1)
+	l.Debug("SearchStores returned stores", "count", len(sts.Stores))
 
 	// address string search
 	sts, err = ss.SearchStores(ctx, &stdom.SearchStoreParams{
@@ -273,8 +286,8 @@ func TestStoresServiceSearchStores(t *testing.T) {
 	})
 	require.NoError(t, err)
 	require.NotNil(t, sts)
-	require.GreaterOrEqual(t, len(sts), 1)
-	l.Debug("SearchStores returned stores", "count", len(sts))
+	require.GreaterOrEqual(t, len(sts.Stores), 1)
+	l.Debug("SearchStores returned stores", "count", len(sts.Stores))
 
 	l.Debug("TestStoresServiceSearchStores done")
 }
@@ -0,0 +1,77 @@
+package stores
+
+import (
+	"errors"
+	"strings"
+
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+const INVALID_STORE = "invalid store"
+
+var ErrInvalidStore = errors.New(INVALID_STORE)
+
+// ValidationError is a store breaking the validation rules, with every violation.
+type ValidationError struct {
+	Violations []*stdom.FieldViolation
+}
+
+func (ve *ValidationError) Error() string {
+	msgs := make([]string, 0, len(ve.Violations))
+	for _, v := range ve.Violations {
+		msgs = append(msgs, v.Field+" "+v.Description)
+	}
+	return INVALID_STORE + ": " + strings.Join(msgs, "; ")
+}
+
+func (ve *ValidationError) Unwrap() error {
+	return ErrInvalidStore
+}
+
+// validateStore checks the store against the validation rules.
+func (ss *storesService) validateStore(st *stdom.Store) error {
+	if violations := ss.rules.Validate(st); len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// validateUpdate checks the store, as the query updates it, against the validation
+// rules. Only the fields the update changes are checked, so stores predating a rule
+// can still be updated otherwise, unless the update moves the store to another org.
+func (ss *storesService) validateUpdate(current *stdom.Store, query *stdom.UpdateStoreQuery) error {
+	if ss.rules == nil {
+		// updates can't clear the required fields
+		return nil
+	}
+
+	changed := changedFields(query)
+	violations := []*stdom.FieldViolation{}
+	for _, v := range ss.rules.Validate(stdom.ApplyUpdate(current, query)) {
+		field, _, _ := strings.Cut(v.Field, "[")
+		if changed[field] || (query.Org != "" && query.Org != current.Org) {
+			violations = append(violations, v)
+		}
+	}
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// changedFields returns the API names of the store fields the query changes.
+func changedFields(query *stdom.UpdateStoreQuery) map[string]bool {
+	return map[string]bool{
+		stdom.STORE_NAME:         query.Name != "",
+		stdom.STORE_ORG:          query.Org != "",
+		stdom.STORE_ADDRESS_ID:   query.AddressId != "",
+		stdom.STORE_DESCRIPTION:  query.Description != "",
+		stdom.STORE_TAGS:         len(query.Tags) > 0,
+		stdom.STORE_SERVICE_AREA: query.ServiceArea != nil,
+		stdom.STORE_PARENT_ID:    query.ParentID != "" || query.DetachParent,
+		stdom.STORE_REGION:       query.Region != "",
+		stdom.STORE_CAPABILITIES: len(query.Capabilities) > 0,
+		stdom.STORE_LOCALE:       query.Locale != "",
+		stdom.STORE_TRANSLATIONS: len(query.Translations) > 0 || query.ClearTranslations,
+	}
+}
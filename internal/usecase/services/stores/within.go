@@ -0,0 +1,86 @@
+package stores
+
+import (
+	"errors"
+	"fmt"
+
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
+	stdom "github.com/comfforts/comff-stores/internal/domain/stores"
+)
+
+// MAX_AREA_VERTICES is the most positions a search or service area's rings can have in all.
+const MAX_AREA_VERTICES = 1000
+
+const INVALID_WITHIN = "invalid within area"
+
+var ErrInvalidWithin = errors.New(INVALID_WITHIN)
+
+// withinArea returns a search area's polygons, a bounding box as a polygon.
+func withinArea(w *stdom.WithinParams) ([]geodom.Polygon, error) {
+	switch {
+	case w.Bounds != nil && w.GeoJSON != "", w.Bounds == nil && w.GeoJSON == "":
+		return nil, fmt.Errorf("%w: set one of bounding box or GeoJSON", ErrInvalidWithin)
+	case w.Bounds != nil:
+		if !w.Bounds.Valid() {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidWithin, ErrInvalidBounds)
+		}
+		return []geodom.Polygon{geodom.BoundsPolygon(w.Bounds)}, nil
+	}
+
+	area, err := parseArea(w.GeoJSON)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidWithin, err)
+	}
+	return area, nil
+}
+
+// parseArea parses a GeoJSON Polygon or MultiPolygon area, which must follow RFC 7946:
+// closed rings of at least 4 positions, exterior rings counterclockwise & holes
+// clockwise, positions in range, with no rings crossing themselves or each other.
+func parseArea(geoJSON string) ([]geodom.Polygon, error) {
+	area, err := geodom.ParseGeoJSONArea([]byte(geoJSON))
+	if err != nil {
+		return nil, err
+	}
+	if len(area) == 0 {
+		return nil, errors.New("no polygons")
+	}
+
+	vertices := 0
+	for i, p := range area {
+		if len(p) == 0 {
+			return nil, fmt.Errorf("polygon %d has no rings", i)
+		}
+		for j, r := range p {
+			vertices += len(r)
+			if vertices > MAX_AREA_VERTICES {
+				return nil, fmt.Errorf("more than %d vertices", MAX_AREA_VERTICES)
+			}
+			if len(r) < 4 {
+				return nil, fmt.Errorf("polygon %d ring %d has fewer than 4 positions", i, j)
+			}
+			if !r.Closed() {
+				return nil, fmt.Errorf("polygon %d ring %d isn't closed", i, j)
+			}
+			for _, pos := range r {
+				if pos[0] < -180 || pos[0] > 180 || pos[1] < -90 || pos[1] > 90 {
+					return nil, fmt.Errorf("polygon %d ring %d position %v out of range", i, j, pos)
+				}
+			}
+
+			area := r.SignedArea()
+			switch {
+			case area == 0:
+				return nil, fmt.Errorf("polygon %d ring %d has no area", i, j)
+			case j == 0 && area < 0:
+				return nil, fmt.Errorf("polygon %d exterior ring isn't counterclockwise", i)
+			case j > 0 && area > 0:
+				return nil, fmt.Errorf("polygon %d hole ring %d isn't clockwise", i, j)
+			}
+		}
+		if p.SelfIntersects() {
+			return nil, fmt.Errorf("polygon %d intersects itself", i)
+		}
+	}
+	return area, nil
+}
@@ -0,0 +1,260 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/comfforts/logger"
+
+	evdom "github.com/comfforts/comff-stores/internal/domain/events"
+	whdom "github.com/comfforts/comff-stores/internal/domain/webhooks"
+)
+
+const (
+	MISSING_REQUIRED_FIELD = "missing required field"
+	INVALID_WEBHOOK_URL    = "invalid webhook URL"
+	INVALID_EVENT_TYPE     = "invalid event type"
+	MISSING_EVENT          = "missing event"
+)
+
+var (
+	ErrMissingRequiredField = errors.New(MISSING_REQUIRED_FIELD)
+	ErrInvalidWebhookURL    = errors.New(INVALID_WEBHOOK_URL)
+	ErrInvalidEventType     = errors.New(INVALID_EVENT_TYPE)
+	ErrMissingEvent         = errors.New(MISSING_EVENT)
+)
+
+var knownEventTypes = map[string]bool{
+	string(evdom.STORE_ADDED):   true,
+	string(evdom.STORE_UPDATED): true,
+	string(evdom.STORE_DELETED): true,
+}
+
+type webhooksService struct {
+	repo whdom.WebhooksRepo
+}
+
+func NewWebhooksService(ctx context.Context, wr whdom.WebhooksRepo) (*webhooksService, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if wr == nil {
+		return nil, ErrMissingRequiredField
+	}
+
+	l.Info("initialized webhooks service")
+	return &webhooksService{
+		repo: wr,
+	}, nil
+}
+
+func (ws *webhooksService) RegisterWebhook(ctx context.Context, params *whdom.RegisterWebhookParams) (string, error) {
+	ctx, span := startSpan(ctx, "stores.webhooks.service.register")
+	defer span.End()
+
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if params == nil || params.URL == "" || params.Secret == "" {
+		finishSpan(span, ErrMissingRequiredField)
+		return "", ErrMissingRequiredField
+	}
+
+	u, err := url.Parse(params.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		l.Error("invalid webhook URL", "url", params.URL)
+		finishSpan(span, ErrInvalidWebhookURL)
+		return "", ErrInvalidWebhookURL
+	}
+	// the dispatcher checks the address again when dialing, hosts can resolve differently later
+	if err := checkWebhookHost(ctx, u.Hostname()); err != nil {
+		l.Error("invalid webhook URL host", "url", params.URL, "error", err.Error())
+		finishSpan(span, ErrInvalidWebhookURL)
+		return "", ErrInvalidWebhookURL
+	}
+
+	for _, et := range params.EventTypes {
+		if !knownEventTypes[et] {
+			l.Error("invalid webhook event type", "event_type", et)
+			finishSpan(span, ErrInvalidEventType)
+			return "", ErrInvalidEventType
+		}
+	}
+
+	eventTypes := params.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	id, err := ws.repo.AddSubscription(ctx, &whdom.Subscription{
+		URL:        params.URL,
+		EventTypes: eventTypes,
+		Org:        params.Org,
+		Secret:     params.Secret,
+		CreatedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		l.Error("error registering webhook", "error", err.Error())
+		finishSpan(span, err)
+		return "", err
+	}
+
+	span.SetAttributes(attribute.String("webhook_id", id))
+	return id, nil
+}
+
+func (ws *webhooksService) DeleteWebhook(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "stores.webhooks.service.delete")
+	defer span.End()
+
+	if id == "" {
+		finishSpan(span, ErrMissingRequiredField)
+		return ErrMissingRequiredField
+	}
+
+	err := ws.repo.DeleteSubscription(ctx, id)
+	finishSpan(span, err)
+	return err
+}
+
+func (ws *webhooksService) ListWebhooks(ctx context.Context, org string) ([]*whdom.Subscription, error) {
+	ctx, span := startSpan(ctx, "stores.webhooks.service.list")
+	defer span.End()
+
+	subs, err := ws.repo.ListSubscriptions(ctx, org)
+	finishSpan(span, err)
+	return subs, err
+}
+
+func (ws *webhooksService) GetDeliveries(ctx context.Context, params *whdom.DeliveriesQuery) ([]*whdom.Delivery, error) {
+	ctx, span := startSpan(ctx, "stores.webhooks.service.deliveries")
+	defer span.End()
+
+	if params == nil || params.SubscriptionID == "" {
+		finishSpan(span, ErrMissingRequiredField)
+		return nil, ErrMissingRequiredField
+	}
+
+	deliveries, err := ws.repo.ListDeliveries(ctx, params)
+	finishSpan(span, err)
+	return deliveries, err
+}
+
+// checkWebhookHost checks the host is, or only resolves to, public addresses, so webhooks
+// can't be pointed at the service's own or internal hosts.
+func checkWebhookHost(ctx context.Context, host string) error {
+	addrs := []netip.Addr{}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = append(addrs, addr)
+	} else if addrs, err = net.DefaultResolver.LookupNetIP(ctx, "ip", host); err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !whdom.PublicAddr(addr) {
+			return fmt.Errorf("webhook host %s address %s is not public", host, addr)
+		}
+	}
+	return nil
+}
+
+// webhookPublisher is an event publisher that fans store events out into
+// pending deliveries for every matching webhook subscription.
+type webhookPublisher struct {
+	repo whdom.WebhooksRepo
+}
+
+func NewWebhookPublisher(ctx context.Context, wr whdom.WebhooksRepo) (*webhookPublisher, error) {
+	l, err := logger.LoggerFromContext(ctx)
+	if err != nil {
+		l = logger.GetSlogLogger()
+	}
+
+	if wr == nil {
+		return nil, ErrMissingRequiredField
+	}
+
+	l.Info("initialized webhook publisher")
+	return &webhookPublisher{
+		repo: wr,
+	}, nil
+}
+
+func (wp *webhookPublisher) Publish(ctx context.Context, ev *evdom.Event) error {
+	ctx, span := startSpan(ctx, "stores.webhooks.publish")
+	defer span.End()
+
+	if ev == nil {
+		finishSpan(span, ErrMissingEvent)
+		return ErrMissingEvent
+	}
+	span.SetAttributes(attribute.String("event_id", ev.ID), attribute.String("event_type", string(ev.Type)))
+
+	// a store moved to another org is the previous org's change too
+	orgs := []string{ev.Org}
+	if ev.PreviousOrg != "" && ev.PreviousOrg != ev.Org {
+		orgs = append(orgs, ev.PreviousOrg)
+	}
+	subs, err := wp.repo.MatchSubscriptions(ctx, string(ev.Type), orgs)
+	if err != nil {
+		finishSpan(span, err)
+		return err
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		finishSpan(span, err)
+		return err
+	}
+
+	now := time.Now().UTC()
+	deliveries := make([]*whdom.Delivery, 0, len(subs))
+	for _, sub := range subs {
+		deliveries = append(deliveries, &whdom.Delivery{
+			SubscriptionID: sub.ID,
+			EventID:        ev.ID,
+			EventType:      string(ev.Type),
+			Payload:        payload,
+			Status:         whdom.DELIVERY_PENDING,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+	}
+
+	err = wp.repo.EnqueueDeliveries(ctx, deliveries)
+	finishSpan(span, err)
+	return err
+}
+
+func (wp *webhookPublisher) Close(ctx context.Context) error {
+	return nil
+}
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer("stores-webhooks-service").Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func finishSpan(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(otelcodes.Error, err.Error())
+}
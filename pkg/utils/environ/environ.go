@@ -3,7 +3,10 @@ package environ
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
+	geodom "github.com/comfforts/comff-stores/internal/domain/geo"
 	indom "github.com/comfforts/comff-stores/internal/domain/infra"
 	"github.com/comfforts/comff-stores/internal/infra/mongostore"
 )
@@ -37,6 +40,134 @@ func BuildMetricsConfig() (string, string) {
 	return metricsPort, otelEndpoint
 }
 
+// BuildStoresRepoConfig returns the stores repository backend (mongo or memory).
+func BuildStoresRepoConfig() string {
+	repo := os.Getenv("STORES_REPO")
+	if repo == "" {
+		repo = "mongo"
+	}
+	return repo
+}
+
+// BuildAddressUniquenessConfig returns the store address uniqueness rule (global, org or none).
+func BuildAddressUniquenessConfig() string {
+	uniq := os.Getenv("STORES_ADDRESS_UNIQUENESS")
+	if uniq == "" {
+		uniq = "global"
+	}
+	return uniq
+}
+
+// BuildMigrateConfig returns whether the server applies pending migrations at startup,
+// true unless MIGRATE_ON_STARTUP is false.
+func BuildMigrateConfig() bool {
+	migrate, err := strconv.ParseBool(os.Getenv("MIGRATE_ON_STARTUP"))
+	if err != nil {
+		return true
+	}
+	return migrate
+}
+
+// BuildOutboxConfig returns the outbox event publisher type (stdout, file or none)
+// and the file path used by the file publisher.
+func BuildOutboxConfig() (string, string) {
+	publisher := os.Getenv("OUTBOX_PUBLISHER")
+	if publisher == "" {
+		publisher = "stdout"
+	}
+	filePath := os.Getenv("OUTBOX_FILE_PATH")
+	return publisher, filePath
+}
+
+// BuildWebhookConfig returns the max webhook delivery attempts before dead-lettering,
+// zero when not set or invalid.
+func BuildWebhookConfig() int {
+	maxAttempts, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+	if err != nil || maxAttempts < 0 {
+		return 0
+	}
+	return maxAttempts
+}
+
+// BuildIdempotencyConfig returns how long idempotency keys are kept,
+// zero when not set or invalid.
+func BuildIdempotencyConfig() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_KEY_TTL"))
+	if err != nil || ttl < 0 {
+		return 0
+	}
+	return ttl
+}
+
+// BuildGeoProviderConfig returns the geocoder provider (comff or offline)
+// and the fixtures file used by the offline geocoder, built-in fixtures when empty.
+func BuildGeoProviderConfig() (string, string) {
+	provider := os.Getenv("GEO_PROVIDER")
+	if provider == "" {
+		provider = "comff"
+	}
+	fixturesPath := os.Getenv("GEO_FIXTURES_FILE")
+	return provider, fixturesPath
+}
+
+// BuildRoutingConfig returns the routing provider (haversine or matrix) and the
+// routing matrix file used by the matrix provider.
+func BuildRoutingConfig() (string, string) {
+	provider := os.Getenv("ROUTING_PROVIDER")
+	if provider == "" {
+		provider = "haversine"
+	}
+	matrixPath := os.Getenv("ROUTING_MATRIX_FILE")
+	return provider, matrixPath
+}
+
+// BuildBlobStoreConfig returns the blob store (local or none, none when empty), the local
+// store's directory, data/blobs when empty, and the base URL blobs are served at, file URIs when empty.
+func BuildBlobStoreConfig() (string, string, string) {
+	provider := os.Getenv("BLOB_STORE")
+	if provider == "" {
+		provider = "none"
+	}
+	dir := os.Getenv("BLOB_STORE_DIR")
+	if dir == "" {
+		dir = "data/blobs"
+	}
+	return provider, dir, os.Getenv("BLOB_BASE_URL")
+}
+
+// BuildCapabilityCatalogConfig returns the capability catalog file, the built-in catalog when empty.
+func BuildCapabilityCatalogConfig() string {
+	return os.Getenv("CAPABILITY_CATALOG_FILE")
+}
+
+// BuildValidationRulesConfig returns the store validation rules file, no rules when empty.
+func BuildValidationRulesConfig() string {
+	return os.Getenv("STORE_VALIDATION_RULES_FILE")
+}
+
+// BuildGeoResilienceConfig returns geo client cache, retry & circuit breaker options,
+// unset or invalid values fall back to the client defaults.
+func BuildGeoResilienceConfig() geodom.ResilienceOptions {
+	opts := geodom.ResilienceOptions{}
+	if v, err := strconv.Atoi(os.Getenv("GEO_CACHE_SIZE")); err == nil {
+		opts.CacheSize = v
+	}
+	if v, err := time.ParseDuration(os.Getenv("GEO_CACHE_TTL")); err == nil {
+		opts.CacheTTL = v
+	}
+	opts.MaxRetries = geodom.DEFAULT_MAX_RETRIES
+	if v, err := strconv.Atoi(os.Getenv("GEO_MAX_RETRIES")); err == nil {
+		opts.MaxRetries = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("GEO_BREAKER_THRESHOLD")); err == nil {
+		opts.BreakerThreshold = v
+	}
+	if v, err := time.ParseDuration(os.Getenv("GEO_BREAKER_COOLDOWN")); err == nil {
+		opts.BreakerCooldown = v
+	}
+	return opts
+}
+
 func BuildServerTLSConfig() indom.TLSConfig {
 	caFilePath := os.Getenv("TLS_CA_FILE")
 	certFilePath := os.Getenv("TLS_CERT_FILE")